@@ -40,6 +40,19 @@
 //	int        int32            *bson.int32Type
 //	timestamp  types.Timestamp  *bson.timestampType
 //	long       int64            *bson.int64Type
+//	decimal    types.Decimal128 *bson.decimal128Type
+//	minKey     types.MinKeyType *bson.minKeyType
+//	maxKey     types.MaxKeyType *bson.maxKeyType
+//
+// Deprecated scalar types
+//
+//	Alias                types package          bson package
+//
+//	undefined            types.UndefinedType    *bson.undefinedType
+//	symbol               types.Symbol           *bson.symbolType
+//	javascript           types.JavaScript       *bson.javaScriptType
+//	javascriptWithScope  types.JavaScriptScope  *bson.javaScriptScopeType
+//	dbPointer            types.DBPointer        *bson.dbPointerType
 //
 //nolint:dupword // false positive
 package bson
@@ -94,6 +107,22 @@ func fromBSON(v bsontype) any {
 		return types.Timestamp(*v)
 	case *int64Type:
 		return int64(*v)
+	case *decimal128Type:
+		return types.Decimal128(*v)
+	case *minKeyType:
+		return types.MinKey
+	case *maxKeyType:
+		return types.MaxKey
+	case *undefinedType:
+		return types.Undefined
+	case *symbolType:
+		return types.Symbol(*v)
+	case *javaScriptType:
+		return types.JavaScript(*v)
+	case *javaScriptScopeType:
+		return types.JavaScriptScope(*v)
+	case *dbPointerType:
+		return types.DBPointer(*v)
 	case *CString:
 		panic("CString should not be there")
 	}
@@ -132,6 +161,22 @@ func toBSON(v any) bsontype {
 		return pointer.To(timestampType(v))
 	case int64:
 		return pointer.To(int64Type(v))
+	case types.Decimal128:
+		return pointer.To(decimal128Type(v))
+	case types.MinKeyType:
+		return pointer.To(minKeyType(v))
+	case types.MaxKeyType:
+		return pointer.To(maxKeyType(v))
+	case types.UndefinedType:
+		return pointer.To(undefinedType(v))
+	case types.Symbol:
+		return pointer.To(symbolType(v))
+	case types.JavaScript:
+		return pointer.To(javaScriptType(v))
+	case types.JavaScriptScope:
+		return pointer.To(javaScriptScopeType(v))
+	case types.DBPointer:
+		return pointer.To(dbPointerType(v))
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
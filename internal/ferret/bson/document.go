@@ -232,7 +232,8 @@ func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
 			fields = append(fields, field{key: key, value: types.Binary(v)})
 
 		case tagUndefined:
-			return lazyerrors.Errorf("bson.Document.ReadFrom: unhandled element type `Undefined (value) Deprecated`")
+			// skip calling ReadFrom that does nothing
+			fields = append(fields, field{key: key, value: types.Undefined})
 
 		case tagObjectID:
 			var v objectIDType
@@ -294,8 +295,54 @@ func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
 
 			fields = append(fields, field{key: key, value: int64(v)})
 
-		case tagDBPointer, tagDecimal, tagJavaScript, tagJavaScriptScope, tagMaxKey, tagMinKey, tagSymbol:
-			return lazyerrors.Errorf("bson.Document.ReadFrom: unhandled element type %#02x (%s)", t, tag(t))
+		case tagDecimal:
+			var v decimal128Type
+			if err := v.ReadFrom(bufr); err != nil {
+				return lazyerrors.Errorf("bson.Document.ReadFrom (Decimal128): %w", err)
+			}
+
+			fields = append(fields, field{key: key, value: types.Decimal128(v)})
+
+		case tagMinKey:
+			// skip calling ReadFrom that does nothing
+			fields = append(fields, field{key: key, value: types.MinKey})
+
+		case tagMaxKey:
+			// skip calling ReadFrom that does nothing
+			fields = append(fields, field{key: key, value: types.MaxKey})
+
+		case tagSymbol:
+			var v symbolType
+			if err := v.ReadFrom(bufr); err != nil {
+				return lazyerrors.Errorf("bson.Document.ReadFrom (Symbol): %w", err)
+			}
+
+			fields = append(fields, field{key: key, value: types.Symbol(v)})
+
+		case tagJavaScript:
+			var v javaScriptType
+			if err := v.ReadFrom(bufr); err != nil {
+				return lazyerrors.Errorf("bson.Document.ReadFrom (JavaScript): %w", err)
+			}
+
+			fields = append(fields, field{key: key, value: types.JavaScript(v)})
+
+		case tagJavaScriptScope:
+			var v javaScriptScopeType
+			if err := v.ReadFrom(bufr); err != nil {
+				return lazyerrors.Errorf("bson.Document.ReadFrom (JavaScriptScope): %w", err)
+			}
+
+			fields = append(fields, field{key: key, value: types.JavaScriptScope(v)})
+
+		case tagDBPointer:
+			var v dbPointerType
+			if err := v.ReadFrom(bufr); err != nil {
+				return lazyerrors.Errorf("bson.Document.ReadFrom (DBPointer): %w", err)
+			}
+
+			fields = append(fields, field{key: key, value: types.DBPointer(v)})
+
 		default:
 			return lazyerrors.Errorf("bson.Document.ReadFrom: unhandled element type %#02x (%s)", t, tag(t))
 		}
@@ -458,6 +505,72 @@ func (doc Document) MarshalBinary() ([]byte, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
+		case types.Decimal128:
+			bufw.WriteByte(byte(tagDecimal))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			if err := decimal128Type(elV).WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+		case types.MinKeyType:
+			bufw.WriteByte(byte(tagMinKey))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			// skip calling WriteTo that does nothing
+
+		case types.MaxKeyType:
+			bufw.WriteByte(byte(tagMaxKey))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			// skip calling WriteTo that does nothing
+
+		case types.UndefinedType:
+			bufw.WriteByte(byte(tagUndefined))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			// skip calling WriteTo that does nothing
+
+		case types.Symbol:
+			bufw.WriteByte(byte(tagSymbol))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			if err := symbolType(elV).WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+		case types.JavaScript:
+			bufw.WriteByte(byte(tagJavaScript))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			if err := javaScriptType(elV).WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+		case types.JavaScriptScope:
+			bufw.WriteByte(byte(tagJavaScriptScope))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			if err := javaScriptScopeType(elV).WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+		case types.DBPointer:
+			bufw.WriteByte(byte(tagDBPointer))
+			if err := ename.WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+			if err := dbPointerType(elV).WriteTo(bufw); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
 		default:
 			return nil, lazyerrors.Errorf("bson.Document.MarshalBinary: unhandled element type %T", elV)
 		}
@@ -0,0 +1,289 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// minKeyType represents BSON MinKey type.
+type minKeyType types.MinKeyType
+
+func (*minKeyType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (*minKeyType) ReadFrom(r *bufio.Reader) error {
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (minKeyType) WriteTo(w *bufio.Writer) error {
+	return nil
+}
+
+// MarshalBinary implements bsontype interface.
+func (minKeyType) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+// maxKeyType represents BSON MaxKey type.
+type maxKeyType types.MaxKeyType
+
+func (*maxKeyType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (*maxKeyType) ReadFrom(r *bufio.Reader) error {
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (maxKeyType) WriteTo(w *bufio.Writer) error {
+	return nil
+}
+
+// MarshalBinary implements bsontype interface.
+func (maxKeyType) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+// undefinedType represents deprecated BSON Undefined type.
+type undefinedType types.UndefinedType
+
+func (*undefinedType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (*undefinedType) ReadFrom(r *bufio.Reader) error {
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (undefinedType) WriteTo(w *bufio.Writer) error {
+	return nil
+}
+
+// MarshalBinary implements bsontype interface.
+func (undefinedType) MarshalBinary() ([]byte, error) {
+	return nil, nil
+}
+
+// symbolType represents deprecated BSON Symbol type.
+//
+// It is encoded as a string.
+type symbolType types.Symbol
+
+func (s *symbolType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (s *symbolType) ReadFrom(r *bufio.Reader) error {
+	var str stringType
+	if err := str.ReadFrom(r); err != nil {
+		return lazyerrors.Errorf("bson.Symbol.ReadFrom: %w", err)
+	}
+
+	*s = symbolType(str)
+
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (s symbolType) WriteTo(w *bufio.Writer) error {
+	return stringType(s).WriteTo(w)
+}
+
+// MarshalBinary implements bsontype interface.
+func (s symbolType) MarshalBinary() ([]byte, error) {
+	return stringType(s).MarshalBinary()
+}
+
+// javaScriptType represents BSON JavaScript code type.
+//
+// It is encoded as a string.
+type javaScriptType types.JavaScript
+
+func (js *javaScriptType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (js *javaScriptType) ReadFrom(r *bufio.Reader) error {
+	var str stringType
+	if err := str.ReadFrom(r); err != nil {
+		return lazyerrors.Errorf("bson.JavaScript.ReadFrom: %w", err)
+	}
+
+	*js = javaScriptType(str)
+
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (js javaScriptType) WriteTo(w *bufio.Writer) error {
+	return stringType(js).WriteTo(w)
+}
+
+// MarshalBinary implements bsontype interface.
+func (js javaScriptType) MarshalBinary() ([]byte, error) {
+	return stringType(js).MarshalBinary()
+}
+
+// javaScriptScopeType represents deprecated BSON JavaScript code with scope type.
+type javaScriptScopeType types.JavaScriptScope
+
+func (js *javaScriptScopeType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (js *javaScriptScopeType) ReadFrom(r *bufio.Reader) error {
+	var l int32
+	if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom (binary.Read): %w", err)
+	}
+
+	// length, code string length, code terminating byte, and the smallest scope document
+	if l < 4+4+1+5 {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom: invalid length %d", l)
+	}
+
+	b := make([]byte, l-4)
+	if n, err := io.ReadFull(r, b); err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom: expected %d, read %d: %w", len(b), n, err)
+	}
+
+	br := bytes.NewReader(b)
+	bufr := bufio.NewReader(br)
+
+	var code stringType
+	if err := code.ReadFrom(bufr); err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom (code): %w", err)
+	}
+
+	var scope Document
+	if err := scope.ReadFrom(bufr); err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom (scope): %w", err)
+	}
+
+	if _, err := bufr.Peek(1); err != io.EOF {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom: unexpected trailing bytes")
+	}
+
+	doc, err := types.ConvertDocument(&scope)
+	if err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.ReadFrom (scope): %w", err)
+	}
+
+	*js = javaScriptScopeType{Code: string(code), Scope: doc}
+
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (js javaScriptScopeType) WriteTo(w *bufio.Writer) error {
+	v, err := js.MarshalBinary()
+	if err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.WriteTo: %w", err)
+	}
+
+	if _, err = w.Write(v); err != nil {
+		return lazyerrors.Errorf("bson.JavaScriptScope.WriteTo: %w", err)
+	}
+
+	return nil
+}
+
+// MarshalBinary implements bsontype interface.
+func (js javaScriptScopeType) MarshalBinary() ([]byte, error) {
+	code, err := stringType(js.Code).MarshalBinary()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	scope, err := ConvertDocument(js.Scope)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	b, err := scope.MarshalBinary()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, int32(4+len(code)+len(b)))
+	buf.Write(code)
+	buf.Write(b)
+
+	return buf.Bytes(), nil
+}
+
+// dbPointerType represents deprecated BSON DBPointer type.
+type dbPointerType types.DBPointer
+
+func (p *dbPointerType) bsontype() {}
+
+// ReadFrom implements bsontype interface.
+func (p *dbPointerType) ReadFrom(r *bufio.Reader) error {
+	var ns stringType
+	if err := ns.ReadFrom(r); err != nil {
+		return lazyerrors.Errorf("bson.DBPointer.ReadFrom (namespace): %w", err)
+	}
+
+	var id objectIDType
+	if err := id.ReadFrom(r); err != nil {
+		return lazyerrors.Errorf("bson.DBPointer.ReadFrom (id): %w", err)
+	}
+
+	*p = dbPointerType{Namespace: string(ns), ID: types.ObjectID(id)}
+
+	return nil
+}
+
+// WriteTo implements bsontype interface.
+func (p dbPointerType) WriteTo(w *bufio.Writer) error {
+	v, err := p.MarshalBinary()
+	if err != nil {
+		return lazyerrors.Errorf("bson.DBPointer.WriteTo: %w", err)
+	}
+
+	if _, err = w.Write(v); err != nil {
+		return lazyerrors.Errorf("bson.DBPointer.WriteTo: %w", err)
+	}
+
+	return nil
+}
+
+// MarshalBinary implements bsontype interface.
+func (p dbPointerType) MarshalBinary() ([]byte, error) {
+	ns, err := stringType(p.Namespace).MarshalBinary()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return append(ns, p.ID[:]...), nil
+}
+
+// check interfaces
+var (
+	_ bsontype = (*minKeyType)(nil)
+	_ bsontype = (*maxKeyType)(nil)
+	_ bsontype = (*undefinedType)(nil)
+	_ bsontype = (*symbolType)(nil)
+	_ bsontype = (*javaScriptType)(nil)
+	_ bsontype = (*javaScriptScopeType)(nil)
+	_ bsontype = (*dbPointerType)(nil)
+)
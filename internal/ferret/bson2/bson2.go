@@ -34,6 +34,17 @@
 //	32-bit integer      int32
 //	Timestamp           bson2.Timestamp
 //	64-bit integer      int64
+//	Decimal128          bson2.Decimal128
+//	Min key             bson2.MinKeyType
+//	Max key             bson2.MaxKeyType
+//
+// Deprecated BSON types are supported too, so documents that contain them round-trip unchanged:
+//
+//	Undefined           bson2.UndefinedType
+//	Symbol              bson2.Symbol
+//	JavaScript code     bson2.JavaScript
+//	Code with scope     bson2.JavaScriptScope
+//	DBPointer           bson2.DBPointer
 //
 // Composite types (Document and Array) are passed by pointers.
 // Raw composite type and scalars are passed by values.
@@ -87,7 +98,8 @@ var (
 
 // Type represents a BSON type.
 type Type interface {
-	ScalarType | CompositeType
+	ScalarType | Decimal128 | MinKeyType | MaxKeyType | UndefinedType | Symbol | JavaScript | JavaScriptScope | DBPointer |
+		CompositeType
 }
 
 // CompositeType represents a BSON composite type (including raw types).
@@ -114,6 +126,14 @@ func validBSONType(v any) bool {
 	case int32:
 	case Timestamp:
 	case int64:
+	case Decimal128:
+	case MinKeyType:
+	case MaxKeyType:
+	case UndefinedType:
+	case Symbol:
+	case JavaScript:
+	case JavaScriptScope:
+	case DBPointer:
 
 	default:
 		return false
@@ -198,6 +218,27 @@ func convertToTypes(v any) (any, error) {
 		return types.Timestamp(v), nil
 	case int64:
 		return v, nil
+	case Decimal128:
+		return types.Decimal128{H: v.H, L: v.L}, nil
+	case MinKeyType:
+		return types.MinKey, nil
+	case MaxKeyType:
+		return types.MaxKey, nil
+	case UndefinedType:
+		return types.Undefined, nil
+	case Symbol:
+		return types.Symbol(v), nil
+	case JavaScript:
+		return types.JavaScript(v), nil
+	case JavaScriptScope:
+		scope, err := v.Scope.Convert()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return types.JavaScriptScope{Code: v.Code, Scope: scope}, nil
+	case DBPointer:
+		return types.DBPointer{Namespace: v.Namespace, ID: types.ObjectID(v.ID)}, nil
 
 	default:
 		panic(fmt.Sprintf("invalid BSON type %T", v))
@@ -254,6 +295,32 @@ func convertFromTypes(v any) (any, error) {
 		return Timestamp(v), nil
 	case int64:
 		return v, nil
+	case types.Decimal128:
+		return Decimal128{H: v.H, L: v.L}, nil
+	case types.MinKeyType:
+		return MinKey, nil
+	case types.MaxKeyType:
+		return MaxKey, nil
+	case types.UndefinedType:
+		return Undefined, nil
+	case types.Symbol:
+		return Symbol(v), nil
+	case types.JavaScript:
+		return JavaScript(v), nil
+	case types.JavaScriptScope:
+		doc, err := ConvertDocument(v.Scope)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		scope, err := doc.Encode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return JavaScriptScope{Code: v.Code, Scope: scope}, nil
+	case types.DBPointer:
+		return DBPointer{Namespace: v.Namespace, ID: ObjectID(v.ID)}, nil
 
 	default:
 		panic(fmt.Sprintf("invalid type %T", v))
@@ -137,6 +137,40 @@ func (doc *Document) Get(name string) any {
 	return nil
 }
 
+// Len returns the number of fields in the Document.
+func (doc *Document) Len() int {
+	return len(doc.fields)
+}
+
+// FieldNames returns a slice of field names in the Document in the original order.
+//
+// If document contains duplicate field names, the result will have duplicates too.
+func (doc *Document) FieldNames() []string {
+	res := make([]string, len(doc.fields))
+	for i, f := range doc.fields {
+		res[i] = f.name
+	}
+
+	return res
+}
+
+// Values returns a slice of field values in the Document in the original order.
+func (doc *Document) Values() []any {
+	res := make([]any, len(doc.fields))
+	for i, f := range doc.fields {
+		res[i] = f.value
+	}
+
+	return res
+}
+
+// Add adds a new field to the end of the Document.
+//
+// It does not check for duplicate field names.
+func (doc *Document) Add(name string, value any) error {
+	return doc.add(name, value)
+}
+
 // add adds a new field to the Document.
 func (doc *Document) add(name string, value any) error {
 	if !validBSONType(value) {
@@ -295,6 +329,22 @@ func encodeScalarField(buf *bytes.Buffer, name string, v any) error {
 		buf.WriteByte(byte(tagTimestamp))
 	case int64:
 		buf.WriteByte(byte(tagInt64))
+	case Decimal128:
+		buf.WriteByte(byte(tagDecimal))
+	case MinKeyType:
+		buf.WriteByte(byte(tagMinKey))
+	case MaxKeyType:
+		buf.WriteByte(byte(tagMaxKey))
+	case UndefinedType:
+		buf.WriteByte(byte(tagUndefined))
+	case Symbol:
+		buf.WriteByte(byte(tagSymbol))
+	case JavaScript:
+		buf.WriteByte(byte(tagJavaScript))
+	case JavaScriptScope:
+		buf.WriteByte(byte(tagJavaScriptScope))
+	case DBPointer:
+		buf.WriteByte(byte(tagDBPointer))
 	default:
 		panic(fmt.Sprintf("invalid type %T", v))
 	}
@@ -306,8 +356,24 @@ func encodeScalarField(buf *bytes.Buffer, name string, v any) error {
 		return lazyerrors.Error(err)
 	}
 
-	b = make([]byte, bsonproto.SizeAny(v))
-	bsonproto.EncodeAny(b, v)
+	b = make([]byte, sizeAny(v))
+
+	switch v := v.(type) {
+	case Decimal128:
+		encodeDecimal128(b, v)
+	case MinKeyType, MaxKeyType, UndefinedType:
+		// no value bytes
+	case Symbol:
+		bsonproto.EncodeString(b, string(v))
+	case JavaScript:
+		bsonproto.EncodeString(b, string(v))
+	case JavaScriptScope:
+		encodeJavaScriptScope(b, v)
+	case DBPointer:
+		encodeDBPointer(b, v)
+	default:
+		bsonproto.EncodeAny(b, v)
+	}
 
 	if _, err := buf.Write(b); err != nil {
 		return lazyerrors.Error(err)
@@ -334,6 +334,59 @@ var (
 		)),
 	}
 
+	decimal128Doc = testCase{
+		name: "decimal128Doc",
+		raw: RawDocument{
+			0x18, 0x00, 0x00, 0x00,
+			0x13, 0x66, 0x00,
+			0x0f, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x3e, 0x30,
+			0x00,
+		},
+		doc: must.NotFail(types.NewDocument(
+			"f", must.NotFail(types.ParseDecimal128("1.5")),
+		)),
+	}
+
+	minMaxKeyDoc = testCase{
+		name: "minMaxKeyDoc",
+		raw: RawDocument{
+			0x0e, 0x00, 0x00, 0x00,
+			0xff, 0x61, 0x00,
+			0x7f, 0x62, 0x00,
+			0x06, 0x63, 0x00,
+			0x00,
+		},
+		doc: must.NotFail(types.NewDocument(
+			"a", types.MinKey,
+			"b", types.MaxKey,
+			"c", types.Undefined,
+		)),
+	}
+
+	deprecatedDoc = testCase{
+		name: "deprecatedDoc",
+		raw: RawDocument{
+			0x47, 0x00, 0x00, 0x00,
+			0x0e, 0x73, 0x00, 0x02, 0x00, 0x00, 0x00, 0x78, 0x00,
+			0x0d, 0x6a, 0x00, 0x02, 0x00, 0x00, 0x00, 0x66, 0x00,
+			0x0f, 0x77, 0x00, 0x16, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x67, 0x00,
+			0x0c, 0x00, 0x00, 0x00, 0x10, 0x76, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+			0x0c, 0x70, 0x00, 0x04, 0x00, 0x00, 0x00, 0x64, 0x2e, 0x63, 0x00,
+			0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff,
+			0x00,
+		},
+		doc: must.NotFail(types.NewDocument(
+			"s", types.Symbol("x"),
+			"j", types.JavaScript("f"),
+			"w", types.JavaScriptScope{Code: "g", Scope: must.NotFail(types.NewDocument("v", int32(1)))},
+			"p", types.DBPointer{
+				Namespace: "d.c",
+				ID:        types.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff},
+			},
+		)),
+	}
+
 	eof = testCase{
 		name:      "EOF",
 		raw:       RawDocument{0x00},
@@ -429,7 +482,7 @@ var (
 	documentTestCases = []testCase{
 		handshake1, handshake2, handshake3, handshake4, all,
 		float64Doc, stringDoc, binaryDoc, objectIDDoc, boolDoc, timeDoc, nullDoc, regexDoc, int32Doc, timestampDoc, int64Doc,
-		eof, smallDoc, shortDoc, invalidDoc, smallArray, shortArray, invalidArray, duplicateKeys,
+		decimal128Doc, minMaxKeyDoc, deprecatedDoc, eof, smallDoc, shortDoc, invalidDoc, smallArray, shortArray, invalidArray, duplicateKeys,
 	}
 )
 
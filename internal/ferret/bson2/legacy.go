@@ -0,0 +1,152 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson2
+
+import (
+	"encoding/binary"
+
+	"github.com/cristalhq/bson/bsonproto"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+type (
+	// MinKeyType represents BSON scalar type MinKey.
+	MinKeyType struct{}
+
+	// MaxKeyType represents BSON scalar type MaxKey.
+	MaxKeyType struct{}
+
+	// UndefinedType represents deprecated BSON scalar type undefined.
+	UndefinedType struct{}
+
+	// Symbol represents deprecated BSON scalar type symbol.
+	Symbol string
+
+	// JavaScript represents BSON scalar type JavaScript code.
+	JavaScript string
+
+	// JavaScriptScope represents deprecated BSON scalar type JavaScript code with scope.
+	//
+	// Scope is kept in the encoded form.
+	JavaScriptScope struct {
+		Code  string
+		Scope RawDocument
+	}
+
+	// DBPointer represents deprecated BSON scalar type DBPointer.
+	DBPointer struct {
+		Namespace string
+		ID        ObjectID
+	}
+)
+
+var (
+	// MinKey represents BSON scalar value MinKey.
+	MinKey = MinKeyType{}
+
+	// MaxKey represents BSON scalar value MaxKey.
+	MaxKey = MaxKeyType{}
+
+	// Undefined represents BSON scalar value undefined.
+	Undefined = UndefinedType{}
+)
+
+// sizeJavaScriptScope returns a size of the encoding of JavaScriptScope v in bytes.
+func sizeJavaScriptScope(v JavaScriptScope) int {
+	return 4 + bsonproto.SizeString(v.Code) + len(v.Scope)
+}
+
+// encodeJavaScriptScope encodes JavaScriptScope value v into b.
+//
+// b must be at least [sizeJavaScriptScope] bytes long; otherwise, encodeJavaScriptScope will panic.
+func encodeJavaScriptScope(b []byte, v JavaScriptScope) {
+	size := sizeJavaScriptScope(v)
+	_ = b[size-1]
+
+	binary.LittleEndian.PutUint32(b, uint32(size))
+	bsonproto.EncodeString(b[4:], v.Code)
+	copy(b[4+bsonproto.SizeString(v.Code):], v.Scope)
+}
+
+// decodeJavaScriptScope decodes JavaScriptScope value from b.
+//
+// If there is not enough bytes, decodeJavaScriptScope will return a wrapped [ErrDecodeShortInput].
+// If the encoded lengths are inconsistent, it will return a wrapped [ErrDecodeInvalidInput].
+// The returned scope references a part of b without copying.
+func decodeJavaScriptScope(b []byte) (JavaScriptScope, error) {
+	var res JavaScriptScope
+
+	if len(b) < 4 {
+		return res, lazyerrors.Errorf("bson2.decodeJavaScriptScope: expected at least 4 bytes, got %d: %w", len(b), ErrDecodeShortInput)
+	}
+
+	size := int(binary.LittleEndian.Uint32(b))
+	if len(b) < size {
+		return res, lazyerrors.Errorf(
+			"bson2.decodeJavaScriptScope: expected at least %d bytes, got %d: %w", size, len(b), ErrDecodeShortInput,
+		)
+	}
+
+	code, err := bsonproto.DecodeString(b[4:size])
+	if err != nil {
+		return res, lazyerrors.Error(err)
+	}
+
+	scope := RawDocument(b[4+bsonproto.SizeString(code) : size])
+	if len(scope) < 5 || int(binary.LittleEndian.Uint32(scope)) != len(scope) {
+		return res, lazyerrors.Errorf("bson2.decodeJavaScriptScope: invalid scope: %w", ErrDecodeInvalidInput)
+	}
+
+	res.Code = code
+	res.Scope = scope
+
+	return res, nil
+}
+
+// sizeDBPointer returns a size of the encoding of DBPointer v in bytes.
+func sizeDBPointer(v DBPointer) int {
+	return bsonproto.SizeString(v.Namespace) + bsonproto.SizeObjectID
+}
+
+// encodeDBPointer encodes DBPointer value v into b.
+//
+// b must be at least [sizeDBPointer] bytes long; otherwise, encodeDBPointer will panic.
+func encodeDBPointer(b []byte, v DBPointer) {
+	bsonproto.EncodeString(b, v.Namespace)
+	bsonproto.EncodeObjectID(b[bsonproto.SizeString(v.Namespace):], v.ID)
+}
+
+// decodeDBPointer decodes DBPointer value from b.
+//
+// If there is not enough bytes, decodeDBPointer will return a wrapped [ErrDecodeShortInput].
+func decodeDBPointer(b []byte) (DBPointer, error) {
+	var res DBPointer
+
+	ns, err := bsonproto.DecodeString(b)
+	if err != nil {
+		return res, lazyerrors.Error(err)
+	}
+
+	id, err := bsonproto.DecodeObjectID(b[bsonproto.SizeString(ns):])
+	if err != nil {
+		return res, lazyerrors.Error(err)
+	}
+
+	res.Namespace = ns
+	res.ID = id
+
+	return res, nil
+}
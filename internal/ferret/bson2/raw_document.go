@@ -205,8 +205,42 @@ func (raw RawDocument) decode(deep bool) (*Document, error) {
 			v, err = bsonproto.DecodeInt64(raw[offset:])
 			offset += bsonproto.SizeInt64
 
-		case tagUndefined, tagDBPointer, tagJavaScript, tagSymbol, tagJavaScriptScope, tagDecimal, tagMinKey, tagMaxKey:
-			return nil, lazyerrors.Errorf("unsupported tag: %s", t)
+		case tagDecimal:
+			v, err = decodeDecimal128(raw[offset:])
+			offset += sizeDecimal128
+
+		case tagMinKey:
+			v = MinKey
+
+		case tagMaxKey:
+			v = MaxKey
+
+		case tagUndefined:
+			v = Undefined
+
+		case tagSymbol:
+			var s string
+			s, err = bsonproto.DecodeString(raw[offset:])
+			offset += bsonproto.SizeString(s)
+			v = Symbol(s)
+
+		case tagJavaScript:
+			var s string
+			s, err = bsonproto.DecodeString(raw[offset:])
+			offset += bsonproto.SizeString(s)
+			v = JavaScript(s)
+
+		case tagJavaScriptScope:
+			var s JavaScriptScope
+			s, err = decodeJavaScriptScope(raw[offset:])
+			offset += sizeJavaScriptScope(s)
+			v = s
+
+		case tagDBPointer:
+			var p DBPointer
+			p, err = decodeDBPointer(raw[offset:])
+			offset += sizeDBPointer(p)
+			v = p
 
 		default:
 			return nil, lazyerrors.Errorf("unexpected tag: %s", t)
@@ -33,6 +33,18 @@ func sizeAny(v any) int {
 		return sizeArray(v)
 	case RawArray:
 		return len(v)
+	case Decimal128:
+		return sizeDecimal128
+	case MinKeyType, MaxKeyType, UndefinedType:
+		return 0
+	case Symbol:
+		return bsonproto.SizeString(string(v))
+	case JavaScript:
+		return bsonproto.SizeString(string(v))
+	case JavaScriptScope:
+		return sizeJavaScriptScope(v)
+	case DBPointer:
+		return sizeDBPointer(v)
 	default:
 		return bsonproto.SizeAny(v)
 	}
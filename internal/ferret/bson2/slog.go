@@ -88,6 +88,16 @@ func slogScalarValue(v any) slog.Value {
 		return slog.StringValue(fmt.Sprintf("%[1]T(%[1]v)", v))
 	case int64:
 		return slog.StringValue(fmt.Sprintf("%[1]T(%[1]v)", v))
+	case Decimal128:
+		return slog.StringValue(fmt.Sprintf("%[1]T(%[1]v)", v))
+	case MinKeyType, MaxKeyType, UndefinedType:
+		return slog.StringValue(fmt.Sprintf("%[1]T(%[1]v)", v))
+	case Symbol, JavaScript:
+		return slog.StringValue(fmt.Sprintf("%[1]T(%[1]v)", v))
+	case JavaScriptScope:
+		return slog.StringValue(fmt.Sprintf("JavaScriptScope(%q, %d bytes)", v.Code, len(v.Scope)))
+	case DBPointer:
+		return slog.StringValue(fmt.Sprintf("DBPointer(%q, %s)", v.Namespace, hex.EncodeToString(v.ID[:])))
 	default:
 		panic(fmt.Sprintf("invalid type %T", v))
 	}
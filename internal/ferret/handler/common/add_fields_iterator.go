@@ -29,12 +29,14 @@ import (
 // It will be added to the given closer.
 //
 // Next method returns the next document after adding the new field to the document.
+// Aggregation expressions are evaluated with the given variables.
 //
 // Close method closes the underlying iterator.
-func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, newField *types.Document) types.DocumentsIterator { //nolint:lll // for readability
+func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, newField *types.Document, vars *operators.Variables) types.DocumentsIterator { //nolint:lll // for readability
 	res := &addFieldsIterator{
 		iter:     iter,
 		newField: newField,
+		vars:     vars,
 	}
 	closer.Add(res)
 
@@ -45,6 +47,7 @@ func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiClose
 type addFieldsIterator struct {
 	iter     types.DocumentsIterator
 	newField *types.Document
+	vars     *operators.Variables
 }
 
 // Next implements iterator.Interface. See addFieldsIterator for details.
@@ -56,7 +59,11 @@ func (iter *addFieldsIterator) Next() (struct{}, *types.Document, error) {
 		return unused, nil, lazyerrors.Error(err)
 	}
 
-	for _, key := range iter.newField.Keys() {
+	// all expressions are evaluated against the input document before any field is set
+	keys := iter.newField.Keys()
+	values := make([]any, len(keys))
+
+	for i, key := range keys {
 		val := must.NotFail(iter.newField.Get(key))
 
 		switch v := val.(type) {
@@ -70,13 +77,30 @@ func (iter *addFieldsIterator) Next() (struct{}, *types.Document, error) {
 				return unused, nil, err
 			}
 
-			val, err = op.Process(doc)
+			val, err = op.Process(doc, iter.vars)
+			if err = processAddFieldsError(err); err != nil {
+				return unused, nil, err
+			}
+
+		case string:
+			// field paths and variables like `$$ROOT`
+			val, err = operators.Evaluate(v, doc, iter.vars)
 			if err = processAddFieldsError(err); err != nil {
 				return unused, nil, err
 			}
 		}
 
-		doc.Set(key, val)
+		values[i] = val
+	}
+
+	for i, key := range keys {
+		if values[i] == nil {
+			// the expression evaluated to a missing value
+			doc.Remove(key)
+			continue
+		}
+
+		doc.Set(key, values[i])
 	}
 
 	return unused, doc, nil
@@ -15,18 +15,31 @@
 package aggregations
 
 import (
+	"fmt"
 	"math"
 	"math/big"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 )
 
 // SumNumbers accumulate numbers and returns the result of summation.
 // The result has the same type as the input, except when the result
 // cannot be presented accurately. Then int32 is converted to int64,
-// and int64 is converted to float64. It ignores non-number values.
+// and int64 is converted to float64. If any value is types.Decimal128,
+// the result is types.Decimal128. It ignores non-number values.
 // For empty `vs`, it returns int32(0).
 // This should only be used for aggregation, aggregation does not return
 // error on overflow.
 func SumNumbers(vs ...any) any {
+	if ds, ok := decimal128Numbers(vs); ok {
+		sum := types.NewDecimal128FromInt64(0)
+		for _, d := range ds {
+			sum = sum.Add(d)
+		}
+
+		return sum
+	}
+
 	// use big.Int to accumulate values larger than math.MaxInt64.
 	intSum := big.NewInt(0)
 
@@ -69,3 +82,155 @@ func SumNumbers(vs ...any) any {
 
 	return integer
 }
+
+// SubtractNumbers returns the difference of two numbers.
+// Like SumNumbers, the result has the same type as the input unless it
+// cannot be presented accurately. Both values must be int32, int64, float64 or types.Decimal128.
+func SubtractNumbers(a, b any) any {
+	if ds, ok := decimal128Numbers([]any{a, b}); ok {
+		return ds[0].Sub(ds[1])
+	}
+
+	if fa, ok := a.(float64); ok {
+		return fa - NumberToFloat64(b)
+	}
+
+	if fb, ok := b.(float64); ok {
+		return NumberToFloat64(a) - fb
+	}
+
+	_, aInt64 := a.(int64)
+	_, bInt64 := b.(int64)
+
+	diff := new(big.Int).Sub(numberToBigInt(a), numberToBigInt(b))
+
+	return bigIntToNumber(diff, aInt64 || bInt64)
+}
+
+// MultiplyNumbers returns the product of numbers.
+// Like SumNumbers, the result has the same type as the input unless it
+// cannot be presented accurately. It ignores non-number values.
+// For empty `vs`, it returns int32(1).
+func MultiplyNumbers(vs ...any) any {
+	if ds, ok := decimal128Numbers(vs); ok {
+		product := types.NewDecimal128FromInt64(1)
+		for _, d := range ds {
+			product = product.Mul(d)
+		}
+
+		return product
+	}
+
+	intProduct := big.NewInt(1)
+	floatProduct := float64(1)
+
+	var hasFloat64, hasInt64 bool
+
+	for _, v := range vs {
+		switch v := v.(type) {
+		case float64:
+			hasFloat64 = true
+
+			floatProduct *= v
+		case int32:
+			intProduct.Mul(intProduct, big.NewInt(int64(v)))
+		case int64:
+			hasInt64 = true
+
+			intProduct.Mul(intProduct, big.NewInt(v))
+		default:
+			// ignore non-number
+		}
+	}
+
+	if hasFloat64 {
+		intAsFloat, _ := new(big.Float).SetInt(intProduct).Float64()
+
+		return intAsFloat * floatProduct
+	}
+
+	return bigIntToNumber(intProduct, hasInt64)
+}
+
+// NumberToFloat64 converts int32, int64, float64 or types.Decimal128 value to float64.
+// It returns NaN for non-number values.
+func NumberToFloat64(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case types.Decimal128:
+		return v.Float64()
+	default:
+		return math.NaN()
+	}
+}
+
+// IsNumber returns true if v is int32, int64, float64 or types.Decimal128.
+func IsNumber(v any) bool {
+	switch v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return true
+	default:
+		return false
+	}
+}
+
+// decimal128Numbers converts numbers to types.Decimal128 if any of them is types.Decimal128,
+// ignoring non-number values. It returns false if there are no types.Decimal128 values.
+func decimal128Numbers(vs []any) ([]types.Decimal128, bool) {
+	var hasDecimal128 bool
+
+	for _, v := range vs {
+		if _, ok := v.(types.Decimal128); ok {
+			hasDecimal128 = true
+			break
+		}
+	}
+
+	if !hasDecimal128 {
+		return nil, false
+	}
+
+	res := make([]types.Decimal128, 0, len(vs))
+
+	for _, v := range vs {
+		if d, ok := types.NewDecimal128FromNumber(v); ok {
+			res = append(res, d)
+		}
+	}
+
+	return res, true
+}
+
+// numberToBigInt converts int32 or int64 value to *big.Int.
+func numberToBigInt(v any) *big.Int {
+	switch v := v.(type) {
+	case int32:
+		return big.NewInt(int64(v))
+	case int64:
+		return big.NewInt(v)
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// bigIntToNumber returns int32 if v fits and hasInt64 is false,
+// int64 if v fits, and float64 otherwise.
+func bigIntToNumber(v *big.Int, hasInt64 bool) any {
+	if !v.IsInt64() {
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	}
+
+	integer := v.Int64()
+
+	if !hasInt64 && integer <= math.MaxInt32 && integer >= math.MinInt32 {
+		return int32(integer)
+	}
+
+	return integer
+}
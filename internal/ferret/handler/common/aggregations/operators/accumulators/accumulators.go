@@ -25,9 +25,11 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
 )
 
@@ -38,8 +40,9 @@ type newAccumulatorFunc func(args ...any) (Accumulator, error)
 // Accumulator is a common interface for aggregation accumulation operators.
 type Accumulator interface {
 	// Accumulate documents and returns the result of applying operator.
+	// Expressions are evaluated with the given variables.
 	// It should always close iterator.
-	Accumulate(iter types.DocumentsIterator) (any, error)
+	Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error)
 }
 
 // NewAccumulator returns accumulator for provided value.
@@ -100,10 +103,110 @@ func NewAccumulator(stage, key string, value any) (Accumulator, error) {
 	return newAccumulator(args...)
 }
 
+// getUnaryArg returns the argument of the accumulator that takes exactly one argument.
+func getUnaryArg(name string, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupUnaryOperator,
+			fmt.Sprintf("The %s accumulator is a unary operator", name),
+			name+" (accumulator)",
+		)
+	}
+
+	return args[0], nil
+}
+
+// evaluateDocuments evaluates the expression for each document of the iterator and closes it.
+// Missing values are returned as nil.
+func evaluateDocuments(iter types.DocumentsIterator, expression any, vars *operators.Variables) ([]any, error) {
+	defer iter.Close()
+
+	var res []any
+
+	for {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		v, err := operators.Evaluate(expression, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, v)
+	}
+
+	return res, nil
+}
+
+// collectDocuments returns all documents of the iterator and closes it.
+func collectDocuments(iter types.DocumentsIterator) ([]*types.Document, error) {
+	defer iter.Close()
+
+	var res []*types.Document
+
+	for {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res = append(res, doc)
+	}
+
+	return res, nil
+}
+
+// numberToFloat64 returns int32, int64, float64 and decimal values as float64.
+// It returns false for non-numeric values.
+func numberToFloat64(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case types.Decimal128:
+		return v.Float64(), true
+	default:
+		return 0, false
+	}
+}
+
 // Accumulators maps all aggregation accumulators.
 var Accumulators = map[string]newAccumulatorFunc{
 	// sorted alphabetically
-	"$count": newCount,
-	"$sum":   newSum,
+	"$addToSet":     newAddToSet,
+	"$avg":          newAvg,
+	"$bottom":       newTopBottom("$bottom", true, true),
+	"$bottomN":      newTopBottom("$bottomN", true, false),
+	"$count":        newCount,
+	"$first":        newFirst,
+	"$firstN":       newNAccumulator("$firstN", firstNKind),
+	"$last":         newLast,
+	"$lastN":        newNAccumulator("$lastN", lastNKind),
+	"$max":          newMax,
+	"$maxN":         newNAccumulator("$maxN", maxNKind),
+	"$median":       newMedian,
+	"$mergeObjects": newMergeObjects,
+	"$min":          newMin,
+	"$minN":         newNAccumulator("$minN", minNKind),
+	"$percentile":   newPercentile,
+	"$push":         newPush,
+	"$stdDevPop":    newStdDevPop,
+	"$stdDevSamp":   newStdDevSamp,
+	"$sum":          newSum,
+	"$top":          newTopBottom("$top", false, true),
+	"$topN":         newTopBottom("$topN", false, false),
 	// please keep sorted alphabetically
 }
@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// avg represents `$avg` accumulator.
+type avg struct {
+	expression any
+}
+
+// newAvg creates a new `$avg` accumulator.
+func newAvg(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$avg", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &avg{
+		expression: expression,
+	}, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// Non-numeric and missing values are ignored; null is returned if there are no numeric values.
+// The average is a decimal if any value is a decimal, and a double otherwise.
+func (a *avg) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, a.expression, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var sum float64
+	var count int
+	var hasDecimal128 bool
+
+	for _, v := range values {
+		f, ok := numberToFloat64(v)
+		if !ok {
+			continue
+		}
+
+		if _, ok = v.(types.Decimal128); ok {
+			hasDecimal128 = true
+		}
+
+		sum += f
+		count++
+	}
+
+	if count == 0 {
+		return types.Null, nil
+	}
+
+	if hasDecimal128 {
+		// SumNumbers sums all numbers as decimals and ignores other values
+		total := aggregations.SumNumbers(values...).(types.Decimal128)
+		return total.Quo(types.NewDecimal128FromInt64(int64(count))), nil
+	}
+
+	return sum / float64(count), nil
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*avg)(nil)
+)
@@ -17,6 +17,7 @@ package accumulators
 import (
 	"errors"
 
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -49,7 +50,7 @@ func newCount(args ...any) (Accumulator, error) {
 }
 
 // Accumulate implements Accumulator interface.
-func (c *count) Accumulate(iter types.DocumentsIterator) (any, error) {
+func (c *count) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
 	defer iter.Close()
 	var count int32
 
@@ -0,0 +1,87 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// firstLast represents `$first` and `$last` accumulators.
+type firstLast struct {
+	expression any
+	last       bool
+}
+
+// newFirst creates a new `$first` accumulator.
+func newFirst(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$first", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &firstLast{
+		expression: expression,
+	}, nil
+}
+
+// newLast creates a new `$last` accumulator.
+func newLast(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$last", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &firstLast{
+		expression: expression,
+		last:       true,
+	}, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// The expression is evaluated for the first or the last document of the group only;
+// missing value is returned as null.
+func (f *firstLast) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	docs, err := collectDocuments(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return types.Null, nil
+	}
+
+	doc := docs[0]
+	if f.last {
+		doc = docs[len(docs)-1]
+	}
+
+	v, err := operators.Evaluate(f.expression, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return types.Null, nil
+	}
+
+	return v, nil
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*firstLast)(nil)
+)
@@ -0,0 +1,54 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// mergeObjects represents `$mergeObjects` accumulator.
+type mergeObjects struct {
+	expression any
+}
+
+// newMergeObjects creates a new `$mergeObjects` accumulator.
+func newMergeObjects(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$mergeObjects", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mergeObjects{
+		expression: expression,
+	}, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// Fields of later documents overwrite fields of earlier ones; null and missing values are ignored.
+func (m *mergeObjects) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, m.expression, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return operators.MergeObjects(values, "$mergeObjects (accumulator)")
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*mergeObjects)(nil)
+)
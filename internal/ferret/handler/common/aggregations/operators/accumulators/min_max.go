@@ -0,0 +1,93 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// minMax represents `$min` and `$max` accumulators.
+type minMax struct {
+	expression any
+	max        bool
+}
+
+// newMin creates a new `$min` accumulator.
+func newMin(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$min", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &minMax{
+		expression: expression,
+	}, nil
+}
+
+// newMax creates a new `$max` accumulator.
+func newMax(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$max", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &minMax{
+		expression: expression,
+		max:        true,
+	}, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// Values are compared using BSON comparison order. Null and missing values are ignored;
+// null is returned if all values are null or missing.
+func (m *minMax) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, m.expression, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var res any = types.Null
+
+	for _, v := range values {
+		if v == nil || v == types.Null {
+			continue
+		}
+
+		if res == types.Null || isBetter(v, res, m.max) {
+			res = v
+		}
+	}
+
+	return res, nil
+}
+
+// isBetter returns true if v is greater than the current value when looking for maximum,
+// or less than the current value when looking for minimum.
+func isBetter(v, current any, max bool) bool {
+	res := types.CompareForAggregation(v, current)
+
+	if max {
+		return res == types.Greater
+	}
+
+	return res == types.Less
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*minMax)(nil)
+)
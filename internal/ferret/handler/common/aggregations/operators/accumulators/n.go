@@ -0,0 +1,215 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// nKind represents the kind of accumulator returning n values.
+type nKind int
+
+const (
+	firstNKind nKind = iota
+	lastNKind
+	minNKind
+	maxNKind
+)
+
+// nAccumulator represents `$firstN`, `$lastN`, `$minN` and `$maxN` accumulators.
+//
+//	{ $firstN: { input: <expression>, n: <expression> } }
+type nAccumulator struct {
+	input any
+	n     int
+	kind  nKind
+}
+
+// newNAccumulator returns a constructor of the accumulator of the given kind.
+func newNAccumulator(name string, kind nKind) newAccumulatorFunc {
+	return func(args ...any) (Accumulator, error) {
+		var doc *types.Document
+		if len(args) == 1 {
+			doc, _ = args[0].(*types.Document)
+		}
+
+		if doc == nil {
+			var found any = types.MakeArray(0)
+			if len(args) == 1 {
+				found = args[0]
+			}
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNAccumulatorNotObject,
+				fmt.Sprintf("specification must be an object; found %s", types.FormatAnyValue(found)),
+				name+" (accumulator)",
+			)
+		}
+
+		var input, n any
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch k {
+			case "input":
+				input = v
+			case "n":
+				n = v
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNAccumulatorUnknownField,
+					fmt.Sprintf("Unknown argument for 'n' operator: %s", k),
+					name+" (accumulator)",
+				)
+			}
+		}
+
+		if n == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNAccumulatorMissingN,
+				"Missing value for 'n'",
+				name+" (accumulator)",
+			)
+		}
+
+		if input == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNAccumulatorMissingInput,
+				"Missing value for 'input'",
+				name+" (accumulator)",
+			)
+		}
+
+		count, err := getN(name, n)
+		if err != nil {
+			return nil, err
+		}
+
+		return &nAccumulator{
+			input: input,
+			n:     count,
+			kind:  kind,
+		}, nil
+	}
+}
+
+// Accumulate implements Accumulator interface.
+//
+// `$firstN` and `$lastN` return missing values as nulls;
+// `$minN` and `$maxN` ignore null and missing values.
+func (a *nAccumulator) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, a.input, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a.kind {
+	case firstNKind, lastNKind:
+		for i, v := range values {
+			if v == nil {
+				values[i] = types.Null
+			}
+		}
+
+		if a.kind == lastNKind && len(values) > a.n {
+			values = values[len(values)-a.n:]
+		}
+
+	case minNKind, maxNKind:
+		filtered := values[:0]
+
+		for _, v := range values {
+			if v != nil && v != types.Null {
+				filtered = append(filtered, v)
+			}
+		}
+
+		values = filtered
+
+		sort.SliceStable(values, func(i, j int) bool {
+			return isBetter(values[i], values[j], a.kind == maxNKind)
+		})
+	}
+
+	values = values[:min(a.n, len(values))]
+
+	res := types.MakeArray(len(values))
+	res.Append(values...)
+
+	return res, nil
+}
+
+// getN evaluates and validates `n` argument of the accumulator.
+//
+// It should be a constant expression that evaluates to a positive integer.
+func getN(name string, expression any) (int, error) {
+	v, err := operators.Evaluate(expression, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if v == nil {
+		v = types.Null
+	}
+
+	n, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		code := handlererrors.ErrNAccumulatorNNotIntegral
+		if errors.Is(err, handlerparams.ErrUnexpectedType) {
+			code = handlererrors.ErrNAccumulatorNBadType
+		}
+
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			code,
+			fmt.Sprintf("Value for 'n' must be of integral type, but found %s", types.FormatAnyValue(v)),
+			name+" (accumulator)",
+		)
+	}
+
+	if n <= 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNAccumulatorNNotPositive,
+			fmt.Sprintf("'n' must be greater than 0, found %d", n),
+			name+" (accumulator)",
+		)
+	}
+
+	return int(n), nil
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*nAccumulator)(nil)
+)
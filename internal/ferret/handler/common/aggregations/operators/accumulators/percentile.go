@@ -0,0 +1,253 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// percentile represents `$percentile` and `$median` accumulators.
+//
+//	{ $percentile: { input: <expression>, p: [ <expression1>, ... ], method: <string> } }
+//	{ $median: { input: <expression>, method: <string> } }
+type percentile struct {
+	input      any
+	p          []float64
+	continuous bool
+	// median is set for `$median` that returns a single value instead of an array
+	median bool
+}
+
+// newPercentile creates a new `$percentile` accumulator.
+func newPercentile(args ...any) (Accumulator, error) {
+	return newPercentileAccumulator("$percentile", args)
+}
+
+// newMedian creates a new `$median` accumulator.
+func newMedian(args ...any) (Accumulator, error) {
+	return newPercentileAccumulator("$median", args)
+}
+
+// newPercentileAccumulator validates arguments and returns `$percentile` or `$median` accumulator.
+func newPercentileAccumulator(name string, args []any) (Accumulator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s' is the wrong type '%s', expected type 'object'",
+				name, handlerparams.AliasFromType(found),
+			),
+			name+" (accumulator)",
+		)
+	}
+
+	median := name == "$median"
+	fields := map[string]any{}
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if k != "input" && k != "method" && (k != "p" || median) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s.%s' is an unknown field.", name, k),
+				name+" (accumulator)",
+			)
+		}
+
+		fields[k] = v
+	}
+
+	required := []string{"input", "p", "method"}
+	if median {
+		required = []string{"input", "method"}
+	}
+
+	for _, k := range required {
+		if _, ok := fields[k]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMissingField,
+				fmt.Sprintf("BSON field '%s.%s' is missing but a required field", name, k),
+				name+" (accumulator)",
+			)
+		}
+	}
+
+	acc := &percentile{
+		input:  fields["input"],
+		p:      []float64{0.5},
+		median: median,
+	}
+
+	switch method := fields["method"]; method {
+	case "approximate", "discrete":
+	case "continuous":
+		acc.continuous = true
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"%s 'method' must be 'approximate', 'discrete' or 'continuous', found %s",
+				name, types.FormatAnyValue(method),
+			),
+			name+" (accumulator)",
+		)
+	}
+
+	if !median {
+		p, err := getPercentiles(fields["p"])
+		if err != nil {
+			return nil, err
+		}
+
+		acc.p = p
+	}
+
+	return acc, nil
+}
+
+// getPercentiles evaluates and validates `p` argument of `$percentile`.
+func getPercentiles(expression any) ([]float64, error) {
+	v, err := operators.Evaluate(expression, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	newErr := func() error {
+		found := v
+		if found == nil {
+			found = types.Null
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPercentileBadP,
+			fmt.Sprintf(
+				"The 'p' field must be an array of numbers from [0.0, 1.0], but found: %s",
+				types.FormatAnyValue(found),
+			),
+			"$percentile (accumulator)",
+		)
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() == 0 {
+		return nil, newErr()
+	}
+
+	res := make([]float64, arr.Len())
+
+	for i := range res {
+		p, ok := numberToFloat64(must.NotFail(arr.Get(i)))
+		if !ok || p < 0 || p > 1 || math.IsNaN(p) {
+			return nil, newErr()
+		}
+
+		res[i] = p
+	}
+
+	return res, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// Non-numeric and missing values are ignored; if there are no numeric values, nulls are returned.
+// Approximate and discrete methods return one of the input values,
+// the continuous method interpolates between two nearest values.
+func (p *percentile) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, p.input, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []float64
+
+	for _, v := range values {
+		if f, ok := numberToFloat64(v); ok && !math.IsNaN(f) {
+			numbers = append(numbers, f)
+		}
+	}
+
+	sort.Float64s(numbers)
+
+	res := types.MakeArray(len(p.p))
+
+	for _, pv := range p.p {
+		if len(numbers) == 0 {
+			res.Append(types.Null)
+			continue
+		}
+
+		res.Append(percentileOf(numbers, pv, p.continuous))
+	}
+
+	if p.median {
+		return must.NotFail(res.Get(0)), nil
+	}
+
+	return res, nil
+}
+
+// percentileOf returns p-th percentile of sorted non-empty numbers.
+func percentileOf(numbers []float64, p float64, continuous bool) float64 {
+	n := float64(len(numbers))
+
+	if !continuous {
+		// the smallest value with at least p of all values less than or equal to it
+		rank := int(math.Ceil(p*n)) - 1
+		return numbers[max(rank, 0)]
+	}
+
+	rank := p * (n - 1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return numbers[lower] + (rank-float64(lower))*(numbers[upper]-numbers[lower])
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*percentile)(nil)
+)
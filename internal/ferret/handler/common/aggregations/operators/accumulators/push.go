@@ -0,0 +1,95 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// push represents `$push` and `$addToSet` accumulators.
+type push struct {
+	expression any
+	unique     bool
+}
+
+// newPush creates a new `$push` accumulator.
+func newPush(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$push", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &push{
+		expression: expression,
+	}, nil
+}
+
+// newAddToSet creates a new `$addToSet` accumulator.
+func newAddToSet(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$addToSet", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &push{
+		expression: expression,
+		unique:     true,
+	}, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// It returns an array of values with missing values skipped.
+// For `$addToSet`, only the first of equal values is kept.
+func (p *push) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, p.expression, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	res := types.MakeArray(len(values))
+
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+
+		if p.unique && containsValue(res, v) {
+			continue
+		}
+
+		res.Append(v)
+	}
+
+	return res, nil
+}
+
+// containsValue returns true if the array contains an element equal to the value.
+func containsValue(arr *types.Array, v any) bool {
+	for i := 0; i < arr.Len(); i++ {
+		if types.CompareForAggregation(must.NotFail(arr.Get(i)), v) == types.Equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*push)(nil)
+)
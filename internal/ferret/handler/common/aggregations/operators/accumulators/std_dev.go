@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"math"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// stdDev represents `$stdDevPop` and `$stdDevSamp` accumulators.
+type stdDev struct {
+	expression any
+	sample     bool
+}
+
+// newStdDevPop creates a new `$stdDevPop` accumulator.
+func newStdDevPop(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$stdDevPop", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stdDev{
+		expression: expression,
+	}, nil
+}
+
+// newStdDevSamp creates a new `$stdDevSamp` accumulator.
+func newStdDevSamp(args ...any) (Accumulator, error) {
+	expression, err := getUnaryArg("$stdDevSamp", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stdDev{
+		expression: expression,
+		sample:     true,
+	}, nil
+}
+
+// Accumulate implements Accumulator interface.
+//
+// Non-numeric and missing values are ignored. Null is returned if there are no numeric values,
+// or, for the sample standard deviation, if there is only one.
+func (s *stdDev) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	values, err := evaluateDocuments(iter, s.expression, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Welford's online algorithm
+	var count int
+	var mean, m2 float64
+
+	for _, v := range values {
+		f, ok := numberToFloat64(v)
+		if !ok {
+			continue
+		}
+
+		count++
+		delta := f - mean
+		mean += delta / float64(count)
+		m2 += delta * (f - mean)
+	}
+
+	switch {
+	case count == 0, s.sample && count == 1:
+		return types.Null, nil
+	case s.sample:
+		return math.Sqrt(m2 / float64(count-1)), nil
+	default:
+		return math.Sqrt(m2 / float64(count)), nil
+	}
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*stdDev)(nil)
+)
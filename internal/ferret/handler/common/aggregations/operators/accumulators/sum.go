@@ -16,6 +16,7 @@ package accumulators
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
@@ -30,6 +31,9 @@ type sum struct {
 	expression *aggregations.Expression
 	operator   operators.Operator
 	number     any
+
+	// variable is a variable expression such as `$$ROOT.v`
+	variable string
 }
 
 // newSum creates a new $sum aggregation operator.
@@ -66,12 +70,17 @@ func newSum(args ...any) (Accumulator, error) {
 		case float64:
 			accumulator.number = arg
 		case string:
+			if strings.HasPrefix(arg, "$$") {
+				accumulator.variable = arg
+				break
+			}
+
 			var err error
 			if accumulator.expression, err = aggregations.NewExpression(arg, nil); err != nil {
 				// $sum returns 0 on non-existent field.
 				accumulator.number = int32(0)
 			}
-		case int32, int64:
+		case int32, int64, types.Decimal128:
 			accumulator.number = arg
 		default:
 			accumulator.number = int32(0)
@@ -83,7 +92,7 @@ func newSum(args ...any) (Accumulator, error) {
 }
 
 // Accumulate implements Accumulator interface.
-func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
+func (s *sum) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
 	var numbers []any
 
 	for {
@@ -99,7 +108,17 @@ func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
 
 		switch {
 		case s.operator != nil:
-			v, err := s.operator.Process(doc)
+			v, err := s.operator.Process(doc, vars)
+			if err != nil {
+				return nil, err
+			}
+
+			numbers = append(numbers, v)
+
+			continue
+
+		case s.variable != "":
+			v, err := operators.Evaluate(s.variable, doc, vars)
 			if err != nil {
 				return nil, err
 			}
@@ -120,7 +139,7 @@ func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
 		}
 
 		switch number := s.number.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			// For number types, the result is equivalent of iterator len*number,
 			// with conversion handled upon overflow of int32 and int64.
 			// For example, { $sum: 1 } is equivalent of { $count: { } }.
@@ -0,0 +1,200 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// topBottom represents `$top`, `$bottom`, `$topN` and `$bottomN` accumulators.
+//
+//	{ $top: { sortBy: { <field1>: <sort order>, ... }, output: <expression> } }
+//	{ $topN: { n: <expression>, sortBy: { <field1>: <sort order>, ... }, output: <expression> } }
+type topBottom struct {
+	sortBy *types.Document
+	output any
+	n      int
+	bottom bool
+	// single is set for `$top` and `$bottom` that return a single value instead of an array
+	single bool
+}
+
+// newTopBottom returns a constructor of one of top and bottom accumulators.
+func newTopBottom(name string, bottom, single bool) newAccumulatorFunc {
+	return func(args ...any) (Accumulator, error) {
+		var doc *types.Document
+		if len(args) == 1 {
+			doc, _ = args[0].(*types.Document)
+		}
+
+		if doc == nil {
+			var found any = types.MakeArray(0)
+			if len(args) == 1 {
+				found = args[0]
+			}
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomNotObject,
+				fmt.Sprintf("specification must be an object; found %s", types.FormatAnyValue(found)),
+				name+" (accumulator)",
+			)
+		}
+
+		var sortBy, output, n any
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch {
+			case k == "sortBy":
+				sortBy = v
+			case k == "output":
+				output = v
+			case k == "n" && !single:
+				n = v
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTopBottomUnknownField,
+					fmt.Sprintf("Unknown argument to %s '%s'", name, k),
+					name+" (accumulator)",
+				)
+			}
+		}
+
+		if output == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomMissingOutput,
+				fmt.Sprintf("Missing value for 'output' in %s", name),
+				name+" (accumulator)",
+			)
+		}
+
+		if sortBy == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomMissingSortBy,
+				fmt.Sprintf("Missing value for 'sortBy' in %s", name),
+				name+" (accumulator)",
+			)
+		}
+
+		sortDoc, ok := sortBy.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomSortByNotObject,
+				fmt.Sprintf("%s sortBy must be an object, found %s", name, types.FormatAnyValue(sortBy)),
+				name+" (accumulator)",
+			)
+		}
+
+		if _, err := common.ValidateSortDocument(sortDoc); err != nil {
+			return nil, err
+		}
+
+		acc := &topBottom{
+			sortBy: sortDoc,
+			output: output,
+			n:      1,
+			bottom: bottom,
+			single: single,
+		}
+
+		if !single {
+			if n == nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNAccumulatorMissingN,
+					"Missing value for 'n'",
+					name+" (accumulator)",
+				)
+			}
+
+			var err error
+			if acc.n, err = getN(name, n); err != nil {
+				return nil, err
+			}
+		}
+
+		return acc, nil
+	}
+}
+
+// Accumulate implements Accumulator interface.
+//
+// Documents are sorted by sortBy; the output is evaluated for the first or the last n of them,
+// with missing values returned as nulls.
+func (t *topBottom) Accumulate(iter types.DocumentsIterator, vars *operators.Variables) (any, error) {
+	docs, err := collectDocuments(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = common.SortDocuments(docs, t.sortBy); err != nil {
+		return nil, err
+	}
+
+	if t.bottom {
+		docs = docs[max(len(docs)-t.n, 0):]
+	} else {
+		docs = docs[:min(t.n, len(docs))]
+	}
+
+	res := types.MakeArray(len(docs))
+
+	for _, doc := range docs {
+		v, err := operators.Evaluate(t.output, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			v = types.Null
+		}
+
+		res.Append(v)
+	}
+
+	if !t.single {
+		return res, nil
+	}
+
+	if res.Len() == 0 {
+		return types.Null, nil
+	}
+
+	return must.NotFail(res.Get(0)), nil
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*topBottom)(nil)
+)
@@ -0,0 +1,113 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// add represents `$add` operator.
+//
+//	{ $add: [ <expression1>, <expression2>, ... ] }
+type add struct {
+	args []any
+}
+
+// newAdd returns `$add` operator.
+func newAdd(args ...any) (Operator, error) {
+	return &add{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It sums numbers; at most one argument may be a date, then the sum of numbers
+// is added to it as milliseconds. If any argument is null or missing, it returns null.
+func (a *add) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(a.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var date *time.Time
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case float64, int32, int64, types.Decimal128:
+		case time.Time:
+			if date != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrAddMultipleDates,
+					"only one date allowed in an $add expression",
+					"$add",
+				)
+			}
+
+			date = &v
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("$add only supports numeric or date types, not %s", handlerparams.AliasFromType(v)),
+				"$add",
+			)
+		}
+	}
+
+	sum := aggregations.SumNumbers(values...)
+
+	if date == nil {
+		return sum, nil
+	}
+
+	return addMilliseconds(*date, sum), nil
+}
+
+// addMilliseconds returns the date moved by the given number of milliseconds.
+// Doubles are rounded to the nearest millisecond.
+func addMilliseconds(date time.Time, ms any) time.Time {
+	var millis int64
+
+	switch ms := ms.(type) {
+	case float64:
+		millis = int64(math.Round(ms))
+	case int32:
+		millis = int64(ms)
+	case int64:
+		millis = ms
+	case types.Decimal128:
+		millis = int64(math.Round(ms.Float64()))
+	}
+
+	return time.UnixMilli(date.UnixMilli() + millis).UTC()
+}
+
+// check interfaces
+var (
+	_ Operator = (*add)(nil)
+)
@@ -0,0 +1,117 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// arrayElemAt represents `$arrayElemAt` operator.
+//
+//	{ $arrayElemAt: [ <array>, <idx> ] }
+type arrayElemAt struct {
+	array any
+	idx   any
+}
+
+// newArrayElemAt returns `$arrayElemAt` operator.
+func newArrayElemAt(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$arrayElemAt", 2, len(args))
+	}
+
+	return &arrayElemAt{
+		array: args[0],
+		idx:   args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the element at the index, negative index counts from the end of the array.
+// Missing value is returned if the index is out of bounds.
+func (a *arrayElemAt) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{a.array, a.idx}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) || isNullish(values[1]) {
+		return types.Null, nil
+	}
+
+	arr, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtArrayBadType,
+			fmt.Sprintf(
+				"$arrayElemAt's first argument must be an array, but is %s",
+				handlerparams.AliasFromType(values[0]),
+			),
+			"$arrayElemAt",
+		)
+	}
+
+	switch values[1].(type) {
+	case float64, int32, int64:
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtIndexBadType,
+			fmt.Sprintf(
+				"$arrayElemAt's second argument must be a numeric value, but is %s",
+				handlerparams.AliasFromType(values[1]),
+			),
+			"$arrayElemAt",
+		)
+	}
+
+	idx, ok := getInt32(values[1])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtIndexNotInt,
+			fmt.Sprintf(
+				"$arrayElemAt's second argument must be representable as a 32-bit integer: %s",
+				types.FormatAnyValue(values[1]),
+			),
+			"$arrayElemAt",
+		)
+	}
+
+	return elemAt(arr, int(idx)), nil
+}
+
+// elemAt returns the array element at the index, negative index counts from the end of the array.
+// It returns nil if the index is out of bounds.
+func elemAt(arr *types.Array, idx int) any {
+	if idx < 0 {
+		idx += arr.Len()
+	}
+
+	if idx < 0 || idx >= arr.Len() {
+		return nil
+	}
+
+	return must.NotFail(arr.Get(idx))
+}
+
+// check interfaces
+var (
+	_ Operator = (*arrayElemAt)(nil)
+)
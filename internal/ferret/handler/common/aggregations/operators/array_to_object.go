@@ -0,0 +1,212 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// arrayToObject represents `$arrayToObject` operator.
+//
+//	{ $arrayToObject: <array expression> }
+//
+// The array contains either two-element arrays `[ <key>, <value> ]`
+// or documents `{ k: <key>, v: <value> }`.
+type arrayToObject struct {
+	arg any
+}
+
+// newArrayToObject returns `$arrayToObject` operator.
+func newArrayToObject(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$arrayToObject", 1, len(args))
+	}
+
+	return &arrayToObject{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// If the same key is set more than once, the last value is used.
+func (a *arrayToObject) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(a.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectBadType,
+			fmt.Sprintf("$arrayToObject requires an array input, found: %s", handlerparams.AliasFromType(v)),
+			"$arrayToObject",
+		)
+	}
+
+	res := types.MakeDocument(arr.Len())
+
+	if arr.Len() == 0 {
+		return res, nil
+	}
+
+	var pairs bool
+
+	switch first := must.NotFail(arr.Get(0)).(type) {
+	case *types.Array:
+		pairs = true
+	case *types.Document:
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectElementBadType,
+			fmt.Sprintf(
+				"Unrecognised input type format for $arrayToObject: %s",
+				handlerparams.AliasFromType(first),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	for i := 0; i < arr.Len(); i++ {
+		elem := must.NotFail(arr.Get(i))
+
+		var k string
+		var v any
+
+		if pairs {
+			k, v, err = getKeyValuePair(elem)
+		} else {
+			k, v, err = getKeyValueDocument(elem)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Set(k, v)
+	}
+
+	return res, nil
+}
+
+// getKeyValuePair returns the key and the value of `[ <key>, <value> ]` element of `$arrayToObject`.
+func getKeyValuePair(elem any) (string, any, error) {
+	pair, ok := elem.(*types.Array)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectInconsistent,
+			fmt.Sprintf(
+				"$arrayToObject requires a consistent input format. Elements must all be arrays or all be objects. "+
+					"Array was detected, now found: %s",
+				handlerparams.AliasFromType(elem),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if pair.Len() != 2 {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectPairSize,
+			fmt.Sprintf("$arrayToObject requires an array of size 2 arrays,found array of size: %d", pair.Len()),
+			"$arrayToObject",
+		)
+	}
+
+	k := must.NotFail(pair.Get(0))
+
+	key, ok := k.(string)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectPairKeyBadType,
+			fmt.Sprintf(
+				"$arrayToObject requires an array of key-value pairs, where the key must be of type string. Found key type: %s",
+				handlerparams.AliasFromType(k),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	return key, must.NotFail(pair.Get(1)), nil
+}
+
+// getKeyValueDocument returns the key and the value of `{ k: <key>, v: <value> }` element of `$arrayToObject`.
+func getKeyValueDocument(elem any) (string, any, error) {
+	kv, ok := elem.(*types.Document)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectInconsistent,
+			fmt.Sprintf(
+				"$arrayToObject requires a consistent input format. Elements must all be arrays or all be objects. "+
+					"Object was detected, now found: %s",
+				handlerparams.AliasFromType(elem),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if kv.Len() != 2 {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectKeyCount,
+			fmt.Sprintf(
+				"$arrayToObject requires an object keys of 'k' and 'v'. Found incorrect number of keys:%d",
+				kv.Len(),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if !kv.Has("k") || !kv.Has("v") {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectMissingKey,
+			fmt.Sprintf(
+				"$arrayToObject requires an object with keys 'k' and 'v'. Missing either or both keys from: %s",
+				types.FormatAnyValue(kv),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	k := must.NotFail(kv.Get("k"))
+
+	key, ok := k.(string)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectKeyBadType,
+			fmt.Sprintf(
+				"$arrayToObject requires an object with keys 'k' and 'v', where the value of 'k' must be of type string. "+
+					"Found type: %s",
+				handlerparams.AliasFromType(k),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	return key, must.NotFail(kv.Get("v")), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*arrayToObject)(nil)
+)
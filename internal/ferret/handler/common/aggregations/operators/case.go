@@ -0,0 +1,125 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// changeCase represents `$toLower` and `$toUpper` operators.
+//
+//	{ $toLower: <expression> }
+//	{ $toUpper: <expression> }
+//
+// Like in MongoDB, only ASCII letters are converted.
+type changeCase struct {
+	name string
+	f    func(string) string
+	arg  any
+}
+
+// newToLower returns `$toLower` operator.
+func newToLower(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$toLower", 1, len(args))
+	}
+
+	return &changeCase{
+		name: "$toLower",
+		f:    asciiToLower,
+		arg:  args[0],
+	}, nil
+}
+
+// newToUpper returns `$toUpper` operator.
+func newToUpper(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$toUpper", 1, len(args))
+	}
+
+	return &changeCase{
+		name: "$toUpper",
+		f:    asciiToUpper,
+		arg:  args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Null or missing argument is converted to the empty string.
+func (c *changeCase) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(c.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := coerceToString(c.name, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.f(s), nil
+}
+
+// strcasecmp represents `$strcasecmp` operator.
+//
+//	{ $strcasecmp: [ <expression1>, <expression2> ] }
+type strcasecmp struct {
+	a any
+	b any
+}
+
+// newStrcasecmp returns `$strcasecmp` operator.
+func newStrcasecmp(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$strcasecmp", 2, len(args))
+	}
+
+	return &strcasecmp{
+		a: args[0],
+		b: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns 1 if the first string is greater than the second one, -1 if it is less,
+// and 0 if strings are equal, ignoring the case of ASCII letters.
+func (s *strcasecmp) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.a, s.b}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := coerceToString("$strcasecmp", values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := coerceToString("$strcasecmp", values[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return int32(strings.Compare(asciiToLower(a), asciiToLower(b))), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*changeCase)(nil)
+	_ Operator = (*strcasecmp)(nil)
+)
@@ -0,0 +1,112 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// compareFunc returns the result of the comparison operator for the compare result of its arguments.
+type compareFunc func(res types.CompareResult) any
+
+// compareOp represents comparison operators: `$eq`, `$ne`, `$gt`, `$gte`, `$lt`, `$lte` and `$cmp`.
+//
+//	{ $eq: [ <expression1>, <expression2> ] }
+type compareOp struct {
+	args [2]any
+	f    compareFunc
+}
+
+// newCompare returns a constructor of the comparison operator with the given name.
+func newCompare(name string, f compareFunc) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 2 {
+			return nil, newArgsLenError(name, 2, len(args))
+		}
+
+		return &compareOp{
+			args: [2]any{args[0], args[1]},
+			f:    f,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// Values are compared with BSON comparison order and strings according to the collation of vars;
+// a missing value is less than any other value, including null.
+func (c *compareOp) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(c.args[:], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.f(compareValues(values[0], values[1], vars.Collation())), nil
+}
+
+// compareValues compares evaluated values according to the given collation,
+// missing values are less than any other value.
+func compareValues(a, b any, coll *types.Collation) types.CompareResult {
+	switch {
+	case a == nil && b == nil:
+		return types.Equal
+	case a == nil:
+		return types.Less
+	case b == nil:
+		return types.Greater
+	default:
+		return coll.CompareForAggregation(a, b)
+	}
+}
+
+// eqResult returns the result of `$eq`.
+func eqResult(res types.CompareResult) any {
+	return res == types.Equal
+}
+
+// neResult returns the result of `$ne`.
+func neResult(res types.CompareResult) any {
+	return res != types.Equal
+}
+
+// gtResult returns the result of `$gt`.
+func gtResult(res types.CompareResult) any {
+	return res == types.Greater
+}
+
+// gteResult returns the result of `$gte`.
+func gteResult(res types.CompareResult) any {
+	return res != types.Less
+}
+
+// ltResult returns the result of `$lt`.
+func ltResult(res types.CompareResult) any {
+	return res == types.Less
+}
+
+// lteResult returns the result of `$lte`.
+func lteResult(res types.CompareResult) any {
+	return res != types.Greater
+}
+
+// cmpResult returns the result of `$cmp`: -1, 0 or 1.
+func cmpResult(res types.CompareResult) any {
+	return int32(res)
+}
+
+// check interfaces
+var (
+	_ Operator = (*compareOp)(nil)
+)
@@ -0,0 +1,72 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// concat represents `$concat` operator.
+//
+//	{ $concat: [ <expression1>, <expression2>, ... ] }
+type concat struct {
+	args []any
+}
+
+// newConcat returns `$concat` operator.
+func newConcat(args ...any) (Operator, error) {
+	return &concat{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (c *concat) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(c.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var res strings.Builder
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			res.WriteString(v)
+		case nil, types.NullType:
+			return types.Null, nil
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConcatBadType,
+				fmt.Sprintf("$concat only supports strings, not %s", handlerparams.AliasFromType(v)),
+				"$concat",
+			)
+		}
+	}
+
+	return res.String(), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*concat)(nil)
+)
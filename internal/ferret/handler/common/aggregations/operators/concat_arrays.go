@@ -0,0 +1,90 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// concatArrays represents `$concatArrays` operator.
+//
+//	{ $concatArrays: [ <array1>, <array2>, ... ] }
+type concatArrays struct {
+	args []any
+}
+
+// newConcatArrays returns `$concatArrays` operator.
+func newConcatArrays(args ...any) (Operator, error) {
+	return &concatArrays{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any of the arguments is null or missing.
+func (c *concatArrays) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(c.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	res := types.MakeArray(0)
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConcatArraysBadType,
+				fmt.Sprintf("$concatArrays only supports arrays, not %s", handlerparams.AliasFromType(v)),
+				"$concatArrays",
+			)
+		}
+
+		iter := arr.Iterator()
+		defer iter.Close()
+
+		for {
+			_, elem, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			res.Append(elem)
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*concatArrays)(nil)
+)
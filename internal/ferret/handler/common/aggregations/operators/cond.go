@@ -0,0 +1,111 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// cond represents `$cond` operator.
+//
+//	{ $cond: { if: <boolean-expression>, then: <true-case>, else: <false-case> } }
+//	{ $cond: [ <boolean-expression>, <true-case>, <false-case> ] }
+type cond struct {
+	ifExpr   any
+	thenExpr any
+	elseExpr any
+}
+
+// newCond returns `$cond` operator.
+func newCond(args ...any) (Operator, error) {
+	if len(args) == 1 {
+		if doc, ok := args[0].(*types.Document); ok && !IsOperator(doc) {
+			return newCondFromDocument(doc)
+		}
+	}
+
+	if len(args) != 3 {
+		return nil, newArgsLenError("$cond", 3, len(args))
+	}
+
+	return &cond{
+		ifExpr:   args[0],
+		thenExpr: args[1],
+		elseExpr: args[2],
+	}, nil
+}
+
+// newCondFromDocument returns `$cond` operator from its document form.
+func newCondFromDocument(doc *types.Document) (Operator, error) {
+	namedArgs, unknown, err := getNamedArgs(doc, "if", "then", "else")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCondUnknownField,
+			fmt.Sprintf("Unrecognized parameter to $cond: %s", unknown),
+			"$cond",
+		)
+	}
+
+	for _, field := range []struct {
+		name string
+		code handlererrors.ErrorCode
+	}{
+		{"if", handlererrors.ErrCondMissingIf},
+		{"then", handlererrors.ErrCondMissingThen},
+		{"else", handlererrors.ErrCondMissingElse},
+	} {
+		if _, ok := namedArgs[field.name]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				field.code,
+				fmt.Sprintf("Missing '%s' parameter to $cond", field.name),
+				"$cond",
+			)
+		}
+	}
+
+	return &cond{
+		ifExpr:   namedArgs["if"],
+		thenExpr: namedArgs["then"],
+		elseExpr: namedArgs["else"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Only the selected case is evaluated.
+func (c *cond) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(c.ifExpr, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTrue(v) {
+		return evaluate(c.thenExpr, doc, vars)
+	}
+
+	return evaluate(c.elseExpr, doc, vars)
+}
+
+// check interfaces
+var (
+	_ Operator = (*cond)(nil)
+)
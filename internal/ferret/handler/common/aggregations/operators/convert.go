@@ -0,0 +1,610 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// bsonTypeNames maps BSON type codes to type names accepted by `to` argument of `$convert`.
+var bsonTypeNames = map[int64]string{
+	-1:  "minKey",
+	1:   "double",
+	2:   "string",
+	3:   "object",
+	4:   "array",
+	5:   "binData",
+	6:   "undefined",
+	7:   "objectId",
+	8:   "bool",
+	9:   "date",
+	10:  "null",
+	11:  "regex",
+	12:  "dbPointer",
+	13:  "javascript",
+	14:  "symbol",
+	15:  "javascriptWithScope",
+	16:  "int",
+	17:  "timestamp",
+	18:  "long",
+	19:  "decimal",
+	127: "maxKey",
+}
+
+// convert represents `$convert` operator and its shorthands like `$toInt`.
+//
+//	{ $convert: { input: <expression>, to: <type expression>, onError: <expression>, onNull: <expression> } }
+//	{ $toInt: <expression> }
+type convert struct {
+	name    string
+	input   any
+	to      any
+	onError any
+	onNull  any
+
+	hasOnError bool
+	hasOnNull  bool
+}
+
+// newConvert returns `$convert` operator.
+func newConvert(args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"$convert expects an object of named arguments but found: %s",
+				handlerparams.AliasFromType(found),
+			),
+			"$convert",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "input", "to", "onError", "onNull")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$convert found an unknown argument: %s", unknown),
+			"$convert",
+		)
+	}
+
+	if err = requireArgs("$convert", namedArgs, handlererrors.ErrFailedToParse, "input", "to"); err != nil {
+		return nil, err
+	}
+
+	c := &convert{
+		name:  "$convert",
+		input: namedArgs["input"],
+		to:    namedArgs["to"],
+	}
+
+	c.onError, c.hasOnError = namedArgs["onError"]
+	c.onNull, c.hasOnNull = namedArgs["onNull"]
+
+	return c, nil
+}
+
+// newConvertTo returns a shorthand of `$convert` operator, like `$toInt`,
+// that converts its only argument to the given type and fails on errors.
+func newConvertTo(name, to string) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newArgsLenError(name, 1, len(args))
+		}
+
+		return &convert{
+			name:  name,
+			input: args[0],
+			to:    to,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+func (c *convert) Process(doc *types.Document, vars *Variables) (any, error) {
+	toValue, err := evaluate(c.to, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := evaluate(c.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var to string
+	if !isNullish(toValue) {
+		if to, err = c.targetType(toValue); err != nil {
+			return nil, err
+		}
+	}
+
+	if isNullish(input) {
+		if c.hasOnNull {
+			return evaluate(c.onNull, doc, vars)
+		}
+
+		return types.Null, nil
+	}
+
+	if to == "" {
+		return types.Null, nil
+	}
+
+	res, err := convertValue(input, to)
+	if err == nil {
+		return res, nil
+	}
+
+	var convErr *conversionError
+	var cmdErr *handlererrors.CommandError
+
+	switch {
+	case errors.As(err, &convErr):
+	case errors.As(err, &cmdErr) && cmdErr.Code() == handlererrors.ErrConversionFailure:
+		// date string parsing error
+	default:
+		return nil, err
+	}
+
+	if c.hasOnError {
+		return evaluate(c.onError, doc, vars)
+	}
+
+	if convErr == nil {
+		return nil, err
+	}
+
+	msg := convErr.msg + " in $convert with no onError value"
+	if convErr.detail != "" {
+		msg += ": " + convErr.detail
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrConversionFailure, msg, c.name)
+}
+
+// targetType returns the type name for the evaluated `to` argument.
+func (c *convert) targetType(to any) (string, error) {
+	switch to := to.(type) {
+	case string:
+		for _, name := range bsonTypeNames {
+			if name == to {
+				return name, nil
+			}
+		}
+
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Unknown type name: %s", to),
+			c.name,
+		)
+
+	case float64, int32, int64:
+		var code int64
+		whole := true
+
+		switch to := to.(type) {
+		case float64:
+			whole = to == math.Trunc(to) && !math.IsInf(to, 0)
+			code = int64(to)
+		default:
+			code = toInt64(to)
+		}
+
+		if !whole {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"In $convert, numeric 'to' argument is not an integer",
+				c.name,
+			)
+		}
+
+		name, ok := bsonTypeNames[code]
+		if !ok {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("In $convert, numeric value for 'to' does not correspond to a BSON type: %d", code),
+				c.name,
+			)
+		}
+
+		return name, nil
+
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"$convert's 'to' argument must be a string or number, but is %s",
+				handlerparams.AliasFromType(to),
+			),
+			c.name,
+		)
+	}
+}
+
+// convertValue converts non-null value to the type with the given name
+// using MongoDB conversion tables.
+//
+// Values that can't be converted are reported with *conversionError.
+func convertValue(v any, to string) (any, error) {
+	switch to {
+	case "double":
+		return convertToDouble(v)
+	case "string":
+		return convertToString(v)
+	case "objectId":
+		return convertToObjectID(v)
+	case "bool":
+		return convertToBool(v), nil
+	case "date":
+		return convertToDate(v)
+	case "int":
+		n, err := convertToInteger(v, math.MinInt32, math.MaxInt32, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return int32(n), nil
+	case "long":
+		if t, ok := v.(time.Time); ok {
+			return t.UnixMilli(), nil
+		}
+
+		return convertToInteger(v, math.MinInt64, math.MaxInt64, 64)
+	case "decimal":
+		return convertToDecimal(v)
+	default:
+		return nil, newUnsupportedConversionError(v, to)
+	}
+}
+
+// convertToDouble converts the value to double.
+func convertToDouble(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+
+		return float64(0), nil
+	case types.Decimal128:
+		return v.Float64(), nil
+	case time.Time:
+		return float64(v.UnixMilli()), nil
+	case string:
+		if v == "" {
+			return nil, newParseNumberError(v, "No digits")
+		}
+
+		// hexadecimal floats are accepted by ParseFloat but not by MongoDB
+		if strings.ContainsAny(v, "xX_") {
+			return nil, newParseNumberError(v, "Did not consume whole string.")
+		}
+
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, newParseNumberError(v, "Out of range")
+			}
+
+			return nil, newParseNumberError(v, "Did not consume whole string.")
+		}
+
+		return f, nil
+	default:
+		return nil, newUnsupportedConversionError(v, "double")
+	}
+}
+
+// convertToInteger converts the value to int64 in the given range, bits is the size of the target type.
+func convertToInteger(v any, min, max int64, bits int) (int64, error) {
+	target := "int"
+	if bits == 64 {
+		target = "long"
+	}
+
+	switch v := v.(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		if v < min || v > max {
+			return 0, newOverflowError(v)
+		}
+
+		return v, nil
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return 0, &conversionError{msg: "Attempt to convert NaN value to integer type", detail: "nan"}
+		case math.IsInf(v, 0):
+			detail := "inf"
+			if v < 0 {
+				detail = "-inf"
+			}
+
+			return 0, &conversionError{msg: "Attempt to convert infinity value to integer type", detail: detail}
+		}
+
+		t := math.Trunc(v)
+
+		// float64(math.MaxInt64) is rounded up to 2^63
+		if t < float64(min) || t > float64(max) || (bits == 64 && t >= math.Exp2(63)) {
+			return 0, newOverflowError(v)
+		}
+
+		return int64(t), nil
+	case types.Decimal128:
+		switch {
+		case v.IsNaN():
+			return 0, &conversionError{msg: "Attempt to convert NaN value to integer type", detail: "NaN"}
+		case v.IsInf() != 0:
+			detail := "Infinity"
+			if v.Signbit() {
+				detail = "-Infinity"
+			}
+
+			return 0, &conversionError{msg: "Attempt to convert infinity value to integer type", detail: detail}
+		}
+
+		r, _ := v.Rat()
+		t := new(big.Int).Quo(r.Num(), r.Denom())
+
+		if !t.IsInt64() || t.Int64() < min || t.Int64() > max {
+			return 0, newOverflowError(v)
+		}
+
+		return t.Int64(), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+
+		return 0, nil
+	case string:
+		return parseInteger(v, bits)
+	default:
+		return 0, newUnsupportedConversionError(v, target)
+	}
+}
+
+// parseInteger parses base 10 integer string of the given size.
+func parseInteger(s string, bits int) (int64, error) {
+	if s == "" {
+		return 0, newParseNumberError(s, "No digits")
+	}
+
+	for i, r := range s {
+		if (r < '0' || r > '9') && !(i == 0 && r == '-') {
+			return 0, newParseNumberError(s, fmt.Sprintf("Bad digit \"%c\" while parsing %s", r, s))
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, bits)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, newParseNumberError(s, "Overflow")
+		}
+
+		return 0, newParseNumberError(s, "No digits")
+	}
+
+	return n, nil
+}
+
+// convertToString converts the value to string.
+func convertToString(v any) (any, error) {
+	switch v := v.(type) {
+	case string, int32, int64, float64, types.Decimal128, time.Time:
+		return coerceToString("$convert", v)
+	case bool:
+		return strconv.FormatBool(v), nil
+	case types.ObjectID:
+		return hex.EncodeToString(v[:]), nil
+	default:
+		return nil, newUnsupportedConversionError(v, "string")
+	}
+}
+
+// convertToObjectID converts the value to ObjectId.
+func convertToObjectID(v any) (any, error) {
+	switch v := v.(type) {
+	case types.ObjectID:
+		return v, nil
+	case string:
+		var id types.ObjectID
+
+		if len(v) != 2*len(id) {
+			return nil, newParseObjectIDError(
+				v,
+				fmt.Sprintf("Invalid string length for parsing to OID, expected %d but found %d", 2*len(id), len(v)),
+			)
+		}
+
+		if _, err := hex.Decode(id[:], []byte(v)); err != nil {
+			return nil, newParseObjectIDError(v, "Invalid character found in hex string: "+v)
+		}
+
+		return id, nil
+	default:
+		return nil, newUnsupportedConversionError(v, "objectId")
+	}
+}
+
+// convertToBool converts the value to bool: numbers are true if they are not zero,
+// all other values are true.
+func convertToBool(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	case types.Decimal128:
+		return !v.IsZero()
+	default:
+		return true
+	}
+}
+
+// convertToDecimal converts the value to decimal.
+// Doubles are converted with 15 significant digits.
+func convertToDecimal(v any) (any, error) {
+	switch v := v.(type) {
+	case types.Decimal128:
+		return v, nil
+	case float64:
+		return types.NewDecimal128FromFloat64(v), nil
+	case int32:
+		return types.NewDecimal128FromInt64(int64(v)), nil
+	case int64:
+		return types.NewDecimal128FromInt64(v), nil
+	case bool:
+		if v {
+			return types.NewDecimal128FromInt64(1), nil
+		}
+
+		return types.NewDecimal128FromInt64(0), nil
+	case time.Time:
+		return types.NewDecimal128FromInt64(v.UnixMilli()), nil
+	case string:
+		if v == "" {
+			return nil, newParseNumberError(v, "No digits")
+		}
+
+		d, err := types.ParseDecimal128(v)
+		if err != nil {
+			return nil, newParseNumberError(v, "Failed to parse string to decimal")
+		}
+
+		return d, nil
+	default:
+		return nil, newUnsupportedConversionError(v, "decimal")
+	}
+}
+
+// convertToDate converts the value to date.
+// Numbers are milliseconds since the Unix epoch.
+func convertToDate(v any) (any, error) {
+	switch v := v.(type) {
+	case time.Time, types.Timestamp, types.ObjectID:
+		t, _ := toDate(v)
+		return t, nil
+	case int64:
+		return time.UnixMilli(v).UTC(), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < math.MinInt64 || v >= math.Exp2(63) {
+			return nil, newOverflowError(v)
+		}
+
+		return time.UnixMilli(int64(v)).UTC(), nil
+	case string:
+		t, err := parseDate(v, time.UTC, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.UTC(), nil
+	default:
+		return nil, newUnsupportedConversionError(v, "date")
+	}
+}
+
+// conversionError represents a value that can't be converted by `$convert`.
+//
+// Its message is formatted as `<msg> in $convert with no onError value: <detail>`.
+type conversionError struct {
+	msg    string
+	detail string
+}
+
+// Error implements error interface.
+func (e *conversionError) Error() string {
+	if e.detail == "" {
+		return e.msg
+	}
+
+	return e.msg + ": " + e.detail
+}
+
+// newUnsupportedConversionError returns conversion error for the value that can't be converted to the type.
+func newUnsupportedConversionError(v any, to string) error {
+	return &conversionError{
+		msg: fmt.Sprintf("Unsupported conversion from %s to %s", handlerparams.AliasFromType(v), to),
+	}
+}
+
+// newParseNumberError returns conversion error for the string that can't be parsed as a number.
+func newParseNumberError(s, reason string) error {
+	return &conversionError{
+		msg:    fmt.Sprintf("Failed to parse number '%s'", s),
+		detail: reason,
+	}
+}
+
+// newParseObjectIDError returns conversion error for the string that can't be parsed as ObjectId.
+func newParseObjectIDError(s, reason string) error {
+	return &conversionError{
+		msg:    fmt.Sprintf("Failed to parse objectId '%s'", s),
+		detail: reason,
+	}
+}
+
+// newOverflowError returns conversion error for the number out of the target type range.
+func newOverflowError(v any) error {
+	return &conversionError{
+		msg:    "Conversion would overflow target type",
+		detail: types.FormatAnyValue(v),
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*convert)(nil)
+	_ error    = (*conversionError)(nil)
+)
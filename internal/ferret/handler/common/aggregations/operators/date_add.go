@@ -0,0 +1,208 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// dateAdd represents `$dateAdd` and `$dateSubtract` operators.
+//
+//	{ $dateAdd: { startDate: <expression>, unit: <expression>, amount: <expression>, timezone: <tz expression> } }
+type dateAdd struct {
+	name      string
+	namedArgs map[string]any
+	subtract  bool
+}
+
+// newDateAdd returns `$dateAdd` operator.
+func newDateAdd(args ...any) (Operator, error) {
+	return newDateAddOperator("$dateAdd", false, args...)
+}
+
+// newDateSubtract returns `$dateSubtract` operator.
+func newDateSubtract(args ...any) (Operator, error) {
+	return newDateAddOperator("$dateSubtract", true, args...)
+}
+
+// newDateAddOperator validates named arguments and returns one of date add operators.
+func newDateAddOperator(name string, subtract bool, args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		name, args,
+		handlererrors.ErrDateAddNotObject, handlererrors.ErrDateAddUnknownField,
+		"startDate", "unit", "amount", "timezone",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range []string{"startDate", "unit", "amount"} {
+		if _, ok := namedArgs[k]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateAddMissingField,
+				fmt.Sprintf("%s requires startDate, unit, and amount to be present", name),
+				name,
+			)
+		}
+	}
+
+	return &dateAdd{
+		name:      name,
+		namedArgs: namedArgs,
+		subtract:  subtract,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateAdd) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, isNull, err := evaluateNamedArgs(d.namedArgs, doc, vars, "startDate", "unit", "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	loc, tzNull, err := evaluateTimezone(d.name, d.namedArgs["timezone"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNull || tzNull {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(values["startDate"])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateAddDateBadType,
+			fmt.Sprintf("%s requires startDate to be convertible to a date", d.name),
+			d.name,
+		)
+	}
+
+	unit, err := parseTimeUnit(d.name, values["unit"])
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := handlerparams.GetWholeNumberParam(values["amount"])
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateAddAmountBadType,
+			fmt.Sprintf("%s expects integer amount of time units", d.name),
+			d.name,
+		)
+	}
+
+	if d.subtract {
+		if amount == math.MinInt64 {
+			return nil, d.overflowError()
+		}
+
+		amount = -amount
+	}
+
+	res, ok := addToDate(t, unit, amount, loc)
+	if !ok {
+		return nil, d.overflowError()
+	}
+
+	return res, nil
+}
+
+// overflowError returns an error for the result that can't be represented as a date.
+func (d *dateAdd) overflowError() error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrDateAddOverflow,
+		fmt.Sprintf("%s overflowed", d.name),
+		d.name,
+	)
+}
+
+// addToDate adds the amount of time units to t in the given time zone.
+//
+// Days, weeks and larger units are added to the wall clock time, so the result keeps the time of day
+// across daylight saving time changes. If adding months results in a day that does not exist,
+// the last day of the month is used instead.
+// It returns false if the result overflows.
+func addToDate(t time.Time, unit timeUnit, amount int64, loc *time.Location) (time.Time, bool) {
+	// the limit is far beyond the dates representable by milliseconds since epoch,
+	// but keeps calculations below from overflowing
+	const maxDays = math.MaxInt64 / int64(24*time.Hour/time.Millisecond)
+
+	local := t.In(loc)
+	year, month, day := local.Date()
+	hour, minute, sec := local.Clock()
+
+	switch unit {
+	case timeUnitYear, timeUnitQuarter, timeUnitMonth:
+		months := int64(unit.months())
+		if amount > maxDays/months/31 || amount < -maxDays/months/31 {
+			return time.Time{}, false
+		}
+
+		first := time.Date(year, month+time.Month(amount*months), 1, 0, 0, 0, 0, time.UTC)
+		lastDay := first.AddDate(0, 1, -1).Day()
+
+		if day > lastDay {
+			day = lastDay
+		}
+
+		return time.Date(first.Year(), first.Month(), day, hour, minute, sec, local.Nanosecond(), loc).UTC(), true
+
+	case timeUnitWeek, timeUnitDay:
+		days := amount
+		if unit == timeUnitWeek {
+			if amount > maxDays/7 || amount < -maxDays/7 {
+				return time.Time{}, false
+			}
+
+			days *= 7
+		}
+
+		if days > maxDays || days < -maxDays {
+			return time.Time{}, false
+		}
+
+		return time.Date(year, month, day+int(days), hour, minute, sec, local.Nanosecond(), loc).UTC(), true
+
+	case timeUnitHour, timeUnitMinute, timeUnitSecond, timeUnitMillisecond:
+		ms := int64(unit.duration() / time.Millisecond)
+		if amount > math.MaxInt64/ms || amount < math.MinInt64/ms {
+			return time.Time{}, false
+		}
+
+		delta := amount * ms
+		start := t.UnixMilli()
+
+		if (delta > 0 && start > math.MaxInt64-delta) || (delta < 0 && start < math.MinInt64-delta) {
+			return time.Time{}, false
+		}
+
+		return time.UnixMilli(start + delta).UTC(), true
+
+	default:
+		panic(fmt.Sprintf("unexpected time unit %q", unit))
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateAdd)(nil)
+)
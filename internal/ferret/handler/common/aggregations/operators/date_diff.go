@@ -0,0 +1,162 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// millisecondsPerDay is the number of milliseconds in a day without daylight saving time changes.
+const millisecondsPerDay = int64(24 * time.Hour / time.Millisecond)
+
+// dateDiff represents `$dateDiff` operator.
+//
+//	{ $dateDiff: {
+//	    startDate: <expression>, endDate: <expression>, unit: <expression>,
+//	    timezone: <tz expression>, startOfWeek: <string>
+//	} }
+type dateDiff struct {
+	namedArgs map[string]any
+}
+
+// newDateDiff returns `$dateDiff` operator.
+func newDateDiff(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$dateDiff", args,
+		handlererrors.ErrDateDiffNotObject, handlererrors.ErrDateDiffUnknownField,
+		"startDate", "endDate", "unit", "timezone", "startOfWeek",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$dateDiff", namedArgs, handlererrors.ErrDateDiffMissingField, "startDate", "endDate", "unit"); err != nil {
+		return nil, err
+	}
+
+	return &dateDiff{
+		namedArgs: namedArgs,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateDiff) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, isNull, err := evaluateNamedArgs(d.namedArgs, doc, vars, "startDate", "endDate", "unit", "startOfWeek")
+	if err != nil {
+		return nil, err
+	}
+
+	loc, tzNull, err := evaluateTimezone("$dateDiff", d.namedArgs["timezone"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNull || tzNull {
+		return types.Null, nil
+	}
+
+	var dates [2]time.Time
+
+	for i, k := range []string{"startDate", "endDate"} {
+		var ok bool
+		if dates[i], ok = toDate(values[k]); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateDiffDateBadType,
+				fmt.Sprintf("$dateDiff requires '%s' to be a date, but got %s", k, handlerparams.AliasFromType(values[k])),
+				"$dateDiff",
+			)
+		}
+	}
+
+	unit, err := parseTimeUnit("$dateDiff", values["unit"])
+	if err != nil {
+		return nil, err
+	}
+
+	startOfWeek := time.Sunday
+
+	if v, ok := values["startOfWeek"]; ok && unit == timeUnitWeek {
+		if startOfWeek, err = parseStartOfWeek("$dateDiff", v); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffDates(dates[0], dates[1], unit, loc, startOfWeek), nil
+}
+
+// diffDates returns the number of unit boundaries crossed between start and end in the given time zone.
+//
+// Days, weeks and larger units are counted using the wall clock time.
+// Hours and smaller units are counted using the elapsed time,
+// aligning hours to the time zone offset that is not a whole number of hours.
+func diffDates(start, end time.Time, unit timeUnit, loc *time.Location, startOfWeek time.Weekday) int64 {
+	switch unit {
+	case timeUnitYear, timeUnitQuarter, timeUnitMonth:
+		months := func(t time.Time) int64 {
+			w := wallClock(t, loc)
+			return floorDiv(int64(w.Year())*12+int64(w.Month())-1, int64(unit.months()))
+		}
+
+		return months(end) - months(start)
+
+	case timeUnitWeek, timeUnitDay:
+		days := func(t time.Time) int64 {
+			d := floorDiv(wallClock(t, loc).UnixMilli(), millisecondsPerDay)
+
+			if unit == timeUnitWeek {
+				// 1970-01-01 was Thursday
+				sinceStartOfWeek := (d + int64(time.Thursday) - int64(startOfWeek)) % 7
+				if sinceStartOfWeek < 0 {
+					sinceStartOfWeek += 7
+				}
+
+				d -= sinceStartOfWeek
+			}
+
+			return d
+		}
+
+		return (days(end) - days(start)) / (int64(unit.duration()) / int64(24*time.Hour))
+
+	case timeUnitHour, timeUnitMinute, timeUnitSecond, timeUnitMillisecond:
+		ms := int64(unit.duration() / time.Millisecond)
+
+		elapsed := func(t time.Time) int64 {
+			res := t.UnixMilli()
+
+			if unit == timeUnitHour {
+				_, offset := t.In(loc).Zone()
+				res += int64(offset%3600) * 1000
+			}
+
+			return floorDiv(res, ms)
+		}
+
+		return elapsed(end) - elapsed(start)
+
+	default:
+		panic(fmt.Sprintf("unexpected time unit %q", unit))
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateDiff)(nil)
+)
@@ -0,0 +1,215 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// dateFromParts represents `$dateFromParts` operator.
+//
+//	{ $dateFromParts: {
+//	    year: <year>, month: <month>, day: <day>,
+//	    hour: <hour>, minute: <minute>, second: <second>, millisecond: <ms>,
+//	    timezone: <tz expression>
+//	} }
+//
+// Instead of year, month and day ISO 8601 isoWeekYear, isoWeek and isoDayOfWeek could be used.
+type dateFromParts struct {
+	parts    map[string]any
+	timezone any
+	iso      bool
+}
+
+// dateFromPartsDefaults contains default values of all date parts.
+var dateFromPartsDefaults = map[string]int64{
+	"year":         1970,
+	"month":        1,
+	"day":          1,
+	"isoWeekYear":  1970,
+	"isoWeek":      1,
+	"isoDayOfWeek": 1,
+	"hour":         0,
+	"minute":       0,
+	"second":       0,
+	"millisecond":  0,
+}
+
+// newDateFromParts returns `$dateFromParts` operator.
+func newDateFromParts(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$dateFromParts", args,
+		handlererrors.ErrDateFromPartsNotObject, handlererrors.ErrDateFromPartsUnknownField,
+		"year", "month", "day", "isoWeekYear", "isoWeek", "isoDayOfWeek",
+		"hour", "minute", "second", "millisecond", "timezone",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var natural, iso bool
+
+	for _, k := range []string{"year", "month", "day"} {
+		if _, ok := namedArgs[k]; ok {
+			natural = true
+		}
+	}
+
+	for _, k := range []string{"isoWeekYear", "isoWeek", "isoDayOfWeek"} {
+		if _, ok := namedArgs[k]; ok {
+			iso = true
+		}
+	}
+
+	if natural && iso {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromPartsMixed,
+			"$dateFromParts does not allow mixing natural dates with ISO dates",
+			"$dateFromParts",
+		)
+	}
+
+	_, hasYear := namedArgs["year"]
+	_, hasISOWeekYear := namedArgs["isoWeekYear"]
+
+	if !hasYear && !hasISOWeekYear {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromPartsMissingYear,
+			"$dateFromParts requires either 'year' or 'isoWeekYear' to be present",
+			"$dateFromParts",
+		)
+	}
+
+	timezone := namedArgs["timezone"]
+	delete(namedArgs, "timezone")
+
+	return &dateFromParts{
+		parts:    namedArgs,
+		timezone: timezone,
+		iso:      iso,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateFromParts) Process(doc *types.Document, vars *Variables) (any, error) {
+	parts := make(map[string]int64, len(dateFromPartsDefaults))
+	for k, v := range dateFromPartsDefaults {
+		parts[k] = v
+	}
+
+	var isNull bool
+
+	// sorted for deterministic errors
+	for _, k := range []string{
+		"year", "month", "day", "isoWeekYear", "isoWeek", "isoDayOfWeek",
+		"hour", "minute", "second", "millisecond",
+	} {
+		expr, ok := d.parts[k]
+		if !ok {
+			continue
+		}
+
+		v, err := evaluate(expr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			isNull = true
+			continue
+		}
+
+		if parts[k], err = getDatePart(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	loc, tzNull, err := evaluateTimezone("$dateFromParts", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNull || tzNull {
+		return types.Null, nil
+	}
+
+	year, month, day := int(parts["year"]), time.Month(parts["month"]), int(parts["day"])
+
+	if d.iso {
+		// ISO week 1 is the week with January 4th in it
+		jan4 := time.Date(int(parts["isoWeekYear"]), time.January, 4, 0, 0, 0, 0, time.UTC)
+		days := (parts["isoWeek"]-1)*7 + parts["isoDayOfWeek"] - int64(isoDayOfWeek(jan4))
+		date := jan4.AddDate(0, 0, int(days))
+
+		year, month, day = date.Date()
+	}
+
+	t := time.Date(
+		year, month, day,
+		int(parts["hour"]), int(parts["minute"]), int(parts["second"]),
+		int(parts["millisecond"])*int(time.Millisecond),
+		loc,
+	)
+
+	return t.UTC(), nil
+}
+
+// getDatePart validates the evaluated value of `$dateFromParts` part.
+func getDatePart(name string, v any) (int64, error) {
+	i, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromPartsNotInteger,
+			fmt.Sprintf(
+				"'%s' must evaluate to an integer, found %s with value %s",
+				name, handlerparams.AliasFromType(v), types.FormatAnyValue(v),
+			),
+			"$dateFromParts",
+		)
+	}
+
+	switch name {
+	case "year", "isoWeekYear":
+		if i < 1 || i > 9999 {
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromPartsYearRange,
+				fmt.Sprintf("'%s' must evaluate to an integer in the range 1 to 9999, found %d", name, i),
+				"$dateFromParts",
+			)
+		}
+
+	default:
+		if i < math.MinInt16 || i > math.MaxInt16 {
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromPartsValueRange,
+				fmt.Sprintf("'%s' must evaluate to a value in the range [-32768, 32767]; value %d is not in range", name, i),
+				"$dateFromParts",
+			)
+		}
+	}
+
+	return i, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateFromParts)(nil)
+)
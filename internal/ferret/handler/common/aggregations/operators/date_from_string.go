@@ -0,0 +1,450 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// dateFromString represents `$dateFromString` operator.
+//
+//	{ $dateFromString: {
+//	    dateString: <dateStringExpression>, format: <formatStringExpression>,
+//	    timezone: <tzExpression>, onError: <onErrorExpression>, onNull: <onNullExpression>
+//	} }
+type dateFromString struct {
+	namedArgs map[string]any
+}
+
+// newDateFromString returns `$dateFromString` operator.
+func newDateFromString(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$dateFromString", args,
+		handlererrors.ErrDateFromStringNotObject, handlererrors.ErrDateFromStringUnknownField,
+		"dateString", "format", "timezone", "onError", "onNull",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := namedArgs["dateString"]; !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromStringMissingDateString,
+			"Missing 'dateString' parameter to $dateFromString",
+			"$dateFromString",
+		)
+	}
+
+	return &dateFromString{
+		namedArgs: namedArgs,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateFromString) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(d.namedArgs["dateString"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var format string
+
+	expr, hasFormat := d.namedArgs["format"]
+	if hasFormat {
+		f, err := evaluate(expr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(f) {
+			return types.Null, nil
+		}
+
+		var ok bool
+		if format, ok = f.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromStringFormatBadType,
+				fmt.Sprintf("$dateFromString requires that 'format' be a string, found: %s", handlerparams.AliasFromType(f)),
+				"$dateFromString",
+			)
+		}
+	}
+
+	loc, tzNull, err := evaluateTimezone("$dateFromString", d.namedArgs["timezone"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if tzNull {
+		return types.Null, nil
+	}
+
+	if isNullish(v) {
+		return d.evaluateFallback("onNull", doc, vars, nil)
+	}
+
+	_, hasTimezone := d.namedArgs["timezone"]
+
+	var t time.Time
+
+	switch s := v.(type) {
+	case string:
+		if hasFormat {
+			t, err = parseDateWithFormat(s, format, loc, hasTimezone)
+		} else {
+			t, err = parseDate(s, loc, hasTimezone)
+		}
+
+	default:
+		err = handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConversionFailure,
+			fmt.Sprintf(
+				"$dateFromString requires that 'dateString' be a string, found: %s with value %s",
+				handlerparams.AliasFromType(v), types.FormatAnyValue(v),
+			),
+			"$dateFromString",
+		)
+	}
+
+	if err != nil {
+		var cmdErr *handlererrors.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code() == handlererrors.ErrConversionFailure {
+			return d.evaluateFallback("onError", doc, vars, err)
+		}
+
+		return nil, err
+	}
+
+	return t.UTC().Truncate(time.Millisecond), nil
+}
+
+// evaluateFallback evaluates onNull or onError argument.
+// If the argument is not specified, null or the given error is returned.
+func (d *dateFromString) evaluateFallback(name string, doc *types.Document, vars *Variables, fallbackErr error) (any, error) {
+	expr, ok := d.namedArgs[name]
+	if !ok {
+		if fallbackErr != nil {
+			return nil, fallbackErr
+		}
+
+		return types.Null, nil
+	}
+
+	v, err := evaluate(expr, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return types.Null, nil
+	}
+
+	return v, nil
+}
+
+// zonelessDateLayouts are layouts of date strings accepted by `$dateFromString` without format.
+var zonelessDateLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05.999999999",
+	"2006/01/02",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"January 2 2006",
+	"January 2, 2006",
+}
+
+// zoneDateLayoutSuffixes are suffixes of zoneless layouts for date strings with UTC offsets.
+var zoneDateLayoutSuffixes = []string{"Z07:00", "Z0700", " Z07:00", " Z0700"}
+
+// parseDate parses the date string without format in ISO 8601-like formats,
+// optionally followed by UTC offset or Olson time zone identifier.
+func parseDate(s string, loc *time.Location, hasTimezone bool) (time.Time, error) {
+	for _, layout := range zonelessDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	zoned := func(t time.Time) (time.Time, error) {
+		if hasTimezone {
+			return time.Time{}, newDateTimezoneConflictError(s)
+		}
+
+		return t, nil
+	}
+
+	for _, layout := range zonelessDateLayouts {
+		for _, suffix := range zoneDateLayoutSuffixes {
+			if t, err := time.Parse(layout+suffix, s); err == nil {
+				return zoned(t)
+			}
+		}
+	}
+
+	if i := strings.LastIndexByte(s, ' '); i > 0 {
+		if tz, ok := parseTimezone(s[i+1:]); ok {
+			for _, layout := range zonelessDateLayouts {
+				if t, err := time.ParseInLocation(layout, s[:i], tz); err == nil {
+					return zoned(t)
+				}
+			}
+		}
+	}
+
+	return time.Time{}, newDateParseError(s, "")
+}
+
+// parseDateWithFormat parses the date string using MongoDB format specifiers such as `%Y` or `%m`.
+func parseDateWithFormat(s, format string, loc *time.Location, hasTimezone bool) (time.Time, error) {
+	year, month, day, yearDay := 1970, 1, 1, 0
+	var hour, minute, second, millisecond int
+	var isoYear, isoWeek, isoDay int
+	var iso bool
+	var offset *int
+
+	pos := 0
+
+	// readNumber reads from 1 to maxDigits digits.
+	readNumber := func(maxDigits int) (int, int, bool) {
+		var n, digits int
+		for digits < maxDigits && pos < len(s) && s[pos] >= '0' && s[pos] <= '9' {
+			n = n*10 + int(s[pos]-'0')
+			pos++
+			digits++
+		}
+
+		return n, digits, digits > 0
+	}
+
+	// readSign reads '+' or '-' and returns 1 or -1.
+	readSign := func() (int, bool) {
+		if pos >= len(s) || (s[pos] != '+' && s[pos] != '-') {
+			return 0, false
+		}
+
+		pos++
+
+		if s[pos-1] == '-' {
+			return -1, true
+		}
+
+		return 1, true
+	}
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			if pos >= len(s) || s[pos] != c {
+				return time.Time{}, newDateParseError(s, "Unexpected data")
+			}
+
+			pos++
+
+			continue
+		}
+
+		if i++; i == len(format) {
+			return time.Time{}, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatUnmatchedPercent,
+				"Unmatched '%' at end of format string",
+				"$dateFromString",
+			)
+		}
+
+		var ok bool
+
+		switch spec := format[i]; spec {
+		case '%':
+			if ok = pos < len(s) && s[pos] == '%'; ok {
+				pos++
+			}
+		case 'Y':
+			year, _, ok = readNumber(4)
+		case 'G':
+			isoYear, _, ok = readNumber(4)
+			iso = true
+		case 'm':
+			month, _, ok = readNumber(2)
+		case 'd':
+			day, _, ok = readNumber(2)
+		case 'H':
+			hour, _, ok = readNumber(2)
+		case 'M':
+			minute, _, ok = readNumber(2)
+		case 'S':
+			second, _, ok = readNumber(2)
+		case 'L':
+			var digits int
+			if millisecond, digits, ok = readNumber(3); ok {
+				for ; digits < 3; digits++ {
+					millisecond *= 10
+				}
+			}
+		case 'j':
+			yearDay, _, ok = readNumber(3)
+		case 'V':
+			isoWeek, _, ok = readNumber(2)
+			iso = true
+		case 'u':
+			isoDay, _, ok = readNumber(1)
+			iso = true
+		case 'b', 'B':
+			for m := time.January; m <= time.December; m++ {
+				name := m.String()
+				if spec == 'b' {
+					name = name[:3]
+				}
+
+				if len(s)-pos >= len(name) && strings.EqualFold(s[pos:pos+len(name)], name) {
+					month, ok = int(m), true
+					pos += len(name)
+
+					break
+				}
+			}
+		case 'z':
+			if pos < len(s) && s[pos] == 'Z' {
+				pos++
+				offset, ok = new(int), true
+
+				break
+			}
+
+			var sign, h, m int
+			if sign, ok = readSign(); !ok {
+				break
+			}
+
+			if h, _, ok = readNumber(2); !ok {
+				break
+			}
+
+			if pos < len(s) && s[pos] == ':' {
+				pos++
+			}
+
+			if m, _, ok = readNumber(2); ok {
+				o := sign * (h*3600 + m*60)
+				offset = &o
+			}
+		case 'Z':
+			var sign, m int
+			if sign, ok = readSign(); !ok {
+				break
+			}
+
+			if m, _, ok = readNumber(4); ok {
+				o := sign * m * 60
+				offset = &o
+			}
+		default:
+			return time.Time{}, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatInvalidChar,
+				fmt.Sprintf("Invalid format character '%%%c' in format string", spec),
+				"$dateFromString",
+			)
+		}
+
+		if !ok {
+			return time.Time{}, newDateParseError(s, "Unexpected data")
+		}
+	}
+
+	if pos != len(s) {
+		return time.Time{}, newDateParseError(s, "Trailing data")
+	}
+
+	if offset != nil {
+		if hasTimezone {
+			return time.Time{}, newDateTimezoneConflictError(s)
+		}
+
+		loc = time.FixedZone("", *offset)
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, newDateParseError(s, "The parsed date was invalid")
+	}
+
+	var date time.Time
+
+	switch {
+	case iso:
+		if isoWeek == 0 {
+			isoWeek = 1
+		}
+
+		if isoDay == 0 {
+			isoDay = 1
+		}
+
+		jan4 := time.Date(isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+		date = jan4.AddDate(0, 0, (isoWeek-1)*7+isoDay-isoDayOfWeek(jan4))
+
+	case yearDay > 0:
+		date = time.Date(year, time.January, yearDay, 0, 0, 0, 0, time.UTC)
+
+	default:
+		date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if date.Day() != day {
+			return time.Time{}, newDateParseError(s, "The parsed date was invalid")
+		}
+	}
+
+	return time.Date(
+		date.Year(), date.Month(), date.Day(),
+		hour, minute, second, millisecond*int(time.Millisecond),
+		loc,
+	), nil
+}
+
+// newDateParseError returns `$dateFromString` error for the date string that can't be parsed.
+func newDateParseError(s, reason string) error {
+	msg := fmt.Sprintf("Error parsing date string '%s'", s)
+	if reason != "" {
+		msg += "; " + reason
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrConversionFailure, msg, "$dateFromString")
+}
+
+// newDateTimezoneConflictError returns `$dateFromString` error for the date string
+// with time zone information used together with timezone argument.
+func newDateTimezoneConflictError(s string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConversionFailure,
+		fmt.Sprintf(
+			"you cannot pass in a date/time string with time zone information ('%s') together with a timezone argument",
+			s,
+		),
+		"$dateFromString",
+	)
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateFromString)(nil)
+)
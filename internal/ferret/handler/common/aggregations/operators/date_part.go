@@ -0,0 +1,181 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// datePartFunc returns a component of the date in its time zone.
+type datePartFunc func(t time.Time) any
+
+// datePart represents date component extractors such as `$year` or `$isoWeek`.
+//
+//	{ $year: <date expression> }
+//	{ $year: { date: <date expression>, timezone: <tz expression> } }
+type datePart struct {
+	name     string
+	f        datePartFunc
+	date     any
+	timezone any
+}
+
+// newDatePart returns a constructor of the date component extractor.
+func newDatePart(name string, f datePartFunc) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDatePartArrayLen,
+				fmt.Sprintf("%s accepts exactly one argument if given an array, but was given %d", name, len(args)),
+				name,
+			)
+		}
+
+		op := &datePart{
+			name: name,
+			f:    f,
+			date: args[0],
+		}
+
+		doc, ok := args[0].(*types.Document)
+		if !ok || IsOperator(doc) {
+			return op, nil
+		}
+
+		namedArgs, unknown, err := getNamedArgs(doc, "date", "timezone")
+		if err != nil {
+			return nil, err
+		}
+
+		if unknown != "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDatePartUnknownField,
+				fmt.Sprintf("unrecognized option to %s: %q", name, unknown),
+				name,
+			)
+		}
+
+		if op.date, ok = namedArgs["date"]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDatePartMissingDate,
+				fmt.Sprintf("missing 'date' argument to %s, provided: %s", name, types.FormatAnyValue(doc)),
+				name,
+			)
+		}
+
+		op.timezone = namedArgs["timezone"]
+
+		return op, nil
+	}
+}
+
+// Process implements Operator interface.
+func (d *datePart) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(d.date, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, tzNull, err := evaluateTimezone(d.name, d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) || tzNull {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(v)
+	if !ok {
+		return nil, newDateBadTypeError(d.name, v)
+	}
+
+	return d.f(t.In(loc)), nil
+}
+
+// newDateBadTypeError returns an error for the value of the operator that can't be converted to a date.
+func newDateBadTypeError(operator string, v any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrDateBadType,
+		fmt.Sprintf("can't convert from BSON type %s to Date", handlerparams.AliasFromType(v)),
+		operator,
+	)
+}
+
+// isoDayOfWeek returns ISO 8601 day of week, from 1 (Monday) to 7 (Sunday).
+func isoDayOfWeek(t time.Time) int {
+	return (int(t.Weekday())+6)%7 + 1
+}
+
+// weekOfYear returns the week of the year, from 0 to 53.
+// Weeks begin on Sundays, and days before the first Sunday of the year are in week 0.
+func weekOfYear(t time.Time) int {
+	return (t.YearDay() - 1 + 7 - int(t.Weekday())) / 7
+}
+
+// yearPart returns the year.
+func yearPart(t time.Time) any { return int32(t.Year()) }
+
+// monthPart returns the month, from 1 to 12.
+func monthPart(t time.Time) any { return int32(t.Month()) }
+
+// dayOfMonthPart returns the day of the month, from 1 to 31.
+func dayOfMonthPart(t time.Time) any { return int32(t.Day()) }
+
+// hourPart returns the hour, from 0 to 23.
+func hourPart(t time.Time) any { return int32(t.Hour()) }
+
+// minutePart returns the minute, from 0 to 59.
+func minutePart(t time.Time) any { return int32(t.Minute()) }
+
+// secondPart returns the second, from 0 to 59.
+func secondPart(t time.Time) any { return int32(t.Second()) }
+
+// millisecondPart returns the millisecond, from 0 to 999.
+func millisecondPart(t time.Time) any { return int32(t.Nanosecond() / int(time.Millisecond)) }
+
+// dayOfYearPart returns the day of the year, from 1 to 366.
+func dayOfYearPart(t time.Time) any { return int32(t.YearDay()) }
+
+// dayOfWeekPart returns the day of the week, from 1 (Sunday) to 7 (Saturday).
+func dayOfWeekPart(t time.Time) any { return int32(t.Weekday()) + 1 }
+
+// weekPart returns the week of the year, from 0 to 53.
+func weekPart(t time.Time) any { return int32(weekOfYear(t)) }
+
+// isoWeekPart returns ISO 8601 week number, from 1 to 53.
+func isoWeekPart(t time.Time) any {
+	_, w := t.ISOWeek()
+	return int32(w)
+}
+
+// isoWeekYearPart returns ISO 8601 week-numbering year.
+func isoWeekYearPart(t time.Time) any {
+	y, _ := t.ISOWeek()
+	return int64(y)
+}
+
+// isoDayOfWeekPart returns ISO 8601 day of week, from 1 (Monday) to 7 (Sunday).
+func isoDayOfWeekPart(t time.Time) any { return int32(isoDayOfWeek(t)) }
+
+// check interfaces
+var (
+	_ Operator = (*datePart)(nil)
+)
@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// dateToParts represents `$dateToParts` operator.
+//
+//	{ $dateToParts: { date: <date expression>, timezone: <tz expression>, iso8601: <boolean> } }
+type dateToParts struct {
+	date     any
+	timezone any
+	iso8601  any
+}
+
+// newDateToParts returns `$dateToParts` operator.
+func newDateToParts(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$dateToParts", args,
+		handlererrors.ErrDateToPartsNotObject, handlererrors.ErrDateToPartsUnknownField,
+		"date", "timezone", "iso8601",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	date, ok := namedArgs["date"]
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToPartsMissingDate,
+			"Missing 'date' parameter to $dateToParts",
+			"$dateToParts",
+		)
+	}
+
+	return &dateToParts{
+		date:     date,
+		timezone: namedArgs["timezone"],
+		iso8601:  namedArgs["iso8601"],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateToParts) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(d.date, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, tzNull, err := evaluateTimezone("$dateToParts", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var iso8601 bool
+
+	if d.iso8601 != nil {
+		i, err := evaluate(d.iso8601, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(i) {
+			return types.Null, nil
+		}
+
+		var ok bool
+		if iso8601, ok = i.(bool); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToPartsISO8601BadType,
+				fmt.Sprintf("iso8601 must evaluate to a bool, found %s", handlerparams.AliasFromType(i)),
+				"$dateToParts",
+			)
+		}
+	}
+
+	if isNullish(v) || tzNull {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(v)
+	if !ok {
+		return nil, newDateBadTypeError("$dateToParts", v)
+	}
+
+	t = t.In(loc)
+
+	res := types.MakeDocument(7)
+
+	if iso8601 {
+		y, w := t.ISOWeek()
+		res.Set("isoWeekYear", int32(y))
+		res.Set("isoWeek", int32(w))
+		res.Set("isoDayOfWeek", int32(isoDayOfWeek(t)))
+	} else {
+		res.Set("year", int32(t.Year()))
+		res.Set("month", int32(t.Month()))
+		res.Set("day", int32(t.Day()))
+	}
+
+	res.Set("hour", int32(t.Hour()))
+	res.Set("minute", int32(t.Minute()))
+	res.Set("second", int32(t.Second()))
+	res.Set("millisecond", millisecondPart(t))
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateToParts)(nil)
+)
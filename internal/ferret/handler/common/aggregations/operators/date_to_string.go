@@ -0,0 +1,217 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+const (
+	// defaultDateFormat is the default format of `$dateToString` without timezone.
+	defaultDateFormat = "%Y-%m-%dT%H:%M:%S.%LZ"
+
+	// defaultDateFormatWithTimezone is the default format of `$dateToString` with timezone.
+	defaultDateFormatWithTimezone = "%Y-%m-%dT%H:%M:%S.%L"
+)
+
+// dateToString represents `$dateToString` operator.
+//
+//	{ $dateToString: {
+//	    date: <date expression>, format: <format string>,
+//	    timezone: <tz expression>, onNull: <expression>
+//	} }
+type dateToString struct {
+	namedArgs map[string]any
+}
+
+// newDateToString returns `$dateToString` operator.
+func newDateToString(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$dateToString", args,
+		handlererrors.ErrDateToStringNotObject, handlererrors.ErrDateToStringUnknownField,
+		"date", "format", "timezone", "onNull",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := namedArgs["date"]; !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToStringMissingDate,
+			"Missing 'date' parameter to $dateToString",
+			"$dateToString",
+		)
+	}
+
+	return &dateToString{
+		namedArgs: namedArgs,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateToString) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(d.namedArgs["date"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	format := defaultDateFormat
+
+	if _, ok := d.namedArgs["timezone"]; ok {
+		format = defaultDateFormatWithTimezone
+	}
+
+	if expr, ok := d.namedArgs["format"]; ok {
+		f, err := evaluate(expr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(f) {
+			return types.Null, nil
+		}
+
+		if format, ok = f.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToStringFormatBadType,
+				fmt.Sprintf("$dateToString parameter 'format' must be a string, found %s", handlerparams.AliasFromType(f)),
+				"$dateToString",
+			)
+		}
+	}
+
+	loc, tzNull, err := evaluateTimezone("$dateToString", d.namedArgs["timezone"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if tzNull {
+		return types.Null, nil
+	}
+
+	if isNullish(v) {
+		onNull, ok := d.namedArgs["onNull"]
+		if !ok {
+			return types.Null, nil
+		}
+
+		if v, err = evaluate(onNull, doc, vars); err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			return types.Null, nil
+		}
+
+		return v, nil
+	}
+
+	t, ok := toDate(v)
+	if !ok {
+		return nil, newDateBadTypeError("$dateToString", v)
+	}
+
+	return formatDate("$dateToString", t.In(loc), format)
+}
+
+// formatDate formats the date using MongoDB format specifiers such as `%Y` or `%m`.
+func formatDate(operator string, t time.Time, format string) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if i++; i == len(format) {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatUnmatchedPercent,
+				"Unmatched '%' at end of format string",
+				operator,
+			)
+		}
+
+		switch spec := format[i]; spec {
+		case '%':
+			sb.WriteByte('%')
+		case 'Y':
+			fmt.Fprintf(&sb, "%04d", t.Year())
+		case 'G':
+			y, _ := t.ISOWeek()
+			fmt.Fprintf(&sb, "%04d", y)
+		case 'm':
+			fmt.Fprintf(&sb, "%02d", t.Month())
+		case 'b':
+			sb.WriteString(t.Month().String()[:3])
+		case 'B':
+			sb.WriteString(t.Month().String())
+		case 'd':
+			fmt.Fprintf(&sb, "%02d", t.Day())
+		case 'H':
+			fmt.Fprintf(&sb, "%02d", t.Hour())
+		case 'M':
+			fmt.Fprintf(&sb, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&sb, "%02d", t.Second())
+		case 'L':
+			fmt.Fprintf(&sb, "%03d", t.Nanosecond()/int(time.Millisecond))
+		case 'j':
+			fmt.Fprintf(&sb, "%03d", t.YearDay())
+		case 'w':
+			sb.WriteString(strconv.Itoa(int(t.Weekday()) + 1))
+		case 'u':
+			sb.WriteString(strconv.Itoa(isoDayOfWeek(t)))
+		case 'U':
+			fmt.Fprintf(&sb, "%02d", weekOfYear(t))
+		case 'V':
+			_, w := t.ISOWeek()
+			fmt.Fprintf(&sb, "%02d", w)
+		case 'z':
+			_, offset := t.Zone()
+			sign := '+'
+
+			if offset < 0 {
+				sign, offset = '-', -offset
+			}
+
+			fmt.Fprintf(&sb, "%c%02d%02d", sign, offset/3600, offset%3600/60)
+		case 'Z':
+			_, offset := t.Zone()
+			fmt.Fprintf(&sb, "%+d", offset/60)
+		default:
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatInvalidChar,
+				fmt.Sprintf("Invalid format character '%%%c' in format string", spec),
+				operator,
+			)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateToString)(nil)
+)
@@ -0,0 +1,165 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// dateTrunc represents `$dateTrunc` operator.
+//
+//	{ $dateTrunc: {
+//	    date: <expression>, unit: <expression>, binSize: <expression>,
+//	    timezone: <tz expression>, startOfWeek: <expression>
+//	} }
+type dateTrunc struct {
+	namedArgs map[string]any
+}
+
+// newDateTrunc returns `$dateTrunc` operator.
+func newDateTrunc(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$dateTrunc", args,
+		handlererrors.ErrDateTruncNotObject, handlererrors.ErrDateTruncUnknownField,
+		"date", "unit", "binSize", "timezone", "startOfWeek",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$dateTrunc", namedArgs, handlererrors.ErrDateTruncMissingField, "date", "unit"); err != nil {
+		return nil, err
+	}
+
+	return &dateTrunc{
+		namedArgs: namedArgs,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (d *dateTrunc) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, isNull, err := evaluateNamedArgs(d.namedArgs, doc, vars, "date", "unit", "binSize", "startOfWeek")
+	if err != nil {
+		return nil, err
+	}
+
+	loc, tzNull, err := evaluateTimezone("$dateTrunc", d.namedArgs["timezone"], doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNull || tzNull {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(values["date"])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateTruncDateBadType,
+			fmt.Sprintf("$dateTrunc requires 'date' to be a date, but got %s", handlerparams.AliasFromType(values["date"])),
+			"$dateTrunc",
+		)
+	}
+
+	unit, err := parseTimeUnit("$dateTrunc", values["unit"])
+	if err != nil {
+		return nil, err
+	}
+
+	binSize := int64(1)
+
+	if v, ok := values["binSize"]; ok {
+		if binSize, err = handlerparams.GetWholeNumberParam(v); err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateTruncBinSizeBadType,
+				fmt.Sprintf(
+					"$dateTrunc requires 'binSize' to be a 64-bit integer, but got value '%s' of type %s",
+					types.FormatAnyValue(v), handlerparams.AliasFromType(v),
+				),
+				"$dateTrunc",
+			)
+		}
+
+		if binSize <= 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateTruncBinSizeNotPositive,
+				fmt.Sprintf("$dateTrunc requires 'binSize' to be greater than 0, but got value %d", binSize),
+				"$dateTrunc",
+			)
+		}
+	}
+
+	startOfWeek := time.Sunday
+
+	if v, ok := values["startOfWeek"]; ok && unit == timeUnitWeek {
+		if startOfWeek, err = parseStartOfWeek("$dateTrunc", v); err != nil {
+			return nil, err
+		}
+	}
+
+	return truncDate(t, unit, binSize, loc, startOfWeek), nil
+}
+
+// truncDate truncates t to the start of the bin of binSize units in the given time zone.
+//
+// Bins are counted from the reference date 2000-01-01T00:00:00 in the time zone;
+// for weeks, from the first startOfWeek day on or after it.
+// Days, weeks and larger units are truncated using the wall clock time,
+// hours and smaller units are truncated using the elapsed time.
+func truncDate(t time.Time, unit timeUnit, binSize int64, loc *time.Location, startOfWeek time.Weekday) time.Time {
+	ref := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	switch unit {
+	case timeUnitYear, timeUnitQuarter, timeUnitMonth:
+		w := wallClock(t, loc)
+		months := int64(w.Year()-ref.Year())*12 + int64(w.Month()-ref.Month())
+		bin := binSize * int64(unit.months())
+
+		return fromWallClock(ref.AddDate(0, int(floorDiv(months, bin)*bin), 0), loc)
+
+	case timeUnitWeek, timeUnitDay:
+		days := binSize
+
+		if unit == timeUnitWeek {
+			ref = ref.AddDate(0, 0, (int(startOfWeek)-int(ref.Weekday())+7)%7)
+			days *= 7
+		}
+
+		elapsed := floorDiv(wallClock(t, loc).UnixMilli()-ref.UnixMilli(), millisecondsPerDay)
+
+		return fromWallClock(ref.AddDate(0, 0, int(floorDiv(elapsed, days)*days)), loc)
+
+	case timeUnitHour, timeUnitMinute, timeUnitSecond, timeUnitMillisecond:
+		start := fromWallClock(ref, loc).UnixMilli()
+		ms := int64(unit.duration() / time.Millisecond)
+
+		bins := floorDiv(floorDiv(t.UnixMilli()-start, ms), binSize) * binSize
+
+		return time.UnixMilli(start + bins*ms).UTC()
+
+	default:
+		panic(fmt.Sprintf("unexpected time unit %q", unit))
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateTrunc)(nil)
+)
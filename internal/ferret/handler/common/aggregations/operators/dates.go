@@ -0,0 +1,338 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// Embed the time zone database so Olson names resolve even on hosts without one.
+	_ "time/tzdata"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// timeUnit represents time unit of date operators such as `$dateAdd` or `$dateTrunc`.
+type timeUnit string
+
+const (
+	timeUnitYear        = timeUnit("year")
+	timeUnitQuarter     = timeUnit("quarter")
+	timeUnitMonth       = timeUnit("month")
+	timeUnitWeek        = timeUnit("week")
+	timeUnitDay         = timeUnit("day")
+	timeUnitHour        = timeUnit("hour")
+	timeUnitMinute      = timeUnit("minute")
+	timeUnitSecond      = timeUnit("second")
+	timeUnitMillisecond = timeUnit("millisecond")
+)
+
+// duration returns the fixed duration of the unit.
+// It returns 0 for units of variable length (months, quarters and years).
+func (u timeUnit) duration() time.Duration {
+	switch u {
+	case timeUnitWeek:
+		return 7 * 24 * time.Hour
+	case timeUnitDay:
+		return 24 * time.Hour
+	case timeUnitHour:
+		return time.Hour
+	case timeUnitMinute:
+		return time.Minute
+	case timeUnitSecond:
+		return time.Second
+	case timeUnitMillisecond:
+		return time.Millisecond
+	case timeUnitYear, timeUnitQuarter, timeUnitMonth:
+		return 0
+	default:
+		panic(fmt.Sprintf("unexpected time unit %q", u))
+	}
+}
+
+// months returns the number of months in the unit.
+// It returns 0 for units of fixed length.
+func (u timeUnit) months() int {
+	switch u {
+	case timeUnitYear:
+		return 12
+	case timeUnitQuarter:
+		return 3
+	case timeUnitMonth:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parseTimeUnit returns time unit from the evaluated unit argument of the operator.
+func parseTimeUnit(operator string, v any) (timeUnit, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTimeUnitBadType,
+			fmt.Sprintf("%s requires 'unit' to be a string, but got %s", operator, handlerparams.AliasFromType(v)),
+			operator,
+		)
+	}
+
+	switch u := timeUnit(s); u {
+	case timeUnitYear, timeUnitQuarter, timeUnitMonth, timeUnitWeek, timeUnitDay,
+		timeUnitHour, timeUnitMinute, timeUnitSecond, timeUnitMillisecond:
+		return u, nil
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTimeUnitInvalid,
+			fmt.Sprintf("%s parameter 'unit' value cannot be recognized as a time unit: %s", operator, s),
+			operator,
+		)
+	}
+}
+
+// parseStartOfWeek returns the weekday from the evaluated startOfWeek argument of the operator.
+// Both full and three-letter day names are accepted case-insensitively.
+func parseStartOfWeek(operator string, v any) (time.Weekday, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStartOfWeekBadType,
+			fmt.Sprintf("%s requires 'startOfWeek' to be a string, but got %s", operator, handlerparams.AliasFromType(v)),
+			operator,
+		)
+	}
+
+	lower := strings.ToLower(s)
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if lower == name || lower == name[:3] {
+			return d, nil
+		}
+	}
+
+	return 0, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrStartOfWeekInvalid,
+		fmt.Sprintf("%s parameter 'startOfWeek' value cannot be recognized as a day of a week: %s", operator, s),
+		operator,
+	)
+}
+
+// utcOffsetRe matches UTC offsets accepted as time zones: +hh, +hhmm and +hh:mm.
+var utcOffsetRe = regexp.MustCompile(`^[+-]\d{2}(:?\d{2})?$`)
+
+// locations caches loaded time zones by their identifiers.
+var locations sync.Map
+
+// parseTimezone returns the location for the Olson time zone identifier or UTC offset.
+//
+// Olson identifiers are resolved with the system time zone database,
+// falling back to the database embedded into the binary.
+func parseTimezone(tz string) (*time.Location, bool) {
+	if loc, ok := locations.Load(tz); ok {
+		return loc.(*time.Location), true
+	}
+
+	var loc *time.Location
+
+	switch {
+	case utcOffsetRe.MatchString(tz):
+		digits := strings.ReplaceAll(tz[1:], ":", "")
+
+		hours, _ := strconv.Atoi(digits[:2])
+
+		var minutes int
+		if len(digits) > 2 {
+			minutes, _ = strconv.Atoi(digits[2:])
+		}
+
+		offset := (hours*60 + minutes) * 60
+		if tz[0] == '-' {
+			offset = -offset
+		}
+
+		loc = time.FixedZone(tz, offset)
+
+	case tz == "" || tz == "Local":
+		// time.LoadLocation treats them as UTC and the host's time zone respectively
+		return nil, false
+
+	default:
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, false
+		}
+	}
+
+	locations.Store(tz, loc)
+
+	return loc, true
+}
+
+// evaluateTimezone evaluates the timezone argument of the operator.
+// UTC is returned if the argument is not specified; isNull is true if it evaluates to null.
+func evaluateTimezone(operator string, expr any, doc *types.Document, vars *Variables) (loc *time.Location, isNull bool, err error) {
+	if expr == nil {
+		return time.UTC, false, nil
+	}
+
+	v, err := evaluate(expr, doc, vars)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if isNullish(v) {
+		return nil, true, nil
+	}
+
+	tz, ok := v.(string)
+	if !ok {
+		return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTimezoneBadType,
+			fmt.Sprintf("timezone must evaluate to a string, found %s", handlerparams.AliasFromType(v)),
+			operator,
+		)
+	}
+
+	loc, ok = parseTimezone(tz)
+	if !ok {
+		return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTimezoneUnrecognized,
+			fmt.Sprintf("unrecognized time zone identifier: %q", tz),
+			operator,
+		)
+	}
+
+	return loc, false, nil
+}
+
+// toDate returns the time of date-like values: dates, timestamps and ObjectIDs.
+func toDate(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case types.Timestamp:
+		return v.Time(), true
+	case types.ObjectID:
+		return time.Unix(int64(binary.BigEndian.Uint32(v[:4])), 0).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// wallClock returns the wall clock time of t in loc as if it was UTC time.
+// That allows date arithmetic in the given time zone to be done with fixed-length units.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fromWallClock is the reverse of wallClock: it returns UTC time of the wall clock time w in loc.
+func fromWallClock(w time.Time, loc *time.Location) time.Time {
+	return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc).UTC()
+}
+
+// floorDiv returns a divided by b rounded towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
+
+// getObjectArgs returns named arguments of the operator that only supports an object as its argument,
+// like `{$dateAdd: {startDate: <expression>, unit: <expression>, amount: <expression>}}`.
+func getObjectArgs(
+	operator string, args []any, notObject, unknownField handlererrors.ErrorCode, allowed ...string,
+) (map[string]any, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			notObject,
+			fmt.Sprintf("%s only supports an object as its argument", operator),
+			operator,
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, allowed...)
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			unknownField,
+			fmt.Sprintf("Unrecognized argument to %s: %s", operator, unknown),
+			operator,
+		)
+	}
+
+	return namedArgs, nil
+}
+
+// requireArgs returns an error if any of the required named arguments of the operator is missing.
+func requireArgs(operator string, namedArgs map[string]any, code handlererrors.ErrorCode, required ...string) error {
+	for _, name := range required {
+		if _, ok := namedArgs[name]; !ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				code,
+				fmt.Sprintf("Missing '%s' parameter to %s", name, operator),
+				operator,
+			)
+		}
+	}
+
+	return nil
+}
+
+// evaluateNamedArgs evaluates the given named arguments; missing arguments are skipped.
+// If any of evaluated arguments is null or missing, isNull is true.
+func evaluateNamedArgs(namedArgs map[string]any, doc *types.Document, vars *Variables, names ...string) (map[string]any, bool, error) {
+	res := make(map[string]any, len(names))
+
+	var isNull bool
+
+	for _, name := range names {
+		expr, ok := namedArgs[name]
+		if !ok {
+			continue
+		}
+
+		v, err := evaluate(expr, doc, vars)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if isNullish(v) {
+			isNull = true
+		}
+
+		res[name] = v
+	}
+
+	return res, isNull, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// divide represents `$divide` operator.
+//
+//	{ $divide: [ <expression1>, <expression2> ] }
+type divide struct {
+	dividend any
+	divisor  any
+}
+
+// newDivide returns `$divide` operator.
+func newDivide(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$divide", 2, len(args))
+	}
+
+	return &divide{
+		dividend: args[0],
+		divisor:  args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The result is a double, or a decimal if any argument is a decimal.
+func (d *divide) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{d.dividend, d.divisor}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := values[0], values[1]
+
+	if isNullish(a) || isNullish(b) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(a) || !aggregations.IsNumber(b) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDivideBadType,
+			fmt.Sprintf(
+				"$divide only supports numeric types, not %s and %s",
+				handlerparams.AliasFromType(a), handlerparams.AliasFromType(b),
+			),
+			"$divide",
+		)
+	}
+
+	_, isDecimal1 := a.(types.Decimal128)
+	_, isDecimal2 := b.(types.Decimal128)
+
+	divisor := aggregations.NumberToFloat64(b)
+	if divisor == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDivideByZero,
+			"can't $divide by zero",
+			"$divide",
+		)
+	}
+
+	if isDecimal1 || isDecimal2 {
+		d1, _ := types.NewDecimal128FromNumber(a)
+		d2, _ := types.NewDecimal128FromNumber(b)
+
+		return d1.Quo(d2), nil
+	}
+
+	return aggregations.NumberToFloat64(a) / divisor, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*divide)(nil)
+)
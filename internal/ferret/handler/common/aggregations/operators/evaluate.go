@@ -0,0 +1,311 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// Evaluate evaluates the aggregation expression for the given document,
+// like the argument of an accumulator.
+//
+// It returns nil if the expression evaluates to a missing field.
+func Evaluate(expression any, doc *types.Document, vars *Variables) (any, error) {
+	return evaluate(expression, doc, vars)
+}
+
+// evaluate evaluates the aggregation expression for the given document.
+//
+// Operator documents are processed, field path expressions are looked up in the document,
+// other documents and arrays are evaluated recursively, and all other values are returned as is.
+//
+// It returns nil if the expression evaluates to a missing field.
+// Missing values are omitted from evaluated documents and replaced with null in evaluated arrays.
+func evaluate(expression any, doc *types.Document, vars *Variables) (any, error) {
+	switch expression := expression.(type) {
+	case *types.Document:
+		if IsOperator(expression) {
+			// NewOperator is called here, doing it in operator constructors creates initialization cycle
+			op, err := NewOperator(expression)
+			if err != nil {
+				var opErr OperatorError
+				if errors.As(err, &opErr) && opErr.Code() == ErrInvalidExpression {
+					opErr.code = ErrInvalidNestedExpression
+					return nil, opErr
+				}
+
+				return nil, err
+			}
+
+			return op.Process(doc, vars)
+		}
+
+		res := types.MakeDocument(expression.Len())
+
+		iter := expression.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if v, err = evaluate(v, doc, vars); err != nil {
+				return nil, err
+			}
+
+			if v != nil {
+				res.Set(k, v)
+			}
+		}
+
+		return res, nil
+
+	case *types.Array:
+		res := types.MakeArray(expression.Len())
+
+		iter := expression.Iterator()
+		defer iter.Close()
+
+		for {
+			_, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if v, err = evaluate(v, doc, vars); err != nil {
+				return nil, err
+			}
+
+			if v == nil {
+				v = types.Null
+			}
+
+			res.Append(v)
+		}
+
+		return res, nil
+
+	case string:
+		if strings.HasPrefix(expression, "$$") {
+			return evaluateVariable(expression, doc, vars)
+		}
+
+		if current, ok := vars.Get("CURRENT"); ok {
+			// field paths are relative to `$$CURRENT` rebound with `$let`
+			doc, _ = current.(*types.Document)
+		}
+
+		ex, err := aggregations.NewExpression(expression, nil)
+
+		var exErr *aggregations.ExpressionError
+		if errors.As(err, &exErr) && exErr.Code() == aggregations.ErrNotExpression {
+			return expression, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := ex.Evaluate(doc)
+		if err != nil {
+			// missing field
+			return nil, nil
+		}
+
+		return v, nil
+
+	default:
+		return expression, nil
+	}
+}
+
+// evaluateVariable evaluates `$$name` or `$$name.path` expression using the given variables.
+//
+// `$$ROOT` and `$$CURRENT` (unless rebound) are the given document, `$$REMOVE` is always missing.
+// `$$NOW` and `$$CLUSTER_TIME` default to the current time if the scope does not define them.
+//
+// It returns nil if the path does not exist in the variable value.
+func evaluateVariable(expression string, doc *types.Document, vars *Variables) (any, error) {
+	name, path, _ := strings.Cut(strings.TrimPrefix(expression, "$$"), ".")
+
+	switch {
+	case name == "":
+		return nil, handlererrors.NewCommandErrorMsg(
+			handlererrors.ErrFailedToParse,
+			"empty variable names are not allowed",
+		)
+	case strings.HasPrefix(name, "$"):
+		return nil, handlererrors.NewCommandErrorMsg(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("'%s' starts with an invalid character for a user variable name", name),
+		)
+	}
+
+	value, ok := vars.Get(name)
+	if !ok {
+		value, ok = systemVariable(name, doc)
+	}
+
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsg(
+			handlererrors.ErrGroupUndefinedVariable,
+			fmt.Sprintf("Use of undefined variable: %s", name),
+		)
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	if path == "" {
+		if d, ok := value.(*types.Document); ok && d == doc {
+			// the result could be set into the processed document itself, like in `$addFields`
+			return d.DeepCopy(), nil
+		}
+
+		return value, nil
+	}
+
+	// evaluate the path as a field path of a document holding the variable value,
+	// so arrays of documents are traversed the same way as for `$field.path`
+	ex, err := aggregations.NewExpression("$"+name+"."+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := ex.Evaluate(must.NotFail(types.NewDocument(name, value)))
+	if err != nil {
+		// missing field
+		return nil, nil
+	}
+
+	return v, nil
+}
+
+// systemVariable returns the value of the system variable that is not defined in the scope.
+// Nil value is returned for `$$REMOVE` and for `$$ROOT` and `$$CURRENT` without a document.
+func systemVariable(name string, doc *types.Document) (any, bool) {
+	switch name {
+	case "ROOT", "CURRENT":
+		if doc == nil {
+			return nil, true
+		}
+
+		return doc, true
+	case "REMOVE":
+		return nil, true
+	case "NOW":
+		return time.Now().UTC().Truncate(time.Millisecond), true
+	case "CLUSTER_TIME":
+		return types.NewTimestamp(time.Now(), 1), true
+	default:
+		return nil, false
+	}
+}
+
+// validateVariableName checks that the name can be used for a user variable,
+// for example, in `as` argument of `$map` operator.
+func validateVariableName(operator, name string) error {
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r > unicode.MaxASCII:
+			continue
+		case i > 0 && (r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_'):
+			continue
+		case i == 0:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("'%s' starts with an invalid character for a user variable name", name),
+				operator,
+			)
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("'%s' contains an invalid character for a variable name: '%c'", name, r),
+				operator,
+			)
+		}
+	}
+
+	if name == "" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"empty variable names are not allowed",
+			operator,
+		)
+	}
+
+	return nil
+}
+
+// evaluateArgs evaluates all arguments of an operator for the given document.
+//
+// Missing values are returned as nil.
+func evaluateArgs(args []any, doc *types.Document, vars *Variables) ([]any, error) {
+	res := make([]any, len(args))
+
+	for i, arg := range args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = v
+	}
+
+	return res, nil
+}
+
+// isNullish returns true if the evaluated value is missing (nil) or null.
+func isNullish(v any) bool {
+	return v == nil || v == types.Null
+}
+
+// isTrue returns true if the evaluated value is true in a boolean context, like `cond` of `$filter`.
+//
+// Null, missing, false and zero numbers are false; all other values are true.
+func isTrue(v any) bool {
+	switch v := v.(type) {
+	case nil, types.NullType:
+		return false
+	case bool:
+		return v
+	case float64, int32, int64, types.Decimal128:
+		return types.Compare(v, int32(0)) != types.Equal
+	default:
+		return true
+	}
+}
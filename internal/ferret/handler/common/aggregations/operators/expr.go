@@ -17,6 +17,7 @@ package operators
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
@@ -52,8 +53,8 @@ func NewExpr(exprValue *types.Document, errArgument string) (Operator, error) {
 }
 
 // Process implements Operator interface.
-func (e *expr) Process(doc *types.Document) (any, error) {
-	return e.processExpr(e.exprValue, doc)
+func (e *expr) Process(doc *types.Document, vars *Variables) (any, error) {
+	return e.processExpr(e.exprValue, doc, vars)
 }
 
 // processExpr recursively validates operators and expressions.
@@ -69,8 +70,7 @@ func (e *expr) validateExpr(exprValue any) error {
 				return processExprOperatorErrors(err, e.errArgument)
 			}
 
-			_, err = op.Process(nil)
-			if err != nil {
+			if err = Validate(op); err != nil {
 				// TODO https://github.com/FerretDB/FerretDB/issues/3129
 				return processExprOperatorErrors(err, e.errArgument)
 			}
@@ -121,6 +121,11 @@ func (e *expr) validateExpr(exprValue any) error {
 			err = nil
 		}
 
+		// variables are resolved during evaluation
+		if errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrUndefinedVariable {
+			err = nil
+		}
+
 		if err != nil {
 			return processExprOperatorErrors(err, e.errArgument)
 		}
@@ -134,7 +139,7 @@ func (e *expr) validateExpr(exprValue any) error {
 // Each array values and document fields are processed recursively.
 // String expression is evaluated if any, and Null is returned if field is missing.
 // Any value that does not require processing, it returns the original value.
-func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
+func (e *expr) processExpr(exprValue any, doc *types.Document, vars *Variables) (any, error) {
 	switch exprValue := exprValue.(type) {
 	case *types.Document:
 		if IsOperator(exprValue) {
@@ -144,10 +149,9 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			v, err := op.Process(doc)
+			v, err := op.Process(doc, vars)
 			if err != nil {
-				// Process does not return error for existing operators
-				return nil, lazyerrors.Error(err)
+				return nil, err
 			}
 
 			return v, nil
@@ -168,9 +172,9 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			processed, err := e.processExpr(v, doc)
+			processed, err := e.processExpr(v, doc, vars)
 			if err != nil {
-				return nil, lazyerrors.Error(err)
+				return nil, err
 			}
 
 			res.Set(k, processed)
@@ -193,9 +197,9 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			processed, err := e.processExpr(v, doc)
+			processed, err := e.processExpr(v, doc, vars)
 			if err != nil {
-				return nil, lazyerrors.Error(err)
+				return nil, err
 			}
 
 			res.Append(processed)
@@ -203,6 +207,20 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 
 		return res, nil
 	case string:
+		if strings.HasPrefix(exprValue, "$$") {
+			v, err := evaluateVariable(exprValue, doc, vars)
+			if err != nil {
+				return nil, err
+			}
+
+			if v == nil {
+				// missing field is set to null
+				return types.Null, nil
+			}
+
+			return v, nil
+		}
+
 		expression, err := aggregations.NewExpression(exprValue, nil)
 
 		var exprErr *aggregations.ExpressionError
@@ -304,6 +322,12 @@ func processExprOperatorErrors(err error, argument string) error {
 		}
 	}
 
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) {
+		// operator evaluation error, for example, division by zero
+		return err
+	}
+
 	return lazyerrors.Error(err)
 }
 
@@ -0,0 +1,153 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// filterOp represents `$filter` operator.
+//
+//	{ $filter: { input: <array>, as: <string>, cond: <expression>, limit: <number expression> } }
+type filterOp struct {
+	input any
+	as    string
+	cond  any
+	limit any
+}
+
+// newFilter returns `$filter` operator.
+func newFilter(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$filter", args, handlererrors.ErrFilterNotObject, handlererrors.ErrFilterUnknownField,
+		"input", "as", "cond", "limit",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$filter", namedArgs, handlererrors.ErrFilterMissingInput, "input"); err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$filter", namedArgs, handlererrors.ErrFilterMissingCond, "cond"); err != nil {
+		return nil, err
+	}
+
+	as, err := getVariableNameArg("$filter", namedArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filterOp{
+		input: namedArgs["input"],
+		as:    as,
+		cond:  namedArgs["cond"],
+		limit: namedArgs["limit"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns elements of the input array for which `cond` evaluates to true,
+// up to `limit` elements if it is set.
+func (f *filterOp) Process(doc *types.Document, vars *Variables) (any, error) {
+	input, err := evaluate(f.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFilterInputBadType,
+			fmt.Sprintf("input to $filter must be an array not %s", handlerparams.AliasFromType(input)),
+			"$filter",
+		)
+	}
+
+	limit := arr.Len()
+
+	if f.limit != nil {
+		v, err := evaluate(f.limit, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isNullish(v) {
+			l, ok := getInt32(v)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFilterLimitNotInt,
+					fmt.Sprintf("$filter: limit must be represented as a 32-bit integral value: %s", types.FormatAnyValue(v)),
+					"$filter",
+				)
+			}
+
+			if l < 1 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFilterLimitNotPositive,
+					fmt.Sprintf("$filter: limit must be greater than 0: %d", l),
+					"$filter",
+				)
+			}
+
+			limit = int(l)
+		}
+	}
+
+	res := types.MakeArray(0)
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for res.Len() < limit {
+		_, elem, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		v, err := evaluate(f.cond, doc, vars.With(f.as, elem))
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue(v) {
+			res.Append(elem)
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*filterOp)(nil)
+)
@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// firstLast represents `$first` and `$last` operators in their expression form.
+//
+//	{ $first: <expression> }
+//	{ $last: <expression> }
+type firstLast struct {
+	name string
+	arg  any
+	last bool
+}
+
+// newFirst returns `$first` operator.
+func newFirst(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$first", 1, len(args))
+	}
+
+	return &firstLast{
+		name: "$first",
+		arg:  args[0],
+	}, nil
+}
+
+// newLast returns `$last` operator.
+func newLast(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$last", 1, len(args))
+	}
+
+	return &firstLast{
+		name: "$last",
+		arg:  args[0],
+		last: true,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the first or the last element of the array, or missing value for an empty array.
+func (f *firstLast) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(f.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtArrayBadType,
+			fmt.Sprintf("%s's argument must be an array, but is %s", f.name, handlerparams.AliasFromType(v)),
+			f.name,
+		)
+	}
+
+	if f.last {
+		return elemAt(arr, -1), nil
+	}
+
+	return elemAt(arr, 0), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*firstLast)(nil)
+)
@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/javascript"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// function represents `$function` operator.
+//
+//	{ $function: { body: <code>, args: <array expression>, lang: "js" } }
+type function struct {
+	body string
+	args *types.Array
+}
+
+// newFunction returns `$function` operator.
+func newFunction(args ...any) (Operator, error) {
+	var expr *types.Document
+	if len(args) == 1 {
+		expr, _ = args[0].(*types.Document)
+	}
+
+	if expr == nil {
+		found := "array"
+		if len(args) == 1 {
+			found = handlerparams.AliasFromType(args[0])
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionNotObject,
+			fmt.Sprintf("$function requires an object as an argument, found: %s", found),
+			"$function",
+		)
+	}
+
+	body, err := expr.Get("body")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionMissingBody,
+			"The body function must be specified.",
+			"$function",
+		)
+	}
+
+	var code string
+
+	switch body := body.(type) {
+	case string:
+		code = body
+	case types.JavaScript:
+		code = string(body)
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionBodyType,
+			"The body function must evaluate to type string or code",
+			"$function",
+		)
+	}
+
+	fnArgs, err := expr.Get("args")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionMissingArgs,
+			"The args field must be specified.",
+			"$function",
+		)
+	}
+
+	arr, ok := fnArgs.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionArgsType,
+			fmt.Sprintf("The args field must be of type array, found %s", handlerparams.AliasFromType(fnArgs)),
+			"$function",
+		)
+	}
+
+	lang, err := expr.Get("lang")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionMissingLang,
+			"The lang field must be specified.",
+			"$function",
+		)
+	}
+
+	if lang != "js" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFunctionLang,
+			"Currently the only supported language specifier is 'js'.",
+			"$function",
+		)
+	}
+
+	return &function{
+		body: code,
+		args: arr,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It evaluates the arguments and calls the body with the JavaScript evaluator of the metadata.
+func (f *function) Process(doc *types.Document, vars *Variables) (any, error) {
+	args := make([]any, f.args.Len())
+
+	for i := range args {
+		v, err := evaluate(must.NotFail(f.args.Get(i)), doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			v = types.Null
+		}
+
+		args[i] = v
+	}
+
+	meta := vars.Metadata()
+	if meta == nil || meta.JavaScript == nil {
+		return nil, javascript.NewDisabledError("$function")
+	}
+
+	return meta.JavaScript.Call(f.body, args)
+}
+
+// check interfaces
+var (
+	_ Operator = (*function)(nil)
+)
@@ -0,0 +1,157 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// getField represents `$getField` operator.
+//
+//	{ $getField: { field: <string>, input: <object> } }
+//	{ $getField: <string> }
+type getField struct {
+	input any
+	field string
+}
+
+// newGetField returns `$getField` operator.
+func newGetField(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$getField", 1, len(args))
+	}
+
+	namedArgs := map[string]any{"field": args[0]}
+
+	if doc, ok := args[0].(*types.Document); ok {
+		var unknown string
+		var err error
+
+		if namedArgs, unknown, err = getNamedArgs(doc, "field", "input"); err != nil {
+			return nil, err
+		}
+
+		if unknown != "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrGetFieldUnknownField,
+				fmt.Sprintf("$getField found an unknown argument: %s", unknown),
+				"$getField",
+			)
+		}
+	}
+
+	fieldExpr, ok := namedArgs["field"]
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGetFieldMissingField,
+			"$getField requires 'field' to be specified",
+			"$getField",
+		)
+	}
+
+	field, err := getConstantField(
+		"$getField", fieldExpr,
+		handlererrors.ErrGetFieldFieldType, handlererrors.ErrGetFieldFieldNotConstant,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	input, ok := namedArgs["input"]
+	if !ok {
+		input = "$$CURRENT"
+	}
+
+	return &getField{
+		input: input,
+		field: field,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The field name is used as is, dots and dollar signs are not interpreted.
+// It returns missing value if input is null or missing, or if the field does not exist.
+func (g *getField) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(g.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return nil, nil
+	}
+
+	input, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGetFieldInputType,
+			fmt.Sprintf("$getField requires 'input' to evaluate to type Object, but got %s", handlerparams.AliasFromType(v)),
+			"$getField",
+		)
+	}
+
+	if !input.Has(g.field) {
+		return nil, nil
+	}
+
+	return input.Get(g.field)
+}
+
+// getConstantField returns the field name of `$getField`, `$setField` and `$unsetField` operators.
+//
+// The field name must be a constant string or `{ $literal: <string> }`;
+// strings starting with a dollar sign are field paths, so they are not constant.
+func getConstantField(operator string, expr any, typeCode, constantCode handlererrors.ErrorCode) (string, error) {
+	if doc, ok := expr.(*types.Document); ok && doc.Len() == 1 && doc.Has("$literal") {
+		literal, _ := doc.Get("$literal")
+
+		if field, ok := literal.(string); ok {
+			return field, nil
+		}
+
+		expr = literal
+	}
+
+	switch expr := expr.(type) {
+	case string:
+		if !strings.HasPrefix(expr, "$") {
+			return expr, nil
+		}
+	case *types.Document, *types.Array:
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			typeCode,
+			fmt.Sprintf("%s requires 'field' to evaluate to type String, but got %s", operator, handlerparams.AliasFromType(expr)),
+			operator,
+		)
+	}
+
+	return "", handlererrors.NewCommandErrorMsgWithArgument(
+		constantCode,
+		fmt.Sprintf("%s requires 'field' to evaluate to a constant, but got a non-constant argument", operator),
+		operator,
+	)
+}
+
+// check interfaces
+var (
+	_ Operator = (*getField)(nil)
+)
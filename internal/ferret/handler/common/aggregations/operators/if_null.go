@@ -0,0 +1,70 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// ifNull represents `$ifNull` operator.
+//
+//	{ $ifNull: [ <input-expression-1>, ... <input-expression-n>, <replacement-expression-if-null> ] }
+type ifNull struct {
+	args []any
+}
+
+// newIfNull returns `$ifNull` operator.
+func newIfNull(args ...any) (Operator, error) {
+	if len(args) < 2 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIfNullArgsLen,
+			fmt.Sprintf("$ifNull needs at least two arguments, had: %d", len(args)),
+			"$ifNull",
+		)
+	}
+
+	return &ifNull{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the first input that is not null or missing, or the replacement.
+// Inputs after the first non-null one are not evaluated.
+func (i *ifNull) Process(doc *types.Document, vars *Variables) (any, error) {
+	last := len(i.args) - 1
+
+	for _, arg := range i.args[:last] {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isNullish(v) {
+			return v, nil
+		}
+	}
+
+	return evaluate(i.args[last], doc, vars)
+}
+
+// check interfaces
+var (
+	_ Operator = (*ifNull)(nil)
+)
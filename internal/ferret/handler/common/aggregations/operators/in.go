@@ -0,0 +1,87 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// in represents `$in` operator in its expression form.
+//
+//	{ $in: [ <expression>, <array expression> ] }
+type in struct {
+	value any
+	array any
+}
+
+// newIn returns `$in` operator.
+func newIn(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$in", 2, len(args))
+	}
+
+	return &in{
+		value: args[0],
+		array: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns true if the array contains the value.
+func (i *in) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{i.value, i.array}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := values[1].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInArrayBadType,
+			fmt.Sprintf("$in requires an array as a second argument, found: %s", aliasOrMissing(values[1])),
+			"$in",
+		)
+	}
+
+	value := values[0]
+	if value == nil {
+		// missing value is compared as null
+		value = types.Null
+	}
+
+	return indexOfValue(arr, value, 0, arr.Len()) >= 0, nil
+}
+
+// indexOfValue returns the index of the first array element from start to end
+// that is equal to the value, or -1.
+func indexOfValue(arr *types.Array, value any, start, end int) int {
+	for n := start; n < end; n++ {
+		if types.CompareForAggregation(must.NotFail(arr.Get(n)), value) == types.Equal {
+			return n
+		}
+	}
+
+	return -1
+}
+
+// check interfaces
+var (
+	_ Operator = (*in)(nil)
+)
@@ -0,0 +1,96 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// indexOfArray represents `$indexOfArray` operator.
+//
+//	{ $indexOfArray: [ <array expression>, <search expression>, <start>, <end> ] }
+type indexOfArray struct {
+	args []any
+}
+
+// newIndexOfArray returns `$indexOfArray` operator.
+func newIndexOfArray(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, newArgsRangeLenError("$indexOfArray", 2, 4, len(args))
+	}
+
+	return &indexOfArray{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the index of the first occurrence of the value in the array, or -1.
+func (i *indexOfArray) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(i.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) {
+		return types.Null, nil
+	}
+
+	arr, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfArrayBadType,
+			fmt.Sprintf(
+				"$indexOfArray requires an array as a first argument, found: %s",
+				handlerparams.AliasFromType(values[0]),
+			),
+			"$indexOfArray",
+		)
+	}
+
+	value := values[1]
+	if value == nil {
+		// missing value is compared as null
+		value = types.Null
+	}
+
+	start, end := 0, arr.Len()
+
+	if len(values) > 2 {
+		if start, err = getIndexOfIndex("$indexOfArray", values[2], "starting", "starting"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(values) > 3 {
+		if end, err = getIndexOfIndex("$indexOfArray", values[3], "ending", "ending"); err != nil {
+			return nil, err
+		}
+	}
+
+	end = min(end, arr.Len())
+
+	return int32(indexOfValue(arr, value, start, end)), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*indexOfArray)(nil)
+)
@@ -0,0 +1,151 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// indexOfCP represents `$indexOfCP` operator.
+//
+//	{ $indexOfCP: [ <string expression>, <substring expression>, <start>, <end> ] }
+type indexOfCP struct {
+	args []any
+}
+
+// newIndexOfCP returns `$indexOfCP` operator.
+func newIndexOfCP(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, newArgsRangeLenError("$indexOfCP", 2, 4, len(args))
+	}
+
+	return &indexOfCP{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the code point index of the first occurrence of the substring, or -1.
+func (i *indexOfCP) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(i.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) {
+		return types.Null, nil
+	}
+
+	str, ok := values[0].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfCPInputBadType,
+			fmt.Sprintf(
+				"$indexOfCP requires a string as the first argument, found: %s",
+				handlerparams.AliasFromType(values[0]),
+			),
+			"$indexOfCP",
+		)
+	}
+
+	substring, ok := values[1].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfCPSubstringBadType,
+			fmt.Sprintf(
+				"$indexOfCP requires a string as the second argument, found: %s",
+				aliasOrMissing(values[1]),
+			),
+			"$indexOfCP",
+		)
+	}
+
+	runes := []rune(str)
+
+	start, end := 0, len(runes)
+
+	if len(values) > 2 {
+		if start, err = getIndexOfIndex("$indexOfCP", values[2], "starting", "start"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(values) > 3 {
+		if end, err = getIndexOfIndex("$indexOfCP", values[3], "ending", "ending"); err != nil {
+			return nil, err
+		}
+	}
+
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	if start > end {
+		return int32(-1), nil
+	}
+
+	idx := strings.Index(string(runes[start:end]), substring)
+	if idx < 0 {
+		return int32(-1), nil
+	}
+
+	return int32(start + utf8.RuneCountInString(string(runes[start:end])[:idx])), nil
+}
+
+// getIndexOfIndex validates start or end index of `$indexOfCP` or `$indexOfArray`.
+func getIndexOfIndex(operator string, v any, integralName, nonnegativeName string) (int, error) {
+	i, ok := getInt32(v)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfCPIndexNotIntegral,
+			fmt.Sprintf(
+				"%s requires an integral %s index, found a value of type: %s, with value: %s",
+				operator, integralName, aliasOrMissing(v), formatOrMissing(v),
+			),
+			operator,
+		)
+	}
+
+	if i < 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfCPIndexNegative,
+			fmt.Sprintf("%s requires a nonnegative %s index, found: %d", operator, nonnegativeName, i),
+			operator,
+		)
+	}
+
+	return int(i), nil
+}
+
+// formatOrMissing formats the evaluated value for error messages, or returns "missing" for nil.
+func formatOrMissing(v any) string {
+	if v == nil {
+		return "missing"
+	}
+
+	return types.FormatAnyValue(v)
+}
+
+// check interfaces
+var (
+	_ Operator = (*indexOfCP)(nil)
+)
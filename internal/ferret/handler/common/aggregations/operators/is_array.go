@@ -0,0 +1,54 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// isArray represents `$isArray` operator.
+//
+//	{ $isArray: [ <expression> ] }
+type isArray struct {
+	arg any
+}
+
+// newIsArray returns `$isArray` operator.
+func newIsArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$isArray", 1, len(args))
+	}
+
+	return &isArray{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (i *isArray) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(i.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := v.(*types.Array)
+
+	return ok, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*isArray)(nil)
+)
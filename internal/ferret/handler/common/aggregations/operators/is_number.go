@@ -0,0 +1,59 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// isNumber represents `$isNumber` operator.
+//
+//	{ $isNumber: <expression> }
+type isNumber struct {
+	arg any
+}
+
+// newIsNumber returns `$isNumber` operator.
+func newIsNumber(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$isNumber", 1, len(args))
+	}
+
+	return &isNumber{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns true for int, long, double and decimal values, and false for all other values including missing.
+func (i *isNumber) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(i.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*isNumber)(nil)
+)
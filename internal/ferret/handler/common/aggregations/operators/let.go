@@ -0,0 +1,133 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// let represents `$let` operator.
+//
+//	{ $let: { vars: { <var1>: <expression>, ... }, in: <expression> } }
+type let struct {
+	names []string
+	vars  []any
+	in    any
+}
+
+// newLet returns `$let` operator.
+func newLet(args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLetNotObject,
+			"$let only supports an object as its argument",
+			"$let",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "vars", "in")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLetUnknownField,
+			fmt.Sprintf("Unrecognized parameter to $let: %s", unknown),
+			"$let",
+		)
+	}
+
+	if err = requireArgs("$let", namedArgs, handlererrors.ErrLetMissingVars, "vars"); err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$let", namedArgs, handlererrors.ErrLetMissingIn, "in"); err != nil {
+		return nil, err
+	}
+
+	varsDoc, ok := namedArgs["vars"].(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexesWrongType,
+			"invalid parameter: expected an object (vars)",
+			"$let",
+		)
+	}
+
+	res := &let{
+		in: namedArgs["in"],
+	}
+
+	iter := varsDoc.Iterator()
+	defer iter.Close()
+
+	for {
+		name, expr, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// CURRENT is the only system variable that could be rebound
+		if name != "CURRENT" {
+			if err = validateVariableName("$let", name); err != nil {
+				return nil, err
+			}
+		}
+
+		res.names = append(res.names, name)
+		res.vars = append(res.vars, expr)
+	}
+
+	return res, nil
+}
+
+// Process implements Operator interface.
+//
+// All variables are evaluated in the outer scope, then `in` is evaluated with them defined.
+func (l *let) Process(doc *types.Document, vars *Variables) (any, error) {
+	scope := vars
+
+	for i, name := range l.names {
+		v, err := evaluate(l.vars[i], doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		scope = scope.With(name, v)
+	}
+
+	return evaluate(l.in, doc, scope)
+}
+
+// check interfaces
+var (
+	_ Operator = (*let)(nil)
+)
@@ -0,0 +1,58 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// literal represents `$literal` operator.
+//
+//	{ $literal: <value> }
+type literal struct {
+	value any
+}
+
+// newLiteral returns `$literal` operator.
+//
+// The value is not split into arguments, see NewOperator.
+func newLiteral(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$literal", 1, len(args))
+	}
+
+	return &literal{
+		value: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the value as is, without evaluating field paths and operators in it.
+func (l *literal) Process(doc *types.Document, vars *Variables) (any, error) {
+	switch v := l.value.(type) {
+	case *types.Document:
+		return v.DeepCopy(), nil
+	case *types.Array:
+		return v.DeepCopy(), nil
+	default:
+		return v, nil
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*literal)(nil)
+)
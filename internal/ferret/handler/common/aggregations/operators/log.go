@@ -0,0 +1,101 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// log represents `$log` operator.
+//
+//	{ $log: [ <number>, <base> ] }
+type log struct {
+	number any
+	base   any
+}
+
+// newLog returns `$log` operator.
+func newLog(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$log", 2, len(args))
+	}
+
+	return &log{
+		number: args[0],
+		base:   args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (l *log) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{l.number, l.base}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	number, base := values[0], values[1]
+
+	if isNullish(number) || isNullish(base) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(number) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogBadType,
+			fmt.Sprintf("$log's argument must be numeric, not %s", handlerparams.AliasFromType(number)),
+			"$log",
+		)
+	}
+
+	if !aggregations.IsNumber(base) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogBaseBadType,
+			fmt.Sprintf("$log's base must be numeric, not %s", handlerparams.AliasFromType(base)),
+			"$log",
+		)
+	}
+
+	n := aggregations.NumberToFloat64(number)
+	b := aggregations.NumberToFloat64(base)
+
+	if n <= 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogNotPositive,
+			fmt.Sprintf("$log's argument must be a positive number, but is %v", number),
+			"$log",
+		)
+	}
+
+	if b <= 0 || b == 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogBadBase,
+			fmt.Sprintf("$log's base must be a positive number not equal to 1, but is %v", base),
+			"$log",
+		)
+	}
+
+	return floatResult(math.Log(n)/math.Log(b), number, base), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*log)(nil)
+)
@@ -0,0 +1,96 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// logical represents `$and` and `$or` operators.
+//
+//	{ $and: [ <expression1>, <expression2>, ... ] }
+//	{ $or: [ <expression1>, <expression2>, ... ] }
+type logical struct {
+	args []any
+	or   bool
+}
+
+// newAnd returns `$and` operator.
+func newAnd(args ...any) (Operator, error) {
+	return &logical{
+		args: args,
+	}, nil
+}
+
+// newOr returns `$or` operator.
+func newOr(args ...any) (Operator, error) {
+	return &logical{
+		args: args,
+		or:   true,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Arguments are evaluated until the result is known.
+// `$and` without arguments returns true, `$or` without arguments returns false.
+func (l *logical) Process(doc *types.Document, vars *Variables) (any, error) {
+	for _, arg := range l.args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue(v) == l.or {
+			return l.or, nil
+		}
+	}
+
+	return !l.or, nil
+}
+
+// not represents `$not` operator.
+//
+//	{ $not: [ <expression> ] }
+type not struct {
+	arg any
+}
+
+// newNot returns `$not` operator.
+func newNot(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$not", 1, len(args))
+	}
+
+	return &not{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (n *not) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(n.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return !isTrue(v), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*logical)(nil)
+	_ Operator = (*not)(nil)
+)
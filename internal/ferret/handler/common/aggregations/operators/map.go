@@ -0,0 +1,139 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// mapOp represents `$map` operator.
+//
+//	{ $map: { input: <expression>, as: <string>, in: <expression> } }
+type mapOp struct {
+	input any
+	as    string
+	in    any
+}
+
+// newMap returns `$map` operator.
+func newMap(args ...any) (Operator, error) {
+	namedArgs, err := getObjectArgs(
+		"$map", args, handlererrors.ErrMapNotObject, handlererrors.ErrMapUnknownField, "input", "as", "in",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$map", namedArgs, handlererrors.ErrMapMissingInput, "input"); err != nil {
+		return nil, err
+	}
+
+	if err = requireArgs("$map", namedArgs, handlererrors.ErrMapMissingIn, "in"); err != nil {
+		return nil, err
+	}
+
+	as, err := getVariableNameArg("$map", namedArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapOp{
+		input: namedArgs["input"],
+		as:    as,
+		in:    namedArgs["in"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It evaluates `in` for each element of the input array with the element bound to the `as` variable.
+func (m *mapOp) Process(doc *types.Document, vars *Variables) (any, error) {
+	input, err := evaluate(m.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMapInputBadType,
+			fmt.Sprintf("input to $map must be an array not %s", handlerparams.AliasFromType(input)),
+			"$map",
+		)
+	}
+
+	res := types.MakeArray(arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, elem, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		v, err := evaluate(m.in, doc, vars.With(m.as, elem))
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			v = types.Null
+		}
+
+		res.Append(v)
+	}
+
+	return res, nil
+}
+
+// getVariableNameArg returns the validated variable name from `as` argument of the operator,
+// or `this` if it is not specified.
+func getVariableNameArg(operator string, namedArgs map[string]any) (string, error) {
+	v, ok := namedArgs["as"]
+	if !ok {
+		return "this", nil
+	}
+
+	// non-string values are handled as an empty name, like MongoDB does
+	name, _ := v.(string)
+
+	if err := validateVariableName(operator, name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*mapOp)(nil)
+)
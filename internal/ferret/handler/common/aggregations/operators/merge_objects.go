@@ -0,0 +1,99 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// mergeObjects represents `$mergeObjects` operator.
+//
+//	{ $mergeObjects: [ <document1>, <document2>, ... ] }
+type mergeObjects struct {
+	args []any
+}
+
+// newMergeObjects returns `$mergeObjects` operator.
+func newMergeObjects(args ...any) (Operator, error) {
+	return &mergeObjects{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (m *mergeObjects) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(m.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return MergeObjects(values, "$mergeObjects")
+}
+
+// MergeObjects merges the given documents into a new one.
+//
+// Fields of later documents overwrite fields of earlier ones; null and missing values are ignored.
+// The argument is used in the error returned for non-document values.
+func MergeObjects(values []any, argument string) (*types.Document, error) {
+	res := new(types.Document)
+
+	for _, v := range values {
+		if isNullish(v) {
+			continue
+		}
+
+		doc, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMergeObjectsBadType,
+				fmt.Sprintf(
+					"$mergeObjects requires object inputs, but input %s is of type %s",
+					types.FormatAnyValue(v), handlerparams.AliasFromType(v),
+				),
+				argument,
+			)
+		}
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			res.Set(k, v)
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*mergeObjects)(nil)
+)
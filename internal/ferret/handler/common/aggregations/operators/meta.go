@@ -0,0 +1,101 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// meta represents `$meta` operator.
+//
+//	{ $meta: <metaDataKeyword> }
+type meta struct {
+	keyword string
+}
+
+// newMeta returns `$meta` operator.
+func newMeta(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$meta", 1, len(args))
+	}
+
+	keyword, ok := args[0].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("Unsupported argument to $meta: %s", types.FormatAnyValue(args[0])),
+			"$meta",
+		)
+	}
+
+	switch {
+	case keyword == "textScore", keyword == "geoNearDistance":
+	case slices.Contains([]string{
+		"geoNearPoint", "indexKey", "randVal", "recordId",
+		"searchHighlights", "searchScore", "searchScoreDetails", "sortKey",
+	}, keyword):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			fmt.Sprintf("$meta: %q is not supported", keyword),
+			"$meta",
+		)
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("Unsupported argument to $meta: %s", keyword),
+			"$meta",
+		)
+	}
+
+	return &meta{
+		keyword: keyword,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the text score that $text assigned to the document,
+// or the distance that $geoNear assigned to it.
+func (m *meta) Process(doc *types.Document, vars *Variables) (any, error) {
+	if m.keyword == "geoNearDistance" {
+		distance, ok := vars.Metadata().GeoNearDistance(doc)
+		if !ok {
+			// missing
+			return nil, nil
+		}
+
+		return distance, nil
+	}
+
+	score, ok := vars.Metadata().TextScore(doc)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTextScoreUnavailable,
+			"query requires text score metadata, but it is not available",
+			"$meta",
+		)
+	}
+
+	return score, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*meta)(nil)
+)
@@ -0,0 +1,146 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/javascript"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/textsearch"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// Metadata holds per-document metadata of a query or a pipeline,
+// like text scores of $text and distances of $near and $geoNear.
+//
+// Metadata is associated with document pointers;
+// stages that produce new documents for the same input use Copy to carry it over.
+// It is not safe for concurrent use.
+type Metadata struct {
+	// TextIndex is the text index used by $text.
+	TextIndex *textsearch.Index
+
+	// Rand is the source of randomness for $rand and $sampleRate.
+	// If nil, a new time-seeded source is created on first use, like Find and Aggregate do.
+	Rand *rand.Rand
+
+	// Collation compares strings in expressions like $eq and $cmp and in $group keys.
+	// If nil, strings are compared by their bytes.
+	Collation *types.Collation
+
+	// JavaScript evaluates $where and $function.
+	// If nil, JavaScript is disabled and they return an error.
+	JavaScript *javascript.Evaluator
+
+	textQueries map[*types.Document]*textsearch.Query
+	textScores  map[*types.Document]float64
+
+	geoNearDistances map[*types.Document]float64
+}
+
+// TextQuery returns the parsed $text query for the given value of $text operator.
+//
+// Queries are parsed once per value.
+func (m *Metadata) TextQuery(v any) (*textsearch.Query, error) {
+	doc, _ := v.(*types.Document)
+	if q, ok := m.textQueries[doc]; ok && doc != nil {
+		return q, nil
+	}
+
+	q, err := m.TextIndex.ParseQuery(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc != nil {
+		if m.textQueries == nil {
+			m.textQueries = make(map[*types.Document]*textsearch.Query)
+		}
+
+		m.textQueries[doc] = q
+	}
+
+	return q, nil
+}
+
+// SetTextScore sets the text score of the document.
+func (m *Metadata) SetTextScore(doc *types.Document, score float64) {
+	if m.textScores == nil {
+		m.textScores = make(map[*types.Document]float64)
+	}
+
+	m.textScores[doc] = score
+}
+
+// TextScore returns the text score of the document, if any.
+func (m *Metadata) TextScore(doc *types.Document) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+
+	score, ok := m.textScores[doc]
+
+	return score, ok
+}
+
+// SetGeoNearDistance sets the distance of the document from the point of $near or $geoNear.
+func (m *Metadata) SetGeoNearDistance(doc *types.Document, distance float64) {
+	if m.geoNearDistances == nil {
+		m.geoNearDistances = make(map[*types.Document]float64)
+	}
+
+	m.geoNearDistances[doc] = distance
+}
+
+// GeoNearDistance returns the distance of the document from the point of $near or $geoNear, if any.
+func (m *Metadata) GeoNearDistance(doc *types.Document) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+
+	distance, ok := m.geoNearDistances[doc]
+
+	return distance, ok
+}
+
+// Random returns a pseudo-random number in [0.0, 1.0) from Rand.
+// Without metadata, the global source of math/rand is used.
+func (m *Metadata) Random() float64 {
+	if m == nil {
+		return rand.Float64()
+	}
+
+	if m.Rand == nil {
+		m.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return m.Rand.Float64()
+}
+
+// Copy copies metadata of the document from to the document to.
+func (m *Metadata) Copy(from, to *types.Document) {
+	if m == nil {
+		return
+	}
+
+	if score, ok := m.textScores[from]; ok {
+		m.SetTextScore(to, score)
+	}
+
+	if distance, ok := m.geoNearDistances[from]; ok {
+		m.SetGeoNearDistance(to, distance)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// mod represents `$mod` operator.
+//
+//	{ $mod: [ <expression1>, <expression2> ] }
+type mod struct {
+	dividend any
+	divisor  any
+}
+
+// newMod returns `$mod` operator.
+func newMod(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$mod", 2, len(args))
+	}
+
+	return &mod{
+		dividend: args[0],
+		divisor:  args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The result is a decimal if any argument is a decimal, a double if any argument is a double,
+// a long if any argument is a long, and an int otherwise.
+func (m *mod) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{m.dividend, m.divisor}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := values[0], values[1]
+
+	if isNullish(a) || isNullish(b) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(a) || !aggregations.IsNumber(b) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrModBadType,
+			fmt.Sprintf(
+				"$mod only supports numeric types, not %s and %s",
+				handlerparams.AliasFromType(a), handlerparams.AliasFromType(b),
+			),
+			"$mod",
+		)
+	}
+
+	if aggregations.NumberToFloat64(b) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrModByZero,
+			"can't $mod by zero",
+			"$mod",
+		)
+	}
+
+	_, aDecimal := a.(types.Decimal128)
+	_, bDecimal := b.(types.Decimal128)
+
+	if aDecimal || bDecimal {
+		d1, _ := types.NewDecimal128FromNumber(a)
+		d2, _ := types.NewDecimal128FromNumber(b)
+
+		return d1.Rem(d2), nil
+	}
+
+	_, aFloat := a.(float64)
+	_, bFloat := b.(float64)
+
+	if aFloat || bFloat {
+		return math.Mod(aggregations.NumberToFloat64(a), aggregations.NumberToFloat64(b)), nil
+	}
+
+	a32, aInt32 := a.(int32)
+	b32, bInt32 := b.(int32)
+
+	if aInt32 && bInt32 {
+		return a32 % b32, nil
+	}
+
+	return toInt64(a) % toInt64(b), nil
+}
+
+// toInt64 converts int32 or int64 value to int64.
+func toInt64(v any) int64 {
+	switch v := v.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*mod)(nil)
+)
@@ -0,0 +1,69 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// multiply represents `$multiply` operator.
+//
+//	{ $multiply: [ <expression1>, <expression2>, ... ] }
+type multiply struct {
+	args []any
+}
+
+// newMultiply returns `$multiply` operator.
+func newMultiply(args ...any) (Operator, error) {
+	return &multiply{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (m *multiply) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(m.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	for _, v := range values {
+		if !aggregations.IsNumber(v) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMultiplyBadType,
+				fmt.Sprintf("$multiply only supports numeric types, not %s", handlerparams.AliasFromType(v)),
+				"$multiply",
+			)
+		}
+	}
+
+	return aggregations.MultiplyNumbers(values...), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*multiply)(nil)
+)
@@ -0,0 +1,251 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// numericFunc computes the result of an unary numeric operator for int32, int64, float64 or decimal value.
+type numericFunc func(v any) (any, error)
+
+// unaryNumeric represents unary numeric operators like `$abs`, `$ceil`, or `$sqrt`.
+//
+//	{ <$operator>: <number> }
+type unaryNumeric struct {
+	name string
+	f    numericFunc
+	arg  any
+}
+
+// newUnaryNumeric returns a function that creates an unary numeric operator with the given name.
+func newUnaryNumeric(name string, f numericFunc) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newArgsLenError(name, 1, len(args))
+		}
+
+		return &unaryNumeric{
+			name: name,
+			f:    f,
+			arg:  args[0],
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// It returns null if the argument is null or missing.
+func (u *unaryNumeric) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(u.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return u.f(v)
+	case nil, types.NullType:
+		return types.Null, nil
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnaryNumericBadType,
+			fmt.Sprintf("%s only supports numeric types, not %s", u.name, handlerparams.AliasFromType(v)),
+			u.name,
+		)
+	}
+}
+
+// absNumber implements `$abs`.
+//
+// The absolute value of the minimal int is returned as long.
+func absNumber(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return math.Abs(v), nil
+	case int32:
+		switch {
+		case v == math.MinInt32:
+			return -int64(v), nil
+		case v < 0:
+			return -v, nil
+		default:
+			return v, nil
+		}
+	case int64:
+		switch {
+		case v == math.MinInt64:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrAbsLongMin,
+				"can't take $abs of long long min",
+				"$abs",
+			)
+		case v < 0:
+			return -v, nil
+		default:
+			return v, nil
+		}
+	case types.Decimal128:
+		if v.Signbit() {
+			return v.Neg(), nil
+		}
+
+		return v, nil
+	}
+
+	panic(fmt.Sprintf("unexpected type %T", v))
+}
+
+// ceilNumber implements `$ceil`; integers are returned as is.
+func ceilNumber(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return math.Ceil(v), nil
+	case types.Decimal128:
+		return roundDecimal128(v, func(q, m *big.Int) {
+			if m.Sign() > 0 {
+				q.Add(q, big.NewInt(1))
+			}
+		}), nil
+	}
+
+	return v, nil
+}
+
+// floorNumber implements `$floor`; integers are returned as is.
+func floorNumber(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return math.Floor(v), nil
+	case types.Decimal128:
+		return roundDecimal128(v, func(q, m *big.Int) {
+			if m.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			}
+		}), nil
+	}
+
+	return v, nil
+}
+
+// roundDecimal128 rounds finite decimal to an integer.
+// The adjust function is called with the quotient truncated toward zero and the remainder
+// to adjust the quotient in place.
+func roundDecimal128(v types.Decimal128, adjust func(q, m *big.Int)) types.Decimal128 {
+	r, ok := v.Rat()
+	if !ok {
+		return v
+	}
+
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	adjust(q, m)
+
+	return types.NewDecimal128FromBigInt(q, 0)
+}
+
+// sqrtNumber implements `$sqrt`.
+//
+// The square root of a decimal is computed with 34 significant digits.
+func sqrtNumber(v any) (any, error) {
+	f := aggregations.NumberToFloat64(v)
+
+	if f < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSqrtNegative,
+			"$sqrt's argument must be greater than or equal to 0",
+			"$sqrt",
+		)
+	}
+
+	d, ok := v.(types.Decimal128)
+	if !ok {
+		return math.Sqrt(f), nil
+	}
+
+	r, ok := d.Rat()
+	if !ok {
+		return d, nil
+	}
+
+	sqrt := new(big.Float).SetPrec(200).SetRat(r)
+	sqrt.Sqrt(sqrt)
+
+	// remove trailing zeros of exact roots
+	mantissa, exp, _ := strings.Cut(sqrt.Text('e', 33), "e")
+	mantissa = strings.TrimRight(strings.TrimRight(mantissa, "0"), ".")
+
+	return must.NotFail(types.ParseDecimal128(mantissa + "e" + exp)), nil
+}
+
+// floatResult returns the result of a computation in float64 precision.
+// If any of the arguments is a decimal, the result is converted to decimal.
+func floatResult(res float64, args ...any) any {
+	for _, arg := range args {
+		if _, ok := arg.(types.Decimal128); ok {
+			return types.NewDecimal128FromFloat64(res)
+		}
+	}
+
+	return res
+}
+
+// expNumber implements `$exp`.
+func expNumber(v any) (any, error) {
+	return floatResult(math.Exp(aggregations.NumberToFloat64(v)), v), nil
+}
+
+// lnNumber implements `$ln`.
+func lnNumber(v any) (any, error) {
+	f := aggregations.NumberToFloat64(v)
+
+	if f <= 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLnNotPositive,
+			fmt.Sprintf("$ln's argument must be a positive number, but is %v", v),
+			"$ln",
+		)
+	}
+
+	return floatResult(math.Log(f), v), nil
+}
+
+// log10Number implements `$log10`.
+func log10Number(v any) (any, error) {
+	f := aggregations.NumberToFloat64(v)
+
+	if f <= 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLog10NotPositive,
+			fmt.Sprintf("$log10's argument must be a positive number, but is %v", v),
+			"$log10",
+		)
+	}
+
+	return floatResult(math.Log10(f), v), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*unaryNumeric)(nil)
+)
@@ -0,0 +1,93 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// objectToArray represents `$objectToArray` operator.
+//
+//	{ $objectToArray: <object> }
+type objectToArray struct {
+	arg any
+}
+
+// newObjectToArray returns `$objectToArray` operator.
+func newObjectToArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$objectToArray", 1, len(args))
+	}
+
+	return &objectToArray{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns an array of `{ k: <key>, v: <value> }` documents in the order of the document fields.
+func (o *objectToArray) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(o.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	d, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrObjectToArrayBadType,
+			fmt.Sprintf("$objectToArray requires a document input, found: %s", handlerparams.AliasFromType(v)),
+			"$objectToArray",
+		)
+	}
+
+	res := types.MakeArray(d.Len())
+
+	iter := d.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Append(must.NotFail(types.NewDocument("k", k, "v", v)))
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*objectToArray)(nil)
+)
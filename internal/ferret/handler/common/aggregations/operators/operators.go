@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
@@ -45,7 +46,7 @@ type newOperatorFunc func(args ...any) (Operator, error)
 // Operator is a common interface for standard aggregation operators.
 type Operator interface {
 	// Process document and returns the result of applying operator.
-	Process(in *types.Document) (any, error)
+	Process(in *types.Document, vars *Variables) (any, error)
 }
 
 // IsOperator returns true if provided document should be
@@ -72,6 +73,21 @@ func IsOperator(doc *types.Document) bool {
 	return false
 }
 
+// Validate processes the operator without a document to find errors of nested operators and expressions.
+//
+// Errors that depend on the processed document, like an argument of the wrong type,
+// are not returned, they are returned when documents are processed.
+func Validate(op Operator) error {
+	_, err := op.Process(nil, nil)
+
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) {
+		return nil
+	}
+
+	return err
+}
+
 // NewOperator returns operator from provided document.
 // The document should look like: `{<$operator>: <operator-value>}`.
 //
@@ -101,7 +117,8 @@ func NewOperator(doc *types.Document) (Operator, error) {
 
 	var args []any
 
-	if arr, ok := expr.(*types.Array); ok {
+	// the argument of $literal is never split into arguments
+	if arr, ok := expr.(*types.Array); ok && operator != "$literal" {
 		iter := arr.Iterator()
 		defer iter.Close()
 
@@ -139,23 +156,128 @@ func NewOperator(doc *types.Document) (Operator, error) {
 // Operators maps all standard aggregation operators.
 var Operators = map[string]newOperatorFunc{
 	// sorted alphabetically
-	"$sum":  newSum,
-	"$type": newType,
+	"$abs":             newUnaryNumeric("$abs", absNumber),
+	"$add":             newAdd,
+	"$and":             newAnd,
+	"$arrayElemAt":     newArrayElemAt,
+	"$arrayToObject":   newArrayToObject,
+	"$ceil":            newUnaryNumeric("$ceil", ceilNumber),
+	"$cmp":             newCompare("$cmp", cmpResult),
+	"$concat":          newConcat,
+	"$concatArrays":    newConcatArrays,
+	"$cond":            newCond,
+	"$convert":         newConvert,
+	"$dateAdd":         newDateAdd,
+	"$dateDiff":        newDateDiff,
+	"$dateFromParts":   newDateFromParts,
+	"$dateFromString":  newDateFromString,
+	"$dateSubtract":    newDateSubtract,
+	"$dateToParts":     newDateToParts,
+	"$dateToString":    newDateToString,
+	"$dateTrunc":       newDateTrunc,
+	"$dayOfMonth":      newDatePart("$dayOfMonth", dayOfMonthPart),
+	"$dayOfWeek":       newDatePart("$dayOfWeek", dayOfWeekPart),
+	"$dayOfYear":       newDatePart("$dayOfYear", dayOfYearPart),
+	"$divide":          newDivide,
+	"$eq":              newCompare("$eq", eqResult),
+	"$exp":             newUnaryNumeric("$exp", expNumber),
+	"$filter":          newFilter,
+	"$first":           newFirst,
+	"$floor":           newUnaryNumeric("$floor", floorNumber),
+	"$function":        newFunction,
+	"$getField":        newGetField,
+	"$gt":              newCompare("$gt", gtResult),
+	"$gte":             newCompare("$gte", gteResult),
+	"$hour":            newDatePart("$hour", hourPart),
+	"$ifNull":          newIfNull,
+	"$in":              newIn,
+	"$indexOfArray":    newIndexOfArray,
+	"$indexOfCP":       newIndexOfCP,
+	"$isArray":         newIsArray,
+	"$isNumber":        newIsNumber,
+	"$isoDayOfWeek":    newDatePart("$isoDayOfWeek", isoDayOfWeekPart),
+	"$isoWeek":         newDatePart("$isoWeek", isoWeekPart),
+	"$isoWeekYear":     newDatePart("$isoWeekYear", isoWeekYearPart),
+	"$last":            newLast,
+	"$let":             newLet,
+	"$literal":         newLiteral,
+	"$ln":              newUnaryNumeric("$ln", lnNumber),
+	"$log":             newLog,
+	"$log10":           newUnaryNumeric("$log10", log10Number),
+	"$lt":              newCompare("$lt", ltResult),
+	"$lte":             newCompare("$lte", lteResult),
+	"$ltrim":           newLtrim,
+	"$map":             newMap,
+	"$mergeObjects":    newMergeObjects,
+	"$meta":            newMeta,
+	"$millisecond":     newDatePart("$millisecond", millisecondPart),
+	"$minute":          newDatePart("$minute", minutePart),
+	"$mod":             newMod,
+	"$month":           newDatePart("$month", monthPart),
+	"$multiply":        newMultiply,
+	"$ne":              newCompare("$ne", neResult),
+	"$not":             newNot,
+	"$objectToArray":   newObjectToArray,
+	"$or":              newOr,
+	"$pow":             newPow,
+	"$rand":            newRandom,
+	"$range":           newRange,
+	"$reduce":          newReduce,
+	"$regexFind":       newRegexFind,
+	"$regexFindAll":    newRegexFindAll,
+	"$regexMatch":      newRegexMatch,
+	"$replaceAll":      newReplaceAll,
+	"$replaceOne":      newReplaceOne,
+	"$reverseArray":    newReverseArray,
+	"$round":           newRound,
+	"$rtrim":           newRtrim,
+	"$second":          newDatePart("$second", secondPart),
+	"$setDifference":   newSetDifference,
+	"$setEquals":       newSetEquals,
+	"$setField":        newSetField,
+	"$setIntersection": newSetIntersection,
+	"$setIsSubset":     newSetIsSubset,
+	"$setUnion":        newSetUnion,
+	"$size":            newSize,
+	"$slice":           newSlice,
+	"$sortArray":       newSortArray,
+	"$split":           newSplit,
+	"$sqrt":            newUnaryNumeric("$sqrt", sqrtNumber),
+	"$strcasecmp":      newStrcasecmp,
+	"$strLenBytes":     newStrLenBytes,
+	"$strLenCP":        newStrLenCP,
+	"$substrBytes":     newSubstrBytes,
+	"$substrCP":        newSubstrCP,
+	"$subtract":        newSubtract,
+	"$sum":             newSum,
+	"$switch":          newSwitch,
+	"$toBool":          newConvertTo("$toBool", "bool"),
+	"$toDate":          newConvertTo("$toDate", "date"),
+	"$toDecimal":       newConvertTo("$toDecimal", "decimal"),
+	"$toDouble":        newConvertTo("$toDouble", "double"),
+	"$toInt":           newConvertTo("$toInt", "int"),
+	"$toLong":          newConvertTo("$toLong", "long"),
+	"$toLower":         newToLower,
+	"$toObjectId":      newConvertTo("$toObjectId", "objectId"),
+	"$toString":        newConvertTo("$toString", "string"),
+	"$toUpper":         newToUpper,
+	"$trim":            newTrim,
+	"$trunc":           newTrunc,
+	"$type":            newType,
+	"$unsetField":      newUnsetField,
+	"$week":            newDatePart("$week", weekPart),
+	"$year":            newDatePart("$year", yearPart),
+	"$zip":             newZip,
 	// please keep sorted alphabetically
 }
 
 // unsupportedOperators maps all unsupported yet operators.
 var unsupportedOperators = map[string]struct{}{
 	// sorted alphabetically
-	"$abs":              {},
 	"$acos":             {},
 	"$acosh":            {},
-	"$add":              {},
 	"$allElementsTrue":  {},
-	"$and":              {},
 	"$anyElementTrue":   {},
-	"$arrayElemAt":      {},
-	"$arrayToObject":    {},
 	"$asin":             {},
 	"$asinh":            {},
 	"$atan":             {},
@@ -164,136 +286,33 @@ var unsupportedOperators = map[string]struct{}{
 	"$avg":              {},
 	"$binarySize":       {},
 	"$bsonSize":         {},
-	"$ceil":             {},
-	"$cmp":              {},
-	"$concat":           {},
-	"$concatArrays":     {},
-	"$cond":             {},
-	"$convert":          {},
 	"$cos":              {},
 	"$cosh":             {},
 	"$covariancePop":    {},
 	"$covarianceSamp":   {},
-	"$dateAdd":          {},
-	"$dateDiff":         {},
-	"$dateFromParts":    {},
-	"$dateSubtract":     {},
-	"$dateTrunc":        {},
-	"$dateToParts":      {},
-	"$dateFromString":   {},
-	"$dateToString":     {},
-	"$dayOfMonth":       {},
-	"$dayOfWeek":        {},
-	"$dayOfYear":        {},
 	"$degreesToRadians": {},
 	"$denseRank":        {},
 	"$derivative":       {},
-	"$divide":           {},
 	"$documentNumber":   {},
-	"$eq":               {},
-	"$exp":              {},
 	"$expMovingAvg":     {},
-	"$filter":           {},
-	"$floor":            {},
-	"$function":         {},
-	"$getField":         {},
-	"$gt":               {},
-	"$gte":              {},
-	"$hour":             {},
-	"$ifNull":           {},
-	"$in":               {},
-	"$indexOfArray":     {},
 	"$indexOfBytes":     {},
-	"$indexOfCP":        {},
 	"$integral":         {},
-	"$isArray":          {},
-	"$isNumber":         {},
-	"$isoDayOfWeek":     {},
-	"$isoWeek":          {},
-	"$isoWeekYear":      {},
-	"$let":              {},
 	"$linearFill":       {},
-	"$literal":          {},
-	"$ln":               {},
 	"$locf":             {},
-	"$log":              {},
-	"$log10":            {},
-	"$lt":               {},
-	"$lte":              {},
-	"$ltrim":            {},
-	"$map":              {},
 	"$max":              {},
-	"$meta":             {},
 	"$min":              {},
 	"$minN":             {},
-	"$millisecond":      {},
-	"$minute":           {},
-	"$mod":              {},
-	"$month":            {},
-	"$multiply":         {},
-	"$ne":               {},
-	"$not":              {},
-	"$objectToArray":    {},
-	"$or":               {},
-	"$pow":              {},
 	"$radiansToDegrees": {},
-	"$rand":             {},
-	"$range":            {},
 	"$rank":             {},
-	"$reduce":           {},
-	"$regexFind":        {},
-	"$regexFindAll":     {},
-	"$regexMatch":       {},
-	"$replaceOne":       {},
-	"$replaceAll":       {},
-	"$reverseArray":     {},
-	"$round":            {},
-	"$rtrim":            {},
-	"$sampleRate":       {},
-	"$second":           {},
-	"$setDifference":    {},
-	"$setEquals":        {},
-	"$setField":         {},
-	"$setIntersection":  {},
-	"$setIsSubset":      {},
-	"$setUnion":         {},
 	"$shift":            {},
-	"$size":             {},
 	"$sin":              {},
 	"$sinh":             {},
-	"$slice":            {},
-	"$sortArray":        {},
-	"$split":            {},
-	"$sqrt":             {},
 	"$stdDevPop":        {},
 	"$stdDevSamp":       {},
-	"$strcasecmp":       {},
-	"$strLenBytes":      {},
-	"$strLenCP":         {},
 	"$substr":           {},
-	"$substrBytes":      {},
-	"$substrCP":         {},
-	"$subtract":         {},
-	"$switch":           {},
 	"$tan":              {},
 	"$tanh":             {},
-	"$toBool":           {},
-	"$toDate":           {},
-	"$toDecimal":        {},
-	"$toDouble":         {},
-	"$toInt":            {},
-	"$toLong":           {},
-	"$toObjectId":       {},
-	"$toString":         {},
-	"$toLower":          {},
-	"$toUpper":          {},
-	"$trim":             {},
-	"$trunc":            {},
 	"$tsIncrement":      {},
 	"$tsSecond":         {},
-	"$unsetField":       {},
-	"$week":             {},
-	"$year":             {},
-	"$zip":              {},
 	// please keep sorted alphabetically
 }
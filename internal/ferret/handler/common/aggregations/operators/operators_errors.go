@@ -14,6 +14,8 @@
 
 package operators
 
+import "fmt"
+
 // operatorErrorCode represents the type of error.
 type operatorErrorCode uint
 
@@ -66,3 +68,25 @@ func (opErr OperatorError) Code() operatorErrorCode {
 func (opErr OperatorError) Name() string {
 	return opErr.name
 }
+
+// newArgsLenError returns ErrArgsInvalidLen error for the operator that takes exactly n arguments.
+func newArgsLenError(name string, n, got int) error {
+	return newOperatorError(
+		ErrArgsInvalidLen,
+		name,
+		fmt.Sprintf("Expression %s takes exactly %d arguments. %d were passed in.", name, n, got),
+	)
+}
+
+// newArgsRangeLenError returns ErrArgsInvalidLen error for the operator
+// that takes from min to max arguments.
+func newArgsRangeLenError(name string, min, max, got int) error {
+	return newOperatorError(
+		ErrArgsInvalidLen,
+		name,
+		fmt.Sprintf(
+			"Expression %s takes at least %d arguments, and at most %d, but %d were passed in.",
+			name, min, max, got,
+		),
+	)
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// pow represents `$pow` operator.
+//
+//	{ $pow: [ <number>, <exponent> ] }
+type pow struct {
+	base     any
+	exponent any
+}
+
+// newPow returns `$pow` operator.
+func newPow(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$pow", 2, len(args))
+	}
+
+	return &pow{
+		base:     args[0],
+		exponent: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// For integer arguments the result is an integer if it can be presented accurately,
+// with int32 promoted to int64 and then to float64 as needed.
+func (p *pow) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{p.base, p.exponent}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	base, exponent := values[0], values[1]
+
+	if isNullish(base) || isNullish(exponent) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(base) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowBaseBadType,
+			fmt.Sprintf("$pow's base must be numeric, not %s", handlerparams.AliasFromType(base)),
+			"$pow",
+		)
+	}
+
+	if !aggregations.IsNumber(exponent) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowExponentBadType,
+			fmt.Sprintf("$pow's exponent must be numeric, not %s", handlerparams.AliasFromType(exponent)),
+			"$pow",
+		)
+	}
+
+	b := aggregations.NumberToFloat64(base)
+	e := aggregations.NumberToFloat64(exponent)
+
+	if b == 0 && e < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowZeroNegativeExponent,
+			"$pow cannot take a base of 0 and a negative exponent",
+			"$pow",
+		)
+	}
+
+	_, baseFloat := base.(float64)
+	_, exponentFloat := exponent.(float64)
+	_, baseDecimal := base.(types.Decimal128)
+	_, exponentDecimal := exponent.(types.Decimal128)
+
+	if baseFloat || exponentFloat || baseDecimal || exponentDecimal {
+		return floatResult(math.Pow(b, e), base, exponent), nil
+	}
+
+	_, baseInt64 := base.(int64)
+	_, exponentInt64 := exponent.(int64)
+	hasInt64 := baseInt64 || exponentInt64
+
+	bi, ei := toInt64(base), toInt64(exponent)
+
+	switch {
+	case bi == 1:
+		return powResult(big.NewInt(1), hasInt64), nil
+	case bi == -1:
+		if ei%2 == 0 {
+			return powResult(big.NewInt(1), hasInt64), nil
+		}
+
+		return powResult(big.NewInt(-1), hasInt64), nil
+	case ei < 0:
+		return math.Pow(b, e), nil
+	case bi == 0:
+		if ei == 0 {
+			return powResult(big.NewInt(1), hasInt64), nil
+		}
+
+		return powResult(big.NewInt(0), hasInt64), nil
+	}
+
+	// avoid computing huge integers that would be converted to double anyway
+	if bits := int64(big.NewInt(bi).BitLen()); ei > 64/(bits-1) {
+		return math.Pow(b, e), nil
+	}
+
+	res := new(big.Int).Exp(big.NewInt(bi), big.NewInt(ei), nil)
+
+	return powResult(res, hasInt64), nil
+}
+
+// powResult returns int32 if v fits and hasInt64 is false,
+// int64 if v fits, and float64 otherwise.
+func powResult(v *big.Int, hasInt64 bool) any {
+	if !v.IsInt64() {
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	}
+
+	i := v.Int64()
+
+	if !hasInt64 && i >= math.MinInt32 && i <= math.MaxInt32 {
+		return int32(i)
+	}
+
+	return i
+}
+
+// check interfaces
+var (
+	_ Operator = (*pow)(nil)
+)
@@ -0,0 +1,52 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// random represents `$rand` operator.
+//
+//	{ $rand: {} }
+type random struct{}
+
+// newRandom returns `$rand` operator.
+func newRandom(args ...any) (Operator, error) {
+	if len(args) == 1 {
+		if arg, ok := args[0].(*types.Document); ok && arg.Len() == 0 {
+			return new(random), nil
+		}
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrRandArgument,
+		"$rand does not currently accept arguments",
+		"$rand",
+	)
+}
+
+// Process implements Operator interface.
+//
+// It returns a float64 in [0.0, 1.0) from the random source of the metadata, see Metadata.Random.
+func (r *random) Process(doc *types.Document, vars *Variables) (any, error) {
+	return vars.Metadata().Random(), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*random)(nil)
+)
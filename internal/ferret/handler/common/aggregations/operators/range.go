@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// rangeOp represents `$range` operator.
+//
+//	{ $range: [ <start>, <end>, <non-zero step> ] }
+type rangeOp struct {
+	args []any
+}
+
+// newRange returns `$range` operator.
+func newRange(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, newArgsRangeLenError("$range", 2, 3, len(args))
+	}
+
+	return &rangeOp{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns an array of int32 values from start up to, but not including, end.
+func (r *rangeOp) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(r.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := getRangeArg(values[0], "starting", handlererrors.ErrRangeStartBadType, handlererrors.ErrRangeStartNotInt)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := getRangeArg(values[1], "ending", handlererrors.ErrRangeEndBadType, handlererrors.ErrRangeEndNotInt)
+	if err != nil {
+		return nil, err
+	}
+
+	step := int64(1)
+
+	if len(values) > 2 {
+		step, err = getRangeArg(values[2], "step", handlererrors.ErrRangeStepBadType, handlererrors.ErrRangeStepNotInt)
+		if err != nil {
+			return nil, err
+		}
+
+		if step == 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRangeStepZero,
+				"$range requires a non-zero step value",
+				"$range",
+			)
+		}
+	}
+
+	res := types.MakeArray(0)
+
+	for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+		res.Append(int32(i))
+	}
+
+	return res, nil
+}
+
+// getRangeArg validates the evaluated argument of `$range` and returns it as int64
+// to make the loop over int32 values overflow-free.
+func getRangeArg(v any, name string, badType, notInt handlererrors.ErrorCode) (int64, error) {
+	switch v.(type) {
+	case float64, int32, int64:
+	default:
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			badType,
+			fmt.Sprintf("$range requires a numeric %s value, found value of type: %s", name, aliasOrMissing(v)),
+			"$range",
+		)
+	}
+
+	i, ok := getInt32(v)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			notInt,
+			fmt.Sprintf(
+				"$range requires a %s value that can be represented as a 32-bit integer, found value: %s",
+				name, types.FormatAnyValue(v),
+			),
+			"$range",
+		)
+	}
+
+	return int64(i), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*rangeOp)(nil)
+)
@@ -0,0 +1,141 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// reduce represents `$reduce` operator.
+//
+//	{ $reduce: { input: <array>, initialValue: <expression>, in: <expression> } }
+type reduce struct {
+	input        any
+	initialValue any
+	in           any
+}
+
+// newReduce returns `$reduce` operator.
+func newReduce(args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReduceNotObject,
+			"$reduce only supports an object as its argument",
+			"$reduce",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "input", "initialValue", "in")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReduceUnknownField,
+			fmt.Sprintf("$reduce found an unknown argument: %s", unknown),
+			"$reduce",
+		)
+	}
+
+	for _, field := range []struct {
+		name string
+		code handlererrors.ErrorCode
+	}{
+		{"input", handlererrors.ErrReduceMissingInput},
+		{"initialValue", handlererrors.ErrReduceMissingInitialValue},
+		{"in", handlererrors.ErrReduceMissingIn},
+	} {
+		if _, ok := namedArgs[field.name]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				field.code,
+				fmt.Sprintf("$reduce requires '%s' to be specified", field.name),
+				"$reduce",
+			)
+		}
+	}
+
+	return &reduce{
+		input:        namedArgs["input"],
+		initialValue: namedArgs["initialValue"],
+		in:           namedArgs["in"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It evaluates `in` for each element of the input array with the element bound to `$$this`
+// and the accumulated value bound to `$$value`.
+func (r *reduce) Process(doc *types.Document, vars *Variables) (any, error) {
+	input, err := evaluate(r.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReduceInputBadType,
+			fmt.Sprintf("$reduce requires that 'input' be an array, found: %s", handlerparams.AliasFromType(input)),
+			"$reduce",
+		)
+	}
+
+	value, err := evaluate(r.initialValue, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, elem, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if value, err = evaluate(r.in, doc, vars.With("value", value).With("this", elem)); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*reduce)(nil)
+)
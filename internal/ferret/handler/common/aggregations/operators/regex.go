@@ -0,0 +1,357 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// regexMode specifies what regex operator returns.
+type regexMode int
+
+const (
+	regexModeMatch regexMode = iota
+	regexModeFind
+	regexModeFindAll
+)
+
+// regex represents `$regexMatch`, `$regexFind`, and `$regexFindAll` operators.
+//
+//	{ $regexMatch: { input: <expression> , regex: <expression>, options: <expression> } }
+type regex struct {
+	name    string
+	input   any
+	regex   any
+	options any
+	mode    regexMode
+}
+
+// newRegexMatch returns `$regexMatch` operator.
+func newRegexMatch(args ...any) (Operator, error) {
+	return newRegexOperator("$regexMatch", regexModeMatch, args...)
+}
+
+// newRegexFind returns `$regexFind` operator.
+func newRegexFind(args ...any) (Operator, error) {
+	return newRegexOperator("$regexFind", regexModeFind, args...)
+}
+
+// newRegexFindAll returns `$regexFindAll` operator.
+func newRegexFindAll(args ...any) (Operator, error) {
+	return newRegexOperator("$regexFindAll", regexModeFindAll, args...)
+}
+
+// newRegexOperator validates named arguments and returns one of regex operators.
+func newRegexOperator(name string, mode regexMode, args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexNotObject,
+			fmt.Sprintf("%s expects an object of named arguments but found: %s", name, handlerparams.AliasFromType(found)),
+			name,
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "input", "regex", "options")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexUnknownField,
+			fmt.Sprintf("%s found an unknown argument: %s", name, unknown),
+			name,
+		)
+	}
+
+	input, ok := namedArgs["input"]
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexMissingInput,
+			fmt.Sprintf("%s requires 'input' parameter", name),
+			name,
+		)
+	}
+
+	re, ok := namedArgs["regex"]
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexMissingRegex,
+			fmt.Sprintf("%s requires 'regex' parameter", name),
+			name,
+		)
+	}
+
+	return &regex{
+		name:    name,
+		input:   input,
+		regex:   re,
+		options: namedArgs["options"],
+		mode:    mode,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (r *regex) Process(doc *types.Document, vars *Variables) (any, error) {
+	input, err := evaluate(r.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isNullish(input) {
+		if _, ok := input.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexInputBadType,
+				fmt.Sprintf("%s needs 'input' to be of type string", r.name),
+				r.name,
+			)
+		}
+	}
+
+	re, err := r.compile(doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := input.(string)
+	if !ok || re == nil {
+		return r.noMatch(), nil
+	}
+
+	switch r.mode {
+	case regexModeMatch:
+		return re.MatchString(s), nil
+
+	case regexModeFind:
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return types.Null, nil
+		}
+
+		return regexMatchDocument(s, loc), nil
+
+	case regexModeFindAll:
+		res := types.MakeArray(0)
+
+		for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+			res.Append(regexMatchDocument(s, loc))
+		}
+
+		return res, nil
+
+	default:
+		panic(fmt.Sprintf("unexpected regex mode %d", r.mode))
+	}
+}
+
+// noMatch returns the result of the operator for null input or regex.
+func (r *regex) noMatch() any {
+	switch r.mode {
+	case regexModeMatch:
+		return false
+	case regexModeFind:
+		return types.Null
+	case regexModeFindAll:
+		return types.MakeArray(0)
+	default:
+		panic(fmt.Sprintf("unexpected regex mode %d", r.mode))
+	}
+}
+
+// compile evaluates regex and options arguments and compiles the regular expression.
+// It returns nil regular expression if the regex argument is null.
+func (r *regex) compile(doc *types.Document, vars *Variables) (*regexp.Regexp, error) {
+	v, err := evaluate(r.regex, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var pattern, regexOptions string
+
+	switch v := v.(type) {
+	case string:
+		pattern = v
+	case types.Regex:
+		pattern, regexOptions = v.Pattern, v.Options
+	case nil, types.NullType:
+		// options are still validated below
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexBadType,
+			fmt.Sprintf("%s needs 'regex' to be of type string or regex", r.name),
+			r.name,
+		)
+	}
+
+	options := regexOptions
+
+	if r.options != nil {
+		o, err := evaluate(r.options, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		switch o := o.(type) {
+		case string:
+			if o != "" && regexOptions != "" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrRegexOptionsConflict,
+					fmt.Sprintf("%s found regex option(s) specified in both 'regex' and 'option' fields", r.name),
+					r.name,
+				)
+			}
+
+			options += o
+		case nil, types.NullType:
+			// null options are the same as no options
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexOptionsBadType,
+				fmt.Sprintf("%s needs 'options' to be of type string", r.name),
+				r.name,
+			)
+		}
+	}
+
+	var flags string
+	var extended bool
+
+	for _, o := range options {
+		switch o {
+		case 'i', 'm', 's':
+			flags += string(o)
+		case 'x':
+			extended = true
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadRegexOption,
+				fmt.Sprintf("%s invalid flag in regex options: %c", r.name, o),
+				r.name,
+			)
+		}
+	}
+
+	if isNullish(v) {
+		return nil, nil
+	}
+
+	if extended {
+		pattern = stripExtendedRegex(pattern)
+	}
+
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexInvalid,
+			fmt.Sprintf("Invalid Regex in %s: %s", r.name, err),
+			r.name,
+		)
+	}
+
+	return re, nil
+}
+
+// stripExtendedRegex removes unescaped whitespace and `#` comments outside of character classes
+// from the pattern, implementing the `x` (extended) regex option.
+func stripExtendedRegex(pattern string) string {
+	var sb strings.Builder
+	var inClass, inComment bool
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case inComment:
+			if c == '\n' {
+				inComment = false
+			}
+
+		case c == '\\' && i+1 < len(pattern):
+			sb.WriteByte(c)
+			sb.WriteByte(pattern[i+1])
+			i++
+
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+
+			sb.WriteByte(c)
+
+		case c == '[':
+			inClass = true
+			sb.WriteByte(c)
+
+		case c == '#':
+			inComment = true
+
+		case c == ' ', c == '\t', c == '\n', c == '\v', c == '\f', c == '\r':
+			// unescaped whitespace is ignored
+
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// regexMatchDocument returns the document describing a single match
+// with `match`, `idx` (in code points), and `captures` fields.
+func regexMatchDocument(s string, loc []int) *types.Document {
+	captures := types.MakeArray(len(loc)/2 - 1)
+
+	for i := 2; i < len(loc); i += 2 {
+		if loc[i] < 0 {
+			captures.Append(types.Null)
+			continue
+		}
+
+		captures.Append(s[loc[i]:loc[i+1]])
+	}
+
+	return must.NotFail(types.NewDocument(
+		"match", s[loc[0]:loc[1]],
+		"idx", int32(utf8.RuneCountInString(s[:loc[0]])),
+		"captures", captures,
+	))
+}
+
+// check interfaces
+var (
+	_ Operator = (*regex)(nil)
+)
@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// replace represents `$replaceOne` and `$replaceAll` operators.
+//
+//	{ $replaceOne: { input: <expression>, find: <expression>, replacement: <expression> } }
+type replace struct {
+	name        string
+	input       any
+	find        any
+	replacement any
+	all         bool
+}
+
+// newReplaceOne returns `$replaceOne` operator.
+func newReplaceOne(args ...any) (Operator, error) {
+	return newReplaceOperator("$replaceOne", false, args...)
+}
+
+// newReplaceAll returns `$replaceAll` operator.
+func newReplaceAll(args ...any) (Operator, error) {
+	return newReplaceOperator("$replaceAll", true, args...)
+}
+
+// newReplaceOperator validates named arguments and returns one of replace operators.
+func newReplaceOperator(name string, all bool, args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReplaceNotObject,
+			fmt.Sprintf("%s requires an object as an argument, found: %s", name, handlerparams.AliasFromType(found)),
+			name,
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "input", "find", "replacement")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReplaceUnknownField,
+			fmt.Sprintf("%s found an unknown argument: %s", name, unknown),
+			name,
+		)
+	}
+
+	for _, field := range []string{"input", "find", "replacement"} {
+		if _, ok := namedArgs[field]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReplaceMissingField,
+				fmt.Sprintf("%s requires '%s' to be specified", name, field),
+				name,
+			)
+		}
+	}
+
+	return &replace{
+		name:        name,
+		input:       namedArgs["input"],
+		find:        namedArgs["find"],
+		replacement: namedArgs["replacement"],
+		all:         all,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (r *replace) Process(doc *types.Document, vars *Variables) (any, error) {
+	var res [3]string
+	var null bool
+
+	args := []struct {
+		field string
+		expr  any
+	}{
+		{"input", r.input},
+		{"find", r.find},
+		{"replacement", r.replacement},
+	}
+
+	for i, arg := range args {
+		v, err := evaluate(arg.expr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			null = true
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReplaceBadType,
+				fmt.Sprintf("%s requires that '%s' be a string, found: %s", r.name, arg.field, types.FormatAnyValue(v)),
+				r.name,
+			)
+		}
+
+		res[i] = s
+	}
+
+	if null {
+		return types.Null, nil
+	}
+
+	if r.all {
+		return strings.ReplaceAll(res[0], res[1], res[2]), nil
+	}
+
+	return strings.Replace(res[0], res[1], res[2], 1), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*replace)(nil)
+)
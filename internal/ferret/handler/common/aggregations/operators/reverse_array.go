@@ -0,0 +1,79 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// reverseArray represents `$reverseArray` operator.
+//
+//	{ $reverseArray: <array expression> }
+type reverseArray struct {
+	arg any
+}
+
+// newReverseArray returns `$reverseArray` operator.
+func newReverseArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$reverseArray", 1, len(args))
+	}
+
+	return &reverseArray{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (r *reverseArray) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(r.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReverseArrayBadType,
+			fmt.Sprintf(
+				"The argument to $reverseArray must be an array, but was of type: %s",
+				handlerparams.AliasFromType(v),
+			),
+			"$reverseArray",
+		)
+	}
+
+	res := types.MakeArray(arr.Len())
+
+	for i := arr.Len() - 1; i >= 0; i-- {
+		res.Append(must.NotFail(arr.Get(i)))
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*reverseArray)(nil)
+)
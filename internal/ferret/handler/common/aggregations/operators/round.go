@@ -0,0 +1,208 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// round represents `$round` and `$trunc` operators.
+//
+//	{ $round: [ <number>, <place> ] }
+//	{ $trunc: [ <number>, <place> ] }
+type round struct {
+	name   string
+	number any
+	place  any
+	trunc  bool
+}
+
+// newRound returns `$round` operator.
+func newRound(args ...any) (Operator, error) {
+	return newRoundOrTrunc("$round", false, args...)
+}
+
+// newTrunc returns `$trunc` operator.
+func newTrunc(args ...any) (Operator, error) {
+	return newRoundOrTrunc("$trunc", true, args...)
+}
+
+// newRoundOrTrunc returns `$round` or `$trunc` operator.
+func newRoundOrTrunc(name string, trunc bool, args ...any) (Operator, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, newArgsRangeLenError(name, 1, 2, len(args))
+	}
+
+	r := &round{
+		name:   name,
+		number: args[0],
+		place:  int32(0),
+		trunc:  trunc,
+	}
+
+	if len(args) == 2 {
+		r.place = args[1]
+	}
+
+	return r, nil
+}
+
+// Process implements Operator interface.
+//
+// Halves are rounded to even. Doubles are rounded as decimals with 15 significant digits,
+// so that `{$round: [2.675, 2]}` returns 2.68 like in MongoDB.
+// Decimals are rounded exactly, and the result has the given number of decimal places.
+func (r *round) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{r.number, r.place}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	number, placeValue := values[0], values[1]
+
+	if isNullish(number) || isNullish(placeValue) {
+		return types.Null, nil
+	}
+
+	place, err := handlerparams.GetWholeNumberParam(placeValue)
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundBadPlace,
+			fmt.Sprintf("precision argument to %s must be a integral value", r.name),
+			r.name,
+		)
+	}
+
+	if place < -20 || place > 100 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundPlaceOutOfRange,
+			fmt.Sprintf("cannot apply %s with precision value %d value must be in [-20, 100]", r.name, place),
+			r.name,
+		)
+	}
+
+	switch number := number.(type) {
+	case float64:
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return number, nil
+		}
+
+		rat, ok := new(big.Rat).SetString(strconv.FormatFloat(number, 'e', 14, 64))
+		if !ok {
+			panic(fmt.Sprintf("cannot parse %v", number))
+		}
+
+		res, _ := roundRat(rat, place, r.trunc).Float64()
+
+		return res, nil
+
+	case int32:
+		if place >= 0 {
+			return number, nil
+		}
+
+		res := roundRat(new(big.Rat).SetInt64(int64(number)), place, r.trunc).Num()
+
+		return powResult(res, false), nil
+
+	case int64:
+		if place >= 0 {
+			return number, nil
+		}
+
+		res := roundRat(new(big.Rat).SetInt64(number), place, r.trunc).Num()
+
+		return powResult(res, true), nil
+
+	case types.Decimal128:
+		rat, ok := number.Rat()
+		if !ok {
+			return number, nil
+		}
+
+		// scale the rounded value back to an integer coefficient
+		res := roundRat(rat, place, r.trunc)
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(place)), nil))
+
+		if place >= 0 {
+			res.Mul(res, scale)
+		} else {
+			res.Quo(res, scale)
+		}
+
+		return types.NewDecimal128FromBigInt(res.Num(), int(-place)), nil
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundBadType,
+			fmt.Sprintf("%s only supports numeric types, not %s", r.name, handlerparams.AliasFromType(number)),
+			r.name,
+		)
+	}
+}
+
+// roundRat rounds v to the given number of decimal places.
+// Negative place rounds to the left of the decimal point.
+// If trunc is true, v is truncated toward zero, otherwise halves are rounded to even.
+func roundRat(v *big.Rat, place int64, trunc bool) *big.Rat {
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(place)), nil))
+
+	x := new(big.Rat).Set(v)
+	if place >= 0 {
+		x.Mul(x, scale)
+	} else {
+		x.Quo(x, scale)
+	}
+
+	q, m := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+
+	if !trunc && m.Sign() != 0 {
+		twice := new(big.Int).Abs(m)
+		twice.Lsh(twice, 1)
+
+		cmp := twice.Cmp(x.Denom())
+		if cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+			q.Add(q, big.NewInt(int64(x.Num().Sign())))
+		}
+	}
+
+	res := new(big.Rat).SetInt(q)
+	if place >= 0 {
+		return res.Quo(res, scale)
+	}
+
+	return res.Mul(res, scale)
+}
+
+// abs64 returns the absolute value of v.
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// check interfaces
+var (
+	_ Operator = (*round)(nil)
+)
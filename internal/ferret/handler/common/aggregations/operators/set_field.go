@@ -0,0 +1,164 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// setField represents `$setField` and `$unsetField` operators.
+//
+//	{ $setField: { field: <string>, input: <object>, value: <expression> } }
+//	{ $unsetField: { field: <string>, input: <object> } }
+type setField struct {
+	name  string
+	input any
+	value any
+	field string
+	unset bool
+}
+
+// newSetField returns `$setField` operator.
+func newSetField(args ...any) (Operator, error) {
+	return parseSetField("$setField", false, args)
+}
+
+// newUnsetField returns `$unsetField` operator.
+func newUnsetField(args ...any) (Operator, error) {
+	return parseSetField("$unsetField", true, args)
+}
+
+// parseSetField parses arguments of `$setField` or `$unsetField` operator.
+func parseSetField(operator string, unset bool, args []any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSetFieldNotObject,
+			fmt.Sprintf("%s only supports an object as its argument", operator),
+			operator,
+		)
+	}
+
+	allowed := []string{"field", "input", "value"}
+	required := []struct {
+		name string
+		code handlererrors.ErrorCode
+	}{
+		{"field", handlererrors.ErrSetFieldMissingField},
+		{"value", handlererrors.ErrSetFieldMissingValue},
+		{"input", handlererrors.ErrSetFieldMissingInput},
+	}
+
+	if unset {
+		allowed = allowed[:2]
+		required = append(required[:1], required[2])
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, allowed...)
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSetFieldUnknownField,
+			fmt.Sprintf("%s found an unknown argument: %s", operator, unknown),
+			operator,
+		)
+	}
+
+	for _, r := range required {
+		if _, ok := namedArgs[r.name]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				r.code,
+				fmt.Sprintf("%s requires '%s' to be specified", operator, r.name),
+				operator,
+			)
+		}
+	}
+
+	field, err := getConstantField(
+		operator, namedArgs["field"],
+		handlererrors.ErrSetFieldFieldType, handlererrors.ErrSetFieldFieldNotConstant,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &setField{
+		name:  operator,
+		input: namedArgs["input"],
+		value: namedArgs["value"],
+		field: field,
+		unset: unset,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns a copy of input with the field set or removed; the field name is used as is.
+// Setting the field to a missing value (for example, `$$REMOVE`) removes it.
+// It returns null if input is null or missing.
+func (s *setField) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(s.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	input, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSetFieldInputType,
+			fmt.Sprintf("%s requires 'input' to evaluate to type Object, but got %s", s.name, handlerparams.AliasFromType(v)),
+			s.name,
+		)
+	}
+
+	res := input.DeepCopy()
+
+	var value any
+
+	if !s.unset {
+		if value, err = evaluate(s.value, doc, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	if value == nil {
+		res.Remove(s.field)
+		return res, nil
+	}
+
+	res.Set(s.field, value)
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*setField)(nil)
+)
@@ -0,0 +1,348 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// setUnion represents `$setUnion` and `$setIntersection` operators.
+//
+//	{ $setUnion: [ <expression1>, <expression2>, ... ] }
+//	{ $setIntersection: [ <array1>, <array2>, ... ] }
+type setUnion struct {
+	name         string
+	code         handlererrors.ErrorCode
+	args         []any
+	intersection bool
+}
+
+// newSetUnion returns `$setUnion` operator.
+func newSetUnion(args ...any) (Operator, error) {
+	return &setUnion{
+		name: "$setUnion",
+		code: handlererrors.ErrSetUnionNotArray,
+		args: args,
+	}, nil
+}
+
+// newSetIntersection returns `$setIntersection` operator.
+func newSetIntersection(args ...any) (Operator, error) {
+	return &setUnion{
+		name:         "$setIntersection",
+		code:         handlererrors.ErrSetIntersectionNotArray,
+		args:         args,
+		intersection: true,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+// Elements of the result are unique and keep the order of their first occurrence.
+func (s *setUnion) Process(doc *types.Document, vars *Variables) (any, error) {
+	var res []any
+
+	for i, arg := range s.args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				s.code,
+				fmt.Sprintf(
+					"All operands of %s must be arrays. One argument is of type: %s",
+					s.name, aliasOrMissing(v),
+				),
+				s.name,
+			)
+		}
+
+		values := uniqueValues(arrayValues(arr))
+
+		switch {
+		case !s.intersection:
+			for _, v := range values {
+				if !containsValue(res, v) {
+					res = append(res, v)
+				}
+			}
+		case i == 0:
+			res = values
+		default:
+			var intersection []any
+
+			for _, v := range res {
+				if containsValue(values, v) {
+					intersection = append(intersection, v)
+				}
+			}
+
+			res = intersection
+		}
+	}
+
+	return newArrayFromValues(res), nil
+}
+
+// setDifference represents `$setDifference` operator.
+//
+//	{ $setDifference: [ <expression1>, <expression2> ] }
+type setDifference struct {
+	first  any
+	second any
+}
+
+// newSetDifference returns `$setDifference` operator.
+func newSetDifference(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$setDifference", 2, len(args))
+	}
+
+	return &setDifference{
+		first:  args[0],
+		second: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns unique elements of the first array that are not in the second one,
+// or null if any argument is null or missing.
+func (s *setDifference) Process(doc *types.Document, vars *Variables) (any, error) {
+	first, second, err := evaluateSetPair("$setDifference", s.first, s.second, doc, vars, true)
+	if err != nil || first == nil {
+		return types.Null, err
+	}
+
+	var res []any
+
+	for _, v := range uniqueValues(first) {
+		if !containsValue(second, v) {
+			res = append(res, v)
+		}
+	}
+
+	return newArrayFromValues(res), nil
+}
+
+// setIsSubset represents `$setIsSubset` operator.
+//
+//	{ $setIsSubset: [ <expression1>, <expression2> ] }
+type setIsSubset struct {
+	first  any
+	second any
+}
+
+// newSetIsSubset returns `$setIsSubset` operator.
+func newSetIsSubset(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$setIsSubset", 2, len(args))
+	}
+
+	return &setIsSubset{
+		first:  args[0],
+		second: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *setIsSubset) Process(doc *types.Document, vars *Variables) (any, error) {
+	first, second, err := evaluateSetPair("$setIsSubset", s.first, s.second, doc, vars, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range first {
+		if !containsValue(second, v) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// setEquals represents `$setEquals` operator.
+//
+//	{ $setEquals: [ <expression1>, <expression2>, ... ] }
+type setEquals struct {
+	args []any
+}
+
+// newSetEquals returns `$setEquals` operator.
+func newSetEquals(args ...any) (Operator, error) {
+	if len(args) < 2 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSetEqualsArgsLen,
+			fmt.Sprintf("$setEquals needs at least two arguments had: %d", len(args)),
+			"$setEquals",
+		)
+	}
+
+	return &setEquals{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Unlike other set operators, null and missing arguments are errors.
+func (s *setEquals) Process(doc *types.Document, vars *Variables) (any, error) {
+	var first []any
+	res := true
+
+	for i, arg := range s.args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSetEqualsNotArray,
+				fmt.Sprintf("All operands of $setEquals must be arrays. One argument is of type: %s", aliasOrMissing(v)),
+				"$setEquals",
+			)
+		}
+
+		values := arrayValues(arr)
+
+		if i == 0 {
+			first = values
+			continue
+		}
+
+		if !res {
+			// all arguments are still checked to be arrays
+			continue
+		}
+
+		for _, v := range values {
+			if !containsValue(first, v) {
+				res = false
+				break
+			}
+		}
+
+		for _, v := range first {
+			if !containsValue(values, v) {
+				res = false
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// evaluateSetPair evaluates arguments of `$setDifference` or `$setIsSubset` and returns their elements.
+//
+// If nullable is true, nil slices are returned for null or missing arguments;
+// otherwise, they are errors like other non-array values.
+func evaluateSetPair(operator string, firstExpr, secondExpr any, doc *types.Document, vars *Variables, nullable bool) ([]any, []any, error) { //nolint:lll // for readability
+	first, err := evaluate(firstExpr, doc, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	second, err := evaluate(secondExpr, doc, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if nullable && (isNullish(first) || isNullish(second)) {
+		return nil, nil, nil
+	}
+
+	codes := map[string][2]handlererrors.ErrorCode{
+		"$setDifference": {handlererrors.ErrSetDifferenceFirstNotArray, handlererrors.ErrSetDifferenceSecondNotArray},
+		"$setIsSubset":   {handlererrors.ErrSetIsSubsetFirstNotArray, handlererrors.ErrSetIsSubsetSecondNotArray},
+	}[operator]
+
+	res := make([][]any, 2)
+
+	for i, v := range []any{first, second} {
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				codes[i],
+				fmt.Sprintf(
+					"both operands of %s must be arrays. %s argument is of type: %s",
+					operator, []string{"First", "Second"}[i], aliasOrMissing(v),
+				),
+				operator,
+			)
+		}
+
+		res[i] = arrayValues(arr)
+	}
+
+	return res[0], res[1], nil
+}
+
+// containsValue returns true if values contain the value equal to v,
+// numbers of different types are equal if they have the same value.
+func containsValue(values []any, v any) bool {
+	for _, value := range values {
+		if types.CompareForAggregation(value, v) == types.Equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uniqueValues returns values without duplicates, keeping the first occurrence.
+func uniqueValues(values []any) []any {
+	res := make([]any, 0, len(values))
+
+	for _, v := range values {
+		if !containsValue(res, v) {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// newArrayFromValues returns a new array with the given values.
+func newArrayFromValues(values []any) *types.Array {
+	res := types.MakeArray(len(values))
+
+	for _, v := range values {
+		res.Append(v)
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Operator = (*setUnion)(nil)
+	_ Operator = (*setDifference)(nil)
+	_ Operator = (*setIsSubset)(nil)
+	_ Operator = (*setEquals)(nil)
+)
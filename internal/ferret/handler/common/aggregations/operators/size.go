@@ -0,0 +1,66 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// size represents `$size` operator in its expression form.
+//
+//	{ $size: <array expression> }
+type size struct {
+	arg any
+}
+
+// newSize returns `$size` operator.
+func newSize(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$size", 1, len(args))
+	}
+
+	return &size{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the number of elements in the array as int32.
+func (s *size) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(s.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSizeBadType,
+			fmt.Sprintf("The argument to $size must be an array. Type of argument: %s", aliasOrMissing(v)),
+			"$size",
+		)
+	}
+
+	return int32(arr.Len()), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*size)(nil)
+)
@@ -0,0 +1,173 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// slice represents `$slice` operator.
+//
+//	{ $slice: [ <array>, <n> ] }
+//	{ $slice: [ <array>, <position>, <n> ] }
+type slice struct {
+	args []any
+}
+
+// newSlice returns `$slice` operator.
+func newSlice(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, newArgsRangeLenError("$slice", 2, 3, len(args))
+	}
+
+	return &slice{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// With two arguments, it returns the first n elements, or the last -n elements for negative n.
+// With three arguments, it returns n elements starting from the position,
+// negative position counts from the end of the array.
+func (s *slice) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(s.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	arr, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceFirstArg,
+			fmt.Sprintf(
+				"First argument to $slice must be an array, but is of type: %s",
+				handlerparams.AliasFromType(values[0]),
+			),
+			"$slice",
+		)
+	}
+
+	switch values[1].(type) {
+	case float64, int32, int64:
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceSecondArgBadType,
+			fmt.Sprintf(
+				"Second argument to $slice must be a numeric value, but is of type: %s",
+				handlerparams.AliasFromType(values[1]),
+			),
+			"$slice",
+		)
+	}
+
+	second, ok := getInt32(values[1])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceSecondArgNotInt,
+			fmt.Sprintf(
+				"Second argument to $slice can't be represented as a 32-bit integer: %s",
+				types.FormatAnyValue(values[1]),
+			),
+			"$slice",
+		)
+	}
+
+	l := arr.Len()
+
+	var start, end int
+
+	if len(values) == 2 {
+		n := int(second)
+
+		if n >= 0 {
+			start, end = 0, min(n, l)
+		} else {
+			start, end = max(l+n, 0), l
+		}
+
+		return sliceArray(arr, start, end), nil
+	}
+
+	switch values[2].(type) {
+	case float64, int32, int64:
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceThirdArgBadType,
+			fmt.Sprintf(
+				"Third argument to $slice must be numeric, but is of type: %s",
+				handlerparams.AliasFromType(values[2]),
+			),
+			"$slice",
+		)
+	}
+
+	n, ok := getInt32(values[2])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceThirdArgNotInt,
+			fmt.Sprintf(
+				"Third argument to $slice can't be represented as a 32-bit integer: %s",
+				types.FormatAnyValue(values[2]),
+			),
+			"$slice",
+		)
+	}
+
+	if n <= 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceThirdArgNotPositive,
+			fmt.Sprintf("Third argument to $slice must be positive: %d", n),
+			"$slice",
+		)
+	}
+
+	start = int(second)
+	if start < 0 {
+		start = max(l+start, 0)
+	}
+
+	start = min(start, l)
+	end = min(start+int(n), l)
+
+	return sliceArray(arr, start, end), nil
+}
+
+// sliceArray returns a new array with elements of arr from start to end.
+func sliceArray(arr *types.Array, start, end int) *types.Array {
+	res := types.MakeArray(end - start)
+
+	for i := start; i < end; i++ {
+		res.Append(must.NotFail(arr.Get(i)))
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Operator = (*slice)(nil)
+)
@@ -0,0 +1,251 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// sortArray represents `$sortArray` operator.
+//
+//	{ $sortArray: { input: <array>, sortBy: <sort spec> } }
+//
+// The sort spec is 1 or -1 to sort by values, or a document like `{ field1: 1, field2: -1 }`
+// to sort documents by their fields.
+type sortArray struct {
+	input any
+
+	// order is set when array values are sorted
+	order types.SortType
+
+	// fields and orders are set when documents are sorted by fields
+	fields []types.Path
+	orders []types.SortType
+}
+
+// newSortArray returns `$sortArray` operator.
+func newSortArray(args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortArrayNotObject,
+			fmt.Sprintf("$sortArray requires an object as an argument, found: %s", handlerparams.AliasFromType(found)),
+			"$sortArray",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "input", "sortBy")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortArrayUnknownField,
+			fmt.Sprintf("$sortArray found an unknown argument: %s", unknown),
+			"$sortArray",
+		)
+	}
+
+	for _, field := range []struct {
+		name string
+		code handlererrors.ErrorCode
+	}{
+		{"input", handlererrors.ErrSortArrayMissingInput},
+		{"sortBy", handlererrors.ErrSortArrayMissingSortBy},
+	} {
+		if _, ok := namedArgs[field.name]; !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				field.code,
+				fmt.Sprintf("$sortArray requires '%s' to be specified", field.name),
+				"$sortArray",
+			)
+		}
+	}
+
+	s := &sortArray{
+		input: namedArgs["input"],
+	}
+
+	sortBy, ok := namedArgs["sortBy"].(*types.Document)
+	if !ok {
+		if s.order, err = getSortArrayOrder(namedArgs["sortBy"]); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	}
+
+	if sortBy.Len() == 0 {
+		return nil, newSortArrayBadSortByError()
+	}
+
+	iter := sortBy.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		path, err := types.NewPathFromString(k)
+		if err != nil {
+			return nil, newSortArrayBadSortByError()
+		}
+
+		order, err := getSortArrayOrder(v)
+		if err != nil {
+			return nil, err
+		}
+
+		s.fields = append(s.fields, path)
+		s.orders = append(s.orders, order)
+	}
+
+	return s, nil
+}
+
+// Process implements Operator interface.
+//
+// The sort is stable, elements that compare equal keep their order.
+func (s *sortArray) Process(doc *types.Document, vars *Variables) (any, error) {
+	input, err := evaluate(s.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortArrayInputBadType,
+			fmt.Sprintf(
+				"The input argument to $sortArray must be an array, but was of type: %s",
+				handlerparams.AliasFromType(input),
+			),
+			"$sortArray",
+		)
+	}
+
+	values := arrayValues(arr)
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return s.less(values[i], values[j])
+	})
+
+	res := types.MakeArray(len(values))
+	res.Append(values...)
+
+	return res, nil
+}
+
+// less reports whether the value a sorts before b.
+func (s *sortArray) less(a, b any) bool {
+	if s.fields == nil {
+		res := types.CompareForAggregation(a, b)
+
+		if s.order == types.Descending {
+			return res == types.Greater
+		}
+
+		return res == types.Less
+	}
+
+	for i, path := range s.fields {
+		res := types.CompareOrderForSort(sortArrayField(a, path), sortArrayField(b, path), s.orders[i])
+
+		switch res {
+		case types.Less:
+			return true
+		case types.Greater:
+			return false
+		}
+	}
+
+	return false
+}
+
+// sortArrayField returns the value of the field of an array element to sort by.
+// Null is returned if the element is not a document or does not have the field,
+// as sort treats null and non-existent field equivalent.
+func sortArrayField(v any, path types.Path) any {
+	d, ok := v.(*types.Document)
+	if !ok {
+		return types.Null
+	}
+
+	res, err := d.GetByPath(path)
+	if err != nil {
+		return types.Null
+	}
+
+	return res
+}
+
+// getSortArrayOrder returns sort order from 1 or -1 value of `$sortArray` sortBy.
+func getSortArrayOrder(v any) (types.SortType, error) {
+	order, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		return 0, newSortArrayBadSortByError()
+	}
+
+	switch order {
+	case 1:
+		return types.Ascending, nil
+	case -1:
+		return types.Descending, nil
+	default:
+		return 0, newSortArrayBadSortByError()
+	}
+}
+
+// newSortArrayBadSortByError returns an error for invalid `$sortArray` sortBy.
+func newSortArrayBadSortByError() error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrSortArrayBadSortBy,
+		"$sortArray sortBy must be 1, -1 or a document with 1 or -1 values",
+		"$sortArray",
+	)
+}
+
+// check interfaces
+var (
+	_ Operator = (*sortArray)(nil)
+)
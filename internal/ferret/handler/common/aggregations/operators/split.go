@@ -0,0 +1,102 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// split represents `$split` operator.
+//
+//	{ $split: [ <string expression>, <delimiter> ] }
+type split struct {
+	str       any
+	delimiter any
+}
+
+// newSplit returns `$split` operator.
+func newSplit(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$split", 2, len(args))
+	}
+
+	return &split{
+		str:       args[0],
+		delimiter: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *split) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.str, s.delimiter}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) || isNullish(values[1]) {
+		return types.Null, nil
+	}
+
+	str, ok := values[0].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSplitInputBadType,
+			fmt.Sprintf(
+				"$split requires an expression that evaluates to a string as a first argument, found: %s",
+				handlerparams.AliasFromType(values[0]),
+			),
+			"$split",
+		)
+	}
+
+	delimiter, ok := values[1].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSplitDelimiterBadType,
+			fmt.Sprintf(
+				"$split requires an expression that evaluates to a string as a second argument, found: %s",
+				handlerparams.AliasFromType(values[1]),
+			),
+			"$split",
+		)
+	}
+
+	if delimiter == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSplitEmptyDelimiter,
+			"$split requires a non-empty separator",
+			"$split",
+		)
+	}
+
+	parts := strings.Split(str, delimiter)
+
+	res := types.MakeArray(len(parts))
+	for _, p := range parts {
+		res.Append(p)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*split)(nil)
+)
@@ -0,0 +1,122 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// coerceToString converts the evaluated value to string like MongoDB does
+// for string operators such as `$substrCP` or `$toLower`.
+//
+// Null and missing values are converted to the empty string.
+func coerceToString(operator string, v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case nil, types.NullType:
+		return "", nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case types.Decimal128:
+		return v.String(), nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStringConversion,
+			fmt.Sprintf("can't convert from BSON type %s to String", handlerparams.AliasFromType(v)),
+			operator,
+		)
+	}
+}
+
+// asciiToLower returns s with ASCII letters converted to lower case.
+// Like in MongoDB, other characters are not changed.
+func asciiToLower(s string) string {
+	b := []byte(s)
+
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+
+	return string(b)
+}
+
+// asciiToUpper returns s with ASCII letters converted to upper case.
+// Like in MongoDB, other characters are not changed.
+func asciiToUpper(s string) string {
+	b := []byte(s)
+
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' {
+			b[i] = c - ('a' - 'A')
+		}
+	}
+
+	return string(b)
+}
+
+// getNamedArgs returns arguments of an operator that takes a document of named arguments,
+// like `{$trim: {input: <string>, chars: <string>}}`.
+//
+// If the document has a field that is not in allowed, its name is returned as unknown.
+func getNamedArgs(doc *types.Document, allowed ...string) (args map[string]any, unknown string, err error) {
+	args = make(map[string]any, doc.Len())
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return args, "", nil
+		}
+
+		if err != nil {
+			return nil, "", lazyerrors.Error(err)
+		}
+
+		var found bool
+
+		for _, a := range allowed {
+			if k == a {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, k, nil
+		}
+
+		args[k] = v
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// strLen represents `$strLenCP` and `$strLenBytes` operators.
+//
+//	{ $strLenCP: <string expression> }
+//	{ $strLenBytes: <string expression> }
+type strLen struct {
+	arg   any
+	bytes bool
+}
+
+// newStrLenCP returns `$strLenCP` operator.
+func newStrLenCP(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$strLenCP", 1, len(args))
+	}
+
+	return &strLen{
+		arg: args[0],
+	}, nil
+}
+
+// newStrLenBytes returns `$strLenBytes` operator.
+func newStrLenBytes(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$strLenBytes", 1, len(args))
+	}
+
+	return &strLen{
+		arg:   args[0],
+		bytes: true,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the number of UTF-8 code points or bytes in the string.
+func (s *strLen) Process(doc *types.Document, vars *Variables) (any, error) {
+	v, err := evaluate(s.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	str, ok := v.(string)
+
+	switch {
+	case !ok && s.bytes:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStrLenBytesBadType,
+			fmt.Sprintf("$strLenBytes requires a string argument, found: %s", aliasOrMissing(v)),
+			"$strLenBytes",
+		)
+	case !ok:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStrLenCPBadType,
+			fmt.Sprintf("$strLenCP requires a string argument, found: %s", aliasOrMissing(v)),
+			"$strLenCP",
+		)
+	case s.bytes:
+		return int32(len(str)), nil
+	default:
+		return int32(utf8.RuneCountInString(str)), nil
+	}
+}
+
+// aliasOrMissing returns the type alias of the evaluated value, or "missing" for nil.
+func aliasOrMissing(v any) string {
+	if v == nil {
+		return "missing"
+	}
+
+	return handlerparams.AliasFromType(v)
+}
+
+// check interfaces
+var (
+	_ Operator = (*strLen)(nil)
+)
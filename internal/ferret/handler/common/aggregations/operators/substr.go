@@ -0,0 +1,255 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// substr represents `$substrCP` and `$substrBytes` operators.
+//
+//	{ $substrCP: [ <string expression>, <code point index>, <code point count> ] }
+//	{ $substrBytes: [ <string expression>, <byte index>, <byte count> ] }
+type substr struct {
+	str   any
+	start any
+	count any
+	bytes bool
+}
+
+// newSubstrCP returns `$substrCP` operator.
+func newSubstrCP(args ...any) (Operator, error) {
+	if len(args) != 3 {
+		return nil, newArgsLenError("$substrCP", 3, len(args))
+	}
+
+	return &substr{
+		str:   args[0],
+		start: args[1],
+		count: args[2],
+	}, nil
+}
+
+// newSubstrBytes returns `$substrBytes` operator.
+func newSubstrBytes(args ...any) (Operator, error) {
+	if len(args) != 3 {
+		return nil, newArgsLenError("$substrBytes", 3, len(args))
+	}
+
+	return &substr{
+		str:   args[0],
+		start: args[1],
+		count: args[2],
+		bytes: true,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *substr) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.str, s.start, s.count}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.bytes {
+		return substrBytes(values[0], values[1], values[2])
+	}
+
+	return substrCP(values[0], values[1], values[2])
+}
+
+// substrCP implements `$substrCP` for evaluated arguments.
+func substrCP(strValue, startValue, countValue any) (any, error) {
+	str, err := coerceToString("$substrCP", strValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if !aggregations.IsNumber(startValue) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPStartType,
+			fmt.Sprintf(
+				"$substrCP: starting index must be a numeric type (is BSON type %s)",
+				aliasOrMissing(startValue),
+			),
+			"$substrCP",
+		)
+	}
+
+	start, ok := getInt32(startValue)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPStartNotIntegral,
+			fmt.Sprintf(
+				"$substrCP: starting index cannot be represented as a 32-bit integral value: %s",
+				types.FormatAnyValue(startValue),
+			),
+			"$substrCP",
+		)
+	}
+
+	if !aggregations.IsNumber(countValue) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPLengthType,
+			fmt.Sprintf("$substrCP: length must be a numeric type (is BSON type %s)", aliasOrMissing(countValue)),
+			"$substrCP",
+		)
+	}
+
+	count, ok := getInt32(countValue)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPLengthNotIntegral,
+			fmt.Sprintf(
+				"$substrCP: length cannot be represented as a 32-bit integral value: %s",
+				types.FormatAnyValue(countValue),
+			),
+			"$substrCP",
+		)
+	}
+
+	if count < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPLengthNegative,
+			"$substrCP: length must be a nonnegative integer.",
+			"$substrCP",
+		)
+	}
+
+	if start < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPStartNegative,
+			"$substrCP: the starting index must be nonnegative integer.",
+			"$substrCP",
+		)
+	}
+
+	runes := []rune(str)
+
+	if int(start) >= len(runes) {
+		return "", nil
+	}
+
+	end := len(runes)
+	if int(count) < end-int(start) {
+		end = int(start) + int(count)
+	}
+
+	return string(runes[start:end]), nil
+}
+
+// substrBytes implements `$substrBytes` for evaluated arguments.
+func substrBytes(strValue, startValue, countValue any) (any, error) {
+	str, err := coerceToString("$substrBytes", strValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if !aggregations.IsNumber(startValue) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesStartType,
+			fmt.Sprintf(
+				"$substrBytes: starting index must be a numeric type (is BSON type %s)",
+				aliasOrMissing(startValue),
+			),
+			"$substrBytes",
+		)
+	}
+
+	if !aggregations.IsNumber(countValue) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesLengthType,
+			fmt.Sprintf("$substrBytes: length must be a numeric type (is BSON type %s)", aliasOrMissing(countValue)),
+			"$substrBytes",
+		)
+	}
+
+	start := truncateToInt64(startValue)
+	count := truncateToInt64(countValue)
+
+	// negative start returns the empty string
+	if start < 0 || start >= int64(len(str)) {
+		return "", nil
+	}
+
+	if !utf8.RuneStart(str[start]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesStartContinuation,
+			"$substrBytes:  Invalid range, starting index is a UTF-8 continuation byte.",
+			"$substrBytes",
+		)
+	}
+
+	// negative count returns the rest of the string
+	end := int64(len(str))
+	if count >= 0 && count < end-start {
+		end = start + count
+	}
+
+	if end < int64(len(str)) && !utf8.RuneStart(str[end]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesEndContinuation,
+			"$substrBytes:  Invalid range, ending index is in the middle of a UTF-8 character.",
+			"$substrBytes",
+		)
+	}
+
+	return str[start:end], nil
+}
+
+// getInt32 returns the value as int32 if it is a whole number that can be represented as int32.
+func getInt32(v any) (int32, bool) {
+	i, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil || i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, false
+	}
+
+	return int32(i), true
+}
+
+// truncateToInt64 converts int32, int64 or float64 value to int64 truncating the fractional part.
+func truncateToInt64(v any) int64 {
+	switch v := v.(type) {
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return 0
+		case v >= math.MaxInt64:
+			return math.MaxInt64
+		case v <= math.MinInt64:
+			return math.MinInt64
+		default:
+			return int64(v)
+		}
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*substr)(nil)
+)
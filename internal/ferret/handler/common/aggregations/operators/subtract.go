@@ -0,0 +1,91 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// subtract represents `$subtract` operator.
+//
+//	{ $subtract: [ <expression1>, <expression2> ] }
+type subtract struct {
+	minuend    any
+	subtrahend any
+}
+
+// newSubtract returns `$subtract` operator.
+func newSubtract(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$subtract", 2, len(args))
+	}
+
+	return &subtract{
+		minuend:    args[0],
+		subtrahend: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Subtracting two dates returns the difference in milliseconds,
+// subtracting a number from a date returns a date.
+func (s *subtract) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.minuend, s.subtrahend}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := values[0], values[1]
+
+	if isNullish(a) || isNullish(b) {
+		return types.Null, nil
+	}
+
+	switch a := a.(type) {
+	case float64, int32, int64, types.Decimal128:
+		if aggregations.IsNumber(b) {
+			return aggregations.SubtractNumbers(a, b), nil
+		}
+
+	case time.Time:
+		switch b := b.(type) {
+		case time.Time:
+			return a.UnixMilli() - b.UnixMilli(), nil
+		case float64, int32, int64, types.Decimal128:
+			return addMilliseconds(a, aggregations.SubtractNumbers(int32(0), b)), nil
+		}
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrSubtractBadType,
+		fmt.Sprintf(
+			"can't $subtract %s from %s",
+			handlerparams.AliasFromType(b), handlerparams.AliasFromType(a),
+		),
+		"$subtract",
+	)
+}
+
+// check interfaces
+var (
+	_ Operator = (*subtract)(nil)
+)
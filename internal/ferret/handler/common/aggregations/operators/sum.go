@@ -16,6 +16,7 @@ package operators
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
@@ -27,6 +28,8 @@ import (
 type sum struct {
 	// expressions are valid path expression requiring evaluation
 	expressions []*aggregations.Expression
+	// variables are variable expressions such as `$$this`
+	variables []string
 	// operators are documents containing operator expressions i.e. `[{$sum: 1}]`
 	operators []*types.Document
 	// numbers are int32, int64 or float64 values
@@ -52,6 +55,11 @@ func newSum(args ...any) (Operator, error) {
 		case float64:
 			operator.numbers = append(operator.numbers, arg)
 		case string:
+			if strings.HasPrefix(arg, "$$") {
+				operator.variables = append(operator.variables, arg)
+				break
+			}
+
 			ex, err := aggregations.NewExpression(arg, nil)
 
 			var exErr *aggregations.ExpressionError
@@ -64,7 +72,7 @@ func newSum(args ...any) (Operator, error) {
 			}
 
 			operator.expressions = append(operator.expressions, ex)
-		case int32, int64:
+		case int32, int64, types.Decimal128:
 			operator.numbers = append(operator.numbers, arg)
 		}
 	}
@@ -74,10 +82,12 @@ func newSum(args ...any) (Operator, error) {
 
 // Process implements Operator interface.
 // It evaluates expressions if any to fetch a value, creates new operator and processes them if any
-// and sums all int32, int64 and float64 numbers ignoring other types.
-func (s *sum) Process(doc *types.Document) (any, error) {
+// and sums all int32, int64, float64 and decimal numbers ignoring other types.
+func (s *sum) Process(doc *types.Document, vars *Variables) (any, error) {
 	var numbers []any
 
+	values := make([]any, 0, len(s.expressions)+len(s.variables))
+
 	for _, expression := range s.expressions {
 		value, err := expression.Evaluate(doc)
 		if err != nil {
@@ -85,6 +95,21 @@ func (s *sum) Process(doc *types.Document) (any, error) {
 			continue
 		}
 
+		values = append(values, value)
+	}
+
+	for _, variable := range s.variables {
+		value, err := evaluateVariable(variable, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+
+	for _, value := range values {
 		switch v := value.(type) {
 		case *types.Array:
 			if s.arrayLen > 1 {
@@ -125,7 +150,7 @@ func (s *sum) Process(doc *types.Document) (any, error) {
 			return nil, err
 		}
 
-		v, err := op.Process(doc)
+		v, err := op.Process(doc, vars)
 		if err != nil {
 			return nil, err
 		}
@@ -135,7 +160,7 @@ func (s *sum) Process(doc *types.Document) (any, error) {
 
 	for _, number := range s.numbers {
 		switch number := number.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			numbers = append(numbers, number)
 		}
 	}
@@ -0,0 +1,184 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// switchOp represents `$switch` operator.
+//
+//	{ $switch: { branches: [ { case: <expression>, then: <expression> }, ... ], default: <expression> } }
+type switchOp struct {
+	branches []switchBranch
+	def      any
+}
+
+// switchBranch represents a single `$switch` branch.
+type switchBranch struct {
+	caseExpr any
+	thenExpr any
+}
+
+// newSwitch returns `$switch` operator.
+func newSwitch(args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchNotObject,
+			fmt.Sprintf("$switch requires an object as an argument, found: %s", handlerparams.AliasFromType(found)),
+			"$switch",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "branches", "default")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchUnknownField,
+			fmt.Sprintf("$switch found an unknown argument: %s", unknown),
+			"$switch",
+		)
+	}
+
+	s := &switchOp{
+		def: namedArgs["default"],
+	}
+
+	if v, ok := namedArgs["branches"]; ok {
+		branches, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSwitchBranchesNotArray,
+				fmt.Sprintf("$switch expected an array for 'branches', found: %s", handlerparams.AliasFromType(v)),
+				"$switch",
+			)
+		}
+
+		for _, b := range arrayValues(branches) {
+			branch, err := newSwitchBranch(b)
+			if err != nil {
+				return nil, err
+			}
+
+			s.branches = append(s.branches, branch)
+		}
+	}
+
+	if len(s.branches) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchNoBranches,
+			"$switch requires at least one branch.",
+			"$switch",
+		)
+	}
+
+	return s, nil
+}
+
+// newSwitchBranch validates and returns a single `$switch` branch.
+func newSwitchBranch(v any) (switchBranch, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return switchBranch{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchBranchNotObject,
+			fmt.Sprintf("$switch expected each branch to be an object, found: %s", handlerparams.AliasFromType(v)),
+			"$switch",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "case", "then")
+	if err != nil {
+		return switchBranch{}, err
+	}
+
+	if unknown != "" {
+		return switchBranch{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchBranchUnknownField,
+			fmt.Sprintf("$switch found an unknown argument to a branch: %s", unknown),
+			"$switch",
+		)
+	}
+
+	caseExpr, ok := namedArgs["case"]
+	if !ok {
+		return switchBranch{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchBranchMissingCase,
+			"$switch requires each branch have a 'case' expression",
+			"$switch",
+		)
+	}
+
+	thenExpr, ok := namedArgs["then"]
+	if !ok {
+		return switchBranch{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchBranchMissingThen,
+			"$switch requires each branch have a 'then' expression.",
+			"$switch",
+		)
+	}
+
+	return switchBranch{
+		caseExpr: caseExpr,
+		thenExpr: thenExpr,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns `then` of the first branch which `case` is true, or `default`.
+func (s *switchOp) Process(doc *types.Document, vars *Variables) (any, error) {
+	for _, branch := range s.branches {
+		v, err := evaluate(branch.caseExpr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue(v) {
+			return evaluate(branch.thenExpr, doc, vars)
+		}
+	}
+
+	if s.def == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchNoMatch,
+			"$switch could not find a matching branch for an input, and no default was specified.",
+			"$switch",
+		)
+	}
+
+	return evaluate(s.def, doc, vars)
+}
+
+// check interfaces
+var (
+	_ Operator = (*switchOp)(nil)
+)
@@ -0,0 +1,163 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// defaultTrimChars are the characters removed by `$trim` when `chars` is not specified.
+const defaultTrimChars = "\x00 \t\n\v\f\r\u00a0\u1680" +
+	"\u2000\u2001\u2002\u2003\u2004\u2005\u2006\u2007\u2008\u2009\u200a"
+
+// trim represents `$trim`, `$ltrim`, and `$rtrim` operators.
+//
+//	{ $trim: { input: <string>, chars: <string> } }
+type trim struct {
+	name  string
+	input any
+	chars any
+	left  bool
+	right bool
+}
+
+// newTrim returns `$trim` operator.
+func newTrim(args ...any) (Operator, error) {
+	return newTrimOperator("$trim", true, true, args...)
+}
+
+// newLtrim returns `$ltrim` operator.
+func newLtrim(args ...any) (Operator, error) {
+	return newTrimOperator("$ltrim", true, false, args...)
+}
+
+// newRtrim returns `$rtrim` operator.
+func newRtrim(args ...any) (Operator, error) {
+	return newTrimOperator("$rtrim", false, true, args...)
+}
+
+// newTrimOperator validates named arguments and returns one of trim operators.
+func newTrimOperator(name string, left, right bool, args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrimNotObject,
+			fmt.Sprintf("%s only supports an object as its argument", name),
+			name,
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "input", "chars")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrimUnknownField,
+			fmt.Sprintf("Unrecognized argument to %s: %s. Expected arguments are input and (optionally) chars.", name, unknown),
+			name,
+		)
+	}
+
+	input, ok := namedArgs["input"]
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrimMissingInput,
+			fmt.Sprintf("%s requires an 'input' field", name),
+			name,
+		)
+	}
+
+	return &trim{
+		name:  name,
+		input: input,
+		chars: namedArgs["chars"],
+		left:  left,
+		right: right,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (t *trim) Process(doc *types.Document, vars *Variables) (any, error) {
+	input, err := evaluate(t.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	s, ok := input.(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrimInputBadType,
+			fmt.Sprintf(
+				"%s requires its input to be a string, got %s (of type %s) instead.",
+				t.name, types.FormatAnyValue(input), handlerparams.AliasFromType(input),
+			),
+			t.name,
+		)
+	}
+
+	chars := defaultTrimChars
+
+	if t.chars != nil {
+		v, err := evaluate(t.chars, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		if chars, ok = v.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimCharsBadType,
+				fmt.Sprintf(
+					"%s requires 'chars' to be a string, got %s (of type %s) instead.",
+					t.name, types.FormatAnyValue(v), handlerparams.AliasFromType(v),
+				),
+				t.name,
+			)
+		}
+	}
+
+	if t.left {
+		s = strings.TrimLeft(s, chars)
+	}
+
+	if t.right {
+		s = strings.TrimRight(s, chars)
+	}
+
+	return s, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*trim)(nil)
+)
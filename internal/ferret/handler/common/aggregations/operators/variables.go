@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// Variables represents a scope of aggregation variables,
+// like `$$this` in `$map` or the variable named by its `as` argument.
+//
+// `$$ROOT` and `$$CURRENT` are not stored in scopes, they refer to the processed document;
+// `$$CURRENT` could be rebound with `$let`.
+//
+// Scopes are immutable, nested scopes are created with With.
+// Nil *Variables is a valid empty scope.
+//
+// Scopes also carry the Metadata of the query or pipeline, see WithMetadata.
+type Variables struct {
+	parent *Variables
+	name   string
+	value  any
+	meta   *Metadata
+}
+
+// With returns a nested scope with the variable set to the given value.
+// The variable shadows the one with the same name in the outer scopes.
+func (v *Variables) With(name string, value any) *Variables {
+	return &Variables{
+		parent: v,
+		name:   name,
+		value:  value,
+	}
+}
+
+// WithMetadata returns a nested scope that carries the given metadata.
+func (v *Variables) WithMetadata(m *Metadata) *Variables {
+	return &Variables{
+		parent: v,
+		meta:   m,
+	}
+}
+
+// Metadata returns the metadata from the innermost scope that carries it, or nil.
+func (v *Variables) Metadata() *Metadata {
+	for s := v; s != nil; s = s.parent {
+		if s.meta != nil {
+			return s.meta
+		}
+	}
+
+	return nil
+}
+
+// Collation returns the collation of the scope's metadata, if any.
+func (v *Variables) Collation() *types.Collation {
+	if m := v.Metadata(); m != nil {
+		return m.Collation
+	}
+
+	return nil
+}
+
+// Get returns the value of the variable from the innermost scope that defines it.
+func (v *Variables) Get(name string) (any, bool) {
+	for s := v; s != nil; s = s.parent {
+		if s.meta == nil && s.name == name {
+			return s.value, true
+		}
+	}
+
+	return nil, false
+}
+
+// NewVariables returns the top-level scope of a pipeline or a query.
+//
+// It defines system variables `$$NOW` and `$$CLUSTER_TIME` set to the given time,
+// and user variables from the let document.
+// Values of let are expressions evaluated once without a document;
+// they can use system variables, but not each other.
+func NewVariables(now time.Time, let *types.Document) (*Variables, error) {
+	vars := new(Variables).
+		With("NOW", now.UTC().Truncate(time.Millisecond)).
+		With("CLUSTER_TIME", types.NewTimestamp(now, 1))
+
+	if let == nil {
+		return vars, nil
+	}
+
+	res := vars
+
+	iter := let.Iterator()
+	defer iter.Close()
+
+	for {
+		name, expr, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err = validateVariableName("let", name); err != nil {
+			return nil, err
+		}
+
+		v, err := evaluate(expr, nil, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			// like $$REMOVE
+			continue
+		}
+
+		res = res.With(name, v)
+	}
+
+	return res, nil
+}
+
+// contextKey is a named unexported type for the safe use of context.WithValue.
+type contextKey struct{}
+
+// Context key for WithVariables/GetVariables.
+var variablesKey = contextKey{}
+
+// WithVariables returns a derived context with the given top-level scope.
+func WithVariables(ctx context.Context, vars *Variables) context.Context {
+	return context.WithValue(ctx, variablesKey, vars)
+}
+
+// GetVariables returns the top-level scope stored in ctx.
+//
+// If ctx has no scope, nil is returned; it is a valid empty scope.
+func GetVariables(ctx context.Context) *Variables {
+	vars, _ := ctx.Value(variablesKey).(*Variables)
+	return vars
+}
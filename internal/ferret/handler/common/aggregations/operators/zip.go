@@ -0,0 +1,229 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// zip represents `$zip` operator.
+//
+//	{ $zip: { inputs: [ <array expression1>, ... ], useLongestLength: <boolean>, defaults: <array expression> } }
+type zip struct {
+	inputs           []any
+	defaults         []any
+	useLongestLength bool
+}
+
+// newZip returns `$zip` operator.
+func newZip(args ...any) (Operator, error) {
+	var doc *types.Document
+	if len(args) == 1 {
+		doc, _ = args[0].(*types.Document)
+	}
+
+	if doc == nil {
+		var found any = types.MakeArray(0)
+		if len(args) == 1 {
+			found = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrZipNotObject,
+			fmt.Sprintf("$zip only supports an object as an argument, found %s", handlerparams.AliasFromType(found)),
+			"$zip",
+		)
+	}
+
+	namedArgs, unknown, err := getNamedArgs(doc, "inputs", "useLongestLength", "defaults")
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrZipUnknownField,
+			fmt.Sprintf("$zip found an unknown argument: %s", unknown),
+			"$zip",
+		)
+	}
+
+	inputs := types.MakeArray(0)
+
+	v, ok := namedArgs["inputs"]
+	if ok {
+		if inputs, ok = v.(*types.Array); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipInputsNotArray,
+				fmt.Sprintf("inputs must be an array of expressions, found %s", handlerparams.AliasFromType(v)),
+				"$zip",
+			)
+		}
+	}
+
+	if inputs.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrZipMissingInputs,
+			"$zip requires at least one input array",
+			"$zip",
+		)
+	}
+
+	z := &zip{
+		inputs: arrayValues(inputs),
+	}
+
+	if v, ok = namedArgs["useLongestLength"]; ok {
+		if z.useLongestLength, ok = v.(bool); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipUseLongestLengthBadType,
+				fmt.Sprintf("useLongestLength must be a bool, found %s", handlerparams.AliasFromType(v)),
+				"$zip",
+			)
+		}
+	}
+
+	if v, ok = namedArgs["defaults"]; ok {
+		defaults, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipDefaultsNotArray,
+				fmt.Sprintf("defaults must be an array of expressions, found %s", handlerparams.AliasFromType(v)),
+				"$zip",
+			)
+		}
+
+		if !z.useLongestLength {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipDefaultsWithoutUseLongestLength,
+				"cannot specify defaults unless useLongestLength is true",
+				"$zip",
+			)
+		}
+
+		if defaults.Len() != inputs.Len() {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipDefaultsLength,
+				"defaults and inputs must have the same length",
+				"$zip",
+			)
+		}
+
+		z.defaults = arrayValues(defaults)
+	}
+
+	return z, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns an array of arrays where the n-th array contains the n-th elements of the inputs.
+// The result is as long as the shortest input, or the longest one if useLongestLength is set;
+// missing elements are filled with defaults or nulls.
+func (z *zip) Process(doc *types.Document, vars *Variables) (any, error) {
+	values, err := evaluateArgs(z.inputs, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := make([]*types.Array, len(values))
+
+	var length int
+
+	for i, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipInputBadType,
+				fmt.Sprintf("$zip found a non-array expression in input: %s", types.FormatAnyValue(v)),
+				"$zip",
+			)
+		}
+
+		arrays[i] = arr
+
+		switch {
+		case i == 0:
+			length = arr.Len()
+		case z.useLongestLength:
+			length = max(length, arr.Len())
+		default:
+			length = min(length, arr.Len())
+		}
+	}
+
+	defaults := make([]any, len(arrays))
+
+	for i := range defaults {
+		defaults[i] = types.Null
+
+		if z.defaults == nil {
+			continue
+		}
+
+		v, err := evaluate(z.defaults[i], doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if v != nil {
+			defaults[i] = v
+		}
+	}
+
+	res := types.MakeArray(length)
+
+	for n := 0; n < length; n++ {
+		tuple := types.MakeArray(len(arrays))
+
+		for i, arr := range arrays {
+			if n < arr.Len() {
+				tuple.Append(must.NotFail(arr.Get(n)))
+				continue
+			}
+
+			tuple.Append(defaults[i])
+		}
+
+		res.Append(tuple)
+	}
+
+	return res, nil
+}
+
+// arrayValues returns the values of the array as a slice.
+func arrayValues(arr *types.Array) []any {
+	res := make([]any, arr.Len())
+
+	for i := range res {
+		res[i] = must.NotFail(arr.Get(i))
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Operator = (*zip)(nil)
+)
@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregations
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// contextKey is a named unexported type for the safe use of context.WithValue.
+type contextKey struct{}
+
+// Context key for WithOptions/GetOptions.
+var optionsKey = contextKey{}
+
+// Options represents pipeline-wide settings that are not part of the pipeline itself.
+type Options struct {
+	// Rand is the source of randomness for stages like $sample.
+	// If nil, a time-seeded source is used.
+	Rand *rand.Rand
+
+	// Collections maps collection names to documents that stages like $unionWith can read.
+	// Documents are copied before being passed to the pipeline.
+	Collections map[string][]*types.Document
+
+	// Collation is used by stages like $match and $sort to compare strings.
+	// If nil, strings are compared by their bytes.
+	Collation *types.Collation
+}
+
+// WithOptions returns a derived context with the given Options.
+func WithOptions(ctx context.Context, opts *Options) context.Context {
+	return context.WithValue(ctx, optionsKey, opts)
+}
+
+// GetOptions returns the Options stored in ctx.
+//
+// If ctx has no Options, empty Options are returned.
+func GetOptions(ctx context.Context) *Options {
+	opts, _ := ctx.Value(optionsKey).(*Options)
+	if opts == nil {
+		return new(Options)
+	}
+
+	return opts
+}
+
+// RandSource returns the source of randomness to use.
+func (opts *Options) RandSource() *rand.Rand {
+	if opts.Rand != nil {
+		return opts.Rand
+	}
+
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// Collection returns deep copies of the documents of the named collection.
+//
+// Unknown collections are treated as empty, like in MongoDB.
+func (opts *Options) Collection(name string) []*types.Document {
+	docs := opts.Collections[name]
+
+	res := make([]*types.Document, len(docs))
+	for i, doc := range docs {
+		res[i] = doc.DeepCopy()
+	}
+
+	return res
+}
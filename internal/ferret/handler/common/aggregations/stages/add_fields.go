@@ -20,6 +20,7 @@ import (
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -63,8 +64,8 @@ func newAddFields(stage *types.Document) (aggregations.Stage, error) {
 }
 
 // Process implements Stage interface.
-func (s *addFields) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.AddFieldsIterator(iter, closer, s.newField), nil
+func (s *addFields) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	return common.AddFieldsIterator(iter, closer, s.newField, operators.GetVariables(ctx)), nil
 }
 
 // check interfaces
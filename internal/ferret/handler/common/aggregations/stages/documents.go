@@ -0,0 +1,187 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// documents represents $documents stage.
+//
+//	{ $documents: <expression> }
+//
+// It must be the first stage of a pipeline; documents from the previous input are ignored.
+type documents struct {
+	expression any
+}
+
+// newDocuments validates stage document and creates a new $documents stage.
+func newDocuments(stage *types.Document) (aggregations.Stage, error) {
+	expression, err := stage.Get("$documents")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	switch expression := expression.(type) {
+	case *types.Array:
+		if _, err = documentsFromArray(expression); err != nil {
+			return nil, err
+		}
+
+	case *types.Document:
+		if !operators.IsOperator(expression) {
+			return nil, documentsNotArrayError()
+		}
+
+		if _, err = operators.NewOperator(expression); err != nil {
+			return nil, processDocumentsError(err)
+		}
+
+	default:
+		return nil, documentsNotArrayError()
+	}
+
+	return &documents{
+		expression: expression,
+	}, nil
+}
+
+// Process implements Stage interface.
+func (s *documents) Process(ctx context.Context, _ types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	value := s.expression
+
+	if doc, ok := value.(*types.Document); ok {
+		op, err := operators.NewOperator(doc)
+		if err != nil {
+			return nil, processDocumentsError(err)
+		}
+
+		if value, err = op.Process(new(types.Document), operators.GetVariables(ctx)); err != nil {
+			return nil, processDocumentsError(err)
+		}
+	}
+
+	arr, ok := value.(*types.Array)
+	if !ok {
+		return nil, documentsNotArrayError()
+	}
+
+	docs, err := documentsFromArray(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, doc := range docs {
+		docs[i] = doc.DeepCopy()
+	}
+
+	res := iterator.Values(iterator.ForSlice(docs))
+	closer.Add(res)
+
+	return res, nil
+}
+
+// documentsFromArray returns the documents of $documents array,
+// or an error if some element is not a document.
+func documentsFromArray(arr *types.Array) ([]*types.Document, error) {
+	res := make([]*types.Document, 0, arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		doc, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"$documents elements must be objects, found "+types.FormatAnyValue(v),
+				"$documents (stage)",
+			)
+		}
+
+		res = append(res, doc)
+	}
+
+	return res, nil
+}
+
+// documentsNotArrayError returns an error for $documents value that is not an array.
+func documentsNotArrayError() error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		"error during aggregation :: caused by :: an array is expected",
+		"$documents (stage)",
+	)
+}
+
+// processDocumentsError takes internal error related to operator evaluation and
+// returns proper CommandError that can be returned by $documents aggregation stage.
+func processDocumentsError(err error) error {
+	var opErr operators.OperatorError
+	if !errors.As(err, &opErr) {
+		return err
+	}
+
+	switch opErr.Code() {
+	case operators.ErrTooManyFields:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrExpressionWrongLenOfFields,
+			"An object representing an expression must have exactly one field",
+			"$documents (stage)",
+		)
+	case operators.ErrNotImplemented:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Invalid $documents :: caused by :: "+opErr.Error(),
+			"$documents (stage)",
+		)
+	case operators.ErrArgsInvalidLen:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrOperatorWrongLenOfArgs,
+			"Invalid $documents :: caused by :: "+opErr.Error(),
+			"$documents (stage)",
+		)
+	case operators.ErrInvalidExpression, operators.ErrInvalidNestedExpression:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidPipelineOperator,
+			"Invalid $documents :: caused by :: "+opErr.Error(),
+			"$documents (stage)",
+		)
+	default:
+		return lazyerrors.Error(err)
+	}
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*documents)(nil)
+)
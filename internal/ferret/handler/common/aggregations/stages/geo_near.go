@@ -0,0 +1,291 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/geo"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// geoNear represents $geoNear stage.
+//
+//	{ $geoNear: {
+//	  near: <GeoJSON point or legacy coordinate pair>,
+//	  distanceField: <string>,
+//	  key: <string>,
+//	  spherical: <bool>,
+//	  minDistance: <number>,
+//	  maxDistance: <number>,
+//	  query: <document>,
+//	  distanceMultiplier: <number>,
+//	  includeLocs: <string>
+//	} }
+//
+// There are no geospatial indexes over in-memory documents, so key is required.
+type geoNear struct {
+	near               *geo.Near
+	key                types.Path
+	distanceField      types.Path
+	includeLocs        *types.Path
+	query              *types.Document
+	distanceMultiplier float64
+}
+
+// newGeoNear validates stage document and creates a new $geoNear stage.
+func newGeoNear(stage *types.Document) (aggregations.Stage, error) {
+	fields, err := stage.Get("$geoNear")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	spec, ok := fields.(*types.Document)
+	if !ok {
+		return nil, newGeoNearError(handlererrors.ErrTypeMismatch, "$geoNear argument must be an object")
+	}
+
+	g := &geoNear{
+		distanceMultiplier: 1,
+	}
+
+	var near, minDistance, maxDistance any
+	var spherical, hasDistanceField, hasKey bool
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch key {
+		case "near":
+			near = value
+
+		case "distanceField", "includeLocs", "key":
+			s, ok := value.(string)
+			if !ok {
+				return nil, newGeoNearError(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$geoNear parameter '%s' must be of type string but found type: %s", key, handlerparams.AliasFromType(value)),
+				)
+			}
+
+			if s == "" {
+				return nil, newGeoNearError(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("$geoNear parameter '%s' cannot be the empty string", key),
+				)
+			}
+
+			path, err := types.NewPathFromString(s)
+			if err != nil {
+				return nil, newGeoNearError(handlererrors.ErrBadValue, fmt.Sprintf("invalid path for '%s': %s", key, s))
+			}
+
+			switch key {
+			case "distanceField":
+				g.distanceField = path
+				hasDistanceField = true
+			case "includeLocs":
+				g.includeLocs = &path
+			case "key":
+				g.key = path
+				hasKey = true
+			}
+
+		case "spherical":
+			if spherical, ok = value.(bool); !ok {
+				return nil, newGeoNearError(handlererrors.ErrTypeMismatch, "spherical must be a boolean")
+			}
+
+		case "minDistance":
+			minDistance = value
+
+		case "maxDistance":
+			maxDistance = value
+
+		case "query":
+			if g.query, ok = value.(*types.Document); !ok {
+				return nil, newGeoNearError(handlererrors.ErrTypeMismatch, "query must be an object")
+			}
+
+			if common.HasNearOperator(g.query) {
+				return nil, newGeoNearError(handlererrors.ErrBadValue, nearNotAllowedMsg)
+			}
+
+		case "distanceMultiplier":
+			var m float64
+
+			switch v := value.(type) {
+			case float64:
+				m = v
+			case int32:
+				m = float64(v)
+			case int64:
+				m = float64(v)
+			default:
+				m = -1
+			}
+
+			if !(m >= 0) {
+				return nil, newGeoNearError(handlererrors.ErrBadValue, "distanceMultiplier must be a non-negative number")
+			}
+
+			g.distanceMultiplier = m
+
+		default:
+			return nil, newGeoNearError(handlererrors.ErrBadValue, fmt.Sprintf("Unknown argument to $geoNear: %s", key))
+		}
+	}
+
+	if near == nil {
+		return nil, newGeoNearError(handlererrors.ErrBadValue, "$geoNear requires a 'near' argument")
+	}
+
+	if !hasDistanceField {
+		return nil, newGeoNearError(handlererrors.ErrBadValue, "$geoNear requires a 'distanceField' option as a String")
+	}
+
+	if !hasKey {
+		return nil, newGeoNearError(
+			handlererrors.ErrIndexNotFound,
+			"$geoNear requires a 'key' option because there are no geospatial indexes",
+		)
+	}
+
+	if g.near, err = geo.NewNear("$geoNear", near, spherical, minDistance, maxDistance); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// nearNotAllowedMsg is the error message for $near and $nearSphere in aggregation pipelines.
+const nearNotAllowedMsg = "$geoNear, $near, and $nearSphere are not allowed in this context, " +
+	"as these operators require sorting geospatial data. If you do not need sort, consider using $geoWithin instead."
+
+// newGeoNearError returns a new error for the invalid $geoNear stage.
+func newGeoNearError(code handlererrors.ErrorCode, msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(code, msg, "$geoNear (stage)")
+}
+
+// Process implements Stage interface.
+//
+// It returns documents that match the query and have a location in the distance range at the key path,
+// from the nearest to the farthest, with distances in the distance field.
+func (g *geoNear) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	vars := operators.GetVariables(ctx)
+	coll := aggregations.GetOptions(ctx).Collation
+
+	type result struct {
+		doc      *types.Document
+		distance float64
+		location any
+	}
+
+	results := make([]result, 0, len(docs))
+
+	for _, doc := range docs {
+		if g.query != nil {
+			matches, err := common.FilterDocumentWithCollation(doc, g.query, vars, coll)
+			if err != nil {
+				return nil, err
+			}
+
+			if !matches {
+				continue
+			}
+		}
+
+		v, err := doc.GetByPath(g.key)
+		if err != nil {
+			continue
+		}
+
+		r := result{doc: doc, distance: math.Inf(1)}
+
+		for _, loc := range geo.ParseLocations(v) {
+			if d := g.near.Distance(loc.Geometry); d < r.distance {
+				r.distance = d
+				r.location = loc.Value
+			}
+		}
+
+		if math.IsInf(r.distance, 1) || !g.near.InRange(r.distance) {
+			continue
+		}
+
+		results = append(results, r)
+	}
+
+	slices.SortStableFunc(results, func(a, b result) int { return cmp.Compare(a.distance, b.distance) })
+
+	res := make([]*types.Document, len(results))
+
+	for i, r := range results {
+		distance := r.distance * g.distanceMultiplier
+
+		if err = r.doc.SetByPath(g.distanceField, distance); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if g.includeLocs != nil {
+			if err = r.doc.SetByPath(*g.includeLocs, r.location); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if meta := vars.Metadata(); meta != nil {
+			meta.SetGeoNearDistance(r.doc, distance)
+		}
+
+		res[i] = r.doc
+	}
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*geoNear)(nil)
+)
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
@@ -118,7 +119,9 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 
 // Process implements Stage interface.
 func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	groupedDocuments, err := g.groupDocuments(iter)
+	vars := operators.GetVariables(ctx)
+
+	groupedDocuments, err := g.groupDocuments(iter, vars)
 	if err != nil {
 		return nil, err
 	}
@@ -128,11 +131,12 @@ func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, close
 	for _, groupedDocument := range groupedDocuments {
 		doc := must.NotFail(types.NewDocument("_id", groupedDocument.groupID))
 
-		groupIter := iterator.Values(iterator.ForSlice(groupedDocument.documents))
-		defer groupIter.Close()
-
 		for _, accumulation := range g.groupBy {
-			out, err := accumulation.accumulator.Accumulate(groupIter)
+			// each accumulator consumes its own iterator over the group
+			groupIter := iterator.Values(iterator.ForSlice(groupedDocument.documents))
+			defer groupIter.Close()
+
+			out, err := accumulation.accumulator.Accumulate(groupIter, vars)
 			if err != nil {
 				// existing accumulators do not return error
 				return nil, processGroupStageError(err)
@@ -173,8 +177,7 @@ func validateGroupKey(groupKey any) error {
 			return processGroupStageError(err)
 		}
 
-		_, err = op.Process(nil)
-		if err != nil {
+		if err = operators.Validate(op); err != nil {
 			// TODO https://github.com/FerretDB/FerretDB/issues/3129
 			return processGroupStageError(err)
 		}
@@ -208,6 +211,11 @@ func validateGroupKey(groupKey any) error {
 		case *types.Document:
 			return validateGroupKey(v)
 		case string:
+			if strings.HasPrefix(v, "$$") {
+				// variables are resolved during evaluation
+				continue
+			}
+
 			_, err := aggregations.NewExpression(v, nil)
 			var exprErr *aggregations.ExpressionError
 
@@ -225,9 +233,9 @@ func validateGroupKey(groupKey any) error {
 }
 
 // groupDocuments groups documents into groups using group key. If group key contains expressions
-// or operators, they are evaluated before using it as the group key of documents.
-func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments, error) {
-	var m groupMap
+// or operators, they are evaluated with the given variables before using it as the group key of documents.
+func (g *group) groupDocuments(iter types.DocumentsIterator, vars *operators.Variables) ([]groupedDocuments, error) {
+	m := groupMap{coll: vars.Collation()}
 
 	for {
 		_, doc, err := iter.Next()
@@ -241,7 +249,7 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 
 		switch groupKey := g.groupExpression.(type) {
 		case *types.Document:
-			val, err := evaluateDocument(groupKey, doc, false)
+			val, err := evaluateDocument(groupKey, doc, false, vars)
 			if err != nil {
 				// operator and expression errors are validated in newGroup
 				return nil, lazyerrors.Error(err)
@@ -249,9 +257,24 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 
 			m.addOrAppend(val, doc)
 		case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time, types.NullType,
-			types.Regex, int32, types.Timestamp, int64:
+			types.Regex, int32, types.Timestamp, int64, types.Decimal128:
 			m.addOrAppend(groupKey, doc)
 		case string:
+			if strings.HasPrefix(groupKey, "$$") {
+				val, err := operators.Evaluate(groupKey, doc, vars)
+				if err != nil {
+					return nil, processGroupStageError(err)
+				}
+
+				if val == nil {
+					val = types.Null
+				}
+
+				m.addOrAppend(val, doc)
+
+				continue
+			}
+
 			expression, err := aggregations.NewExpression(groupKey, nil)
 			if err != nil {
 				var exprErr *aggregations.ExpressionError
@@ -283,7 +306,7 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 }
 
 // evaluateDocument recursively evaluates document's field expressions and operators.
-func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error) {
+func evaluateDocument(expr, doc *types.Document, nestedField bool, vars *operators.Variables) (any, error) {
 	if operators.IsOperator(expr) {
 		op, err := operators.NewOperator(expr)
 		if err != nil {
@@ -291,12 +314,17 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 			return nil, processGroupStageError(err)
 		}
 
-		v, err := op.Process(doc)
+		v, err := op.Process(doc, vars)
 		if err != nil {
 			// operator and expression errors are validated in newGroup
 			return nil, processGroupStageError(err)
 		}
 
+		if v == nil {
+			// missing values are grouped as null
+			return types.Null, nil
+		}
+
 		return v, nil
 	}
 
@@ -317,13 +345,32 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 
 		switch exprVal := exprVal.(type) {
 		case *types.Document:
-			v, err := evaluateDocument(exprVal, doc, true)
+			v, err := evaluateDocument(exprVal, doc, true, vars)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
 
 			evaluatedDocument.Set(k, v)
 		case string:
+			if strings.HasPrefix(exprVal, "$$") {
+				v, err := operators.Evaluate(exprVal, doc, vars)
+				if err != nil {
+					return nil, processGroupStageError(err)
+				}
+
+				if v == nil {
+					if expr.Len() == 1 && !nestedField {
+						evaluatedDocument.Set(k, types.Null)
+					}
+
+					continue
+				}
+
+				evaluatedDocument.Set(k, v)
+
+				continue
+			}
+
 			expression, err := aggregations.NewExpression(exprVal, nil)
 
 			var exprErr *aggregations.ExpressionError
@@ -365,6 +412,7 @@ type groupedDocuments struct {
 // groupMap holds groups of documents.
 type groupMap struct {
 	docs []groupedDocuments
+	coll *types.Collation // compares string keys
 }
 
 // addOrAppend adds a groupID documents pair if the groupID does not exist,
@@ -375,7 +423,7 @@ func (m *groupMap) addOrAppend(groupKey any, docs ...*types.Document) {
 		// so we cannot use structure like map.
 		// Compare is used to check if groupID exists in groupMap, because
 		// numbers are grouped for the same value regardless of their number type.
-		if types.CompareForAggregation(groupKey, g.groupID) == types.Equal {
+		if m.coll.CompareForAggregation(groupKey, g.groupID) == types.Equal {
 			m.docs[i].documents = append(m.docs[i].documents, docs...)
 			return
 		}
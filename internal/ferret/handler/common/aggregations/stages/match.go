@@ -52,11 +52,17 @@ func newMatch(stage *types.Document) (aggregations.Stage, error) {
 
 // Process implements Stage interface.
 func (m *match) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.FilterIterator(iter, closer, m.filter), nil
+	coll := aggregations.GetOptions(ctx).Collation
+
+	return common.FilterIterator(iter, closer, m.filter, operators.GetVariables(ctx), coll), nil
 }
 
-// validateMatch validates $expr field if any.
+// validateMatch validates $expr field if any and rejects $near and $nearSphere.
 func validateMatch(filter *types.Document) error {
+	if common.HasNearOperator(filter) {
+		return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, nearNotAllowedMsg, "$match (stage)")
+	}
+
 	if filter.Has("$expr") {
 		_, err := operators.NewExpr(filter, "$match (stage)")
 		if err != nil {
@@ -0,0 +1,106 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// Pipeline represents a sequence of aggregation stages that is processed in memory.
+//
+// It is used for pipelines that are not backed by a collection query,
+// such as $unionWith sub-pipelines.
+type Pipeline []aggregations.Stage
+
+// NewPipeline validates the given array of stage documents and creates a new Pipeline.
+//
+// The argument is used in error messages.
+func NewPipeline(pipeline *types.Array, argument string) (Pipeline, error) {
+	values := must.NotFail(iterator.ConsumeValues(pipeline.Iterator()))
+	res := make(Pipeline, 0, len(values))
+
+	for i, v := range values {
+		d, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"Each element of the 'pipeline' array must be an object",
+				argument,
+			)
+		}
+
+		s, err := NewStage(d)
+		if err != nil {
+			return nil, err
+		}
+
+		switch name := d.Command(); name {
+		case "$collStats":
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCollStatsIsNotFirstStage,
+					name+" is only valid as the first stage in a pipeline",
+					argument,
+				)
+			}
+		case "$documents":
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrDocumentsIsNotFirstStage,
+					name+" is only valid as the first stage in a pipeline",
+					argument,
+				)
+			}
+		case "$geoNear":
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrGeoNearNotFirstStage,
+					"$geoNear was not the first stage in the pipeline.",
+					argument,
+				)
+			}
+		}
+
+		res = append(res, s)
+	}
+
+	return res, nil
+}
+
+// Process implements Stage interface.
+//
+// It applies all stages in order.
+func (p Pipeline) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	var err error
+
+	for _, s := range p {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = Pipeline(nil)
+)
@@ -19,6 +19,7 @@ import (
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/stages/projection"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
@@ -63,8 +64,8 @@ func newProject(stage *types.Document) (aggregations.Stage, error) {
 // Process implements Stage interface.
 //
 //nolint:lll // for readability
-func (p *project) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
-	return projection.ProjectionIterator(iter, closer, p.projection)
+func (p *project) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
+	return projection.ProjectionIterator(iter, closer, p.projection, operators.GetVariables(ctx))
 }
 
 // check interfaces
@@ -45,8 +45,12 @@ import (
 //   - `ErrWrongPositionalOperatorLocation` when there are multiple `$`;
 //   - `ErrAggregatePositionalProject` when `$` is used in the suffix key;
 //   - `ErrAggregatePositionalProject` when positional projection contains empty path;
+//   - `ErrEmptySubProject` when sub-projection document is empty;
 //   - `ErrNotImplemented` when there is unimplemented projection operators and expressions.
 //
+// Sub-projections like {a: {b: 1}} are returned with dotted paths like {"a.b": true}.
+// Field paths, arrays and operators are aggregation expressions, other values are literals.
+//
 //nolint:goconst // remove it when you change it
 func ValidateProjection(projection *types.Document) (*types.Document, bool, error) {
 	validated := types.MakeDocument(0)
@@ -59,6 +63,11 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 		)
 	}
 
+	projection, err := flattenSubProjections(projection)
+	if err != nil {
+		return nil, false, err
+	}
+
 	var projectionVal *bool
 
 	iter := projection.Iterator()
@@ -145,15 +154,7 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 		switch value := value.(type) {
 		case *types.Document:
 			if !operators.IsOperator(value) {
-				if value.Len() == 0 {
-					return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
-						handlererrors.ErrEmptySubProject,
-						"Invalid $project :: caused by :: An empty sub-projection is not a valid value."+
-							" Found empty object at path",
-						"$project (stage)",
-					)
-				}
-
+				// expression object of _id, sub-projections of other fields are flattened
 				validated.Set(key, value)
 				result = true
 
@@ -165,8 +166,7 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				return nil, false, err
 			}
 
-			_, err = op.Process(must.NotFail(types.NewDocument("key", "value")))
-			if err = processOperatorError(err); err != nil {
+			if err = processOperatorError(operators.Validate(op)); err != nil {
 				return nil, false, err
 			}
 
@@ -180,7 +180,7 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 			result = true
 
 			validated.Set(key, value)
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			// projection treats 0 as false and any other value as true
 			comparison := types.Compare(value, int32(0))
 
@@ -239,10 +239,12 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 }
 
 // ProjectDocument applies projection to the copy of the document.
-func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Document, error) {
-	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
-	if err != nil {
-		return nil, err
+// Aggregation expressions are evaluated with the given variables.
+func ProjectDocument(doc, projection *types.Document, inclusion bool, vars *operators.Variables) (*types.Document, error) {
+	projected := types.MakeDocument(1)
+
+	if id, err := doc.Get("_id"); err == nil {
+		projected.Set("_id", id)
 	}
 
 	if projection.Has("_id") {
@@ -251,31 +253,21 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 		var set bool
 
 		switch idValue := idValue.(type) {
-		case *types.Document: // field: { $elemMatch: { field2: value }}
-			var op operators.Operator
-			var value any
-
-			if !operators.IsOperator(idValue) {
-				projected.Set("_id", idValue)
-				set = true
-
-				break
-			}
-
-			op, err = operators.NewOperator(idValue)
+		case *types.Document, *types.Array, string: // expressions
+			value, err := operators.Evaluate(idValue, doc, vars)
 			if err != nil {
 				return nil, processOperatorError(err)
 			}
 
-			value, err = op.Process(doc)
-			if err != nil {
-				return nil, err
+			if value == nil {
+				// the expression evaluated to a missing value
+				break
 			}
 
 			set = true
 			projected.Set("_id", value)
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
@@ -293,7 +285,7 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 		}
 	}
 
-	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, inclusion)
+	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, inclusion, vars)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2633
 		return nil, err
@@ -308,7 +300,7 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 
 // projectDocumentWithoutID applies projection to the copy of the document and returns projected document.
 // It ignores _id field in the projection.
-func projectDocumentWithoutID(doc *types.Document, projection *types.Document, inclusion bool) (*types.Document, error) {
+func projectDocumentWithoutID(doc *types.Document, projection *types.Document, inclusion bool, vars *operators.Variables) (*types.Document, error) { //nolint:lll // for readability
 	projectionWithoutID := projection.DeepCopy()
 	projectionWithoutID.Remove("_id")
 
@@ -321,6 +313,10 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 		projected = docWithoutID.DeepCopy()
 	}
 
+	// computed fields are set after included fields, like MongoDB does
+	var computedPaths []types.Path
+	var computedValues []any
+
 	iter := projectionWithoutID.Iterator()
 	defer iter.Close()
 
@@ -341,30 +337,24 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 		}
 
 		switch value := value.(type) { // found in the projection
-		case *types.Document: // field: { $elemMatch: { field2: value }}
-			var op operators.Operator
-			var v any
-
-			if !operators.IsOperator(value) {
-				projected.Set(key, value)
-				break
-			}
-
-			op, err = operators.NewOperator(value)
+		case *types.Document, *types.Array, string: // expressions
+			v, err := operators.Evaluate(value, doc, vars)
 			if err != nil {
 				return nil, processOperatorError(err)
 			}
 
-			v, err = op.Process(doc)
-			if err != nil {
-				return nil, err
+			if v == nil {
+				// the expression evaluated to a missing value
+				break
 			}
 
-			projected.Set(key, v)
+			computedPaths = append(computedPaths, path)
+			computedValues = append(computedValues, v)
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
-			projected.Set(key, value)
+			computedPaths = append(computedPaths, path)
+			computedValues = append(computedValues, value)
 
 		case bool: // field: bool
 			if inclusion {
@@ -383,9 +373,133 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 		}
 	}
 
+	for i, path := range computedPaths {
+		setComputedField(path, computedValues[i], docWithoutID, projected)
+	}
+
 	return projected, nil
 }
 
+// setComputedField sets the value of the computed field on the path in projected.
+// Missing or non-document values on the path are replaced with documents.
+// Missing fields with documents or arrays in the source get the documents of the source,
+// like an inclusion of the path.
+// When an array is on the path, the field is set in each element of the array.
+//
+//	Example: "v.foo" path with value 1:
+//	{}                      -> {v: {foo: 1}}
+//	{v: 42}                 -> {v: {foo: 1}}
+//	{v: {bar: 1}}           -> {v: {bar: 1, foo: 1}}
+//	{v: [{bar: 1}, 42]}     -> {v: [{bar: 1, foo: 1}, {foo: 1}]}
+//
+//	Example: "v.foo" path with value 1 and source {v: [{bar: 1}, 42]}:
+//	{}                      -> {v: [{foo: 1}]}
+func setComputedField(path types.Path, value any, source, projected *types.Document) {
+	key := path.Prefix()
+
+	if path.Len() == 1 {
+		projected.Set(key, value)
+		return
+	}
+
+	var embeddedSource any
+	if source != nil {
+		embeddedSource, _ = source.Get(key)
+	}
+
+	embedded, err := projected.Get(key)
+	if err != nil {
+		// the field is not included, use documents of the source
+		switch embeddedSource := embeddedSource.(type) {
+		case *types.Document:
+			embedded = types.MakeDocument(1)
+		case *types.Array:
+			arr := types.MakeArray(embeddedSource.Len())
+
+			for i := 0; i < embeddedSource.Len(); i++ {
+				if _, ok := must.NotFail(embeddedSource.Get(i)).(*types.Document); ok {
+					arr.Append(types.MakeDocument(1))
+				}
+			}
+
+			embedded = arr
+		}
+	}
+
+	switch embedded := embedded.(type) {
+	case *types.Document:
+		sourceDoc, _ := embeddedSource.(*types.Document)
+		setComputedField(path.TrimPrefix(), value, sourceDoc, embedded)
+		projected.Set(key, embedded)
+	case *types.Array:
+		for i := 0; i < embedded.Len(); i++ {
+			elem, ok := must.NotFail(embedded.Get(i)).(*types.Document)
+			if !ok {
+				elem = types.MakeDocument(1)
+			}
+
+			setComputedField(path.TrimPrefix(), value, nil, elem)
+			must.NoError(embedded.Set(i, elem))
+		}
+
+		projected.Set(key, embedded)
+	default:
+		doc := types.MakeDocument(1)
+		setComputedField(path.TrimPrefix(), value, nil, doc)
+		projected.Set(key, doc)
+	}
+}
+
+// flattenSubProjections returns the projection with sub-projections like {a: {b: 1}}
+// replaced with dotted paths like {"a.b": 1}.
+// Operator expressions and the value of _id are not sub-projections.
+//
+// Command error codes:
+// - ErrEmptySubProject when sub-projection is empty.
+func flattenSubProjections(projection *types.Document) (*types.Document, error) {
+	res := types.MakeDocument(projection.Len())
+
+	iter := projection.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		sub, ok := value.(*types.Document)
+		if !ok || key == "_id" || operators.IsOperator(sub) {
+			res.Set(key, value)
+			continue
+		}
+
+		if sub.Len() == 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrEmptySubProject,
+				"Invalid $project :: caused by :: An empty sub-projection is not a valid value."+
+					" Found empty object at path",
+				"$project (stage)",
+			)
+		}
+
+		flattened, err := flattenSubProjections(sub)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subKey := range flattened.Keys() {
+			res.Set(key+"."+subKey, must.NotFail(flattened.Get(subKey)))
+		}
+	}
+
+	return res, nil
+}
+
 // includeProjection copies the field on the path from source to projected.
 // When an array is on the path, it returns the array containing any document
 // with the same key. Dot notation with array index path does not include
@@ -711,5 +825,11 @@ func processOperatorError(err error) error {
 		}
 	}
 
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) {
+		// operator evaluation error, for example, division by zero
+		return err
+	}
+
 	return lazyerrors.Error(err)
 }
@@ -15,6 +15,7 @@
 package projection
 
 import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
@@ -24,9 +25,10 @@ import (
 // It will be added to the given closer.
 //
 // Next method returns the next projected document.
+// Aggregation expressions are evaluated with the given variables.
 //
 // Close method closes the underlying iterator.
-func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, projection *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
+func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, projection *types.Document, vars *operators.Variables) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	projectionValidated, inclusion, err := ValidateProjection(projection)
 	if err != nil {
 		return nil, err
@@ -36,6 +38,7 @@ func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiClos
 		iter:       iter,
 		projection: projectionValidated,
 		inclusion:  inclusion,
+		vars:       vars,
 	}
 	closer.Add(res)
 
@@ -47,6 +50,7 @@ type projectionIterator struct {
 	iter       types.DocumentsIterator
 	projection *types.Document
 	inclusion  bool
+	vars       *operators.Variables
 }
 
 // Next implements iterator.Interface. See ProjectionIterator for details.
@@ -58,11 +62,14 @@ func (iter *projectionIterator) Next() (struct{}, *types.Document, error) {
 		return unused, nil, lazyerrors.Error(err)
 	}
 
-	projected, err := ProjectDocument(doc, iter.projection, iter.inclusion)
+	projected, err := ProjectDocument(doc, iter.projection, iter.inclusion, iter.vars)
 	if err != nil {
 		return unused, nil, err
 	}
 
+	// keep text scores for the following stages
+	iter.vars.Metadata().Copy(doc, projected)
+
 	return unused, projected, nil
 }
 
@@ -0,0 +1,153 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// sample represents $sample stage.
+//
+//	{ $sample: { size: <positive integer N> } }
+type sample struct {
+	size int64
+}
+
+// newSample validates stage document and creates a new $sample stage.
+func newSample(stage *types.Document) (aggregations.Stage, error) {
+	fields, err := stage.Get("$sample")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	fieldsDoc, ok := fields.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageSampleNotObject,
+			"the $sample stage specification must be an object",
+			"$sample (stage)",
+		)
+	}
+
+	var size int64
+	var sizeSet bool
+
+	iter := fieldsDoc.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if key != "size" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageSampleUnknownOption,
+				fmt.Sprintf("unrecognized option to $sample: %s", key),
+				"$sample (stage)",
+			)
+		}
+
+		switch v := value.(type) {
+		case float64:
+			if math.IsNaN(v) {
+				size = 0
+				break
+			}
+
+			size = int64(math.Max(math.Min(v, math.MaxInt64), math.MinInt64))
+		case int32:
+			size = int64(v)
+		case int64:
+			size = v
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageSampleSizeNotNumber,
+				"size argument to $sample must be a number",
+				"$sample (stage)",
+			)
+		}
+
+		if size < 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageSampleSizeNegative,
+				"size argument to $sample must not be negative",
+				"$sample (stage)",
+			)
+		}
+
+		sizeSet = true
+	}
+
+	if !sizeSet {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageSampleNoSize,
+			"$sample stage must specify a size",
+			"$sample (stage)",
+		)
+	}
+
+	return &sample{
+		size: size,
+	}, nil
+}
+
+// Process implements Stage interface.
+//
+// It picks documents uniformly at random without replacement
+// using the random source from aggregations.Options.
+func (s *sample) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	n := len(docs)
+	if s.size < int64(n) {
+		n = int(s.size)
+	}
+
+	r := aggregations.GetOptions(ctx).RandSource()
+
+	// partial Fisher-Yates shuffle: the first n documents are the sample
+	for i := 0; i < n; i++ {
+		j := i + r.Intn(len(docs)-i)
+		docs[i], docs[j] = docs[j], docs[i]
+	}
+
+	res := iterator.Values(iterator.ForSlice(docs[:n]))
+	closer.Add(res)
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*sample)(nil)
+)
@@ -20,6 +20,7 @@ import (
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -61,7 +62,9 @@ func newSort(stage *types.Document) (aggregations.Stage, error) {
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func (s *sort) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	iter, err := common.SortIterator(iter, closer, s.fields)
+	vars := operators.GetVariables(ctx)
+
+	iter, err := common.SortIterator(iter, closer, s.fields, vars, aggregations.GetOptions(ctx).Collation)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/3125
 		var pathErr *types.PathError
@@ -32,10 +32,13 @@ var Stages = map[string]newStageFunc{
 	"$addFields": newAddFields,
 	"$collStats": newCollStats,
 	"$count":     newCount,
+	"$documents": newDocuments,
+	"$geoNear":   newGeoNear,
 	"$group":     newGroup,
 	"$limit":     newLimit,
 	"$match":     newMatch,
 	"$project":   newProject,
+	"$sample":    newSample,
 	"$set":       newSet,
 	"$skip":      newSkip,
 	"$sort":      newSort,
@@ -52,10 +55,8 @@ var unsupportedStages = map[string]struct{}{
 	"$changeStream":           {},
 	"$currentOp":              {},
 	"$densify":                {},
-	"$documents":              {},
 	"$facet":                  {},
 	"$fill":                   {},
-	"$geoNear":                {},
 	"$graphLookup":            {},
 	"$indexStats":             {},
 	"$listLocalSessions":      {},
@@ -67,13 +68,11 @@ var unsupportedStages = map[string]struct{}{
 	"$redact":                 {},
 	"$replaceRoot":            {},
 	"$replaceWith":            {},
-	"$sample":                 {},
 	"$search":                 {},
 	"$searchMeta":             {},
 	"$setWindowFields":        {},
 	"$sharedDataDistribution": {},
 	"$sortByCount":            {},
-	"$unionWith":              {},
 	// please keep sorted alphabetically
 }
 
@@ -0,0 +1,194 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// unionWith represents $unionWith stage.
+//
+//	{ $unionWith: <collection> }
+//	{ $unionWith: { coll: <collection>, pipeline: [ <stage1>, ... ] } }
+//
+// Collections are read from aggregations.Options; unknown collections are empty.
+type unionWith struct {
+	coll     string
+	pipeline Pipeline
+}
+
+// $unionWith is added to Stages in init to avoid initialization cycle:
+// it creates sub-pipeline stages with NewStage which uses Stages.
+func init() {
+	Stages["$unionWith"] = newUnionWith
+}
+
+// newUnionWith validates stage document and creates a new $unionWith stage.
+func newUnionWith(stage *types.Document) (aggregations.Stage, error) {
+	spec, err := stage.Get("$unionWith")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	switch spec := spec.(type) {
+	case string:
+		return &unionWith{
+			coll: spec,
+		}, nil
+
+	case *types.Document:
+		return newUnionWithFromDocument(spec)
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"the $unionWith stage specification must be an object or string, but found %s",
+				handlerparams.AliasFromType(spec),
+			),
+			"$unionWith (stage)",
+		)
+	}
+}
+
+// newUnionWithFromDocument creates a new $unionWith stage from the document form of the specification.
+func newUnionWithFromDocument(spec *types.Document) (aggregations.Stage, error) {
+	var res unionWith
+	var collSet bool
+	var pipeline *types.Array
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch key {
+		case "coll":
+			coll, ok := value.(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '$unionWith.coll' is the wrong type '%s', expected type 'string'",
+						handlerparams.AliasFromType(value),
+					),
+					"$unionWith (stage)",
+				)
+			}
+
+			res.coll = coll
+			collSet = true
+
+		case "pipeline":
+			var ok bool
+			if pipeline, ok = value.(*types.Array); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '$unionWith.pipeline' is the wrong type '%s', expected type 'array'",
+						handlerparams.AliasFromType(value),
+					),
+					"$unionWith (stage)",
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$unionWith.%s' is an unknown field.", key),
+				"$unionWith (stage)",
+			)
+		}
+	}
+
+	if pipeline != nil {
+		var err error
+		if res.pipeline, err = NewPipeline(pipeline, "$unionWith (stage)"); err != nil {
+			return nil, err
+		}
+	}
+
+	if !collSet && !startsWithDocuments(pipeline) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$unionWith stage without explicit collection must have a pipeline with $documents as first stage",
+			"$unionWith (stage)",
+		)
+	}
+
+	return &res, nil
+}
+
+// startsWithDocuments returns true if the first stage of the given pipeline is $documents.
+func startsWithDocuments(pipeline *types.Array) bool {
+	if pipeline == nil || pipeline.Len() == 0 {
+		return false
+	}
+
+	first, ok := must.NotFail(pipeline.Get(0)).(*types.Document)
+
+	return ok && first.Command() == "$documents"
+}
+
+// Process implements Stage interface.
+//
+// It returns all documents from the input followed by the documents
+// produced by the sub-pipeline from the other collection.
+func (s *unionWith) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var unionIter types.DocumentsIterator = iterator.Values(iterator.ForSlice(aggregations.GetOptions(ctx).Collection(s.coll)))
+	closer.Add(unionIter)
+
+	if unionIter, err = s.pipeline.Process(ctx, unionIter, closer); err != nil {
+		return nil, err
+	}
+
+	unionDocs, err := iterator.ConsumeValues(unionIter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := iterator.Values(iterator.ForSlice(append(docs, unionDocs...)))
+	closer.Add(res)
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*unionWith)(nil)
+)
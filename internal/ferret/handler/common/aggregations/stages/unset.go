@@ -141,7 +141,7 @@ func newUnset(stage *types.Document) (aggregations.Stage, error) {
 // Process implements Stage interface.
 func (u *unset) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// Use $project to unset fields, $unset is alias for $project exclusion.
-	return projection.ProjectionIterator(iter, closer, u.exclusion)
+	return projection.ProjectionIterator(iter, closer, u.exclusion, nil)
 }
 
 // validateUnsetField returns error on invalid field value.
@@ -18,11 +18,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
-	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/commonpath"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
@@ -30,84 +31,209 @@ import (
 )
 
 // unwind represents $unwind stage.
+//
+//	{ $unwind: <field path> }
+//	{ $unwind: {
+//	  path: <field path>,
+//	  includeArrayIndex: <string>,
+//	  preserveNullAndEmptyArrays: <boolean>
+//	} }
 type unwind struct {
-	field *aggregations.Expression
+	path                       types.Path
+	indexPath                  *types.Path
+	preserveNullAndEmptyArrays bool
 }
 
-// newUnwind creates a new $unwind stage.
+// newUnwind validates stage document and creates a new $unwind stage.
 func newUnwind(stage *types.Document) (aggregations.Stage, error) {
 	field, err := stage.Get("$unwind")
 	if err != nil {
 		return nil, err
 	}
 
-	var expr *aggregations.Expression
+	var u unwind
+	var path string
 
 	switch field := field.(type) {
 	case *types.Document:
-		return nil, common.Unimplemented(stage, "$unwind")
-	case string:
-		if field == "" {
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrStageUnwindNoPath,
-				"no path specified to $unwind stage",
-				"$unwind (stage)",
-			)
-		}
+		iter := field.Iterator()
+		defer iter.Close()
 
-		// For $unwind to deconstruct an array from dot notation, array must be at the suffix.
-		// It returns empty result if array is found at other parts of dot notation,
-		// so it does not return value by index of array nor values for given key in array's document.
-		expr, err = aggregations.NewExpression(field, &commonpath.FindValuesOpts{
-			FindArrayIndex:     false,
-			FindArrayDocuments: false,
-		})
-		if err != nil {
-			var exprErr *aggregations.ExpressionError
-			if !errors.As(err, &exprErr) {
+		for {
+			key, value, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
 
-			switch exprErr.Code() {
-			case aggregations.ErrNotExpression:
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrStageUnwindNoPrefix,
-					fmt.Sprintf("path option to $unwind stage should be prefixed with a '$': %v", types.FormatAnyValue(field)),
-					"$unwind (stage)",
-				)
-			case aggregations.ErrEmptyFieldPath:
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrEmptyFieldPath,
-					"Expression cannot be constructed with empty string",
-					"$unwind (stage)",
-				)
-			case aggregations.ErrEmptyVariable, aggregations.ErrInvalidExpression, aggregations.ErrUndefinedVariable:
+			switch key {
+			case "path":
+				var ok bool
+				if path, ok = value.(string); !ok {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrStageUnwindPathType,
+						fmt.Sprintf("expected a string as the path for $unwind stage, got %s", handlerparams.AliasFromType(value)),
+						"$unwind (stage)",
+					)
+				}
+
+			case "preserveNullAndEmptyArrays":
+				var ok bool
+				if u.preserveNullAndEmptyArrays, ok = value.(bool); !ok {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrStageUnwindPreserveType,
+						fmt.Sprintf(
+							"expected a boolean for the preserveNullAndEmptyArrays option to $unwind stage, got %s",
+							handlerparams.AliasFromType(value),
+						),
+						"$unwind (stage)",
+					)
+				}
+
+			case "includeArrayIndex":
+				if u.indexPath, err = newUnwindIndexPath(value); err != nil {
+					return nil, err
+				}
+
+			default:
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrFieldPathInvalidName,
-					"Expression field names may not start with '$'. Consider using $getField or $setField",
+					handlererrors.ErrStageUnwindUnknownOption,
+					fmt.Sprintf("unrecognized option to $unwind stage: %s", key),
 					"$unwind (stage)",
 				)
-			default:
-				return nil, lazyerrors.Error(err)
 			}
 		}
+	case string:
+		path = field
 	default:
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrStageUnwindWrongType,
 			fmt.Sprintf(
 				"expected either a string or an object as specification for $unwind stage, got %s",
-				types.FormatAnyValue(field),
+				handlerparams.AliasFromType(field),
 			),
 			"$unwind (Stage)",
 		)
 	}
 
-	return &unwind{
-		field: expr,
-	}, nil
+	if u.path, err = newUnwindPath(path); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// newUnwindPath validates the path of $unwind stage and returns it without the '$' prefix.
+func newUnwindPath(path string) (types.Path, error) {
+	if path == "" {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageUnwindNoPath,
+			"no path specified to $unwind stage",
+			"$unwind (stage)",
+		)
+	}
+
+	_, err := aggregations.NewExpression(path, nil)
+	if err != nil {
+		var exprErr *aggregations.ExpressionError
+		if !errors.As(err, &exprErr) {
+			return types.Path{}, lazyerrors.Error(err)
+		}
+
+		switch exprErr.Code() {
+		case aggregations.ErrNotExpression:
+			return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageUnwindNoPrefix,
+				fmt.Sprintf("path option to $unwind stage should be prefixed with a '$': %s", path),
+				"$unwind (stage)",
+			)
+		case aggregations.ErrEmptyFieldPath:
+			return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrEmptyFieldPath,
+				"Expression cannot be constructed with empty string",
+				"$unwind (stage)",
+			)
+		case aggregations.ErrEmptyVariable, aggregations.ErrInvalidExpression, aggregations.ErrUndefinedVariable:
+			return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFieldPathInvalidName,
+				"Expression field names may not start with '$'. Consider using $getField or $setField",
+				"$unwind (stage)",
+			)
+		default:
+			return types.Path{}, lazyerrors.Error(err)
+		}
+	}
+
+	return newUnwindFieldPath(strings.TrimPrefix(path, "$"))
+}
+
+// newUnwindIndexPath validates includeArrayIndex option of $unwind stage.
+func newUnwindIndexPath(value any) (*types.Path, error) {
+	index, ok := value.(string)
+	if !ok || index == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageUnwindIndexType,
+			// MongoDB's message has two spaces before "option"
+			fmt.Sprintf(
+				"expected a non-empty string for the includeArrayIndex  option to $unwind stage, got %s",
+				handlerparams.AliasFromType(value),
+			),
+			"$unwind (stage)",
+		)
+	}
+
+	if strings.HasPrefix(index, "$") {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageUnwindIndexPrefix,
+			fmt.Sprintf("includeArrayIndex option to $unwind stage should not be prefixed with a '$': %s", index),
+			"$unwind (stage)",
+		)
+	}
+
+	path, err := newUnwindFieldPath(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+// newUnwindFieldPath returns the path of the field, or an error for an invalid path.
+func newUnwindFieldPath(s string) (types.Path, error) {
+	path, err := types.NewPathFromString(s)
+	if err != nil {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPathContainsEmptyElement,
+			"FieldPath field names may not be empty strings.",
+			"$unwind (stage)",
+		)
+	}
+
+	for _, e := range path.Slice() {
+		if strings.HasPrefix(e, "$") {
+			return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFieldPathInvalidName,
+				"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
+				"$unwind (stage)",
+			)
+		}
+	}
+
+	return path, nil
 }
 
 // Process implements Stage interface.
+//
+// For each document, it returns a copy of the document for each element of the array at the path,
+// with the element in place of the array.
+// A value that is not an array is treated as a single element array.
+// Documents with missing, null or empty array values are dropped,
+// unless preserveNullAndEmptyArrays is set; empty arrays are removed from such documents.
+// If includeArrayIndex is set, the index of the element, or null for non-array values,
+// is set in the given field.
 func (u *unwind) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// TODO https://github.com/FerretDB/FerretDB/issues/2490
 	docs, err := iterator.ConsumeValues(iter)
@@ -117,42 +243,59 @@ func (u *unwind) Process(ctx context.Context, iter types.DocumentsIterator, clos
 
 	var out []*types.Document
 
-	if u.field == nil {
-		return nil, nil
-	}
-
-	key := u.field.GetExpressionSuffix()
-
 	for _, doc := range docs {
-		d, err := u.field.Evaluate(doc)
+		// the path does not traverse arrays
+		vals, err := commonpath.FindValues(doc, u.path, &commonpath.FindValuesOpts{
+			FindArrayIndex:     false,
+			FindArrayDocuments: false,
+		})
 		if err != nil {
-			// Ignore non-existent values
-			continue
+			return nil, lazyerrors.Error(err)
 		}
 
-		switch d := d.(type) {
-		case *types.Array:
-			iter := d.Iterator()
-			defer iter.Close()
+		var val any = types.Null
+		if len(vals) > 0 {
+			val = vals[0]
+		}
 
-			id := must.NotFail(doc.Get("_id"))
+		arr, isArray := val.(*types.Array)
 
-			for {
-				_, v, err := iter.Next()
-				if err != nil {
-					if errors.Is(err, iterator.ErrIteratorDone) {
-						break
-					}
+		switch {
+		case isArray && arr.Len() > 0:
+			for i := 0; i < arr.Len(); i++ {
+				newDoc := doc.DeepCopy()
 
+				if err = newDoc.SetByPath(u.path, must.NotFail(arr.Get(i))); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				if err = u.setIndex(newDoc, int64(i)); err != nil {
 					return nil, err
 				}
 
-				newDoc := must.NotFail(types.NewDocument("_id", id, key, v))
 				out = append(out, newDoc)
 			}
-		case types.NullType:
-			// Ignore Nulls
+
+		case isArray, val == types.Null:
+			if !u.preserveNullAndEmptyArrays {
+				continue
+			}
+
+			if isArray {
+				doc.RemoveByPath(u.path)
+			}
+
+			if err = u.setIndex(doc, types.Null); err != nil {
+				return nil, err
+			}
+
+			out = append(out, doc)
+
 		default:
+			if err = u.setIndex(doc, types.Null); err != nil {
+				return nil, err
+			}
+
 			out = append(out, doc)
 		}
 	}
@@ -163,6 +306,19 @@ func (u *unwind) Process(ctx context.Context, iter types.DocumentsIterator, clos
 	return iter, nil
 }
 
+// setIndex sets includeArrayIndex field of the document, if any.
+func (u *unwind) setIndex(doc *types.Document, index any) error {
+	if u.indexPath == nil {
+		return nil
+	}
+
+	if err := doc.SetByPath(*u.indexPath, index); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
 // check interfaces
 var (
 	_ aggregations.Stage = (*unwind)(nil)
@@ -0,0 +1,143 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// GetCollation returns the collation for the collation document of find, update and other commands.
+//
+// It returns nil for nil document and for the "simple" locale.
+// Options that change only rarely used details of string comparison (caseFirst, maxVariable, backwards)
+// are not implemented and return an error for non-default values.
+func GetCollation(doc *types.Document) (*types.Collation, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	var opts types.CollationOptions
+
+	for _, key := range doc.Keys() {
+		v := must.NotFail(doc.Get(key))
+
+		switch key {
+		case "locale", "alternate", "caseFirst", "maxVariable":
+			s, ok := v.(string)
+			if !ok {
+				msg := fmt.Sprintf(
+					`BSON field 'collation.%s' is the wrong type '%s', expected type 'string'`,
+					key, handlerparams.AliasFromType(v),
+				)
+
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrTypeMismatch, msg, "collation")
+			}
+
+			switch key {
+			case "locale":
+				opts.Locale = s
+			case "alternate":
+				opts.Alternate = s
+			case "caseFirst":
+				if s != "off" {
+					return nil, collationNotImplemented(key)
+				}
+			case "maxVariable":
+				if s != "punct" {
+					return nil, collationNotImplemented(key)
+				}
+			}
+
+		case "strength":
+			strength, err := handlerparams.GetWholeNumberParam(v)
+			if err != nil {
+				msg := fmt.Sprintf(
+					`BSON field 'collation.strength' is the wrong type '%s', expected type 'int'`,
+					handlerparams.AliasFromType(v),
+				)
+
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrTypeMismatch, msg, "collation")
+			}
+
+			if strength < 1 || strength > 5 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"Field 'strength' must be an integer 1 through 5",
+					"collation",
+				)
+			}
+
+			opts.Strength = int(strength)
+
+		case "caseLevel", "numericOrdering", "backwards", "normalization":
+			b, err := handlerparams.GetBoolOptionalParam("collation."+key, v)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key {
+			case "caseLevel":
+				opts.CaseLevel = b
+			case "numericOrdering":
+				opts.NumericOrdering = b
+			case "backwards":
+				if b {
+					return nil, collationNotImplemented(key)
+				}
+			case "normalization":
+				// strings are always normalized by the collator
+			}
+
+		case "version":
+			// ignored, like the version of ICU reported by MongoDB
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("BSON field 'collation.%s' is an unknown field.", key),
+				"collation",
+			)
+		}
+	}
+
+	if opts.Locale == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field 'collation.locale' is missing but a required field",
+			"collation",
+		)
+	}
+
+	coll, err := types.NewCollation(opts)
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, err.Error(), "collation")
+	}
+
+	return coll, nil
+}
+
+// collationNotImplemented returns an error for non-default values of unsupported collation fields.
+func collationNotImplemented(key string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrNotImplemented,
+		fmt.Sprintf("collation field %q is not implemented yet", key),
+		"collation",
+	)
+}
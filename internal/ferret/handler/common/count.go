@@ -30,7 +30,7 @@ type CountParams struct {
 	Skip  int64 `ferretdb:"skip,opt,positiveNumber"`
 	Limit int64 `ferretdb:"limit,opt,positiveNumber"`
 
-	Collation *types.Document `ferretdb:"collation,unimplemented"`
+	Collation *types.Document `ferretdb:"collation,opt"`
 
 	Fields any `ferretdb:"fields,ignored"` // legacy MongoDB shell adds it, but it is never actually used
 
@@ -40,7 +40,7 @@ type DistinctParams struct {
 
 	Query any `ferretdb:"query,opt"`
 
-	Collation *types.Document `ferretdb:"collation,unimplemented"`
+	Collation *types.Document `ferretdb:"collation,opt"`
 
 	ReadConcern    *types.Document `ferretdb:"readConcern,ignored"`
 	LSID           any             `ferretdb:"lsid,ignored"`
@@ -89,7 +89,9 @@ func GetDistinctParams(document *types.Document, l *zap.Logger) (*DistinctParams
 //
 // If the key is found in the document, and the value is an array, each element of the array is added to the result.
 // Otherwise, the value itself is added to the result.
-func FilterDistinctValues(iter types.DocumentsIterator, key string) (*types.Array, error) {
+//
+// Strings are compared according to the given collation.
+func FilterDistinctValues(iter types.DocumentsIterator, key string, coll *types.Collation) (*types.Array, error) {
 	distinct := types.MakeArray(0)
 
 	defer iter.Close()
@@ -128,20 +130,20 @@ func FilterDistinctValues(iter types.DocumentsIterator, key string) (*types.Arra
 						return nil, lazyerrors.Error(err)
 					}
 
-					if !distinct.Contains(el) {
+					if !coll.Contains(distinct, el) {
 						distinct.Append(el)
 					}
 				}
 
 			default:
-				if !distinct.Contains(v) {
+				if !coll.Contains(distinct, v) {
 					distinct.Append(v)
 				}
 			}
 		}
 	}
 
-	SortArray(distinct, types.Ascending)
+	SortArrayWithCollation(distinct, types.Ascending, coll)
 
 	return distinct, nil
 }
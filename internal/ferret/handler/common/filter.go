@@ -24,8 +24,10 @@ import (
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/commonpath"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/geo"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/javascript"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
@@ -36,6 +38,20 @@ import (
 //
 // Passed arguments must not be modified.
 func FilterDocument(doc, filter *types.Document) (bool, error) {
+	return FilterDocumentWithVariables(doc, filter, nil)
+}
+
+// FilterDocumentWithVariables is like FilterDocument,
+// but aggregation expressions of $expr are evaluated with the given variables.
+func FilterDocumentWithVariables(doc, filter *types.Document, vars *operators.Variables) (bool, error) {
+	return FilterDocumentWithCollation(doc, filter, vars, nil)
+}
+
+// FilterDocumentWithCollation is like FilterDocumentWithVariables,
+// but strings are compared according to the given collation.
+func FilterDocumentWithCollation(
+	doc, filter *types.Document, vars *operators.Variables, coll *types.Collation,
+) (bool, error) {
 	iter := filter.Iterator()
 	defer iter.Close()
 
@@ -50,7 +66,7 @@ func FilterDocument(doc, filter *types.Document) (bool, error) {
 		}
 
 		// top-level filters are ANDed together
-		matches, err := filterDocumentPair(doc, filterKey, filterValue)
+		matches, err := filterDocumentPair(doc, filterKey, filterValue, vars, coll)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -95,8 +111,118 @@ func HasQueryOperator(filter *types.Document) (bool, error) {
 	}
 }
 
+// HasNearOperator returns true if the filter uses $near or $nearSphere on a field,
+// at the top level or within $and, $or and $nor.
+func HasNearOperator(filter *types.Document) bool {
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			return false
+		}
+
+		switch v := v.(type) {
+		case *types.Document:
+			if v.Has("$near") || v.Has("$nearSphere") {
+				return true
+			}
+
+		case *types.Array:
+			if k != "$and" && k != "$or" && k != "$nor" {
+				continue
+			}
+
+			for i := 0; i < v.Len(); i++ {
+				if expr, ok := must.NotFail(v.Get(i)).(*types.Document); ok && HasNearOperator(expr) {
+					return true
+				}
+			}
+		}
+	}
+}
+
+// ValidateNearOperator returns an error if the filter uses $near or $nearSphere more than once
+// or within $or or $nor, like MongoDB that requires them to be top-level expressions.
+// Within top-level $and, they are allowed.
+func ValidateNearOperator(filter *types.Document) error {
+	n, err := countNearOperators(filter, true)
+	if err != nil {
+		return err
+	}
+
+	if n > 1 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Too many geoNear expressions",
+			"$near",
+		)
+	}
+
+	return nil
+}
+
+// countNearOperators returns the number of field expressions with $near or $nearSphere in the filter.
+// If topLevel is false, the filter is within $or or $nor, and any of them is an error.
+func countNearOperators(filter *types.Document, topLevel bool) (int, error) {
+	var n int
+
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				return n, nil
+			}
+
+			return 0, lazyerrors.Error(err)
+		}
+
+		switch v := v.(type) {
+		case *types.Document:
+			if !v.Has("$near") && !v.Has("$nearSphere") {
+				continue
+			}
+
+			if !topLevel {
+				return 0, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"geoNear must be top-level expr",
+					"$near",
+				)
+			}
+
+			n++
+
+		case *types.Array:
+			if k != "$and" && k != "$or" && k != "$nor" {
+				continue
+			}
+
+			for i := 0; i < v.Len(); i++ {
+				expr, ok := must.NotFail(v.Get(i)).(*types.Document)
+				if !ok {
+					continue
+				}
+
+				c, err := countNearOperators(expr, topLevel && k == "$and")
+				if err != nil {
+					return 0, err
+				}
+
+				n += c
+			}
+		}
+	}
+}
+
 // filterDocumentPair handles a single filter element key/value pair {filterKey: filterValue}.
-func filterDocumentPair(doc *types.Document, filterKey string, filterValue any) (bool, error) {
+func filterDocumentPair(
+	doc *types.Document, filterKey string, filterValue any, vars *operators.Variables, coll *types.Collation,
+) (bool, error) {
 	var vals []any
 	filterSuffix := filterKey
 
@@ -124,11 +250,15 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 
 	if strings.HasPrefix(filterKey, "$") {
 		// {$operator: filterValue}
-		return filterOperator(doc, filterKey, filterValue)
+		return filterOperator(doc, filterKey, filterValue, vars, coll)
 	}
 
 	switch filterValue := filterValue.(type) {
 	case *types.Document:
+		if filterValue.Has("$near") || filterValue.Has("$nearSphere") {
+			return filterFieldExprNear(doc, vals, filterValue, vars)
+		}
+
 		var docs []*types.Document
 		for _, val := range vals {
 			docs = append(docs, must.NotFail(types.NewDocument(filterSuffix, val)))
@@ -141,7 +271,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 
 		for _, doc := range docs {
 			// {field: {expr}} or {field: {document}}
-			ok, err := filterFieldExpr(doc, filterKey, filterSuffix, filterValue)
+			ok, err := filterFieldExpr(doc, filterKey, filterSuffix, filterValue, coll)
 			if err != nil {
 				return false, err
 			}
@@ -157,7 +287,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 		}
 
 		for _, val := range vals {
-			if result := types.Compare(val, filterValue); result == types.Equal {
+			if result := coll.Compare(val, filterValue); result == types.Equal {
 				return true, nil
 			}
 		}
@@ -174,7 +304,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 		}
 	default:
 		for _, val := range vals {
-			if result := types.Compare(val, filterValue); result == types.Equal {
+			if result := coll.Compare(val, filterValue); result == types.Equal {
 				return true, nil
 			}
 		}
@@ -185,7 +315,9 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 }
 
 // filterOperator handles a top-level operator filter {$operator: filterValue}.
-func filterOperator(doc *types.Document, operator string, filterValue any) (bool, error) {
+func filterOperator(
+	doc *types.Document, operator string, filterValue any, vars *operators.Variables, coll *types.Collation,
+) (bool, error) {
 	switch operator {
 	case "$and":
 		// {$and: [{expr1}, {expr2}, ...]}
@@ -220,7 +352,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := FilterDocumentWithCollation(doc, expr, vars, coll)
 			if err != nil {
 				return false, err
 			}
@@ -264,7 +396,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := FilterDocumentWithCollation(doc, expr, vars, coll)
 			if err != nil {
 				return false, err
 			}
@@ -308,7 +440,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := FilterDocumentWithCollation(doc, expr, vars, coll)
 			if err != nil {
 				return false, err
 			}
@@ -323,7 +455,99 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		return true, nil
 
 	case "$expr":
-		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)))
+		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)), vars)
+
+	case "$jsonSchema":
+		// {$jsonSchema: {schema}}
+		schema, err := parseJSONSchema(filterValue)
+		if err != nil {
+			return false, err
+		}
+
+		return len(schema.validate(doc)) == 0, nil
+
+	case "$text":
+		// {$text: {$search: <string>, $language: <string>, $caseSensitive: <bool>, $diacriticSensitive: <bool>}}
+		meta := vars.Metadata()
+		if meta == nil || meta.TextIndex == nil {
+			return false, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexNotFound,
+				"text index required for $text query",
+				operator,
+			)
+		}
+
+		q, err := meta.TextQuery(filterValue)
+		if err != nil {
+			return false, err
+		}
+
+		score, matches, err := meta.TextIndex.Score(doc, q)
+		if err != nil {
+			return false, err
+		}
+
+		if matches {
+			meta.SetTextScore(doc, score)
+		}
+
+		return matches, nil
+
+	case "$sampleRate":
+		// {$sampleRate: <number in [0, 1]>}, the same as {$expr: {$lt: [{$rand: {}}, <number>]}}
+		var rate float64
+
+		switch v := filterValue.(type) {
+		case float64:
+			rate = v
+		case int32:
+			rate = float64(v)
+		case int64:
+			rate = float64(v)
+		case types.Decimal128:
+			rate = v.Float64()
+		default:
+			return false, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"argument to $sampleRate must be a numeric type",
+				operator,
+			)
+		}
+
+		if !(rate >= 0 && rate <= 1) {
+			return false, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"numeric argument to $sampleRate must be in [0, 1]",
+				operator,
+			)
+		}
+
+		return vars.Metadata().Random() < rate, nil
+
+	case "$where":
+		// {$where: <JavaScript code>}
+		var code string
+
+		switch filterValue := filterValue.(type) {
+		case string:
+			code = filterValue
+		case types.JavaScript:
+			code = string(filterValue)
+		default:
+			return false, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"$where got bad type",
+				operator,
+			)
+		}
+
+		meta := vars.Metadata()
+		if meta == nil || meta.JavaScript == nil {
+			return false, javascript.NewDisabledError(operator)
+		}
+
+		return meta.JavaScript.Where(code, doc)
+
 	default:
 		msg := fmt.Sprintf(
 			`unknown top level operator: %s. `+
@@ -341,26 +565,26 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 // $expr is primary used by operators such as $gt and $cond which return boolean result.
 // However, if non-boolean result is returned from processing aggregation expression,
 // it returns false for null or zero value and true for all other values.
-func filterExprOperator(doc, filter *types.Document) (bool, error) {
+func filterExprOperator(doc, filter *types.Document, vars *operators.Variables) (bool, error) {
 	// TODO https://github.com/FerretDB/FerretDB/issues/3170
 	op, err := operators.NewExpr(filter, "$expr")
 	if err != nil {
 		return false, err
 	}
 
-	v, err := op.Process(doc)
+	v, err := op.Process(doc, vars)
 	if err != nil {
-		return false, lazyerrors.Error(err)
+		return false, err
 	}
 
 	switch v := v.(type) {
 	case *types.Document, *types.Array, string, types.Binary, types.ObjectID, time.Time, types.Regex, types.Timestamp:
 		return true, nil
-	case float64, int32, int64:
+	case float64, int32, int64, types.Decimal128:
 		return types.Compare(v, int32(0)) != types.Equal, nil
 	case bool:
 		return v, nil
-	case types.NullType:
+	case types.NullType, nil:
 		return false, nil
 	default:
 		panic(fmt.Sprintf("common.filterExprOperator: unexpected type %[1]T (%#[1]v)", v))
@@ -368,7 +592,9 @@ func filterExprOperator(doc, filter *types.Document) (bool, error) {
 }
 
 // filterFieldExpr handles {field: {expr}} or {field: {document}} filter.
-func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *types.Document) (bool, error) {
+func filterFieldExpr(
+	doc *types.Document, filterKey, filterSuffix string, expr *types.Document, coll *types.Collation,
+) (bool, error) {
 	// check if both documents are empty
 	if expr.Len() == 0 {
 		fieldValue, err := doc.Get(filterSuffix)
@@ -408,7 +634,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 
 		if !strings.HasPrefix(exprKey, "$") {
 			if documentValue, ok := fieldValue.(*types.Document); ok {
-				result := types.Compare(documentValue, expr)
+				result := coll.Compare(documentValue, expr)
 				return result == types.Equal, nil
 			}
 			return false, nil
@@ -420,12 +646,12 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			switch exprValue := exprValue.(type) {
 			case *types.Document:
 				if fieldValue, ok := fieldValue.(*types.Document); ok {
-					result := types.Compare(exprValue, fieldValue)
+					result := coll.Compare(exprValue, fieldValue)
 					return result == types.Equal, nil
 				}
 				return false, nil
 			default:
-				result := types.Compare(fieldValue, exprValue)
+				result := coll.Compare(fieldValue, exprValue)
 				if result != types.Equal {
 					return false, nil
 				}
@@ -436,7 +662,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			switch exprValue := exprValue.(type) {
 			case *types.Document:
 				if fieldValue, ok := fieldValue.(*types.Document); ok {
-					result := types.Compare(exprValue, fieldValue)
+					result := coll.Compare(exprValue, fieldValue)
 					return result != types.Equal, nil
 				}
 
@@ -448,7 +674,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 					exprKey,
 				)
 			default:
-				result := types.Compare(fieldValue, exprValue)
+				result := coll.Compare(fieldValue, exprValue)
 				if result == types.Equal {
 					return false, nil
 				}
@@ -474,7 +700,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $gt comparison.
 
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
+			result := coll.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
 			if result != types.Greater {
 				return false, nil
 			}
@@ -498,7 +724,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// Above compares the maximum number of array 41.5 to the filter 42,
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $gte comparison.
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
+			result := coll.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
 			if result != types.Equal && result != types.Greater {
 				return false, nil
 			}
@@ -523,7 +749,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $lt comparison.
 
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
+			result := coll.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
 			if result != types.Less {
 				return false, nil
 			}
@@ -548,7 +774,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $lt comparison.
 
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
+			result := coll.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
 			if result != types.Equal && result != types.Less {
 				return false, nil
 			}
@@ -583,7 +809,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 					}
 
 					if fieldValue, ok := fieldValue.(*types.Document); ok {
-						if result := types.Compare(fieldValue, arrValue); result == types.Equal {
+						if result := coll.Compare(fieldValue, arrValue); result == types.Equal {
 							found = true
 						}
 					}
@@ -596,7 +822,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 						found = true
 					}
 				default:
-					result := types.Compare(fieldValue, arrValue)
+					result := coll.Compare(fieldValue, arrValue)
 					if result == types.Equal {
 						found = true
 					}
@@ -637,7 +863,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 					}
 
 					if fieldValue, ok := fieldValue.(*types.Document); ok {
-						if result := types.Compare(fieldValue, arrValue); result == types.Equal {
+						if result := coll.Compare(fieldValue, arrValue); result == types.Equal {
 							found = true
 						}
 					}
@@ -650,7 +876,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 						found = true
 					}
 				default:
-					result := types.Compare(fieldValue, arrValue)
+					result := coll.Compare(fieldValue, arrValue)
 					if result == types.Equal {
 						found = true
 					}
@@ -665,7 +891,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// {field: {$not: {expr}}}
 			switch exprValue := exprValue.(type) {
 			case *types.Document:
-				res, err := filterFieldExpr(doc, filterKey, filterSuffix, exprValue)
+				res, err := filterFieldExpr(doc, filterKey, filterSuffix, exprValue, coll)
 				if res || err != nil {
 					return false, err
 				}
@@ -693,7 +919,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 
 		case "$elemMatch":
 			// {field: {$elemMatch: value}}
-			res, err := filterFieldExprElemMatch(doc, filterKey, filterSuffix, exprValue)
+			res, err := filterFieldExprElemMatch(doc, filterKey, filterSuffix, exprValue, coll)
 			if !res || err != nil {
 				return false, err
 			}
@@ -707,7 +933,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 
 		case "$all":
 			// {field: {$all: [value, another_value, ...]}}
-			res, err := filterFieldExprAll(fieldValue, exprValue)
+			res, err := filterFieldExprAll(fieldValue, exprValue, coll)
 			if !res || err != nil {
 				return false, err
 			}
@@ -761,6 +987,20 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 				return false, err
 			}
 
+		case "$geoWithin":
+			// {field: {$geoWithin: shape}}
+			res, err := filterFieldExprGeoWithin(fieldValue, exprValue)
+			if !res || err != nil {
+				return false, err
+			}
+
+		case "$geoIntersects":
+			// {field: {$geoIntersects: {$geometry: geometry}}}
+			res, err := filterFieldExprGeoIntersects(fieldValue, exprValue)
+			if !res || err != nil {
+				return false, err
+			}
+
 		default:
 			return false, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrBadValue,
@@ -926,7 +1166,7 @@ func filterFieldExprSize(fieldValue any, sizeValue any) (bool, error) {
 // filterFieldExprAll handles {field: {$all: [value, another_value, ...]}} filter.
 // The main purpose of $all is to filter arrays.
 // It is possible to filter non-arrays: {field: {$all: [value]}}, but such statement is equivalent to {field: value}.
-func filterFieldExprAll(fieldValue any, allValue any) (bool, error) {
+func filterFieldExprAll(fieldValue any, allValue any, coll *types.Collation) (bool, error) {
 	query, ok := allValue.(*types.Array)
 	if !ok {
 		return false, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, "$all needs an array", "$all")
@@ -943,13 +1183,13 @@ func filterFieldExprAll(fieldValue any, allValue any) (bool, error) {
 
 	case *types.Array:
 		// For arrays we check that the array contains all the elements of the query.
-		return value.ContainsAll(query), nil
+		return coll.ContainsAll(value, query), nil
 
 	default:
 		// For other types (scalars) we check that the value is equal to each scalar in the query.
 		// Example: value: 42, query: [42, 42] should give us `true`
 		for i := 0; i < query.Len(); i++ {
-			res := types.Compare(value, must.NotFail(query.Get(i)))
+			res := coll.Compare(value, must.NotFail(query.Get(i)))
 			if res != types.Equal {
 				return false, nil
 			}
@@ -1459,20 +1699,46 @@ func filterFieldValueByTypeCode(fieldValue any, code handlerparams.TypeCode) (bo
 		if _, ok := fieldValue.(int64); !ok {
 			return false, nil
 		}
+	case handlerparams.TypeCodeDecimal:
+		if _, ok := fieldValue.(types.Decimal128); !ok {
+			return false, nil
+		}
 	case handlerparams.TypeCodeNumber:
-		// TypeCodeNumber should match int32, int64 and float64 types
+		// TypeCodeNumber should match int32, int64, float64 and decimal types
 		switch fieldValue.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			return true, nil
 		default:
 			return false, nil
 		}
-	case handlerparams.TypeCodeDecimal, handlerparams.TypeCodeMinKey, handlerparams.TypeCodeMaxKey:
-		return false, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrNotImplemented,
-			fmt.Sprintf(`Type code %v not implemented`, code),
-			"$type",
-		)
+	case handlerparams.TypeCodeMinKey:
+		if _, ok := fieldValue.(types.MinKeyType); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeMaxKey:
+		if _, ok := fieldValue.(types.MaxKeyType); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeUndefined:
+		if _, ok := fieldValue.(types.UndefinedType); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeDBPointer:
+		if _, ok := fieldValue.(types.DBPointer); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeJavaScript:
+		if _, ok := fieldValue.(types.JavaScript); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeSymbol:
+		if _, ok := fieldValue.(types.Symbol); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeJavaScriptWithScope:
+		if _, ok := fieldValue.(types.JavaScriptScope); !ok {
+			return false, nil
+		}
 	default:
 		return false, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrBadValue,
@@ -1484,9 +1750,75 @@ func filterFieldValueByTypeCode(fieldValue any, code handlerparams.TypeCode) (bo
 	return true, nil
 }
 
+// filterFieldExprGeoWithin handles {field: {$geoWithin: shape}}.
+// Documents with multiple locations match if any of them lies within the shape.
+func filterFieldExprGeoWithin(fieldValue, exprValue any) (bool, error) {
+	shape, err := geo.ParseWithin(exprValue)
+	if err != nil {
+		return false, err
+	}
+
+	for _, loc := range geo.ParseLocations(fieldValue) {
+		if shape.Contains(loc.Geometry) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// filterFieldExprGeoIntersects handles {field: {$geoIntersects: {$geometry: geometry}}}.
+func filterFieldExprGeoIntersects(fieldValue, exprValue any) (bool, error) {
+	g, err := geo.ParseIntersects(exprValue)
+	if err != nil {
+		return false, err
+	}
+
+	for _, loc := range geo.ParseLocations(fieldValue) {
+		if geo.Intersects(loc.Geometry, g) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// filterFieldExprNear handles {field: {$near: point, $minDistance: value, $maxDistance: value}}
+// and the same form of $nearSphere for the field values.
+//
+// The distance to the closest location is stored in the metadata of vars, if any,
+// so the caller could sort documents by it.
+func filterFieldExprNear(doc *types.Document, vals []any, expr *types.Document, vars *operators.Variables) (bool, error) {
+	near, err := geo.ParseNear(expr)
+	if err != nil {
+		return false, err
+	}
+
+	distance := math.Inf(1)
+
+	for _, v := range vals {
+		for _, loc := range geo.ParseLocations(v) {
+			distance = math.Min(distance, near.Distance(loc.Geometry))
+		}
+	}
+
+	// documents without locations do not match
+	if math.IsInf(distance, 1) || !near.InRange(distance) {
+		return false, nil
+	}
+
+	if meta := vars.Metadata(); meta != nil {
+		meta.SetGeoNearDistance(doc, distance)
+	}
+
+	return true, nil
+}
+
 // filterFieldExprElemMatch handles {field: {$elemMatch: value}}.
 // Returns false if doc value is not an array.
-func filterFieldExprElemMatch(doc *types.Document, filterKey, filterSuffix string, exprValue any) (bool, error) {
+func filterFieldExprElemMatch(
+	doc *types.Document, filterKey, filterSuffix string, exprValue any, coll *types.Collation,
+) (bool, error) {
 	expr, ok := exprValue.(*types.Document)
 	if !ok {
 		return false, handlererrors.NewCommandErrorMsgWithArgument(
@@ -1541,5 +1873,5 @@ func filterFieldExprElemMatch(doc *types.Document, filterKey, filterSuffix strin
 		return false, nil
 	}
 
-	return filterFieldExpr(doc, filterKey, filterSuffix, expr)
+	return filterFieldExpr(doc, filterKey, filterSuffix, expr, coll)
 }
@@ -15,21 +15,26 @@
 package common
 
 import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
 )
 
 // FilterIterator returns an iterator that filters out documents that don't match the filter.
+// Aggregation expressions of $expr are evaluated with the given variables,
+// strings are compared according to the given collation.
 // It will be added to the given closer.
 //
 // Next method returns the next document that matches the filter.
 //
 // Close method closes the underlying iterator.
-func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, filter *types.Document) types.DocumentsIterator {
+func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, filter *types.Document, vars *operators.Variables, coll *types.Collation) types.DocumentsIterator { //nolint:lll // for readability
 	res := &filterIterator{
 		iter:   iter,
 		filter: filter,
+		vars:   vars,
+		coll:   coll,
 	}
 	closer.Add(res)
 
@@ -40,6 +45,8 @@ func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser,
 type filterIterator struct {
 	iter   types.DocumentsIterator
 	filter *types.Document
+	vars   *operators.Variables
+	coll   *types.Collation
 }
 
 // Next implements iterator.Interface. See FilterIterator for details.
@@ -52,7 +59,7 @@ func (iter *filterIterator) Next() (struct{}, *types.Document, error) {
 			return unused, nil, lazyerrors.Error(err)
 		}
 
-		matches, err := FilterDocument(doc, iter.filter)
+		matches, err := FilterDocumentWithCollation(doc, iter.filter, iter.vars, iter.coll)
 		if err != nil {
 			return unused, nil, lazyerrors.Error(err)
 		}
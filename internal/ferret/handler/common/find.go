@@ -43,8 +43,8 @@ type FindParams struct {
 	Tailable     bool            `ferretdb:"tailable,opt"`
 	AwaitData    bool            `ferretdb:"awaitData,opt"`
 
-	Collation *types.Document `ferretdb:"collation,unimplemented"`
-	Let       *types.Document `ferretdb:"let,unimplemented"`
+	Collation *types.Document `ferretdb:"collation,opt"`
+	Let       *types.Document `ferretdb:"let,opt"`
 
 	AllowDiskUse   bool            `ferretdb:"allowDiskUse,ignored"`
 	ReadConcern    *types.Document `ferretdb:"readConcern,ignored"`
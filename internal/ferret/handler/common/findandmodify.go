@@ -42,8 +42,8 @@ type FindAndModifyParams struct {
 
 	HasUpdateOperators bool `ferretdb:"-"`
 
-	Let          *types.Document `ferretdb:"let,unimplemented"`
-	Collation    *types.Document `ferretdb:"collation,unimplemented"`
+	Let          *types.Document `ferretdb:"let,opt"`
+	Collation    *types.Document `ferretdb:"collation,opt"`
 	Fields       *types.Document `ferretdb:"fields,unimplemented"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
 
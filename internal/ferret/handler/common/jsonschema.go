@@ -0,0 +1,1030 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// jsonSchema represents a parsed $jsonSchema document.
+//
+// Like in MongoDB, it implements JSON Schema draft 4 with the bsonType extension
+// and without the $ref, $schema, default, definitions, format and id keywords.
+// Keywords that apply to a particular type (for example, minimum or properties)
+// are ignored for values of other types.
+type jsonSchema struct {
+	spec *types.Document // the original schema document, used for error details
+
+	typeKeyword string // "bsonType" or "type", if set
+	typeCodes   []handlerparams.TypeCode
+
+	enum     *types.Array
+	required []string
+
+	properties           map[string]*jsonSchema
+	patternProperties    []patternProperty
+	additionalProperties any // nil, bool or *jsonSchema
+	dependencies         []dependency
+	minProperties        *int64
+	maxProperties        *int64
+
+	minimum          any
+	maximum          any
+	exclusiveMinimum bool
+	exclusiveMaximum bool
+	multipleOf       any
+
+	minLength *int64
+	maxLength *int64
+	pattern   *regexp.Regexp
+
+	items           any // nil, *jsonSchema or []*jsonSchema
+	additionalItems any // nil, bool or *jsonSchema
+	minItems        *int64
+	maxItems        *int64
+	uniqueItems     bool
+
+	allOf []*jsonSchema
+	anyOf []*jsonSchema
+	oneOf []*jsonSchema
+	not   *jsonSchema
+}
+
+// patternProperty represents a single patternProperties entry.
+type patternProperty struct {
+	re     *regexp.Regexp
+	schema *jsonSchema
+}
+
+// dependency represents a single dependencies entry.
+type dependency struct {
+	schema     *jsonSchema // set for schema dependencies
+	property   string
+	properties []string // set for property dependencies
+}
+
+// jsonTypes maps JSON Schema type names to BSON type codes.
+var jsonTypes = map[string]handlerparams.TypeCode{
+	"object":  handlerparams.TypeCodeObject,
+	"array":   handlerparams.TypeCodeArray,
+	"number":  handlerparams.TypeCodeNumber,
+	"boolean": handlerparams.TypeCodeBool,
+	"string":  handlerparams.TypeCodeString,
+	"null":    handlerparams.TypeCodeNull,
+}
+
+// parseJSONSchema parses the value of $jsonSchema operator.
+func parseJSONSchema(v any) (*jsonSchema, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			"$jsonSchema must be an object",
+			"$jsonSchema",
+		)
+	}
+
+	return newJSONSchema(doc)
+}
+
+// newJSONSchema parses the given (sub)schema document.
+func newJSONSchema(doc *types.Document) (*jsonSchema, error) {
+	s := &jsonSchema{spec: doc}
+
+	var err error
+
+	for _, key := range doc.Keys() {
+		v := must.NotFail(doc.Get(key))
+
+		switch key {
+		case "bsonType", "type":
+			if s.typeKeyword != "" {
+				return nil, jsonSchemaError(handlererrors.ErrFailedToParse, "Cannot specify both $jsonSchema keywords 'type' and 'bsonType'")
+			}
+
+			s.typeKeyword = key
+
+			if s.typeCodes, err = parseJSONSchemaTypes(key, v); err != nil {
+				return nil, err
+			}
+
+		case "enum":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "an array")
+			}
+
+			if arr.Len() == 0 {
+				return nil, jsonSchemaError(handlererrors.ErrFailedToParse, "$jsonSchema keyword 'enum' cannot be an empty array")
+			}
+
+			s.enum = arr
+
+		case "required":
+			if s.required, err = parseJSONSchemaStrings(key, v); err != nil {
+				return nil, err
+			}
+
+			if len(s.required) == 0 {
+				return nil, jsonSchemaError(handlererrors.ErrFailedToParse, "$jsonSchema keyword 'required' cannot be an empty array")
+			}
+
+		case "properties":
+			props, ok := v.(*types.Document)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "an object")
+			}
+
+			s.properties = make(map[string]*jsonSchema, props.Len())
+
+			for _, name := range props.Keys() {
+				if s.properties[name], err = newJSONSubschema("properties."+name, must.NotFail(props.Get(name))); err != nil {
+					return nil, err
+				}
+			}
+
+		case "patternProperties":
+			props, ok := v.(*types.Document)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "an object")
+			}
+
+			for _, pattern := range props.Keys() {
+				var p patternProperty
+
+				if p.re, err = compileJSONSchemaPattern(pattern); err != nil {
+					return nil, err
+				}
+
+				if p.schema, err = newJSONSubschema("patternProperties."+pattern, must.NotFail(props.Get(pattern))); err != nil {
+					return nil, err
+				}
+
+				s.patternProperties = append(s.patternProperties, p)
+			}
+
+		case "additionalProperties", "additionalItems":
+			var res any
+
+			switch v := v.(type) {
+			case bool:
+				res = v
+			case *types.Document:
+				if res, err = newJSONSchema(v); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, jsonSchemaTypeError(key, "either an object or a boolean")
+			}
+
+			if key == "additionalProperties" {
+				s.additionalProperties = res
+			} else {
+				s.additionalItems = res
+			}
+
+		case "dependencies":
+			deps, ok := v.(*types.Document)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "an object")
+			}
+
+			for _, property := range deps.Keys() {
+				d := dependency{property: property}
+
+				switch dep := must.NotFail(deps.Get(property)).(type) {
+				case *types.Document:
+					if d.schema, err = newJSONSchema(dep); err != nil {
+						return nil, err
+					}
+				case *types.Array:
+					if d.properties, err = parseJSONSchemaStrings("dependencies."+property, dep); err != nil {
+						return nil, err
+					}
+				default:
+					return nil, jsonSchemaTypeError("dependencies."+property, "either an object or an array")
+				}
+
+				s.dependencies = append(s.dependencies, d)
+			}
+
+		case "minProperties", "maxProperties", "minLength", "maxLength", "minItems", "maxItems":
+			n, err := handlerparams.GetWholeNumberParam(v)
+			if err != nil || n < 0 {
+				return nil, jsonSchemaError(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf("$jsonSchema keyword '%s' must be a non-negative integral number", key),
+				)
+			}
+
+			switch key {
+			case "minProperties":
+				s.minProperties = &n
+			case "maxProperties":
+				s.maxProperties = &n
+			case "minLength":
+				s.minLength = &n
+			case "maxLength":
+				s.maxLength = &n
+			case "minItems":
+				s.minItems = &n
+			case "maxItems":
+				s.maxItems = &n
+			}
+
+		case "minimum", "maximum", "multipleOf":
+			if !isJSONSchemaNumber(v) {
+				return nil, jsonSchemaTypeError(key, "a number")
+			}
+
+			switch key {
+			case "minimum":
+				s.minimum = v
+			case "maximum":
+				s.maximum = v
+			case "multipleOf":
+				if types.Compare(v, int32(0)) != types.Greater {
+					return nil, jsonSchemaError(
+						handlererrors.ErrFailedToParse,
+						"$jsonSchema keyword 'multipleOf' must have a positive value",
+					)
+				}
+
+				s.multipleOf = v
+			}
+
+		case "exclusiveMinimum", "exclusiveMaximum", "uniqueItems":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "a boolean")
+			}
+
+			switch key {
+			case "exclusiveMinimum":
+				s.exclusiveMinimum = b
+			case "exclusiveMaximum":
+				s.exclusiveMaximum = b
+			case "uniqueItems":
+				s.uniqueItems = b
+			}
+
+		case "pattern":
+			pattern, ok := v.(string)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "a string")
+			}
+
+			if s.pattern, err = compileJSONSchemaPattern(pattern); err != nil {
+				return nil, err
+			}
+
+		case "items":
+			switch v := v.(type) {
+			case *types.Document:
+				if s.items, err = newJSONSchema(v); err != nil {
+					return nil, err
+				}
+			case *types.Array:
+				if s.items, err = parseJSONSchemaList(key, v); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, jsonSchemaTypeError(key, "either an object or an array")
+			}
+
+		case "allOf", "anyOf", "oneOf":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, jsonSchemaTypeError(key, "an array")
+			}
+
+			if arr.Len() == 0 {
+				return nil, jsonSchemaError(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf("$jsonSchema keyword '%s' must be a non-empty array", key),
+				)
+			}
+
+			list, err := parseJSONSchemaList(key, arr)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key {
+			case "allOf":
+				s.allOf = list
+			case "anyOf":
+				s.anyOf = list
+			case "oneOf":
+				s.oneOf = list
+			}
+
+		case "not":
+			if s.not, err = newJSONSubschema(key, v); err != nil {
+				return nil, err
+			}
+
+		case "title", "description":
+			if _, ok := v.(string); !ok {
+				return nil, jsonSchemaTypeError(key, "a string")
+			}
+
+		case "$ref", "$schema", "default", "definitions", "format", "id":
+			return nil, jsonSchemaError(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$jsonSchema keyword '%s' is not currently supported", key),
+			)
+
+		default:
+			return nil, jsonSchemaError(handlererrors.ErrFailedToParse, fmt.Sprintf("Unknown $jsonSchema keyword: %s", key))
+		}
+	}
+
+	if s.exclusiveMinimum && s.minimum == nil {
+		return nil, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			"$jsonSchema keyword 'minimum' must be a present if exclusiveMinimum is present",
+		)
+	}
+
+	if s.exclusiveMaximum && s.maximum == nil {
+		return nil, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			"$jsonSchema keyword 'maximum' must be a present if exclusiveMaximum is present",
+		)
+	}
+
+	return s, nil
+}
+
+// newJSONSubschema parses the subschema of the given keyword.
+func newJSONSubschema(keyword string, v any) (*jsonSchema, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, jsonSchemaTypeError(keyword, "an object")
+	}
+
+	return newJSONSchema(doc)
+}
+
+// parseJSONSchemaList parses the array of subschemas of the given keyword.
+func parseJSONSchemaList(keyword string, arr *types.Array) ([]*jsonSchema, error) {
+	res := make([]*jsonSchema, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		var err error
+		if res[i], err = newJSONSubschema(keyword, must.NotFail(arr.Get(i))); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// parseJSONSchemaStrings parses the array of unique strings of the given keyword.
+func parseJSONSchemaStrings(keyword string, v any) ([]string, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, jsonSchemaTypeError(keyword, "an array")
+	}
+
+	res := make([]string, arr.Len())
+	seen := make(map[string]struct{}, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		s, ok := must.NotFail(arr.Get(i)).(string)
+		if !ok {
+			return nil, jsonSchemaTypeError(keyword, "an array of strings")
+		}
+
+		if _, ok := seen[s]; ok {
+			return nil, jsonSchemaError(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$jsonSchema keyword '%s' array cannot contain duplicate values", keyword),
+			)
+		}
+
+		seen[s] = struct{}{}
+		res[i] = s
+	}
+
+	return res, nil
+}
+
+// parseJSONSchemaTypes parses the value of bsonType or type keyword.
+func parseJSONSchemaTypes(keyword string, v any) ([]handlerparams.TypeCode, error) {
+	var names []string
+
+	switch v := v.(type) {
+	case string:
+		names = []string{v}
+	case *types.Array:
+		var err error
+		if names, err = parseJSONSchemaStrings(keyword, v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, jsonSchemaTypeError(keyword, "either a string or an array of strings")
+	}
+
+	res := make([]handlerparams.TypeCode, len(names))
+
+	for i, name := range names {
+		if keyword == "bsonType" {
+			code, err := handlerparams.ParseTypeCode(name)
+			if err != nil {
+				return nil, err
+			}
+
+			res[i] = code
+
+			continue
+		}
+
+		code, ok := jsonTypes[name]
+		if !ok {
+			if name == "integer" {
+				return nil, jsonSchemaError(
+					handlererrors.ErrFailedToParse,
+					"$jsonSchema type 'integer' is not currently supported.",
+				)
+			}
+
+			return nil, jsonSchemaError(handlererrors.ErrBadValue, fmt.Sprintf("Unknown type name alias: %s", name))
+		}
+
+		res[i] = code
+	}
+
+	return res, nil
+}
+
+// compileJSONSchemaPattern compiles the regular expression of pattern or patternProperties keyword.
+func compileJSONSchemaPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := types.Regex{Pattern: pattern}.Compile()
+	if err != nil {
+		return nil, jsonSchemaError(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("$jsonSchema regular expression %q is invalid: %s", pattern, err),
+		)
+	}
+
+	return re, nil
+}
+
+// jsonSchemaTypeError returns an error for the keyword value of the wrong type.
+func jsonSchemaTypeError(keyword, expected string) error {
+	return jsonSchemaError(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf("$jsonSchema keyword '%s' must be %s", keyword, expected),
+	)
+}
+
+// jsonSchemaError returns $jsonSchema parsing error with the given code.
+func jsonSchemaError(code handlererrors.ErrorCode, msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(code, msg, "$jsonSchema")
+}
+
+// isJSONSchemaNumber returns true for numbers that numeric keywords apply to.
+func isJSONSchemaNumber(v any) bool {
+	switch v := v.(type) {
+	case float64:
+		return !math.IsNaN(v)
+	case int32, int64:
+		return true
+	case types.Decimal128:
+		return !v.IsNaN()
+	default:
+		return false
+	}
+}
+
+// validate returns the rules of the schema that the value does not satisfy.
+//
+// Rules are documents in the format of MongoDB's schemaRulesNotSatisfied error details.
+// An empty result means that the value matches the schema.
+func (s *jsonSchema) validate(v any) []*types.Document {
+	var res []*types.Document
+
+	if s.typeKeyword != "" && !s.matchesType(v) {
+		res = append(res, s.rule(s.typeKeyword,
+			"reason", "type did not match",
+			"consideredValue", v,
+			"consideredType", handlerparams.AliasFromType(v),
+		))
+	}
+
+	if s.enum != nil && !s.enum.Contains(v) {
+		res = append(res, s.rule("enum",
+			"reason", "value was not found in enum",
+			"consideredValue", v,
+		))
+	}
+
+	switch v := v.(type) {
+	case *types.Document:
+		res = append(res, s.validateDocument(v)...)
+	case *types.Array:
+		res = append(res, s.validateArray(v)...)
+	case string:
+		res = append(res, s.validateString(v)...)
+	default:
+		if isJSONSchemaNumber(v) {
+			res = append(res, s.validateNumber(v)...)
+		}
+	}
+
+	res = append(res, s.validateLogical(v)...)
+
+	return res
+}
+
+// matchesType returns true if the value matches bsonType or type keyword.
+func (s *jsonSchema) matchesType(v any) bool {
+	alias := handlerparams.AliasFromType(v)
+
+	for _, code := range s.typeCodes {
+		if code == handlerparams.TypeCodeNumber {
+			switch v.(type) {
+			case float64, int32, int64, types.Decimal128:
+				return true
+			}
+
+			continue
+		}
+
+		if code.String() == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateDocument validates keywords that apply to documents.
+func (s *jsonSchema) validateDocument(doc *types.Document) []*types.Document {
+	var res []*types.Document
+
+	if s.required != nil {
+		missing := types.MakeArray(0)
+
+		for _, name := range s.required {
+			if !doc.Has(name) {
+				missing.Append(name)
+			}
+		}
+
+		if missing.Len() > 0 {
+			res = append(res, s.rule("required", "missingProperties", missing))
+		}
+	}
+
+	if s.minProperties != nil && int64(doc.Len()) < *s.minProperties {
+		res = append(res, s.rule("minProperties",
+			"reason", "specified number of properties was not satisfied",
+			"numberOfProperties", int32(doc.Len()),
+		))
+	}
+
+	if s.maxProperties != nil && int64(doc.Len()) > *s.maxProperties {
+		res = append(res, s.rule("maxProperties",
+			"reason", "specified number of properties was not satisfied",
+			"numberOfProperties", int32(doc.Len()),
+		))
+	}
+
+	if s.properties != nil {
+		notSatisfied := types.MakeArray(0)
+
+		for _, name := range doc.Keys() {
+			sub := s.properties[name]
+			if sub == nil {
+				continue
+			}
+
+			if details := sub.validate(must.NotFail(doc.Get(name))); len(details) > 0 {
+				notSatisfied.Append(propertyDetails(name, details))
+			}
+		}
+
+		if notSatisfied.Len() > 0 {
+			res = append(res, must.NotFail(types.NewDocument(
+				"operatorName", "properties",
+				"propertiesNotSatisfied", notSatisfied,
+			)))
+		}
+	}
+
+	if s.patternProperties != nil {
+		notSatisfied := types.MakeArray(0)
+
+		for _, name := range doc.Keys() {
+			for _, p := range s.patternProperties {
+				if !p.re.MatchString(name) {
+					continue
+				}
+
+				if details := p.schema.validate(must.NotFail(doc.Get(name))); len(details) > 0 {
+					notSatisfied.Append(propertyDetails(name, details))
+				}
+			}
+		}
+
+		if notSatisfied.Len() > 0 {
+			res = append(res, must.NotFail(types.NewDocument(
+				"operatorName", "patternProperties",
+				"propertiesNotSatisfied", notSatisfied,
+			)))
+		}
+	}
+
+	if s.additionalProperties != nil {
+		res = append(res, s.validateAdditionalProperties(doc)...)
+	}
+
+	for _, d := range s.dependencies {
+		if !doc.Has(d.property) {
+			continue
+		}
+
+		if d.schema != nil {
+			if details := d.schema.validate(doc); len(details) > 0 {
+				res = append(res, s.rule("dependencies",
+					"conditionalProperty", d.property,
+					"details", detailsArray(details),
+				))
+			}
+
+			continue
+		}
+
+		missing := types.MakeArray(0)
+
+		for _, name := range d.properties {
+			if !doc.Has(name) {
+				missing.Append(name)
+			}
+		}
+
+		if missing.Len() > 0 {
+			res = append(res, s.rule("dependencies",
+				"conditionalProperty", d.property,
+				"missingProperties", missing,
+			))
+		}
+	}
+
+	return res
+}
+
+// validateAdditionalProperties validates additionalProperties keyword.
+func (s *jsonSchema) validateAdditionalProperties(doc *types.Document) []*types.Document {
+	additional := types.MakeArray(0)
+	notSatisfied := types.MakeArray(0)
+
+	for _, name := range doc.Keys() {
+		if _, ok := s.properties[name]; ok {
+			continue
+		}
+
+		var matched bool
+
+		for _, p := range s.patternProperties {
+			if p.re.MatchString(name) {
+				matched = true
+				break
+			}
+		}
+
+		if matched {
+			continue
+		}
+
+		switch a := s.additionalProperties.(type) {
+		case bool:
+			if !a {
+				additional.Append(name)
+			}
+		case *jsonSchema:
+			if details := a.validate(must.NotFail(doc.Get(name))); len(details) > 0 {
+				notSatisfied.Append(propertyDetails(name, details))
+			}
+		}
+	}
+
+	var res []*types.Document
+
+	if additional.Len() > 0 {
+		res = append(res, s.rule("additionalProperties", "additionalProperties", additional))
+	}
+
+	if notSatisfied.Len() > 0 {
+		res = append(res, must.NotFail(types.NewDocument(
+			"operatorName", "additionalProperties",
+			"propertiesNotSatisfied", notSatisfied,
+		)))
+	}
+
+	return res
+}
+
+// validateArray validates keywords that apply to arrays.
+func (s *jsonSchema) validateArray(arr *types.Array) []*types.Document {
+	var res []*types.Document
+
+	if s.minItems != nil && int64(arr.Len()) < *s.minItems {
+		res = append(res, s.rule("minItems",
+			"reason", "array did not match specified length",
+			"consideredValue", arr,
+		))
+	}
+
+	if s.maxItems != nil && int64(arr.Len()) > *s.maxItems {
+		res = append(res, s.rule("maxItems",
+			"reason", "array did not match specified length",
+			"consideredValue", arr,
+		))
+	}
+
+	if s.uniqueItems {
+	unique:
+		for i := 0; i < arr.Len(); i++ {
+			for j := 0; j < i; j++ {
+				if types.Compare(must.NotFail(arr.Get(i)), must.NotFail(arr.Get(j))) == types.Equal {
+					res = append(res, s.rule("uniqueItems",
+						"reason", "found a duplicate item",
+						"consideredValue", arr,
+						"duplicatedValue", must.NotFail(arr.Get(i)),
+					))
+
+					break unique
+				}
+			}
+		}
+	}
+
+	switch items := s.items.(type) {
+	case *jsonSchema:
+		for i := 0; i < arr.Len(); i++ {
+			if details := items.validate(must.NotFail(arr.Get(i))); len(details) > 0 {
+				res = append(res, must.NotFail(types.NewDocument(
+					"operatorName", "items",
+					"reason", "At least one item did not match the sub-schema",
+					"itemIndex", int32(i),
+					"details", detailsArray(details),
+				)))
+
+				break
+			}
+		}
+
+	case []*jsonSchema:
+		for i := 0; i < arr.Len() && i < len(items); i++ {
+			if details := items[i].validate(must.NotFail(arr.Get(i))); len(details) > 0 {
+				res = append(res, must.NotFail(types.NewDocument(
+					"operatorName", "items",
+					"reason", "At least one item did not match the sub-schema",
+					"itemIndex", int32(i),
+					"details", detailsArray(details),
+				)))
+
+				break
+			}
+		}
+
+		if arr.Len() <= len(items) {
+			break
+		}
+
+		switch a := s.additionalItems.(type) {
+		case bool:
+			if !a {
+				res = append(res, s.rule("additionalItems",
+					"reason", "found additional items",
+					"additionalItems", arrayTail(arr, len(items)),
+				))
+			}
+		case *jsonSchema:
+			for i := len(items); i < arr.Len(); i++ {
+				if details := a.validate(must.NotFail(arr.Get(i))); len(details) > 0 {
+					res = append(res, must.NotFail(types.NewDocument(
+						"operatorName", "additionalItems",
+						"reason", "At least one additional item did not match the sub-schema",
+						"itemIndex", int32(i),
+						"details", detailsArray(details),
+					)))
+
+					break
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// validateString validates keywords that apply to strings.
+func (s *jsonSchema) validateString(str string) []*types.Document {
+	var res []*types.Document
+
+	length := int64(utf8.RuneCountInString(str))
+
+	if s.minLength != nil && length < *s.minLength {
+		res = append(res, s.rule("minLength",
+			"reason", "specified string length was not satisfied",
+			"consideredValue", str,
+		))
+	}
+
+	if s.maxLength != nil && length > *s.maxLength {
+		res = append(res, s.rule("maxLength",
+			"reason", "specified string length was not satisfied",
+			"consideredValue", str,
+		))
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		res = append(res, s.rule("pattern",
+			"reason", "regular expression did not match",
+			"consideredValue", str,
+		))
+	}
+
+	return res
+}
+
+// validateNumber validates keywords that apply to numbers.
+func (s *jsonSchema) validateNumber(v any) []*types.Document {
+	var res []*types.Document
+
+	if s.minimum != nil {
+		result := types.Compare(v, s.minimum)
+		if result == types.Less || (s.exclusiveMinimum && result == types.Equal) {
+			res = append(res, s.rule("minimum",
+				"reason", "comparison failed",
+				"consideredValue", v,
+			))
+		}
+	}
+
+	if s.maximum != nil {
+		result := types.Compare(v, s.maximum)
+		if result == types.Greater || (s.exclusiveMaximum && result == types.Equal) {
+			res = append(res, s.rule("maximum",
+				"reason", "comparison failed",
+				"consideredValue", v,
+			))
+		}
+	}
+
+	if s.multipleOf != nil {
+		dv, _ := types.NewDecimal128FromNumber(v)
+		dm, _ := types.NewDecimal128FromNumber(s.multipleOf)
+
+		if rem := dv.Rem(dm); !rem.IsZero() {
+			res = append(res, s.rule("multipleOf",
+				"reason", "considered value is not a multiple of the specified value",
+				"consideredValue", v,
+			))
+		}
+	}
+
+	return res
+}
+
+// validateLogical validates allOf, anyOf, oneOf and not keywords.
+func (s *jsonSchema) validateLogical(v any) []*types.Document {
+	var res []*types.Document
+
+	if s.allOf != nil {
+		notSatisfied := types.MakeArray(0)
+
+		for i, sub := range s.allOf {
+			if details := sub.validate(v); len(details) > 0 {
+				notSatisfied.Append(schemaDetails(i, details))
+			}
+		}
+
+		if notSatisfied.Len() > 0 {
+			res = append(res, s.rule("allOf", "schemasNotSatisfied", notSatisfied))
+		}
+	}
+
+	if s.anyOf != nil {
+		notSatisfied := types.MakeArray(0)
+
+		for i, sub := range s.anyOf {
+			details := sub.validate(v)
+			if len(details) == 0 {
+				notSatisfied = nil
+				break
+			}
+
+			notSatisfied.Append(schemaDetails(i, details))
+		}
+
+		if notSatisfied != nil {
+			res = append(res, s.rule("anyOf", "schemasNotSatisfied", notSatisfied))
+		}
+	}
+
+	if s.oneOf != nil {
+		notSatisfied := types.MakeArray(0)
+		matching := types.MakeArray(0)
+
+		for i, sub := range s.oneOf {
+			if details := sub.validate(v); len(details) > 0 {
+				notSatisfied.Append(schemaDetails(i, details))
+			} else {
+				matching.Append(int32(i))
+			}
+		}
+
+		if matching.Len() == 0 {
+			res = append(res, s.rule("oneOf", "schemasNotSatisfied", notSatisfied))
+		}
+
+		if matching.Len() > 1 {
+			res = append(res, s.rule("oneOf",
+				"reason", "more than one subschema matched",
+				"matchingSchemaIndexes", matching,
+			))
+		}
+	}
+
+	if s.not != nil && len(s.not.validate(v)) == 0 {
+		res = append(res, s.rule("not", "reason", "child expression matched"))
+	}
+
+	return res
+}
+
+// rule returns details of the keyword that was not satisfied.
+func (s *jsonSchema) rule(keyword string, pairs ...any) *types.Document {
+	doc := must.NotFail(types.NewDocument(
+		"operatorName", keyword,
+		"specifiedAs", must.NotFail(types.NewDocument(keyword, must.NotFail(s.spec.Get(keyword)))),
+	))
+
+	for i := 0; i < len(pairs); i += 2 {
+		doc.Set(pairs[i].(string), pairs[i+1])
+	}
+
+	return doc
+}
+
+// propertyDetails returns details of the property that did not match the subschema.
+func propertyDetails(name string, details []*types.Document) *types.Document {
+	return must.NotFail(types.NewDocument(
+		"propertyName", name,
+		"details", detailsArray(details),
+	))
+}
+
+// schemaDetails returns details of the subschema of allOf, anyOf or oneOf that did not match.
+func schemaDetails(index int, details []*types.Document) *types.Document {
+	return must.NotFail(types.NewDocument(
+		"index", int32(index),
+		"details", detailsArray(details),
+	))
+}
+
+// detailsArray converts rules to an array.
+func detailsArray(details []*types.Document) *types.Array {
+	arr := types.MakeArray(len(details))
+	for _, d := range details {
+		arr.Append(d)
+	}
+
+	return arr
+}
+
+// arrayTail returns array elements starting from the given index.
+func arrayTail(arr *types.Array, from int) *types.Array {
+	res := types.MakeArray(arr.Len() - from)
+	for i := from; i < arr.Len(); i++ {
+		res.Append(must.NotFail(arr.Get(i)))
+	}
+
+	return res
+}
@@ -317,9 +317,14 @@ func getBinaryMaskParam(operator string, mask any) (uint64, error) {
 }
 
 // addNumbers returns the result of v1 and v2 addition and error if addition failed.
-// The v1 and v2 parameters could be float64, int32, int64.
-// The result would be the broader type possible, i.e. int32 + int64 produces int64.
+// The v1 and v2 parameters could be float64, int32, int64 or types.Decimal128.
+// The result would be the broader type possible, i.e. int32 + int64 produces int64,
+// and any number + types.Decimal128 produces types.Decimal128.
 func addNumbers(v1, v2 any) (any, error) {
+	if res, ok, err := decimal128Arithmetic(v1, v2, types.Decimal128.Add); ok {
+		return res, err
+	}
+
 	switch v1 := v1.(type) {
 	case float64:
 		switch v2 := v2.(type) {
@@ -398,11 +403,15 @@ func addNumbers(v1, v2 any) (any, error) {
 }
 
 // multiplyNumbers returns the multiplication of v1 and v2.
-// The v1 and v2 parameters could be float64, int32 and int64.
+// The v1 and v2 parameters could be float64, int32, int64 and types.Decimal128.
 // Multiplication of negative number with zero produces 0, not -0.
 // The produced result maybe be the broader type:
 // int32 * int64 produces int64.
 func multiplyNumbers(v1, v2 any) (any, error) {
+	if res, ok, err := decimal128Arithmetic(v1, v2, types.Decimal128.Mul); ok {
+		return res, err
+	}
+
 	switch v1 := v1.(type) {
 	case float64:
 		var res float64
@@ -458,6 +467,29 @@ func multiplyNumbers(v1, v2 any) (any, error) {
 	}
 }
 
+// decimal128Arithmetic applies op to v1 and v2 if any of them is Decimal128,
+// converting the other number to Decimal128. If neither of them is Decimal128, ok is false.
+func decimal128Arithmetic(v1, v2 any, op func(a, b types.Decimal128) types.Decimal128) (res any, ok bool, err error) {
+	_, isDecimal1 := v1.(types.Decimal128)
+	_, isDecimal2 := v2.(types.Decimal128)
+
+	if !isDecimal1 && !isDecimal2 {
+		return nil, false, nil
+	}
+
+	d1, ok := types.NewDecimal128FromNumber(v1)
+	if !ok {
+		return nil, true, handlerparams.ErrUnexpectedLeftOpType
+	}
+
+	d2, ok := types.NewDecimal128FromNumber(v2)
+	if !ok {
+		return nil, true, handlerparams.ErrUnexpectedRightOpType
+	}
+
+	return op(d1, d2), true, nil
+}
+
 // multiplyLongSafely returns the multiplication of two int64 values.
 // It handles int64 overflows, and returns errLongExceeded error on one.
 //
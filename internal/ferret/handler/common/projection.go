@@ -21,6 +21,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -46,7 +48,11 @@ import (
 //   - `ErrBadPositionalProjection` when array or filter at positional projection path is empty;
 //   - `ErrBadPositionalProjection` when there is no filter field key for positional projection path;
 //   - `ErrElementMismatchPositionalProjection` when unexpected array was found on positional projection path;
+//   - `ErrEmptySubProject` when sub-projection document is empty;
 //   - `ErrNotImplemented` when there is unimplemented projection operators and expressions.
+//
+// Sub-projections like {a: {b: 1}} are returned with dotted paths like {"a.b": true}.
+// Field paths, arrays and operators are aggregation expressions, other values are literals.
 func ValidateProjection(projection *types.Document) (*types.Document, bool, error) {
 	validated := types.MakeDocument(0)
 
@@ -55,6 +61,11 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 		return types.MakeDocument(0), false, nil
 	}
 
+	projection, err := flattenSubProjections(projection)
+	if err != nil {
+		return nil, false, err
+	}
+
 	var inclusion *bool
 
 	iter := projection.Iterator()
@@ -147,16 +158,48 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 
 		switch value := value.(type) {
 		case *types.Document:
-			return nil, false, handlererrors.NewCommandErrorMsg(
-				handlererrors.ErrNotImplemented,
-				fmt.Sprintf("projection expression %s is not supported", types.FormatAnyValue(value)),
-			)
+			if isTextScoreMeta(value) {
+				// metadata fields are allowed in both inclusion and exclusion projections
+				validated.Set(key, value)
+
+				continue
+			}
+
+			if !operators.IsOperator(value) {
+				// expression object of _id, sub-projections of other fields are flattened
+				inclusionField = true
+
+				validated.Set(key, value)
+
+				break
+			}
+
+			// find-only projection operators
+			if value.Has("$elemMatch") || value.Has("$slice") {
+				return nil, false, handlererrors.NewCommandErrorMsg(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("projection expression %s is not supported", types.FormatAnyValue(value)),
+				)
+			}
+
+			op, err := operators.NewOperator(value)
+			if err = processProjectionError(err); err != nil {
+				return nil, false, err
+			}
+
+			if err = processProjectionError(operators.Validate(op)); err != nil {
+				return nil, false, err
+			}
+
+			inclusionField = true
+
+			validated.Set(key, value)
 		case *types.Array, string, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
 			inclusionField = true
 
 			validated.Set(key, value)
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			// projection treats 0 as false and any other value as true
 			comparison := types.Compare(value, int32(0))
 
@@ -218,9 +261,25 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 		}
 	}
 
+	if inclusion == nil {
+		// only metadata fields are projected
+		return validated, false, nil
+	}
+
 	return validated, *inclusion, nil
 }
 
+// isTextScoreMeta returns true if the projection value is {$meta: "textScore"}.
+func isTextScoreMeta(value *types.Document) bool {
+	if value.Len() != 1 {
+		return false
+	}
+
+	keyword, err := value.Get("$meta")
+
+	return err == nil && keyword == "textScore"
+}
+
 // ProjectDocument applies projection to the copy of the document.
 // It returns proper CommandError that can be returned by $project aggregation stage.
 //
@@ -230,10 +289,15 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 // - ErrNotImplemented when the operator is not implemented yet.
 // - ErrOperatorWrongLenOfArgs when the operator has an invalid number of arguments.
 // - ErrInvalidPipelineOperator when an the operator does not exist.
-func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*types.Document, error) {
-	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
-	if err != nil {
-		return nil, err
+//
+// Metadata fields like {score: {$meta: "textScore"}} are set from the metadata of vars.
+func ProjectDocument(
+	doc, projection, filter *types.Document, inclusion bool, vars *operators.Variables,
+) (*types.Document, error) {
+	projected := types.MakeDocument(1)
+
+	if id, err := doc.Get("_id"); err == nil {
+		projected.Set("_id", id)
 	}
 
 	projected.SetRecordID(doc.RecordID())
@@ -244,15 +308,22 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 		var set bool
 
 		switch idValue := idValue.(type) {
-		case *types.Document: // field: { $elemMatch: { field2: value }}
-			return nil, handlererrors.NewCommandErrorMsg(
-				handlererrors.ErrCommandNotFound,
-				fmt.Sprintf("projection %s is not supported",
-					types.FormatAnyValue(idValue),
-				),
-			)
+		case *types.Document, *types.Array, string: // expressions
+			value, err := operators.Evaluate(idValue, doc, vars)
+			if err != nil {
+				return nil, processProjectionError(err)
+			}
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+			if value == nil {
+				// the expression evaluated to a missing value
+				break
+			}
+
+			projected.Set("_id", value)
+
+			set = true
+
+		case types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
@@ -269,7 +340,7 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 		}
 	}
 
-	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, filter, inclusion)
+	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, filter, inclusion, vars)
 	if err != nil {
 		return nil, err
 	}
@@ -283,7 +354,9 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 
 // projectDocumentWithoutID applies projection to the copy of the document and returns projected document.
 // It ignores _id field in the projection.
-func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Document, inclusion bool) (*types.Document, error) {
+func projectDocumentWithoutID(
+	doc *types.Document, projection, filter *types.Document, inclusion bool, vars *operators.Variables,
+) (*types.Document, error) {
 	projectionWithoutID := projection.DeepCopy()
 	projectionWithoutID.Remove("_id")
 
@@ -296,6 +369,10 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 		projected = docWithoutID.DeepCopy()
 	}
 
+	// computed fields are set after included fields, like MongoDB does
+	var computedPaths []types.Path
+	var computedValues []any
+
 	iter := projectionWithoutID.Iterator()
 	defer iter.Close()
 
@@ -315,17 +392,36 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 		}
 
 		switch value := value.(type) { // found in the projection
-		case *types.Document: // field: { $elemMatch: { field2: value }}
-			return nil, handlererrors.NewCommandErrorMsg(
-				handlererrors.ErrCommandNotFound,
-				fmt.Sprintf("projection %s is not supported",
-					types.FormatAnyValue(value),
-				),
-			)
+		case *types.Document, *types.Array, string: // expressions
+			var v any
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+			if d, ok := value.(*types.Document); ok && isTextScoreMeta(d) {
+				score, ok := vars.Metadata().TextScore(doc)
+				if !ok {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrTextScoreUnavailable,
+						"query requires text score metadata, but it is not available",
+						"projection",
+					)
+				}
+
+				v = score
+			} else if v, err = operators.Evaluate(value, doc, vars); err != nil {
+				return nil, processProjectionError(err)
+			}
+
+			if v == nil {
+				// the expression evaluated to a missing value
+				break
+			}
+
+			computedPaths = append(computedPaths, path)
+			computedValues = append(computedValues, v)
+
+		case types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
-			projected.Set(key, value)
+			computedPaths = append(computedPaths, path)
+			computedValues = append(computedValues, value)
 
 		case bool: // field: bool
 			if inclusion {
@@ -344,9 +440,185 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 		}
 	}
 
+	for i, path := range computedPaths {
+		setComputedField(path, computedValues[i], docWithoutID, projected)
+	}
+
 	return projected, nil
 }
 
+// setComputedField sets the value of the computed field on the path in projected.
+// Missing or non-document values on the path are replaced with documents.
+// Missing fields with documents or arrays in the source get the documents of the source,
+// like an inclusion of the path.
+// When an array is on the path, the field is set in each element of the array.
+//
+// Example: "v.foo" path with value 1:
+//
+//	{}                  -> {v: {foo: 1}}
+//	{v: 42}             -> {v: {foo: 1}}
+//	{v: {bar: 1}}       -> {v: {bar: 1, foo: 1}}
+//	{v: [{bar: 1}, 42]} -> {v: [{bar: 1, foo: 1}, {foo: 1}]}
+//
+// Example: "v.foo" path with value 1 and source {v: [{bar: 1}, 42]}:
+//
+//	{} -> {v: [{foo: 1}]}
+func setComputedField(path types.Path, value any, source, projected *types.Document) {
+	key := path.Prefix()
+
+	if path.Len() == 1 {
+		projected.Set(key, value)
+		return
+	}
+
+	var embeddedSource any
+	if source != nil {
+		embeddedSource, _ = source.Get(key)
+	}
+
+	embedded, err := projected.Get(key)
+	if err != nil {
+		// the field is not included, use documents of the source
+		switch embeddedSource := embeddedSource.(type) {
+		case *types.Document:
+			embedded = types.MakeDocument(1)
+		case *types.Array:
+			arr := types.MakeArray(embeddedSource.Len())
+
+			for i := 0; i < embeddedSource.Len(); i++ {
+				if _, ok := must.NotFail(embeddedSource.Get(i)).(*types.Document); ok {
+					arr.Append(types.MakeDocument(1))
+				}
+			}
+
+			embedded = arr
+		}
+	}
+
+	switch embedded := embedded.(type) {
+	case *types.Document:
+		sourceDoc, _ := embeddedSource.(*types.Document)
+		setComputedField(path.TrimPrefix(), value, sourceDoc, embedded)
+		projected.Set(key, embedded)
+	case *types.Array:
+		for i := 0; i < embedded.Len(); i++ {
+			elem, ok := must.NotFail(embedded.Get(i)).(*types.Document)
+			if !ok {
+				elem = types.MakeDocument(1)
+			}
+
+			setComputedField(path.TrimPrefix(), value, nil, elem)
+			must.NoError(embedded.Set(i, elem))
+		}
+
+		projected.Set(key, embedded)
+	default:
+		doc := types.MakeDocument(1)
+		setComputedField(path.TrimPrefix(), value, nil, doc)
+		projected.Set(key, doc)
+	}
+}
+
+// flattenSubProjections returns the projection with sub-projections like {a: {b: 1}}
+// replaced with dotted paths like {"a.b": 1}.
+// Operator expressions and the value of _id are not sub-projections.
+//
+// Command error codes:
+//   - `ErrEmptySubProject` when sub-projection is empty.
+func flattenSubProjections(projection *types.Document) (*types.Document, error) {
+	res := types.MakeDocument(projection.Len())
+
+	iter := projection.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		sub, ok := value.(*types.Document)
+		if !ok || key == "_id" || operators.IsOperator(sub) {
+			res.Set(key, value)
+			continue
+		}
+
+		if sub.Len() == 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrEmptySubProject,
+				"An empty sub-projection is not a valid value. Found empty object at path",
+				"projection",
+			)
+		}
+
+		flattened, err := flattenSubProjections(sub)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subKey := range flattened.Keys() {
+			res.Set(key+"."+subKey, must.NotFail(flattened.Get(subKey)))
+		}
+	}
+
+	return res, nil
+}
+
+// processProjectionError takes internal error related to operator evaluation and
+// returns proper CommandError that can be returned for the projection.
+func processProjectionError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var opErr operators.OperatorError
+	var exErr *aggregations.ExpressionError
+
+	switch {
+	case errors.As(err, &opErr):
+		switch opErr.Code() {
+		case operators.ErrTooManyFields:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFieldPathInvalidName,
+				"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
+				"projection",
+			)
+		case operators.ErrNotImplemented:
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNotImplemented, opErr.Error(), "projection")
+		case operators.ErrArgsInvalidLen:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrOperatorWrongLenOfArgs, opErr.Error(), "projection",
+			)
+		case operators.ErrInvalidExpression, operators.ErrInvalidNestedExpression:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidPipelineOperator, opErr.Error(), "projection",
+			)
+		}
+
+	case errors.As(err, &exErr):
+		switch exErr.Code() {
+		case aggregations.ErrEmptyFieldPath:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrGroupInvalidFieldPath,
+				"'$' by itself is not a valid FieldPath",
+				"projection",
+			)
+		case aggregations.ErrNotExpression, aggregations.ErrInvalidExpression:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"'$' starts with an invalid character for a user variable name",
+				"projection",
+			)
+		}
+	}
+
+	return err
+}
+
 // includeProjection copies the field on the path from source to projected.
 // When an array is on the path, it returns the array containing any document
 // with the same key. Dot notation with array index path does not include
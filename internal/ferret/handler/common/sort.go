@@ -20,6 +20,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
@@ -31,6 +32,14 @@ import (
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
+	return SortDocumentsWithCollation(docs, sortDoc, nil, nil)
+}
+
+// SortDocumentsWithCollation is like SortDocuments, but compares strings according to the given collation.
+// Metadata sort keys like {score: {$meta: "textScore"}} use the metadata of the given variables.
+func SortDocumentsWithCollation(
+	docs []*types.Document, sortDoc *types.Document, vars *operators.Variables, coll *types.Collation,
+) error {
 	if sortDoc.Len() == 0 {
 		return nil
 	}
@@ -61,6 +70,16 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 
 		sortField := must.NotFail(sortDoc.Get(sortKey))
 
+		if metaDoc, ok := sortField.(*types.Document); ok {
+			if err := validateSortMeta(sortKey, metaDoc); err != nil {
+				return err
+			}
+
+			sortFuncs[i] = textScoreLessFunc(vars.Metadata())
+
+			continue
+		}
+
 		sortType, err := GetSortType(sortKey, sortField)
 		if err != nil {
 			return err
@@ -71,7 +90,7 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 			return err
 		}
 
-		sortFuncs[i] = lessFunc(sortPath, sortType)
+		sortFuncs[i] = lessFunc(sortPath, sortType, coll)
 	}
 
 	if len(sortFuncs) == 0 {
@@ -125,6 +144,16 @@ func ValidateSortDocument(sortDoc *types.Document) (*types.Document, error) {
 
 		sortField := must.NotFail(sortDoc.Get(sortKey))
 
+		if metaDoc, ok := sortField.(*types.Document); ok {
+			if err := validateSortMeta(sortKey, metaDoc); err != nil {
+				return nil, err
+			}
+
+			res.Set(sortKey, metaDoc)
+
+			continue
+		}
+
 		sortValue, err := getSortValue(sortKey, sortField)
 		if err != nil {
 			return nil, err
@@ -143,7 +172,7 @@ func ValidateSortDocument(sortDoc *types.Document) (*types.Document, error) {
 
 // lessFunc takes sort key and type and returns sort.Interface's Less function which
 // compares selected key of 2 documents.
-func lessFunc(sortPath types.Path, sortType types.SortType) func(a, b *types.Document) bool {
+func lessFunc(sortPath types.Path, sortType types.SortType, coll *types.Collation) func(a, b *types.Document) bool {
 	return func(a, b *types.Document) bool {
 		aField, err := a.GetByPath(sortPath)
 		if err != nil {
@@ -158,12 +187,44 @@ func lessFunc(sortPath types.Path, sortType types.SortType) func(a, b *types.Doc
 			bField = types.Null
 		}
 
-		result := types.CompareOrderForSort(aField, bField, sortType)
+		result := coll.CompareOrderForSort(aField, bField, sortType)
 
 		return result == types.Less
 	}
 }
 
+// textScoreLessFunc returns sort.Interface's Less function which
+// orders documents by descending text score; documents without it are last.
+func textScoreLessFunc(meta *operators.Metadata) func(a, b *types.Document) bool {
+	return func(a, b *types.Document) bool {
+		aScore, _ := meta.TextScore(a)
+		bScore, _ := meta.TextScore(b)
+
+		return aScore > bScore
+	}
+}
+
+// validateSortMeta validates the metadata sort value {$meta: "textScore"}.
+func validateSortMeta(key string, value *types.Document) error {
+	if value.Len() != 1 || !value.Has("$meta") {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortBadValue,
+			fmt.Sprintf(`Illegal key in $sort specification: %v: %v`, key, types.FormatAnyValue(value)),
+			"$sort",
+		)
+	}
+
+	if keyword := must.NotFail(value.Get("$meta")); keyword != "textScore" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortBadValue,
+			fmt.Sprintf(`Illegal $meta sort: %v: %v`, key, types.FormatAnyValue(value)),
+			"$sort",
+		)
+	}
+
+	return nil
+}
+
 type sortFunc func(a, b *types.Document) bool
 
 type docsSorter struct {
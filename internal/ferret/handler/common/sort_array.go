@@ -23,13 +23,19 @@ import (
 
 // SortArray sorts the values of given array.
 func SortArray(arr *types.Array, sortType types.SortType) {
-	sorter := &arraySorter{arr: arr, sortType: sortType}
+	SortArrayWithCollation(arr, sortType, nil)
+}
+
+// SortArrayWithCollation is like SortArray, but compares strings according to the given collation.
+func SortArrayWithCollation(arr *types.Array, sortType types.SortType, coll *types.Collation) {
+	sorter := &arraySorter{arr: arr, sortType: sortType, coll: coll}
 	sort.Sort(sorter)
 }
 
 // arraySorter implements sort.Interface to sort values of arrays.
 type arraySorter struct {
 	arr      *types.Array
+	coll     *types.Collation
 	sortType types.SortType
 }
 
@@ -56,7 +62,7 @@ func (as *arraySorter) Less(i, j int) bool {
 		p = types.Null
 	}
 
-	result := types.CompareOrderForSort(p, q, as.sortType)
+	result := as.coll.CompareOrderForSort(p, q, as.sortType)
 
 	return result == types.Less
 }
@@ -15,6 +15,7 @@
 package common
 
 import (
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
@@ -25,7 +26,8 @@ import (
 //
 // Since sorting iterator is impossible, this function fully consumes and closes the underlying iterator,
 // sorts documents in memory and returns a new iterator over the sorted slice.
-func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
+// Strings are compared according to the given collation, and metadata sort keys use the metadata of vars.
+func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document, vars *operators.Variables, coll *types.Collation) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// don't consume all documents if there is no sort
 	if sort.Len() == 0 {
 		return iter, nil
@@ -36,7 +38,7 @@ func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, so
 		return nil, lazyerrors.Error(err)
 	}
 
-	if err = SortDocuments(docs, sort); err != nil {
+	if err = SortDocumentsWithCollation(docs, sort, vars, coll); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
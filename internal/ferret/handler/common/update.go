@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
@@ -31,6 +32,53 @@ import (
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
 )
 
+// UpdatePaths maps keys of update operators to parsed paths.
+//
+// It allows to parse paths once for the update applied to many documents.
+// Keys missing from the map are parsed on demand; nil UpdatePaths is valid.
+type UpdatePaths map[string]types.Path
+
+// ParseUpdatePaths parses keys of all update operators (and $rename targets) of the given update document.
+// To validate update document, must call ValidateUpdateOperators before calling ParseUpdatePaths.
+func ParseUpdatePaths(update *types.Document) UpdatePaths {
+	paths := UpdatePaths{}
+
+	for _, updateOp := range update.Keys() {
+		opDoc, ok := must.NotFail(update.Get(updateOp)).(*types.Document)
+		if !ok || !strings.HasPrefix(updateOp, "$") {
+			continue
+		}
+
+		for _, key := range opDoc.Keys() {
+			keys := []string{key}
+
+			if updateOp == "$rename" {
+				if target, ok := must.NotFail(opDoc.Get(key)).(string); ok {
+					keys = append(keys, target)
+				}
+			}
+
+			for _, k := range keys {
+				// invalid paths are left to the operators that report them with proper errors
+				if path, err := types.NewPathFromString(k); err == nil {
+					paths[k] = path
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+// path returns the parsed path for the given key, parsing it if needed.
+func (p UpdatePaths) path(key string) (types.Path, error) {
+	if path, ok := p[key]; ok {
+		return path, nil
+	}
+
+	return types.NewPathFromString(key)
+}
+
 // UpdateDocument updates the given document with a series of update operators.
 // Returns true if document was changed.
 // To validate update document, must call ValidateUpdateOperators before calling UpdateDocument.
@@ -38,6 +86,17 @@ import (
 // WriteError for other commands.
 // TODO https://github.com/FerretDB/FerretDB/issues/3013
 func UpdateDocument(command string, doc, update *types.Document, insert bool) (bool, error) {
+	return UpdateDocumentWithPaths(command, doc, update, insert, nil, nil, nil)
+}
+
+// UpdateDocumentWithPaths is like UpdateDocument, but uses paths parsed beforehand by ParseUpdatePaths.
+// $currentDate sets the value of $$NOW from the given variables, which may be nil;
+// then the current time is used.
+// Array update operators compare strings according to the given collation.
+func UpdateDocumentWithPaths(
+	command string, doc, update *types.Document, insert bool,
+	paths UpdatePaths, vars *operators.Variables, coll *types.Collation,
+) (bool, error) {
 	var docUpdated bool
 	var err error
 
@@ -61,13 +120,13 @@ func UpdateDocument(command string, doc, update *types.Document, insert bool) (b
 
 		switch updateOp {
 		case "$currentDate":
-			updated, err = processCurrentDateFieldExpression(doc, updateV)
+			updated, err = processCurrentDateFieldExpression(doc, updateV, currentTime(vars))
 			if err != nil {
 				return false, err
 			}
 
 		case "$set":
-			updated, err = processSetFieldExpression(command, doc, updateV.(*types.Document), false)
+			updated, err = processSetFieldExpression(command, doc, updateV.(*types.Document), false, paths)
 			if err != nil {
 				return false, err
 			}
@@ -77,7 +136,7 @@ func UpdateDocument(command string, doc, update *types.Document, insert bool) (b
 				continue
 			}
 
-			updated, err = processSetFieldExpression(command, doc, updateV.(*types.Document), true)
+			updated, err = processSetFieldExpression(command, doc, updateV.(*types.Document), true, paths)
 			if err != nil {
 				return false, err
 			}
@@ -89,7 +148,7 @@ func UpdateDocument(command string, doc, update *types.Document, insert bool) (b
 			for _, key := range unsetDoc.Keys() {
 				var path types.Path
 
-				path, err = types.NewPathFromString(key)
+				path, err = paths.path(key)
 				if err != nil {
 					// ValidateUpdateOperators checked already $unset contains valid path.
 					panic(err)
@@ -102,66 +161,66 @@ func UpdateDocument(command string, doc, update *types.Document, insert bool) (b
 			}
 
 		case "$inc":
-			updated, err = processIncFieldExpression(command, doc, updateV)
+			updated, err = processIncFieldExpression(command, doc, updateV, paths)
 			if err != nil {
 				return false, err
 			}
 
 		case "$max":
-			updated, err = processMaxFieldExpression(command, doc, updateV)
+			updated, err = processMaxFieldExpression(command, doc, updateV, paths)
 			if err != nil {
 				return false, err
 			}
 
 		case "$min":
-			updated, err = processMinFieldExpression(command, doc, updateV)
+			updated, err = processMinFieldExpression(command, doc, updateV, paths)
 			if err != nil {
 				return false, err
 			}
 
 		case "$mul":
-			if updated, err = processMulFieldExpression(command, doc, updateV); err != nil {
+			if updated, err = processMulFieldExpression(command, doc, updateV, paths); err != nil {
 				return false, err
 			}
 
 		case "$rename":
-			updated, err = processRenameFieldExpression(command, doc, updateV.(*types.Document))
+			updated, err = processRenameFieldExpression(command, doc, updateV.(*types.Document), paths)
 			if err != nil {
 				return false, err
 			}
 
 		case "$pop":
-			updated, err = processPopArrayUpdateExpression(doc, updateV.(*types.Document))
+			updated, err = processPopArrayUpdateExpression(doc, updateV.(*types.Document), paths)
 			if err != nil {
 				return false, err
 			}
 
 		case "$push":
-			updated, err = processPushArrayUpdateExpression(doc, updateV.(*types.Document))
+			updated, err = processPushArrayUpdateExpression(doc, updateV.(*types.Document), paths)
 			if err != nil {
 				return false, err
 			}
 
 		case "$addToSet":
-			updated, err = processAddToSetArrayUpdateExpression(doc, updateV.(*types.Document))
+			updated, err = processAddToSetArrayUpdateExpression(doc, updateV.(*types.Document), paths, coll)
 			if err != nil {
 				return false, err
 			}
 
 		case "$pullAll":
-			updated, err = processPullAllArrayUpdateExpression(doc, updateV.(*types.Document))
+			updated, err = processPullAllArrayUpdateExpression(doc, updateV.(*types.Document), paths, coll)
 			if err != nil {
 				return false, err
 			}
 
 		case "$pull":
-			updated, err = processPullArrayUpdateExpression(doc, updateV.(*types.Document))
+			updated, err = processPullArrayUpdateExpression(doc, updateV.(*types.Document), paths, coll)
 			if err != nil {
 				return false, err
 			}
 
 		case "$bit":
-			updated, err = processBitFieldExpression(command, doc, updateV.(*types.Document))
+			updated, err = processBitFieldExpression(command, doc, updateV.(*types.Document), paths)
 			if err != nil {
 				return false, err
 			}
@@ -199,7 +258,7 @@ func UpdateDocument(command string, doc, update *types.Document, insert bool) (b
 
 // processSetFieldExpression changes document according to $set and $setOnInsert operators.
 // If the document was changed it returns true.
-func processSetFieldExpression(command string, doc, setDoc *types.Document, setOnInsert bool) (bool, error) {
+func processSetFieldExpression(command string, doc, setDoc *types.Document, setOnInsert bool, paths UpdatePaths) (bool, error) {
 	var changed bool
 
 	setDocKeys := setDoc.Keys()
@@ -223,7 +282,7 @@ func processSetFieldExpression(command string, doc, setDoc *types.Document, setO
 		}
 
 		// setKey has valid path, checked in ValidateUpdateOperators.
-		path := must.NotFail(types.NewPathFromString(setKey))
+		path := must.NotFail(paths.path(setKey))
 
 		if doc.HasByPath(path) {
 			docValue := must.NotFail(doc.GetByPath(path))
@@ -249,7 +308,7 @@ func processSetFieldExpression(command string, doc, setDoc *types.Document, setO
 
 // processRenameFieldExpression changes document according to $rename operator.
 // If the document was changed it returns true.
-func processRenameFieldExpression(command string, doc *types.Document, update *types.Document) (bool, error) {
+func processRenameFieldExpression(command string, doc *types.Document, update *types.Document, paths UpdatePaths) (bool, error) {
 	update.SortFieldsByKey()
 
 	var changed bool
@@ -268,7 +327,7 @@ func processRenameFieldExpression(command string, doc *types.Document, update *t
 		// this is covered in validateRenameExpression
 		renameValue := renameRawValue.(string)
 
-		sourcePath, err := types.NewPathFromString(key)
+		sourcePath, err := paths.path(key)
 		if err != nil {
 			var pathErr *types.PathError
 			if errors.As(err, &pathErr) && pathErr.Code() == types.ErrPathElementEmpty {
@@ -283,7 +342,7 @@ func processRenameFieldExpression(command string, doc *types.Document, update *t
 			}
 		}
 
-		targetPath, err := types.NewPathFromString(renameValue)
+		targetPath, err := paths.path(renameValue)
 		if err != nil {
 			return changed, lazyerrors.Error(err)
 		}
@@ -327,7 +386,7 @@ func processRenameFieldExpression(command string, doc *types.Document, update *t
 
 // processIncFieldExpression changes document according to $inc operator.
 // If the document was changed it returns true.
-func processIncFieldExpression(command string, doc *types.Document, updateV any) (bool, error) {
+func processIncFieldExpression(command string, doc *types.Document, updateV any, paths UpdatePaths) (bool, error) {
 	// updateV is document, checked in ValidateUpdateOperators.
 	incDoc := updateV.(*types.Document)
 
@@ -338,7 +397,7 @@ func processIncFieldExpression(command string, doc *types.Document, updateV any)
 
 		// ensure incValue is a valid number type.
 		switch incValue.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 		default:
 			return false, newUpdateError(
 				handlererrors.ErrTypeMismatch,
@@ -350,7 +409,7 @@ func processIncFieldExpression(command string, doc *types.Document, updateV any)
 		var err error
 
 		// incKey has valid path, checked in ValidateUpdateOperators.
-		path := must.NotFail(types.NewPathFromString(incKey))
+		path := must.NotFail(paths.path(incKey))
 
 		if !doc.HasByPath(path) {
 			// $inc sets the field if it does not exist.
@@ -367,7 +426,7 @@ func processIncFieldExpression(command string, doc *types.Document, updateV any)
 			continue
 		}
 
-		path, err = types.NewPathFromString(incKey)
+		path, err = paths.path(incKey)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -379,6 +438,15 @@ func processIncFieldExpression(command string, doc *types.Document, updateV any)
 
 		incremented, err := addNumbers(incValue, docValue)
 		if err == nil {
+			if incremented, ok := incremented.(types.Decimal128); ok && incremented.IsInf() != 0 {
+				return false, handlererrors.NewCommandErrorMsg(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("update produces invalid value: { %q: %s } "+
+						"(update operations that produce infinity values are not allowed)", path, incremented,
+					),
+				)
+			}
+
 			if err = doc.SetByPath(path, incremented); err != nil {
 				return false, lazyerrors.Error(err)
 			}
@@ -445,7 +513,7 @@ func processIncFieldExpression(command string, doc *types.Document, updateV any)
 
 // processMaxFieldExpression changes document according to $max operator.
 // If the document was changed it returns true.
-func processMaxFieldExpression(command string, doc *types.Document, updateV any) (bool, error) {
+func processMaxFieldExpression(command string, doc *types.Document, updateV any, paths UpdatePaths) (bool, error) {
 	maxExpression := updateV.(*types.Document)
 	maxExpression.SortFieldsByKey()
 
@@ -465,7 +533,7 @@ func processMaxFieldExpression(command string, doc *types.Document, updateV any)
 		}
 
 		// maxKey has valid path, checked in ValidateUpdateOperators.
-		path := must.NotFail(types.NewPathFromString(maxKey))
+		path := must.NotFail(paths.path(maxKey))
 
 		if !doc.HasByPath(path) {
 			err = doc.SetByPath(path, maxVal)
@@ -513,7 +581,7 @@ func processMaxFieldExpression(command string, doc *types.Document, updateV any)
 
 // processMinFieldExpression changes document according to $min operator.
 // If the document was changed it returns true.
-func processMinFieldExpression(command string, doc *types.Document, updateV any) (bool, error) {
+func processMinFieldExpression(command string, doc *types.Document, updateV any, paths UpdatePaths) (bool, error) {
 	minExpression := updateV.(*types.Document)
 	minExpression.SortFieldsByKey()
 
@@ -533,7 +601,7 @@ func processMinFieldExpression(command string, doc *types.Document, updateV any)
 		}
 
 		// minKey has valid path, checked in ValidateUpdateOperators.
-		path := must.NotFail(types.NewPathFromString(minKey))
+		path := must.NotFail(paths.path(minKey))
 
 		if !doc.HasByPath(path) {
 			err = doc.SetByPath(path, minVal)
@@ -574,7 +642,7 @@ func processMinFieldExpression(command string, doc *types.Document, updateV any)
 
 // processMulFieldExpression updates document according to $mul operator.
 // If the document was changed it returns true.
-func processMulFieldExpression(command string, doc *types.Document, updateV any) (bool, error) {
+func processMulFieldExpression(command string, doc *types.Document, updateV any, paths UpdatePaths) (bool, error) {
 	// updateV is document, checked in ValidateUpdateOperators.
 	mulDoc := updateV.(*types.Document)
 
@@ -586,7 +654,7 @@ func processMulFieldExpression(command string, doc *types.Document, updateV any)
 		var path types.Path
 		var err error
 
-		path, err = types.NewPathFromString(mulKey)
+		path, err = paths.path(mulKey)
 		if err != nil {
 			// ValidateUpdateOperators checked already $mul contains valid path.
 			panic(err)
@@ -601,6 +669,8 @@ func processMulFieldExpression(command string, doc *types.Document, updateV any)
 				mulValue = int32(0)
 			case int64:
 				mulValue = int64(0)
+			case types.Decimal128:
+				mulValue = types.NewDecimal128FromInt64(0)
 			default:
 				return false, newUpdateError(
 					handlererrors.ErrTypeMismatch,
@@ -642,6 +712,15 @@ func processMulFieldExpression(command string, doc *types.Document, updateV any)
 				)
 			}
 
+			if multiplied, ok := multiplied.(types.Decimal128); ok && multiplied.IsInf() != 0 {
+				return false, handlererrors.NewCommandErrorMsg(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("update produces invalid value: { %q: %s } "+
+						"(update operations that produce infinity values are not allowed)", path, multiplied,
+					),
+				)
+			}
+
 			err = doc.SetByPath(path, multiplied)
 			if err != nil {
 				// after successfully getting value from path, setting it back cannot fail.
@@ -716,11 +795,10 @@ func processMulFieldExpression(command string, doc *types.Document, updateV any)
 
 // processCurrentDateFieldExpression changes document according to $currentDate operator.
 // If the document was changed it returns true.
-func processCurrentDateFieldExpression(doc *types.Document, currentDateVal any) (bool, error) {
+func processCurrentDateFieldExpression(doc *types.Document, currentDateVal any, now time.Time) (bool, error) {
 	var changed bool
 	currentDateExpression := currentDateVal.(*types.Document)
 
-	now := time.Now().UTC()
 	keys := currentDateExpression.Keys()
 	sort.Strings(keys)
 
@@ -755,9 +833,20 @@ func processCurrentDateFieldExpression(doc *types.Document, currentDateVal any)
 	return changed, nil
 }
 
+// currentTime returns the value of $$NOW from vars, or the current time if they do not define it.
+func currentTime(vars *operators.Variables) time.Time {
+	if now, ok := vars.Get("NOW"); ok {
+		if t, ok := now.(time.Time); ok {
+			return t
+		}
+	}
+
+	return time.Now().UTC()
+}
+
 // processBitFieldExpression updates document according to $bit operator.
 // If document was changed, it returns true.
-func processBitFieldExpression(command string, doc *types.Document, updateV any) (bool, error) {
+func processBitFieldExpression(command string, doc *types.Document, updateV any, paths UpdatePaths) (bool, error) {
 	var changed bool
 
 	bitDoc := updateV.(*types.Document)
@@ -789,7 +878,7 @@ func processBitFieldExpression(command string, doc *types.Document, updateV any)
 		}
 
 		// bitKey has valid path, checked in ValidateUpdateOperators
-		path := must.NotFail(types.NewPathFromString(bitKey))
+		path := must.NotFail(paths.path(bitKey))
 
 		// $bit sets the field if it does not exist by applying bitwise operat on 0 and operand value.
 		var docValue any = int32(0)
@@ -28,7 +28,7 @@ import (
 
 // processPopArrayUpdateExpression changes document according to $pop operator.
 // If the document was changed it returns true.
-func processPopArrayUpdateExpression(doc *types.Document, update *types.Document) (bool, error) {
+func processPopArrayUpdateExpression(doc *types.Document, update *types.Document, paths UpdatePaths) (bool, error) {
 	var changed bool
 
 	iter := update.Iterator()
@@ -59,7 +59,7 @@ func processPopArrayUpdateExpression(doc *types.Document, update *types.Document
 			)
 		}
 
-		path, err := types.NewPathFromString(key)
+		path, err := paths.path(key)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -144,7 +144,7 @@ func checkUnsuitableValueError(doc *types.Document, key string, path types.Path)
 
 // processPushArrayUpdateExpression changes document according to $push array update operator.
 // If the document was changed it returns true.
-func processPushArrayUpdateExpression(doc *types.Document, update *types.Document) (bool, error) {
+func processPushArrayUpdateExpression(doc *types.Document, update *types.Document, paths UpdatePaths) (bool, error) {
 	var changed bool
 
 	iter := update.Iterator()
@@ -179,7 +179,7 @@ func processPushArrayUpdateExpression(doc *types.Document, update *types.Documen
 			}
 		}
 
-		path, err := types.NewPathFromString(key)
+		path, err := paths.path(key)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -233,7 +233,11 @@ func processPushArrayUpdateExpression(doc *types.Document, update *types.Documen
 
 // processAddToSetArrayUpdateExpression changes document according to $addToSet array update operator.
 // If the document was changed it returns true.
-func processAddToSetArrayUpdateExpression(doc, update *types.Document) (bool, error) {
+func processAddToSetArrayUpdateExpression(
+	doc, update *types.Document,
+	paths UpdatePaths,
+	coll *types.Collation,
+) (bool, error) {
 	var changed bool
 
 	iter := update.Iterator()
@@ -268,7 +272,7 @@ func processAddToSetArrayUpdateExpression(doc, update *types.Document) (bool, er
 			}
 		}
 
-		path, err := types.NewPathFromString(key)
+		path, err := paths.path(key)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -309,7 +313,7 @@ func processAddToSetArrayUpdateExpression(doc, update *types.Document) (bool, er
 		for i := 0; i < each.Len(); i++ {
 			value := must.NotFail(each.Get(i))
 
-			if array.Contains(value) {
+			if coll.Contains(array, value) {
 				continue
 			}
 
@@ -328,7 +332,11 @@ func processAddToSetArrayUpdateExpression(doc, update *types.Document) (bool, er
 
 // processPullAllArrayUpdateExpression changes document according to $pullAll array update operator.
 // If the document was changed it returns true.
-func processPullAllArrayUpdateExpression(doc, update *types.Document) (bool, error) {
+func processPullAllArrayUpdateExpression(
+	doc, update *types.Document,
+	paths UpdatePaths,
+	coll *types.Collation,
+) (bool, error) {
 	var changed bool
 
 	iter := update.Iterator()
@@ -344,7 +352,7 @@ func processPullAllArrayUpdateExpression(doc, update *types.Document) (bool, err
 			return false, lazyerrors.Error(err)
 		}
 
-		path, err := types.NewPathFromString(key)
+		path, err := paths.path(key)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -403,7 +411,7 @@ func processPullAllArrayUpdateExpression(doc, update *types.Document) (bool, err
 					return false, lazyerrors.Error(err)
 				}
 
-				if types.Compare(value, valueToPull) == types.Equal {
+				if coll.Compare(value, valueToPull) == types.Equal {
 					array.Remove(i)
 
 					changed = true
@@ -421,7 +429,11 @@ func processPullAllArrayUpdateExpression(doc, update *types.Document) (bool, err
 
 // processPullArrayUpdateExpression changes document according to $pull array update operator.
 // If the document was changed it returns true.
-func processPullArrayUpdateExpression(doc *types.Document, update *types.Document) (bool, error) {
+func processPullArrayUpdateExpression(
+	doc *types.Document, update *types.Document,
+	paths UpdatePaths,
+	coll *types.Collation,
+) (bool, error) {
 	var changed bool
 
 	iter := update.Iterator()
@@ -437,7 +449,7 @@ func processPullArrayUpdateExpression(doc *types.Document, update *types.Documen
 			return false, lazyerrors.Error(err)
 		}
 
-		path, err := types.NewPathFromString(key)
+		path, err := paths.path(key)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -470,7 +482,7 @@ func processPullArrayUpdateExpression(doc *types.Document, update *types.Documen
 		for i := array.Len() - 1; i >= 0; i-- {
 			value := must.NotFail(array.Get(i))
 
-			if types.Compare(value, pullValueRaw) == types.Equal {
+			if coll.Compare(value, pullValueRaw) == types.Equal {
 				array.Remove(i)
 
 				changed = true
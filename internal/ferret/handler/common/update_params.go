@@ -32,7 +32,7 @@ type UpdateParams struct {
 
 	Comment string `ferretdb:"comment,opt"`
 
-	Let *types.Document `ferretdb:"let,unimplemented"`
+	Let *types.Document `ferretdb:"let,opt"`
 
 	Ordered                  bool            `ferretdb:"ordered,ignored"`
 	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
@@ -52,7 +52,7 @@ type Update struct {
 	Upsert bool            `ferretdb:"upsert,opt,numericBool"`
 
 	C            *types.Document `ferretdb:"c,unimplemented"`
-	Collation    *types.Document `ferretdb:"collation,unimplemented"`
+	Collation    *types.Document `ferretdb:"collation,opt"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
 
 	Hint string `ferretdb:"hint,ignored"`
@@ -0,0 +1,285 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// Validation levels and actions of collection validators.
+const (
+	// ValidationLevelOff disables validation.
+	ValidationLevelOff = "off"
+	// ValidationLevelStrict validates all inserts and updates.
+	ValidationLevelStrict = "strict"
+	// ValidationLevelModerate validates inserts and updates of documents that were valid before the update.
+	ValidationLevelModerate = "moderate"
+
+	// ValidationActionError rejects invalid documents.
+	ValidationActionError = "error"
+	// ValidationActionWarn allows invalid documents; the caller should log a warning.
+	ValidationActionWarn = "warn"
+)
+
+// Validator represents a collection validator set by
+// validator, validationLevel and validationAction options of create and collMod commands.
+type Validator struct {
+	filter *types.Document
+	Level  string
+	Action string
+}
+
+// NewValidator returns a new validator for the given query filter that may use $jsonSchema.
+//
+// Empty level and action default to strict and error.
+func NewValidator(filter *types.Document, level, action string) (*Validator, error) {
+	switch level {
+	case "":
+		level = ValidationLevelStrict
+	case ValidationLevelOff, ValidationLevelStrict, ValidationLevelModerate:
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Enumeration value '%s' for field 'validationLevel' is not a valid value.", level),
+			"validationLevel",
+		)
+	}
+
+	switch action {
+	case "":
+		action = ValidationActionError
+	case ValidationActionError, ValidationActionWarn:
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Enumeration value '%s' for field 'validationAction' is not a valid value.", action),
+			"validationAction",
+		)
+	}
+
+	for _, key := range filter.Keys() {
+		switch key {
+		case "$near", "$nearSphere", "$text", "$where":
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrQueryFeatureNotAllowed,
+				fmt.Sprintf("%s is not allowed in collection validators", key),
+				"validator",
+			)
+		case "$jsonSchema":
+			if _, err := parseJSONSchema(must.NotFail(filter.Get(key))); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if HasNearOperator(filter) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrQueryFeatureNotAllowed,
+			"$near is not allowed in collection validators",
+			"validator",
+		)
+	}
+
+	// report other errors of the filter early
+	if _, err := FilterDocument(types.MakeDocument(0), filter); err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		filter: filter,
+		Level:  level,
+		Action: action,
+	}, nil
+}
+
+// ValidateUpdate checks the updated document against the validator according to the validation level.
+// oldDoc is the document before the update; it should be nil for inserts and
+// is used only by the moderate level.
+//
+// It returns DocumentValidationFailure error with errInfo details if the document is not valid,
+// regardless of the validation action.
+func (v *Validator) ValidateUpdate(oldDoc, newDoc *types.Document) error {
+	if v.Level == ValidationLevelOff {
+		return nil
+	}
+
+	matches, err := FilterDocument(newDoc, v.filter)
+	if err != nil {
+		return err
+	}
+
+	if matches {
+		return nil
+	}
+
+	if v.Level == ValidationLevelModerate && oldDoc != nil {
+		if matches, err = FilterDocument(oldDoc, v.filter); err != nil {
+			return err
+		}
+
+		if !matches {
+			return nil
+		}
+	}
+
+	details, err := v.explain(newDoc)
+	if err != nil {
+		return err
+	}
+
+	errInfo := types.MakeDocument(2)
+	if id, _ := newDoc.Get("_id"); id != nil {
+		errInfo.Set("failingDocumentId", id)
+	}
+
+	errInfo.Set("details", details)
+
+	return handlererrors.NewCommandErrorMsgWithDetails(
+		handlererrors.ErrDocumentValidationFailure,
+		"Document failed validation",
+		errInfo,
+	)
+}
+
+// explain returns the details of the validator clauses that the document does not satisfy.
+//
+// Several top-level clauses are reported as the implicit $and.
+func (v *Validator) explain(doc *types.Document) (*types.Document, error) {
+	if v.filter.Len() == 1 {
+		key := v.filter.Keys()[0]
+		return explainClause(doc, key, must.NotFail(v.filter.Get(key)))
+	}
+
+	clauses := types.MakeArray(0)
+
+	for i, key := range v.filter.Keys() {
+		value := must.NotFail(v.filter.Get(key))
+
+		matches, err := FilterDocument(doc, must.NotFail(types.NewDocument(key, value)))
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			continue
+		}
+
+		details, err := explainClause(doc, key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		clauses.Append(must.NotFail(types.NewDocument("index", int32(i), "details", details)))
+	}
+
+	return must.NotFail(types.NewDocument(
+		"operatorName", "$and",
+		"clausesNotSatisfied", clauses,
+	)), nil
+}
+
+// explainClause returns the details of the single validator clause that the document does not satisfy.
+func explainClause(doc *types.Document, key string, value any) (*types.Document, error) {
+	specifiedAs := must.NotFail(types.NewDocument(key, value))
+
+	switch {
+	case key == "$jsonSchema":
+		schema, err := parseJSONSchema(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return must.NotFail(types.NewDocument(
+			"operatorName", key,
+			"schemaRulesNotSatisfied", detailsArray(schema.validate(doc)),
+		)), nil
+
+	case key == "$and" || key == "$or" || key == "$nor":
+		exprs, ok := value.(*types.Array)
+		if !ok || key == "$nor" {
+			break
+		}
+
+		clauses := types.MakeArray(0)
+
+		for i := 0; i < exprs.Len(); i++ {
+			expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+			if !ok {
+				continue
+			}
+
+			matches, err := FilterDocument(doc, expr)
+			if err != nil {
+				return nil, err
+			}
+
+			if matches {
+				continue
+			}
+
+			details, err := (&Validator{filter: expr}).explain(doc)
+			if err != nil {
+				return nil, err
+			}
+
+			clauses.Append(must.NotFail(types.NewDocument("index", int32(i), "details", details)))
+		}
+
+		return must.NotFail(types.NewDocument(
+			"operatorName", key,
+			"clausesNotSatisfied", clauses,
+		)), nil
+
+	case strings.HasPrefix(key, "$"):
+		// $expr and other top-level operators
+		return must.NotFail(types.NewDocument(
+			"operatorName", key,
+			"specifiedAs", specifiedAs,
+			"reason", "expression did not match",
+		)), nil
+	}
+
+	operator := "$eq"
+	if expr, ok := value.(*types.Document); ok && expr.Len() > 0 && strings.HasPrefix(expr.Keys()[0], "$") {
+		operator = expr.Keys()[0]
+		if expr.Len() > 1 {
+			operator = "$and"
+		}
+	}
+
+	details := must.NotFail(types.NewDocument(
+		"operatorName", operator,
+		"specifiedAs", specifiedAs,
+	))
+
+	path, err := types.NewPathFromString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if considered, err := doc.GetByPath(path); err == nil {
+		details.Set("reason", "comparison failed")
+		details.Set("consideredValue", considered)
+	} else {
+		details.Set("reason", "field was missing")
+	}
+
+	return details, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// point returns a GeoJSON Point document.
+func point(lng, lat float64) *types.Document {
+	return must.NotFail(types.NewDocument("type", "Point", "coordinates", must.NotFail(types.NewArray(lng, lat))))
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	ring := func(points ...float64) *types.Array {
+		arr := types.MakeArray(len(points) / 2)
+		for i := 0; i < len(points); i += 2 {
+			arr.Append(must.NotFail(types.NewArray(points[i], points[i+1])))
+		}
+
+		return arr
+	}
+
+	for name, tc := range map[string]struct {
+		doc *types.Document
+		err string
+	}{
+		"Point": {
+			doc: point(10, 20),
+		},
+		"PointLatitude": {
+			doc: point(10, 91),
+			err: "latitude",
+		},
+		"Polygon": {
+			doc: must.NotFail(types.NewDocument(
+				"type", "Polygon",
+				"coordinates", must.NotFail(types.NewArray(ring(0, 0, 1, 0, 1, 1, 0, 0))),
+			)),
+		},
+		"PolygonNotClosed": {
+			doc: must.NotFail(types.NewDocument(
+				"type", "Polygon",
+				"coordinates", must.NotFail(types.NewArray(ring(0, 0, 1, 0, 1, 1, 0, 1))),
+			)),
+			err: "Loop is not closed",
+		},
+		"LineStringShort": {
+			doc: must.NotFail(types.NewDocument("type", "LineString", "coordinates", ring(0, 0))),
+			err: "LineString",
+		},
+		"UnknownType": {
+			doc: must.NotFail(types.NewDocument("type", "Circle", "coordinates", ring(0, 0))),
+			err: "unknown GeoJSON type",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseGeoJSON(tc.doc)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestNearDistance(t *testing.T) {
+	t.Parallel()
+
+	// one degree of longitude on the equator
+	n, err := NewNear("$near", point(0, 0), false, nil, nil)
+	require.NoError(t, err)
+
+	locs := ParseLocations(point(1, 0))
+	require.Len(t, locs, 1)
+	assert.InDelta(t, 111318.8, n.Distance(locs[0].Geometry), 0.1)
+
+	// legacy pairs are planar unless spherical is set
+	n, err = NewNear("$near", must.NotFail(types.NewArray(0.0, 0.0)), false, nil, int32(4))
+	require.NoError(t, err)
+
+	locs = ParseLocations(must.NotFail(types.NewArray(3.0, 4.0)))
+	require.Len(t, locs, 1)
+	assert.Equal(t, 5.0, n.Distance(locs[0].Geometry))
+	assert.False(t, n.InRange(5))
+}
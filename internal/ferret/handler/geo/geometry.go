@@ -0,0 +1,389 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geo provides geometries and geospatial query operators for in-memory documents.
+//
+// GeoJSON objects use spherical geometry with longitude and latitude in degrees;
+// legacy coordinate pairs use planar geometry unless a spherical operator is used.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+)
+
+// Point is a position given by its longitude and latitude in degrees,
+// or by its x and y coordinates for legacy coordinate pairs.
+type Point struct {
+	X, Y float64
+}
+
+// Polygon is a list of linear rings: the exterior ring followed by holes.
+// Rings are closed: the last point is equal to the first one.
+type Polygon [][]Point
+
+// Geometry is a parsed GeoJSON object or a legacy coordinate pair.
+type Geometry struct {
+	Points   []Point
+	Lines    [][]Point
+	Polygons []Polygon
+}
+
+// vertices returns all points of the geometry, including vertices of lines and rings.
+func (g *Geometry) vertices() []Point {
+	res := append([]Point(nil), g.Points...)
+
+	for _, l := range g.Lines {
+		res = append(res, l...)
+	}
+
+	for _, p := range g.Polygons {
+		for _, r := range p {
+			res = append(res, r...)
+		}
+	}
+
+	return res
+}
+
+// edges returns all segments of lines and rings of the geometry.
+func (g *Geometry) edges() [][2]Point {
+	var res [][2]Point
+
+	add := func(l []Point) {
+		for i := 0; i+1 < len(l); i++ {
+			res = append(res, [2]Point{l[i], l[i+1]})
+		}
+	}
+
+	for _, l := range g.Lines {
+		add(l)
+	}
+
+	for _, p := range g.Polygons {
+		for _, r := range p {
+			add(r)
+		}
+	}
+
+	return res
+}
+
+// Location is a parsed location of a document with its original value.
+type Location struct {
+	Value    any
+	Geometry *Geometry
+}
+
+// ParseLocations returns the locations stored in the document field value:
+// a GeoJSON object, a legacy coordinate pair, or an array of them.
+// Invalid values are not locations, like for documents without a geospatial index.
+func ParseLocations(v any) []Location {
+	if g, ok := parseLocation(v); ok {
+		return []Location{{Value: v, Geometry: g}}
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil
+	}
+
+	var res []Location
+
+	for i := 0; i < arr.Len(); i++ {
+		elem, _ := arr.Get(i)
+		if g, ok := parseLocation(elem); ok {
+			res = append(res, Location{Value: elem, Geometry: g})
+		}
+	}
+
+	return res
+}
+
+// parseLocation parses a single location.
+func parseLocation(v any) (*Geometry, bool) {
+	if doc, ok := v.(*types.Document); ok && doc.Has("type") {
+		g, err := ParseGeoJSON(doc)
+		return g, err == nil
+	}
+
+	p, ok := parseLegacyPoint(v)
+	if !ok {
+		return nil, false
+	}
+
+	return &Geometry{Points: []Point{p}}, true
+}
+
+// parseLegacyPoint parses a legacy coordinate pair:
+// an array or a document with at least two elements, the first two of them numbers.
+func parseLegacyPoint(v any) (Point, bool) {
+	var values []any
+
+	switch v := v.(type) {
+	case *types.Array:
+		for i := 0; i < v.Len() && i < 2; i++ {
+			values = append(values, orNil(v.Get(i)))
+		}
+
+	case *types.Document:
+		iter := v.Iterator()
+		defer iter.Close()
+
+		for len(values) < 2 {
+			_, value, err := iter.Next()
+			if err != nil {
+				break
+			}
+
+			values = append(values, value)
+		}
+
+	default:
+		return Point{}, false
+	}
+
+	if len(values) < 2 {
+		return Point{}, false
+	}
+
+	x, okX := toFloat(values[0])
+	y, okY := toFloat(values[1])
+
+	return Point{X: x, Y: y}, okX && okY
+}
+
+// ParseGeoJSON parses and validates the GeoJSON object.
+func ParseGeoJSON(doc *types.Document) (*Geometry, error) {
+	typ, _ := doc.Get("type")
+
+	if typ == "GeometryCollection" {
+		geometries, ok := orNil(doc.Get("geometries")).(*types.Array)
+		if !ok {
+			return nil, newError("GeometryCollection geometries must be an array")
+		}
+
+		res := new(Geometry)
+
+		iter := geometries.Iterator()
+		defer iter.Close()
+
+		for {
+			_, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			d, ok := v.(*types.Document)
+			if !ok {
+				return nil, newError("Element of GeometryCollection geometries must be an object")
+			}
+
+			g, err := ParseGeoJSON(d)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Points = append(res.Points, g.Points...)
+			res.Lines = append(res.Lines, g.Lines...)
+			res.Polygons = append(res.Polygons, g.Polygons...)
+		}
+
+		return res, nil
+	}
+
+	coordinates, ok := orNil(doc.Get("coordinates")).(*types.Array)
+	if !ok {
+		return nil, newError("GeoJSON coordinates must be an array")
+	}
+
+	res := new(Geometry)
+
+	switch typ {
+	case "Point":
+		p, err := parsePosition(coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Points = []Point{p}
+
+	case "MultiPoint":
+		points, err := parsePositions(coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Points = points
+
+	case "LineString":
+		l, err := parseLineString(coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Lines = [][]Point{l}
+
+	case "MultiLineString":
+		for i := 0; i < coordinates.Len(); i++ {
+			l, err := parseLineString(orNil(coordinates.Get(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			res.Lines = append(res.Lines, l)
+		}
+
+	case "Polygon":
+		p, err := parsePolygon(coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Polygons = []Polygon{p}
+
+	case "MultiPolygon":
+		for i := 0; i < coordinates.Len(); i++ {
+			p, err := parsePolygon(orNil(coordinates.Get(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			res.Polygons = append(res.Polygons, p)
+		}
+
+	default:
+		return nil, newError(fmt.Sprintf("unknown GeoJSON type: %s", types.FormatAnyValue(doc)))
+	}
+
+	return res, nil
+}
+
+// parsePosition parses GeoJSON position [longitude, latitude].
+func parsePosition(v any) (Point, error) {
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() < 2 {
+		return Point{}, newError("GeoJSON coordinates must be an array of coordinates")
+	}
+
+	var coords [2]float64
+
+	for i := range coords {
+		if coords[i], ok = toFloat(orNil(arr.Get(i))); !ok {
+			return Point{}, newError("Point must only contain numeric elements")
+		}
+	}
+
+	lng, lat := coords[0], coords[1]
+	if lng < -180 || lng > 180 || lat < -90 || lat > 90 {
+		return Point{}, newError(fmt.Sprintf("longitude/latitude is out of bounds, lng: %v lat: %v", lng, lat))
+	}
+
+	return Point{X: lng, Y: lat}, nil
+}
+
+// parsePositions parses an array of GeoJSON positions.
+func parsePositions(v any) ([]Point, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, newError("GeoJSON coordinates must be an array of coordinates")
+	}
+
+	res := make([]Point, arr.Len())
+
+	for i := range res {
+		var err error
+		if res[i], err = parsePosition(orNil(arr.Get(i))); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// parseLineString parses GeoJSON LineString coordinates.
+func parseLineString(v any) ([]Point, error) {
+	points, err := parsePositions(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) < 2 {
+		return nil, newError("GeoJSON LineString must have at least 2 vertices")
+	}
+
+	return points, nil
+}
+
+// parsePolygon parses GeoJSON Polygon coordinates.
+func parsePolygon(v any) (Polygon, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, newError("Polygon coordinates must be an array")
+	}
+
+	if arr.Len() == 0 {
+		return nil, newError("Polygon has no loops.")
+	}
+
+	res := make(Polygon, arr.Len())
+
+	for i := range res {
+		ring, err := parsePositions(orNil(arr.Get(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ring) < 4 {
+			return nil, newError("Loop must have at least 3 different vertices")
+		}
+
+		if ring[0] != ring[len(ring)-1] {
+			return nil, newError("Loop is not closed, first vertex does not equal last vertex")
+		}
+
+		res[i] = ring
+	}
+
+	return res, nil
+}
+
+// toFloat converts the number to float64.
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, !math.IsNaN(v)
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// orNil returns the value ignoring the error, so missing values are nil.
+func orNil(v any, _ error) any {
+	return v
+}
+
+// newError returns BadValue error for the invalid geometry or query.
+func newError(msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, "$geo")
+}
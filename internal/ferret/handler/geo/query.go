@@ -0,0 +1,330 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+)
+
+// Shape is the shape of $geoWithin.
+type Shape struct {
+	space    space
+	polygons []Polygon
+
+	// for $center and $centerSphere
+	center *Point
+	radius float64
+}
+
+// ParseWithin parses the value of $geoWithin operator:
+//
+//	{$geometry: <GeoJSON Polygon or MultiPolygon>}
+//	{$box: [[<x1>, <y1>], [<x2>, <y2>]]}
+//	{$polygon: [[<x1>, <y1>], [<x2>, <y2>], ...]}
+//	{$center: [[<x>, <y>], <radius>]}
+//	{$centerSphere: [[<x>, <y>], <radius in radians>]}
+//
+// $geometry and $centerSphere use spherical geometry, other shapes are planar.
+func ParseWithin(v any) (*Shape, error) {
+	doc, ok := v.(*types.Document)
+	if !ok || doc.Len() != 1 {
+		return nil, newError(fmt.Sprintf("$geoWithin not supported with provided geometry: %s", types.FormatAnyValue(v)))
+	}
+
+	key := doc.Command()
+	value := orNil(doc.Get(key))
+
+	switch key {
+	case "$geometry":
+		g, err := parseGeometryOperand("$geoWithin", value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(g.Points) > 0 || len(g.Lines) > 0 || len(g.Polygons) == 0 {
+			return nil, newError(
+				fmt.Sprintf("$geoWithin not supported with provided geometry: %s", types.FormatAnyValue(v)),
+			)
+		}
+
+		return &Shape{space: sphere{}, polygons: g.Polygons}, nil
+
+	case "$box":
+		points, err := parseLegacyPoints(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(points) != 2 {
+			return nil, newError("Point must only contain numeric elements")
+		}
+
+		minX, maxX := math.Min(points[0].X, points[1].X), math.Max(points[0].X, points[1].X)
+		minY, maxY := math.Min(points[0].Y, points[1].Y), math.Max(points[0].Y, points[1].Y)
+
+		ring := []Point{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY}}
+
+		return &Shape{space: plane{}, polygons: []Polygon{{ring}}}, nil
+
+	case "$polygon":
+		points, err := parseLegacyPoints(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(points) < 3 {
+			return nil, newError("Polygon must have at least 3 points")
+		}
+
+		ring := append(points, points[0])
+
+		return &Shape{space: plane{}, polygons: []Polygon{{ring}}}, nil
+
+	case "$center", "$centerSphere":
+		arr, ok := value.(*types.Array)
+		if !ok || arr.Len() != 2 {
+			return nil, newError(fmt.Sprintf("%s requires an array of a point and a radius", key))
+		}
+
+		center, ok := parseLegacyPoint(orNil(arr.Get(0)))
+		if !ok {
+			return nil, newError("Point must only contain numeric elements")
+		}
+
+		radius, ok := toFloat(orNil(arr.Get(1)))
+		if !ok || radius < 0 {
+			return nil, newError("radius must be a non-negative number")
+		}
+
+		if key == "$center" {
+			return &Shape{space: plane{}, center: &center, radius: radius}, nil
+		}
+
+		return &Shape{space: sphere{}, center: &center, radius: radius}, nil
+
+	default:
+		return nil, newError(fmt.Sprintf("unknown geo specifier: %s", types.FormatAnyValue(doc)))
+	}
+}
+
+// Contains returns true if the geometry lies entirely within the shape.
+func (s *Shape) Contains(g *Geometry) bool {
+	if s.center == nil {
+		return withinPolygons(s.space, g, s.polygons)
+	}
+
+	for _, v := range g.vertices() {
+		if s.space.distance(*s.center, v) > s.radius {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseIntersects parses the value of $geoIntersects operator:
+//
+//	{$geometry: <GeoJSON object>}
+func ParseIntersects(v any) (*Geometry, error) {
+	doc, ok := v.(*types.Document)
+	if !ok || doc.Len() != 1 || !doc.Has("$geometry") {
+		return nil, newError(
+			fmt.Sprintf("$geoIntersects not supported with provided geometry: %s", types.FormatAnyValue(v)),
+		)
+	}
+
+	return parseGeometryOperand("$geoIntersects", orNil(doc.Get("$geometry")))
+}
+
+// Intersects returns true if geometries have at least one point in common.
+// It uses spherical geometry.
+func Intersects(a, b *Geometry) bool {
+	return intersects(sphere{}, a, b)
+}
+
+// parseGeometryOperand parses GeoJSON object of $geometry.
+func parseGeometryOperand(operator string, v any) (*Geometry, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, newError(fmt.Sprintf("%s: $geometry must be a GeoJSON object", operator))
+	}
+
+	return ParseGeoJSON(doc)
+}
+
+// parseLegacyPoints parses an array of legacy coordinate pairs.
+func parseLegacyPoints(operator string, v any) ([]Point, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, newError(fmt.Sprintf("%s must be an array of points", operator))
+	}
+
+	res := make([]Point, arr.Len())
+
+	for i := range res {
+		if res[i], ok = parseLegacyPoint(orNil(arr.Get(i))); !ok {
+			return nil, newError("Point must only contain numeric elements")
+		}
+	}
+
+	return res, nil
+}
+
+// Near is a reference point with a distance range of $near, $nearSphere and $geoNear.
+type Near struct {
+	point Point
+	space space
+
+	// factor converts distances of the space to the units of the query:
+	// meters for GeoJSON points, radians or coordinate units for legacy points
+	factor float64
+
+	min, max float64
+}
+
+// NewNear returns Near for the given point:
+// a GeoJSON Point, or a legacy coordinate pair.
+//
+// Distances from a GeoJSON Point are spherical, in meters.
+// Distances from a legacy coordinate pair are in radians if spherical is true,
+// and planar in coordinate units otherwise.
+// minDistance and maxDistance are nil if not set.
+func NewNear(operator string, point any, spherical bool, minDistance, maxDistance any) (*Near, error) {
+	n := &Near{
+		factor: 1,
+		max:    math.Inf(1),
+	}
+
+	if doc, ok := point.(*types.Document); ok && doc.Has("type") {
+		g, err := ParseGeoJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(g.Points) != 1 || len(g.Lines) > 0 || len(g.Polygons) > 0 {
+			return nil, newError(fmt.Sprintf("%s requires a point, given %s", operator, types.FormatAnyValue(point)))
+		}
+
+		n.point = g.Points[0]
+		n.space = sphere{}
+		n.factor = EarthRadius
+	} else {
+		p, ok := parseLegacyPoint(point)
+		if !ok {
+			return nil, newError(fmt.Sprintf("%s requires a point, given %s", operator, types.FormatAnyValue(point)))
+		}
+
+		n.point = p
+		n.space = plane{}
+
+		if spherical {
+			n.space = sphere{}
+		}
+	}
+
+	for _, d := range []struct {
+		name  string
+		value any
+		dst   *float64
+	}{
+		{"minDistance", minDistance, &n.min},
+		{"maxDistance", maxDistance, &n.max},
+	} {
+		if d.value == nil {
+			continue
+		}
+
+		f, ok := toFloat(d.value)
+		if !ok {
+			return nil, newError(fmt.Sprintf("%s must be a number", d.name))
+		}
+
+		if f < 0 {
+			return nil, newError(fmt.Sprintf("%s must be non-negative", d.name))
+		}
+
+		*d.dst = f
+	}
+
+	return n, nil
+}
+
+// ParseNear parses {$near: <point>, $maxDistance: <number>, $minDistance: <number>} field expression;
+// $nearSphere is used instead of $near for spherical distances from legacy coordinate pairs.
+//
+// GeoJSON points are given as {$geometry: <Point>, $maxDistance: <number>, $minDistance: <number>}.
+func ParseNear(expr *types.Document) (*Near, error) {
+	var operator string
+	var point, minDistance, maxDistance any
+
+	for _, key := range expr.Keys() {
+		value := orNil(expr.Get(key))
+
+		switch key {
+		case "$near", "$nearSphere":
+			if operator != "" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"Too many geoNear expressions",
+					key,
+				)
+			}
+
+			operator = key
+			point = value
+
+			if doc, ok := value.(*types.Document); ok && doc.Has("$geometry") {
+				point = orNil(doc.Get("$geometry"))
+
+				if v, err := doc.Get("$minDistance"); err == nil {
+					minDistance = v
+				}
+
+				if v, err := doc.Get("$maxDistance"); err == nil {
+					maxDistance = v
+				}
+			}
+
+		case "$minDistance":
+			minDistance = value
+
+		case "$maxDistance":
+			maxDistance = value
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("unknown operator: %s", key),
+				"$operator",
+			)
+		}
+	}
+
+	return NewNear(operator, point, operator == "$nearSphere", minDistance, maxDistance)
+}
+
+// Distance returns the distance from the reference point to the closest point of the geometry.
+func (n *Near) Distance(g *Geometry) float64 {
+	return distanceTo(n.space, n.point, g) * n.factor
+}
+
+// InRange returns true if the distance is within the range of minimum and maximum distances.
+func (n *Near) InRange(d float64) bool {
+	return d >= n.min && d <= n.max
+}
@@ -0,0 +1,388 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import "math"
+
+// EarthRadius is the radius of the Earth in meters used for spherical distances, like in MongoDB.
+const EarthRadius = 6378100.0
+
+// epsilon is the tolerance of geometric predicates.
+const epsilon = 1e-12
+
+// space implements geometric predicates in either spherical or planar geometry.
+type space interface {
+	// distance returns the distance between points:
+	// in radians on the sphere, in coordinate units on the plane.
+	distance(a, b Point) float64
+
+	// segmentDistance returns the distance from the point to the closest point of the segment.
+	segmentDistance(p, a, b Point) float64
+
+	// onSegment returns true if the point lies on the segment.
+	onSegment(p, a, b Point) bool
+
+	// segmentsCross returns true if the interiors of segments cross each other.
+	segmentsCross(a, b, c, d Point) bool
+
+	// ringContains returns true if the point lies inside the closed ring or on its boundary.
+	ringContains(ring []Point, p Point) bool
+}
+
+// sphere is the spherical geometry of longitude and latitude in degrees.
+// Segments are the shortest great circle arcs.
+type sphere struct{}
+
+// plane is the planar geometry of legacy coordinate pairs.
+type plane struct{}
+
+// vector is a point in 3D space.
+type vector struct {
+	x, y, z float64
+}
+
+// toVector returns the unit vector for the point on the sphere.
+func toVector(p Point) vector {
+	lng, lat := p.X*math.Pi/180, p.Y*math.Pi/180
+
+	return vector{
+		x: math.Cos(lat) * math.Cos(lng),
+		y: math.Cos(lat) * math.Sin(lng),
+		z: math.Sin(lat),
+	}
+}
+
+func (v vector) dot(u vector) float64 {
+	return v.x*u.x + v.y*u.y + v.z*u.z
+}
+
+func (v vector) cross(u vector) vector {
+	return vector{
+		x: v.y*u.z - v.z*u.y,
+		y: v.z*u.x - v.x*u.z,
+		z: v.x*u.y - v.y*u.x,
+	}
+}
+
+func (v vector) scale(f float64) vector {
+	return vector{x: v.x * f, y: v.y * f, z: v.z * f}
+}
+
+func (v vector) sub(u vector) vector {
+	return vector{x: v.x - u.x, y: v.y - u.y, z: v.z - u.z}
+}
+
+func (v vector) norm() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// angle returns the angle between vectors in radians.
+func angle(v, u vector) float64 {
+	return math.Atan2(v.cross(u).norm(), v.dot(u))
+}
+
+// distance implements space interface.
+func (sphere) distance(a, b Point) float64 {
+	return angle(toVector(a), toVector(b))
+}
+
+// segmentDistance implements space interface.
+func (sphere) segmentDistance(p, a, b Point) float64 {
+	vp, va, vb := toVector(p), toVector(a), toVector(b)
+
+	d := math.Min(angle(vp, va), angle(vp, vb))
+
+	n := va.cross(vb)
+	if n.norm() < epsilon {
+		return d
+	}
+
+	n = n.scale(1 / n.norm())
+
+	// the closest point of the great circle is the projection of p onto its plane
+	proj := vp.sub(n.scale(vp.dot(n)))
+	if proj.norm() < epsilon {
+		return d
+	}
+
+	if va.cross(proj).dot(n) >= 0 && proj.cross(vb).dot(n) >= 0 {
+		return math.Asin(math.Min(1, math.Abs(vp.dot(n))))
+	}
+
+	return d
+}
+
+// onSegment implements space interface.
+func (s sphere) onSegment(p, a, b Point) bool {
+	return s.segmentDistance(p, a, b) < epsilon
+}
+
+// segmentsCross implements space interface.
+//
+// It is the simple crossing test of S2 geometry library.
+func (sphere) segmentsCross(a, b, c, d Point) bool {
+	va, vb, vc, vd := toVector(a), toVector(b), toVector(c), toVector(d)
+
+	ab := va.cross(vb)
+	acb := -ab.dot(vc)
+	bda := ab.dot(vd)
+
+	if acb*bda <= 0 {
+		return false
+	}
+
+	cd := vc.cross(vd)
+	cbd := -cd.dot(vb)
+	dac := cd.dot(va)
+
+	return acb*cbd > 0 && acb*dac > 0
+}
+
+// ringContains implements space interface.
+//
+// It sums the angles that ring edges subtend at the point;
+// the sum is ±2π for points inside the ring and 0 for points outside it.
+func (s sphere) ringContains(ring []Point, p Point) bool {
+	vp := toVector(p)
+
+	var sum float64
+
+	for i := 0; i+1 < len(ring); i++ {
+		if s.onSegment(p, ring[i], ring[i+1]) {
+			return true
+		}
+
+		// project vertices onto the tangent plane at p
+		va, vb := toVector(ring[i]), toVector(ring[i+1])
+		ta := va.sub(vp.scale(va.dot(vp)))
+		tb := vb.sub(vp.scale(vb.dot(vp)))
+
+		sum += math.Atan2(ta.cross(tb).dot(vp), ta.dot(tb))
+	}
+
+	return math.Abs(sum) > math.Pi
+}
+
+// distance implements space interface.
+func (plane) distance(a, b Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// segmentDistance implements space interface.
+func (pl plane) segmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+
+	l := dx*dx + dy*dy
+	if l == 0 {
+		return pl.distance(p, a)
+	}
+
+	t := math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+
+	return pl.distance(p, Point{X: a.X + t*dx, Y: a.Y + t*dy})
+}
+
+// onSegment implements space interface.
+func (pl plane) onSegment(p, a, b Point) bool {
+	return pl.segmentDistance(p, a, b) < epsilon
+}
+
+// orientation returns the sign of the turn a -> b -> c.
+func orientation(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// segmentsCross implements space interface.
+func (plane) segmentsCross(a, b, c, d Point) bool {
+	return orientation(a, b, c)*orientation(a, b, d) < 0 && orientation(c, d, a)*orientation(c, d, b) < 0
+}
+
+// ringContains implements space interface.
+func (pl plane) ringContains(ring []Point, p Point) bool {
+	var inside bool
+
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := ring[i], ring[i+1]
+
+		if pl.onSegment(p, a, b) {
+			return true
+		}
+
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// polygonContains returns true if the point lies inside the polygon or on its boundary.
+func polygonContains(s space, poly Polygon, p Point) bool {
+	if !s.ringContains(poly[0], p) {
+		return false
+	}
+
+	for _, hole := range poly[1:] {
+		if s.ringContains(hole, p) && !onRing(s, hole, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// onRing returns true if the point lies on the boundary of the ring.
+func onRing(s space, ring []Point, p Point) bool {
+	for i := 0; i+1 < len(ring); i++ {
+		if s.onSegment(p, ring[i], ring[i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withinPolygon returns true if all vertices lie in the polygon and no edge crosses its boundary.
+func withinPolygon(s space, vertices []Point, edges [][2]Point, poly Polygon) bool {
+	for _, v := range vertices {
+		if !polygonContains(s, poly, v) {
+			return false
+		}
+	}
+
+	for _, ring := range poly {
+		for i := 0; i+1 < len(ring); i++ {
+			for _, e := range edges {
+				if s.segmentsCross(e[0], e[1], ring[i], ring[i+1]) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// withinPolygons returns true if every part of the geometry lies in one of the polygons.
+func withinPolygons(s space, g *Geometry, polys []Polygon) bool {
+	within := func(vertices []Point, edges [][2]Point) bool {
+		for _, poly := range polys {
+			if withinPolygon(s, vertices, edges, poly) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, p := range g.Points {
+		if !within([]Point{p}, nil) {
+			return false
+		}
+	}
+
+	for _, l := range g.Lines {
+		part := &Geometry{Lines: [][]Point{l}}
+		if !within(l, part.edges()) {
+			return false
+		}
+	}
+
+	for _, poly := range g.Polygons {
+		part := &Geometry{Polygons: []Polygon{poly[:1]}}
+		if !within(poly[0], part.edges()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// intersects returns true if geometries have at least one point in common.
+func intersects(s space, a, b *Geometry) bool {
+	av, bv := a.vertices(), b.vertices()
+	ae, be := a.edges(), b.edges()
+
+	for _, v := range av {
+		for _, poly := range b.Polygons {
+			if polygonContains(s, poly, v) {
+				return true
+			}
+		}
+
+		for _, e := range be {
+			if s.onSegment(v, e[0], e[1]) {
+				return true
+			}
+		}
+
+		for _, p := range b.Points {
+			if s.distance(v, p) < epsilon {
+				return true
+			}
+		}
+	}
+
+	for _, v := range bv {
+		for _, poly := range a.Polygons {
+			if polygonContains(s, poly, v) {
+				return true
+			}
+		}
+
+		for _, e := range ae {
+			if s.onSegment(v, e[0], e[1]) {
+				return true
+			}
+		}
+	}
+
+	for _, e1 := range ae {
+		for _, e2 := range be {
+			if s.segmentsCross(e1[0], e1[1], e2[0], e2[1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// distanceTo returns the distance from the point to the closest point of the geometry.
+func distanceTo(s space, p Point, g *Geometry) float64 {
+	res := math.Inf(1)
+
+	for _, poly := range g.Polygons {
+		if polygonContains(s, poly, p) {
+			return 0
+		}
+	}
+
+	for _, v := range g.Points {
+		res = math.Min(res, s.distance(p, v))
+	}
+
+	for _, e := range g.edges() {
+		res = math.Min(res, s.segmentDistance(p, e[0], e[1]))
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ space = sphere{}
+	_ space = plane{}
+)
@@ -66,6 +66,17 @@ func NewCommandErrorMsgWithArgument(code ErrorCode, msg string, argument string)
 	}
 }
 
+// NewCommandErrorMsgWithDetails creates a new wire protocol error with errInfo details.
+func NewCommandErrorMsgWithDetails(code ErrorCode, msg string, details *types.Document) error {
+	return &CommandError{
+		code: code,
+		err:  errors.New(msg),
+		info: &ErrInfo{
+			Details: details,
+		},
+	}
+}
+
 // Err returns original error.
 //
 // It is not called Unwrap to prevent unwrapping by errors.Is and errors.As.
@@ -96,6 +107,10 @@ func (e *CommandError) Document() *types.Document {
 		d.Set("codeName", e.code.String())
 	}
 
+	if e.info != nil && e.info.Details != nil {
+		d.Set("errInfo", e.info.Details)
+	}
+
 	return d
 }
 
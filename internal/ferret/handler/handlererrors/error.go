@@ -114,6 +114,9 @@ const (
 	// ErrDocumentValidationFailure indicates that document validation failed.
 	ErrDocumentValidationFailure = ErrorCode(121) // DocumentValidationFailure
 
+	// ErrJSInterpreterFailure indicates that JavaScript of $where or $function failed.
+	ErrJSInterpreterFailure = ErrorCode(139) // JSInterpreterFailure
+
 	// ErrInvalidIndexSpecificationOption indicates that the index option is invalid.
 	ErrInvalidIndexSpecificationOption = ErrorCode(197) // InvalidIndexSpecificationOption
 
@@ -123,15 +126,366 @@ const (
 	// ErrClientMetadataCannotBeMutated indicates that client metadata cannot be mutated.
 	ErrClientMetadataCannotBeMutated = ErrorCode(186) // ClientMetadataCannotBeMutated
 
+	// ErrQueryFeatureNotAllowed indicates that a query operator is not allowed in this context.
+	ErrQueryFeatureNotAllowed = ErrorCode(224) // QueryFeatureNotAllowed
+
 	// ErrNotImplemented indicates that a flag or command is not implemented.
 	ErrNotImplemented = ErrorCode(238) // NotImplemented
 
+	// ErrConversionFailure indicates that the value could not be converted.
+	ErrConversionFailure = ErrorCode(241) // ConversionFailure
+
 	// ErrIndexesWrongType indicates that indexes parameter has wrong type.
 	ErrIndexesWrongType = ErrorCode(10065) // Location10065
 
 	// ErrDuplicateKeyInsert indicates duplicate key violation on inserting document.
 	ErrDuplicateKeyInsert = ErrorCode(11000) // Location11000
 
+	// ErrDateBadType indicates that the value can not be converted to a date.
+	ErrDateBadType = ErrorCode(16006) // Location16006
+
+	// ErrStringConversion indicates that value cannot be converted to string.
+	ErrStringConversion = ErrorCode(16007) // Location16007
+
+	// ErrSubstrBytesStartType indicates that $substrBytes starting index is not a number.
+	ErrSubstrBytesStartType = ErrorCode(16034) // Location16034
+
+	// ErrSubstrBytesLengthType indicates that $substrBytes length is not a number.
+	ErrSubstrBytesLengthType = ErrorCode(16035) // Location16035
+
+	// ErrConcatBadType indicates that $concat argument is not a string.
+	ErrConcatBadType = ErrorCode(16702) // Location16702
+
+	// ErrLetNotObject indicates that $let argument is not an object.
+	ErrLetNotObject = ErrorCode(16874) // Location16874
+
+	// ErrLetUnknownField indicates that $let argument has an unknown field.
+	ErrLetUnknownField = ErrorCode(16875) // Location16875
+
+	// ErrLetMissingVars indicates that $let vars argument is missing.
+	ErrLetMissingVars = ErrorCode(16876) // Location16876
+
+	// ErrLetMissingIn indicates that $let in argument is missing.
+	ErrLetMissingIn = ErrorCode(16877) // Location16877
+
+	// ErrMapNotObject indicates that $map argument is not an object.
+	ErrMapNotObject = ErrorCode(16878) // Location16878
+
+	// ErrMapUnknownField indicates that $map has an unknown argument.
+	ErrMapUnknownField = ErrorCode(16879) // Location16879
+
+	// ErrMapMissingInput indicates that $map input argument is missing.
+	ErrMapMissingInput = ErrorCode(16880) // Location16880
+
+	// ErrMapMissingIn indicates that $map in argument is missing.
+	ErrMapMissingIn = ErrorCode(16882) // Location16882
+
+	// ErrMapInputBadType indicates that $map input is not an array.
+	ErrMapInputBadType = ErrorCode(16883) // Location16883
+
+	// ErrSetIsSubsetSecondNotArray indicates that the second argument of $setIsSubset is not an array.
+	ErrSetIsSubsetSecondNotArray = ErrorCode(17042) // Location17042
+
+	// ErrSetUnionNotArray indicates that an argument of $setUnion is not an array.
+	ErrSetUnionNotArray = ErrorCode(17043) // Location17043
+
+	// ErrSetEqualsNotArray indicates that an argument of $setEquals is not an array.
+	ErrSetEqualsNotArray = ErrorCode(17044) // Location17044
+
+	// ErrSetEqualsArgsLen indicates that $setEquals has less than two arguments.
+	ErrSetEqualsArgsLen = ErrorCode(17045) // Location17045
+
+	// ErrSetIsSubsetFirstNotArray indicates that the first argument of $setIsSubset is not an array.
+	ErrSetIsSubsetFirstNotArray = ErrorCode(17046) // Location17046
+
+	// ErrSetIntersectionNotArray indicates that an argument of $setIntersection is not an array.
+	ErrSetIntersectionNotArray = ErrorCode(17047) // Location17047
+
+	// ErrSetDifferenceFirstNotArray indicates that the first argument of $setDifference is not an array.
+	ErrSetDifferenceFirstNotArray = ErrorCode(17048) // Location17048
+
+	// ErrSetDifferenceSecondNotArray indicates that the second argument of $setDifference is not an array.
+	ErrSetDifferenceSecondNotArray = ErrorCode(17049) // Location17049
+
+	// ErrCondMissingIf indicates that $cond if argument is missing.
+	ErrCondMissingIf = ErrorCode(17080) // Location17080
+
+	// ErrCondMissingThen indicates that $cond then argument is missing.
+	ErrCondMissingThen = ErrorCode(17081) // Location17081
+
+	// ErrCondMissingElse indicates that $cond else argument is missing.
+	ErrCondMissingElse = ErrorCode(17082) // Location17082
+
+	// ErrCondUnknownField indicates that $cond has an unknown argument.
+	ErrCondUnknownField = ErrorCode(17083) // Location17083
+
+	// ErrSizeBadType indicates that $size argument is not an array.
+	ErrSizeBadType = ErrorCode(17124) // Location17124
+
+	// ErrDateToStringFormatBadType indicates that $dateToString format is not a string.
+	ErrDateToStringFormatBadType = ErrorCode(18533) // Location18533
+
+	// ErrDateToStringUnknownField indicates that $dateToString has an unknown argument.
+	ErrDateToStringUnknownField = ErrorCode(18534) // Location18534
+
+	// ErrDateFormatUnmatchedPercent indicates that date format string ends with %.
+	ErrDateFormatUnmatchedPercent = ErrorCode(18535) // Location18535
+
+	// ErrDateFormatInvalidChar indicates that date format string has an invalid format character.
+	ErrDateFormatInvalidChar = ErrorCode(18536) // Location18536
+
+	// ErrDateToStringMissingDate indicates that $dateToString date argument is missing.
+	ErrDateToStringMissingDate = ErrorCode(18628) // Location18628
+
+	// ErrDateToStringNotObject indicates that $dateToString argument is not an object.
+	ErrDateToStringNotObject = ErrorCode(18629) // Location18629
+
+	// ErrFilterNotObject indicates that $filter argument is not an object.
+	ErrFilterNotObject = ErrorCode(28646) // Location28646
+
+	// ErrFilterUnknownField indicates that $filter has an unknown argument.
+	ErrFilterUnknownField = ErrorCode(28647) // Location28647
+
+	// ErrFilterMissingInput indicates that $filter input argument is missing.
+	ErrFilterMissingInput = ErrorCode(28648) // Location28648
+
+	// ErrFilterMissingCond indicates that $filter cond argument is missing.
+	ErrFilterMissingCond = ErrorCode(28650) // Location28650
+
+	// ErrFilterInputBadType indicates that $filter input is not an array.
+	ErrFilterInputBadType = ErrorCode(28651) // Location28651
+
+	// ErrSubstrBytesStartContinuation indicates that $substrBytes starting index is a UTF-8 continuation byte.
+	ErrSubstrBytesStartContinuation = ErrorCode(28656) // Location28656
+
+	// ErrSubstrBytesEndContinuation indicates that $substrBytes ending index is in the middle of a UTF-8 character.
+	ErrSubstrBytesEndContinuation = ErrorCode(28657) // Location28657
+
+	// ErrConcatArraysBadType indicates that $concatArrays argument is not an array.
+	ErrConcatArraysBadType = ErrorCode(28664) // Location28664
+
+	// ErrArrayElemAtArrayBadType indicates that $arrayElemAt, $first or $last argument is not an array.
+	ErrArrayElemAtArrayBadType = ErrorCode(28689) // Location28689
+
+	// ErrArrayElemAtIndexBadType indicates that $arrayElemAt index is not a number.
+	ErrArrayElemAtIndexBadType = ErrorCode(28690) // Location28690
+
+	// ErrArrayElemAtIndexNotInt indicates that $arrayElemAt index is not a 32-bit integer.
+	ErrArrayElemAtIndexNotInt = ErrorCode(28691) // Location28691
+
+	// ErrSliceSecondArgBadType indicates that $slice second argument is not a number.
+	ErrSliceSecondArgBadType = ErrorCode(28725) // Location28725
+
+	// ErrSliceSecondArgNotInt indicates that $slice second argument is not a 32-bit integer.
+	ErrSliceSecondArgNotInt = ErrorCode(28726) // Location28726
+
+	// ErrSliceThirdArgBadType indicates that $slice third argument is not a number.
+	ErrSliceThirdArgBadType = ErrorCode(28727) // Location28727
+
+	// ErrSliceThirdArgNotInt indicates that $slice third argument is not a 32-bit integer.
+	ErrSliceThirdArgNotInt = ErrorCode(28728) // Location28728
+
+	// ErrSliceThirdArgNotPositive indicates that $slice third argument is not positive.
+	ErrSliceThirdArgNotPositive = ErrorCode(28729) // Location28729
+
+	// ErrStageUnwindPathType indicates that $unwind stage path is not a string.
+	ErrStageUnwindPathType = ErrorCode(28808) // Location28808
+
+	// ErrStageUnwindPreserveType indicates that $unwind stage preserveNullAndEmptyArrays option is not a boolean.
+	ErrStageUnwindPreserveType = ErrorCode(28809) // Location28809
+
+	// ErrStageUnwindIndexType indicates that $unwind stage includeArrayIndex option is not a non-empty string.
+	ErrStageUnwindIndexType = ErrorCode(28810) // Location28810
+
+	// ErrStageUnwindUnknownOption indicates that $unwind stage has unrecognized option.
+	ErrStageUnwindUnknownOption = ErrorCode(28811) // Location28811
+
+	// ErrStageUnwindIndexPrefix indicates that $unwind stage includeArrayIndex option is prefixed with a '$'.
+	ErrStageUnwindIndexPrefix = ErrorCode(28822) // Location28822
+
+	// ErrRegexMissingInput indicates that regex operator does not have input.
+	ErrRegexMissingInput = ErrorCode(31022) // Location31022
+
+	// ErrRegexMissingRegex indicates that regex operator does not have regex.
+	ErrRegexMissingRegex = ErrorCode(31023) // Location31023
+
+	// ErrRegexUnknownField indicates that regex operator has unknown field.
+	ErrRegexUnknownField = ErrorCode(31024) // Location31024
+
+	// ErrDateFromPartsValueRange indicates that $dateFromParts argument is out of range.
+	ErrDateFromPartsValueRange = ErrorCode(31034) // Location31034
+
+	// ErrFunctionNotObject indicates that $function argument is not an object.
+	ErrFunctionNotObject = ErrorCode(31260) // Location31260
+
+	// ErrFunctionMissingBody indicates that $function body is not specified.
+	ErrFunctionMissingBody = ErrorCode(31261) // Location31261
+
+	// ErrFunctionBodyType indicates that $function body is not a string or code.
+	ErrFunctionBodyType = ErrorCode(31262) // Location31262
+
+	// ErrFunctionMissingArgs indicates that $function args are not specified.
+	ErrFunctionMissingArgs = ErrorCode(31263) // Location31263
+
+	// ErrFunctionArgsType indicates that $function args is not an array.
+	ErrFunctionArgsType = ErrorCode(31264) // Location31264
+
+	// ErrFunctionMissingLang indicates that $function lang is not specified.
+	ErrFunctionMissingLang = ErrorCode(31418) // Location31418
+
+	// ErrFunctionLang indicates that $function lang is not js.
+	ErrFunctionLang = ErrorCode(31419) // Location31419
+
+	// ErrReverseArrayBadType indicates that $reverseArray argument is not an array.
+	ErrReverseArrayBadType = ErrorCode(34435) // Location34435
+
+	// ErrRangeStartBadType indicates that $range start is not a number.
+	ErrRangeStartBadType = ErrorCode(34443) // Location34443
+
+	// ErrRangeStartNotInt indicates that $range start is not a 32-bit integer.
+	ErrRangeStartNotInt = ErrorCode(34444) // Location34444
+
+	// ErrRangeEndBadType indicates that $range end is not a number.
+	ErrRangeEndBadType = ErrorCode(34445) // Location34445
+
+	// ErrRangeEndNotInt indicates that $range end is not a 32-bit integer.
+	ErrRangeEndNotInt = ErrorCode(34446) // Location34446
+
+	// ErrRangeStepBadType indicates that $range step is not a number.
+	ErrRangeStepBadType = ErrorCode(34447) // Location34447
+
+	// ErrRangeStepNotInt indicates that $range step is not a 32-bit integer.
+	ErrRangeStepNotInt = ErrorCode(34448) // Location34448
+
+	// ErrRangeStepZero indicates that $range step is zero.
+	ErrRangeStepZero = ErrorCode(34449) // Location34449
+
+	// ErrSubstrCPStartType indicates that $substrCP starting index is not a number.
+	ErrSubstrCPStartType = ErrorCode(34450) // Location34450
+
+	// ErrSubstrCPStartNotIntegral indicates that $substrCP starting index is not an integral value.
+	ErrSubstrCPStartNotIntegral = ErrorCode(34451) // Location34451
+
+	// ErrSubstrCPLengthType indicates that $substrCP length is not a number.
+	ErrSubstrCPLengthType = ErrorCode(34452) // Location34452
+
+	// ErrSubstrCPLengthNotIntegral indicates that $substrCP length is not an integral value.
+	ErrSubstrCPLengthNotIntegral = ErrorCode(34453) // Location34453
+
+	// ErrSubstrCPLengthNegative indicates that $substrCP length is negative.
+	ErrSubstrCPLengthNegative = ErrorCode(34454) // Location34454
+
+	// ErrSubstrCPStartNegative indicates that $substrCP starting index is negative.
+	ErrSubstrCPStartNegative = ErrorCode(34455) // Location34455
+
+	// ErrZipNotObject indicates that $zip argument is not an object.
+	ErrZipNotObject = ErrorCode(34460) // Location34460
+
+	// ErrZipInputsNotArray indicates that $zip inputs is not an array.
+	ErrZipInputsNotArray = ErrorCode(34461) // Location34461
+
+	// ErrZipDefaultsNotArray indicates that $zip defaults is not an array.
+	ErrZipDefaultsNotArray = ErrorCode(34462) // Location34462
+
+	// ErrZipUseLongestLengthBadType indicates that $zip useLongestLength is not a boolean.
+	ErrZipUseLongestLengthBadType = ErrorCode(34463) // Location34463
+
+	// ErrZipUnknownField indicates that $zip has an unknown argument.
+	ErrZipUnknownField = ErrorCode(34464) // Location34464
+
+	// ErrZipMissingInputs indicates that $zip has no input arrays.
+	ErrZipMissingInputs = ErrorCode(34465) // Location34465
+
+	// ErrZipDefaultsWithoutUseLongestLength indicates that $zip defaults are set without useLongestLength.
+	ErrZipDefaultsWithoutUseLongestLength = ErrorCode(34466) // Location34466
+
+	// ErrZipDefaultsLength indicates that $zip inputs and defaults have different lengths.
+	ErrZipDefaultsLength = ErrorCode(34467) // Location34467
+
+	// ErrZipInputBadType indicates that $zip input is not an array.
+	ErrZipInputBadType = ErrorCode(34468) // Location34468
+
+	// ErrStrLenCPBadType indicates that $strLenCP argument is not a string.
+	ErrStrLenCPBadType = ErrorCode(34471) // Location34471
+
+	// ErrStrLenBytesBadType indicates that $strLenBytes argument is not a string.
+	ErrStrLenBytesBadType = ErrorCode(34473) // Location34473
+
+	// ErrSwitchNotObject indicates that $switch argument is not an object.
+	ErrSwitchNotObject = ErrorCode(40060) // Location40060
+
+	// ErrSwitchBranchesNotArray indicates that $switch branches is not an array.
+	ErrSwitchBranchesNotArray = ErrorCode(40061) // Location40061
+
+	// ErrSwitchBranchNotObject indicates that $switch branch is not an object.
+	ErrSwitchBranchNotObject = ErrorCode(40062) // Location40062
+
+	// ErrSwitchBranchUnknownField indicates that $switch branch has an unknown argument.
+	ErrSwitchBranchUnknownField = ErrorCode(40063) // Location40063
+
+	// ErrSwitchBranchMissingCase indicates that $switch branch case is missing.
+	ErrSwitchBranchMissingCase = ErrorCode(40064) // Location40064
+
+	// ErrSwitchBranchMissingThen indicates that $switch branch then is missing.
+	ErrSwitchBranchMissingThen = ErrorCode(40065) // Location40065
+
+	// ErrSwitchNoMatch indicates that no $switch branch matched and there is no default.
+	ErrSwitchNoMatch = ErrorCode(40066) // Location40066
+
+	// ErrSwitchUnknownField indicates that $switch has an unknown argument.
+	ErrSwitchUnknownField = ErrorCode(40067) // Location40067
+
+	// ErrSwitchNoBranches indicates that $switch has no branches.
+	ErrSwitchNoBranches = ErrorCode(40068) // Location40068
+
+	// ErrReduceNotObject indicates that $reduce argument is not an object.
+	ErrReduceNotObject = ErrorCode(40075) // Location40075
+
+	// ErrReduceUnknownField indicates that $reduce has an unknown argument.
+	ErrReduceUnknownField = ErrorCode(40076) // Location40076
+
+	// ErrReduceMissingInput indicates that $reduce input argument is missing.
+	ErrReduceMissingInput = ErrorCode(40077) // Location40077
+
+	// ErrReduceMissingInitialValue indicates that $reduce initialValue argument is missing.
+	ErrReduceMissingInitialValue = ErrorCode(40078) // Location40078
+
+	// ErrReduceMissingIn indicates that $reduce in argument is missing.
+	ErrReduceMissingIn = ErrorCode(40079) // Location40079
+
+	// ErrReduceInputBadType indicates that $reduce input is not an array.
+	ErrReduceInputBadType = ErrorCode(40080) // Location40080
+
+	// ErrInArrayBadType indicates that $in second argument is not an array.
+	ErrInArrayBadType = ErrorCode(40081) // Location40081
+
+	// ErrSplitInputBadType indicates that $split input is not a string.
+	ErrSplitInputBadType = ErrorCode(40085) // Location40085
+
+	// ErrSplitDelimiterBadType indicates that $split delimiter is not a string.
+	ErrSplitDelimiterBadType = ErrorCode(40086) // Location40086
+
+	// ErrSplitEmptyDelimiter indicates that $split delimiter is empty.
+	ErrSplitEmptyDelimiter = ErrorCode(40087) // Location40087
+
+	// ErrIndexOfArrayBadType indicates that $indexOfArray first argument is not an array.
+	ErrIndexOfArrayBadType = ErrorCode(40090) // Location40090
+
+	// ErrIndexOfCPInputBadType indicates that $indexOfCP input is not a string.
+	ErrIndexOfCPInputBadType = ErrorCode(40093) // Location40093
+
+	// ErrIndexOfCPSubstringBadType indicates that $indexOfCP substring is not a string.
+	ErrIndexOfCPSubstringBadType = ErrorCode(40094) // Location40094
+
+	// ErrIndexOfCPIndexNotIntegral indicates that $indexOfCP or $indexOfArray index is not an integral value.
+	ErrIndexOfCPIndexNotIntegral = ErrorCode(40096) // Location40096
+
+	// ErrIndexOfCPIndexNegative indicates that $indexOfCP or $indexOfArray index is negative.
+	ErrIndexOfCPIndexNegative = ErrorCode(40097) // Location40097
+
+	// ErrTextScoreUnavailable indicates that textScore metadata was requested for a document without it.
+	ErrTextScoreUnavailable = ErrorCode(40218) // Location40218
+
 	// ErrSetBadExpression indicates set expression is not object.
 	ErrSetBadExpression = ErrorCode(40272) // Location40272
 
@@ -184,6 +538,27 @@ const (
 	// ErrFieldPathInvalidName indicates that FieldPath is invalid.
 	ErrFieldPathInvalidName = ErrorCode(16410) // Location16410
 
+	// ErrMultiplyBadType indicates that $multiply argument is not a number.
+	ErrMultiplyBadType = ErrorCode(16555) // Location16555
+
+	// ErrSubtractBadType indicates that $subtract arguments have unsupported types.
+	ErrSubtractBadType = ErrorCode(16556) // Location16556
+
+	// ErrDivideByZero indicates that $divide divisor is zero.
+	ErrDivideByZero = ErrorCode(16608) // Location16608
+
+	// ErrDivideBadType indicates that $divide argument is not a number.
+	ErrDivideBadType = ErrorCode(16609) // Location16609
+
+	// ErrModByZero indicates that $mod divisor is zero.
+	ErrModByZero = ErrorCode(16610) // Location16610
+
+	// ErrModBadType indicates that $mod argument is not a number.
+	ErrModBadType = ErrorCode(16611) // Location16611
+
+	// ErrAddMultipleDates indicates that $add has more than one date argument.
+	ErrAddMultipleDates = ErrorCode(16612) // Location16612
+
 	// ErrGroupInvalidFieldPath indicates invalid path is given for group _id.
 	ErrGroupInvalidFieldPath = ErrorCode(16872) // Location16872
 
@@ -196,6 +571,57 @@ const (
 	// ErrSliceFirstArg for $slice indicates that the first argument is not an array.
 	ErrSliceFirstArg = ErrorCode(28724) // Location28724
 
+	// ErrStageSampleNotObject indicates that $sample stage specification is not an object.
+	ErrStageSampleNotObject = ErrorCode(28745) // Location28745
+
+	// ErrStageSampleSizeNotNumber indicates that $sample size is not a number.
+	ErrStageSampleSizeNotNumber = ErrorCode(28746) // Location28746
+
+	// ErrStageSampleSizeNegative indicates that $sample size is negative.
+	ErrStageSampleSizeNegative = ErrorCode(28747) // Location28747
+
+	// ErrStageSampleUnknownOption indicates that $sample stage contains unknown option.
+	ErrStageSampleUnknownOption = ErrorCode(28748) // Location28748
+
+	// ErrStageSampleNoSize indicates that $sample stage doesn't specify size.
+	ErrStageSampleNoSize = ErrorCode(28749) // Location28749
+
+	// ErrAbsLongMin indicates that $abs argument is the minimal long value.
+	ErrAbsLongMin = ErrorCode(28680) // Location28680
+
+	// ErrSqrtNegative indicates that $sqrt argument is negative.
+	ErrSqrtNegative = ErrorCode(28714) // Location28714
+
+	// ErrLogBadType indicates that $log argument is not a number.
+	ErrLogBadType = ErrorCode(28756) // Location28756
+
+	// ErrLogBaseBadType indicates that $log base is not a number.
+	ErrLogBaseBadType = ErrorCode(28757) // Location28757
+
+	// ErrLogNotPositive indicates that $log argument is not positive.
+	ErrLogNotPositive = ErrorCode(28758) // Location28758
+
+	// ErrLogBadBase indicates that $log base is not positive or equals 1.
+	ErrLogBadBase = ErrorCode(28759) // Location28759
+
+	// ErrLog10NotPositive indicates that $log10 argument is not positive.
+	ErrLog10NotPositive = ErrorCode(28761) // Location28761
+
+	// ErrPowBaseBadType indicates that $pow base is not a number.
+	ErrPowBaseBadType = ErrorCode(28762) // Location28762
+
+	// ErrPowExponentBadType indicates that $pow exponent is not a number.
+	ErrPowExponentBadType = ErrorCode(28763) // Location28763
+
+	// ErrPowZeroNegativeExponent indicates that $pow has zero base and negative exponent.
+	ErrPowZeroNegativeExponent = ErrorCode(28764) // Location28764
+
+	// ErrUnaryNumericBadType indicates that numeric operator like $abs or $ceil argument is not a number.
+	ErrUnaryNumericBadType = ErrorCode(28765) // Location28765
+
+	// ErrLnNotPositive indicates that $ln argument is not positive.
+	ErrLnNotPositive = ErrorCode(28766) // Location28766
+
 	// ErrStageUnsetNoPath indicates that $unwind aggregation stage is empty.
 	ErrStageUnsetNoPath = ErrorCode(31119) // Location31119
 
@@ -272,18 +698,126 @@ const (
 	// ErrInvalidFieldPath indicates that the field path is not valid.
 	ErrInvalidFieldPath = ErrorCode(40353) // Location40353
 
+	// ErrArrayToObjectBadType indicates that $arrayToObject argument is not an array.
+	ErrArrayToObjectBadType = ErrorCode(40386) // Location40386
+
+	// ErrObjectToArrayBadType indicates that $objectToArray argument is not an object.
+	ErrObjectToArrayBadType = ErrorCode(40390) // Location40390
+
+	// ErrArrayToObjectKeyCount indicates that $arrayToObject element object does not have exactly two keys.
+	ErrArrayToObjectKeyCount = ErrorCode(40392) // Location40392
+
+	// ErrArrayToObjectMissingKey indicates that $arrayToObject element object does not have k and v keys.
+	ErrArrayToObjectMissingKey = ErrorCode(40393) // Location40393
+
+	// ErrArrayToObjectKeyBadType indicates that $arrayToObject element object key is not a string.
+	ErrArrayToObjectKeyBadType = ErrorCode(40394) // Location40394
+
+	// ErrArrayToObjectPairKeyBadType indicates that $arrayToObject key-value pair key is not a string.
+	ErrArrayToObjectPairKeyBadType = ErrorCode(40395) // Location40395
+
+	// ErrArrayToObjectPairSize indicates that $arrayToObject key-value pair is not of size 2.
+	ErrArrayToObjectPairSize = ErrorCode(40396) // Location40396
+
+	// ErrArrayToObjectInconsistent indicates that $arrayToObject elements are of different formats.
+	ErrArrayToObjectInconsistent = ErrorCode(40397) // Location40397
+
+	// ErrArrayToObjectElementBadType indicates that $arrayToObject element is neither an array nor an object.
+	ErrArrayToObjectElementBadType = ErrorCode(40398) // Location40398
+
+	// ErrMergeObjectsBadType indicates that $mergeObjects input is not an object.
+	ErrMergeObjectsBadType = ErrorCode(40400) // Location40400
+
 	// ErrMissingField indicates that the required field in document is missing.
 	ErrMissingField = ErrorCode(40414) // Location40414
 
 	// ErrFailedToParseInput indicates invalid input (absent or malformed fields).
 	ErrFailedToParseInput = ErrorCode(40415) // Location40415
 
+	// ErrTimezoneUnrecognized indicates that the time zone identifier is not recognized.
+	ErrTimezoneUnrecognized = ErrorCode(40485) // Location40485
+
+	// ErrDateFromPartsMixed indicates that $dateFromParts mixes natural and ISO dates.
+	ErrDateFromPartsMixed = ErrorCode(40489) // Location40489
+
+	// ErrDateFromPartsNotInteger indicates that $dateFromParts argument is not an integer.
+	ErrDateFromPartsNotInteger = ErrorCode(40515) // Location40515
+
+	// ErrDateFromPartsMissingYear indicates that $dateFromParts has neither year nor isoWeekYear.
+	ErrDateFromPartsMissingYear = ErrorCode(40516) // Location40516
+
+	// ErrTimezoneBadType indicates that the timezone is not a string.
+	ErrTimezoneBadType = ErrorCode(40517) // Location40517
+
+	// ErrDateFromPartsUnknownField indicates that $dateFromParts has an unknown argument.
+	ErrDateFromPartsUnknownField = ErrorCode(40518) // Location40518
+
+	// ErrDateFromPartsNotObject indicates that $dateFromParts argument is not an object.
+	ErrDateFromPartsNotObject = ErrorCode(40519) // Location40519
+
+	// ErrDateToPartsUnknownField indicates that $dateToParts has an unknown argument.
+	ErrDateToPartsUnknownField = ErrorCode(40520) // Location40520
+
+	// ErrDateToPartsISO8601BadType indicates that $dateToParts iso8601 argument is not a boolean.
+	ErrDateToPartsISO8601BadType = ErrorCode(40521) // Location40521
+
+	// ErrDateToPartsMissingDate indicates that $dateToParts date argument is missing.
+	ErrDateToPartsMissingDate = ErrorCode(40522) // Location40522
+
+	// ErrDateFromPartsYearRange indicates that $dateFromParts year is out of range.
+	ErrDateFromPartsYearRange = ErrorCode(40523) // Location40523
+
+	// ErrDateToPartsNotObject indicates that $dateToParts argument is not an object.
+	ErrDateToPartsNotObject = ErrorCode(40524) // Location40524
+
+	// ErrDatePartUnknownField indicates that date part operator has an unknown argument.
+	ErrDatePartUnknownField = ErrorCode(40535) // Location40535
+
+	// ErrDatePartArrayLen indicates that date part operator got an array with more than one element.
+	ErrDatePartArrayLen = ErrorCode(40536) // Location40536
+
+	// ErrDatePartMissingDate indicates that date part operator date argument is missing.
+	ErrDatePartMissingDate = ErrorCode(40539) // Location40539
+
+	// ErrDateFromStringNotObject indicates that $dateFromString argument is not an object.
+	ErrDateFromStringNotObject = ErrorCode(40540) // Location40540
+
+	// ErrDateFromStringUnknownField indicates that $dateFromString has an unknown argument.
+	ErrDateFromStringUnknownField = ErrorCode(40541) // Location40541
+
+	// ErrDateFromStringMissingDateString indicates that $dateFromString dateString argument is missing.
+	ErrDateFromStringMissingDateString = ErrorCode(40542) // Location40542
+
 	// ErrCollStatsIsNotFirstStage indicates that $collStats must be the first stage in the pipeline.
 	ErrCollStatsIsNotFirstStage = ErrorCode(40602) // Location40602
 
+	// ErrDocumentsIsNotFirstStage indicates that $documents must be the first stage in the pipeline.
+	ErrDocumentsIsNotFirstStage = ErrorCode(40602) // Location40602
+
+	// ErrGeoNearNotFirstStage indicates that $geoNear is not the first stage of the pipeline.
+	ErrGeoNearNotFirstStage = ErrorCode(40603) // Location40603
+
+	// ErrDateFromStringFormatBadType indicates that $dateFromString format is not a string.
+	ErrDateFromStringFormatBadType = ErrorCode(40684) // Location40684
+
 	// ErrSetEmptyPassword indicates that a password must not be empty.
 	ErrSetEmptyPassword = ErrorCode(50687) // Location50687
 
+	// ErrTrimUnknownField indicates that $trim has unknown field.
+	ErrTrimUnknownField = ErrorCode(50694) // Location50694
+
+	// ErrTrimMissingInput indicates that $trim does not have input.
+	ErrTrimMissingInput = ErrorCode(50695) // Location50695
+
+	// ErrTrimNotObject indicates that $trim argument is not an object.
+	ErrTrimNotObject = ErrorCode(50696) // Location50696
+
+	// ErrTrimInputBadType indicates that $trim input is not a string.
+	ErrTrimInputBadType = ErrorCode(50699) // Location50699
+
+	// ErrTrimCharsBadType indicates that $trim chars is not a string.
+	ErrTrimCharsBadType = ErrorCode(50700) // Location50700
+
 	// ErrFreeMonitoringDisabled indicates that free monitoring is disabled
 	// by command-line or config file.
 	ErrFreeMonitoringDisabled = ErrorCode(50840) // Location50840
@@ -297,12 +831,39 @@ const (
 	// ErrRegexOptions indicates regex options error.
 	ErrRegexOptions = ErrorCode(51075) // Location51075
 
+	// ErrRoundBadType indicates that $round or $trunc argument is not a number.
+	ErrRoundBadType = ErrorCode(51081) // Location51081
+
+	// ErrRoundBadPlace indicates that $round or $trunc place is not an integral number.
+	ErrRoundBadPlace = ErrorCode(51082) // Location51082
+
+	// ErrRoundPlaceOutOfRange indicates that $round or $trunc place is out of range.
+	ErrRoundPlaceOutOfRange = ErrorCode(51083) // Location51083
+
 	// ErrRegexMissingParen indicates missing parentheses in regex expression.
 	ErrRegexMissingParen = ErrorCode(51091) // Location51091
 
+	// ErrRegexNotObject indicates that regex operator argument is not an object.
+	ErrRegexNotObject = ErrorCode(51103) // Location51103
+
+	// ErrRegexInputBadType indicates that regex operator input is not a string.
+	ErrRegexInputBadType = ErrorCode(51104) // Location51104
+
+	// ErrRegexBadType indicates that regex operator regex is not a string or regex.
+	ErrRegexBadType = ErrorCode(51105) // Location51105
+
+	// ErrRegexOptionsBadType indicates that regex operator options is not a string.
+	ErrRegexOptionsBadType = ErrorCode(51106) // Location51106
+
+	// ErrRegexOptionsConflict indicates that regex options are specified in both regex and options fields.
+	ErrRegexOptionsConflict = ErrorCode(51107) // Location51107
+
 	// ErrBadRegexOption indicates bad regex option value passed.
 	ErrBadRegexOption = ErrorCode(51108) // Location51108
 
+	// ErrRegexInvalid indicates that regex operator regex is invalid.
+	ErrRegexInvalid = ErrorCode(51111) // Location51111
+
 	// ErrBadPositionalProjection indicates that positional operator could not find a matching element in the array.
 	ErrBadPositionalProjection = ErrorCode(51246) // Location51246
 
@@ -315,6 +876,84 @@ const (
 	// ErrEmptyProject indicates that projection specification must have at least one field.
 	ErrEmptyProject = ErrorCode(51272) // Location51272
 
+	// ErrReplaceBadType indicates that $replaceOne or $replaceAll argument is not a string.
+	ErrReplaceBadType = ErrorCode(51746) // Location51746
+
+	// ErrReplaceMissingField indicates that $replaceOne or $replaceAll required argument is missing.
+	ErrReplaceMissingField = ErrorCode(51749) // Location51749
+
+	// ErrReplaceUnknownField indicates that $replaceOne or $replaceAll has unknown argument.
+	ErrReplaceUnknownField = ErrorCode(51750) // Location51750
+
+	// ErrReplaceNotObject indicates that $replaceOne or $replaceAll argument is not an object.
+	ErrReplaceNotObject = ErrorCode(51751) // Location51751
+
+	// ErrFilterLimitNotPositive indicates that $filter limit is not positive.
+	ErrFilterLimitNotPositive = ErrorCode(327391) // Location327391
+
+	// ErrFilterLimitNotInt indicates that $filter limit is not a 32-bit integer.
+	ErrFilterLimitNotInt = ErrorCode(327392) // Location327392
+
+	// ErrIfNullArgsLen indicates that $ifNull has less than two arguments.
+	ErrIfNullArgsLen = ErrorCode(1257300) // Location1257300
+
+	// ErrSortArrayNotObject indicates that $sortArray argument is not an object.
+	ErrSortArrayNotObject = ErrorCode(2942500) // Location2942500
+
+	// ErrSortArrayUnknownField indicates that $sortArray has an unknown argument.
+	ErrSortArrayUnknownField = ErrorCode(2942501) // Location2942501
+
+	// ErrSortArrayMissingInput indicates that $sortArray input argument is missing.
+	ErrSortArrayMissingInput = ErrorCode(2942502) // Location2942502
+
+	// ErrSortArrayMissingSortBy indicates that $sortArray sortBy argument is missing.
+	ErrSortArrayMissingSortBy = ErrorCode(2942503) // Location2942503
+
+	// ErrSortArrayInputBadType indicates that $sortArray input is not an array.
+	ErrSortArrayInputBadType = ErrorCode(2942504) // Location2942504
+
+	// ErrSortArrayBadSortBy indicates that $sortArray sortBy is invalid.
+	ErrSortArrayBadSortBy = ErrorCode(2942505) // Location2942505
+
+	// ErrRandArgument indicates that $rand operator has arguments.
+	ErrRandArgument = ErrorCode(3040501) // Location3040501
+
+	// ErrGetFieldUnknownField indicates that $getField argument has an unknown field.
+	ErrGetFieldUnknownField = ErrorCode(3041702) // Location3041702
+
+	// ErrGetFieldMissingField indicates that $getField field argument is missing.
+	ErrGetFieldMissingField = ErrorCode(3041703) // Location3041703
+
+	// ErrGetFieldFieldType indicates that $getField field argument is not a string.
+	ErrGetFieldFieldType = ErrorCode(3041704) // Location3041704
+
+	// ErrGetFieldInputType indicates that $getField input argument is not an object.
+	ErrGetFieldInputType = ErrorCode(3041705) // Location3041705
+
+	// ErrSetFieldNotObject indicates that $setField argument is not an object.
+	ErrSetFieldNotObject = ErrorCode(4161100) // Location4161100
+
+	// ErrSetFieldUnknownField indicates that $setField argument has an unknown field.
+	ErrSetFieldUnknownField = ErrorCode(4161101) // Location4161101
+
+	// ErrSetFieldMissingField indicates that $setField field argument is missing.
+	ErrSetFieldMissingField = ErrorCode(4161102) // Location4161102
+
+	// ErrSetFieldMissingValue indicates that $setField value argument is missing.
+	ErrSetFieldMissingValue = ErrorCode(4161103) // Location4161103
+
+	// ErrSetFieldMissingInput indicates that $setField input argument is missing.
+	ErrSetFieldMissingInput = ErrorCode(4161104) // Location4161104
+
+	// ErrSetFieldFieldType indicates that $setField field argument is not a string.
+	ErrSetFieldFieldType = ErrorCode(4161105) // Location4161105
+
+	// ErrSetFieldFieldNotConstant indicates that $setField field argument is not a constant.
+	ErrSetFieldFieldNotConstant = ErrorCode(4161106) // Location4161106
+
+	// ErrSetFieldInputType indicates that $setField input argument is not an object.
+	ErrSetFieldInputType = ErrorCode(4161107) // Location4161107
+
 	// ErrDuplicateField indicates duplicate field is specified.
 	ErrDuplicateField = ErrorCode(4822819) // Location4822819
 
@@ -324,16 +963,119 @@ const (
 	// ErrStageLimitInvalidArg indicates invalid argument for the aggregation $limit stage.
 	ErrStageLimitInvalidArg = ErrorCode(5107201) // Location5107201
 
+	// ErrDateDiffNotObject indicates that $dateDiff argument is not an object.
+	ErrDateDiffNotObject = ErrorCode(5166300) // Location5166300
+
+	// ErrDateDiffUnknownField indicates that $dateDiff has an unknown argument.
+	ErrDateDiffUnknownField = ErrorCode(5166301) // Location5166301
+
+	// ErrDateDiffMissingField indicates that $dateDiff required argument is missing.
+	ErrDateDiffMissingField = ErrorCode(5166302) // Location5166302
+
+	// ErrDateDiffDateBadType indicates that $dateDiff start or end date is not a date.
+	ErrDateDiffDateBadType = ErrorCode(5166307) // Location5166307
+
+	// ErrDateAddNotObject indicates that $dateAdd or $dateSubtract argument is not an object.
+	ErrDateAddNotObject = ErrorCode(5166400) // Location5166400
+
+	// ErrDateAddUnknownField indicates that $dateAdd or $dateSubtract has an unknown argument.
+	ErrDateAddUnknownField = ErrorCode(5166401) // Location5166401
+
+	// ErrDateAddMissingField indicates that $dateAdd or $dateSubtract required argument is missing.
+	ErrDateAddMissingField = ErrorCode(5166402) // Location5166402
+
+	// ErrDateAddDateBadType indicates that $dateAdd or $dateSubtract start date is not a date.
+	ErrDateAddDateBadType = ErrorCode(5166403) // Location5166403
+
+	// ErrDateAddAmountBadType indicates that $dateAdd or $dateSubtract amount is not an integer.
+	ErrDateAddAmountBadType = ErrorCode(5166404) // Location5166404
+
+	// ErrDateAddOverflow indicates that $dateAdd or $dateSubtract result overflowed.
+	ErrDateAddOverflow = ErrorCode(5166406) // Location5166406
+
+	// ErrDateTruncNotObject indicates that $dateTrunc argument is not an object.
+	ErrDateTruncNotObject = ErrorCode(5439007) // Location5439007
+
+	// ErrDateTruncUnknownField indicates that $dateTrunc has an unknown argument.
+	ErrDateTruncUnknownField = ErrorCode(5439008) // Location5439008
+
+	// ErrDateTruncMissingField indicates that $dateTrunc required argument is missing.
+	ErrDateTruncMissingField = ErrorCode(5439009) // Location5439009
+
+	// ErrDateTruncDateBadType indicates that $dateTrunc date is not a date.
+	ErrDateTruncDateBadType = ErrorCode(5439012) // Location5439012
+
+	// ErrTimeUnitBadType indicates that the time unit is not a string.
+	ErrTimeUnitBadType = ErrorCode(5439013) // Location5439013
+
+	// ErrTimeUnitInvalid indicates that the time unit is not recognized.
+	ErrTimeUnitInvalid = ErrorCode(5439014) // Location5439014
+
+	// ErrStartOfWeekInvalid indicates that startOfWeek is not a day of a week.
+	ErrStartOfWeekInvalid = ErrorCode(5439015) // Location5439015
+
+	// ErrStartOfWeekBadType indicates that startOfWeek is not a string.
+	ErrStartOfWeekBadType = ErrorCode(5439016) // Location5439016
+
+	// ErrDateTruncBinSizeBadType indicates that $dateTrunc binSize is not an integer.
+	ErrDateTruncBinSizeBadType = ErrorCode(5439017) // Location5439017
+
+	// ErrDateTruncBinSizeNotPositive indicates that $dateTrunc binSize is not positive.
+	ErrDateTruncBinSizeNotPositive = ErrorCode(5439018) // Location5439018
+
 	// ErrStageCollStatsInvalidArg indicates invalid argument for the aggregation $collStats stage.
 	ErrStageCollStatsInvalidArg = ErrorCode(5447000) // Location5447000
 
+	// ErrGetFieldFieldNotConstant indicates that $getField field argument is not a constant.
+	ErrGetFieldFieldNotConstant = ErrorCode(5654601) // Location5654601
+
+	// ErrNAccumulatorUnknownField indicates that $firstN, $lastN, $minN or $maxN has an unknown argument.
+	ErrNAccumulatorUnknownField = ErrorCode(5787901) // Location5787901
+
+	// ErrNAccumulatorNBadType indicates that n of an accumulator is not a number.
+	ErrNAccumulatorNBadType = ErrorCode(5787902) // Location5787902
+
+	// ErrNAccumulatorNNotIntegral indicates that n of an accumulator is not an integral number.
+	ErrNAccumulatorNNotIntegral = ErrorCode(5787903) // Location5787903
+
+	// ErrNAccumulatorMissingN indicates that n of an accumulator is missing.
+	ErrNAccumulatorMissingN = ErrorCode(5787906) // Location5787906
+
+	// ErrNAccumulatorMissingInput indicates that input of an accumulator is missing.
+	ErrNAccumulatorMissingInput = ErrorCode(5787907) // Location5787907
+
+	// ErrNAccumulatorNNotPositive indicates that n of an accumulator is not positive.
+	ErrNAccumulatorNNotPositive = ErrorCode(5787908) // Location5787908
+
+	// ErrTopBottomNotObject indicates that $top, $bottom, $topN or $bottomN argument is not an object.
+	ErrTopBottomNotObject = ErrorCode(5788001) // Location5788001
+
+	// ErrTopBottomUnknownField indicates that $top, $bottom, $topN or $bottomN has an unknown argument.
+	ErrTopBottomUnknownField = ErrorCode(5788002) // Location5788002
+
+	// ErrTopBottomMissingOutput indicates that output of $top, $bottom, $topN or $bottomN is missing.
+	ErrTopBottomMissingOutput = ErrorCode(5788003) // Location5788003
+
+	// ErrTopBottomMissingSortBy indicates that sortBy of $top, $bottom, $topN or $bottomN is missing.
+	ErrTopBottomMissingSortBy = ErrorCode(5788004) // Location5788004
+
+	// ErrTopBottomSortByNotObject indicates that sortBy of $top, $bottom, $topN or $bottomN is not an object.
+	ErrTopBottomSortByNotObject = ErrorCode(5788005) // Location5788005
+
 	// ErrStageIndexedStringVectorDuplicate indicates that input to IndexedStringVector contained duplicate values.
 	ErrStageIndexedStringVectorDuplicate = ErrorCode(7582300) // Location7582300
+	// ErrNAccumulatorNotObject indicates that $firstN, $lastN, $minN or $maxN argument is not an object.
+	ErrNAccumulatorNotObject = ErrorCode(5787900) // Location5787900
+
+	// ErrPercentileBadP indicates that p of $percentile is not an array of numbers from 0 to 1.
+	ErrPercentileBadP = ErrorCode(7750301) // Location7750301
+
 )
 
 // ErrInfo represents additional optional error information.
 type ErrInfo struct {
-	Argument string // command's argument, operator, or aggregation pipeline stage that caused an error
+	Argument string          // command's argument, operator, or aggregation pipeline stage that caused an error
+	Details  *types.Document // errInfo returned to the client, like details of DocumentValidationFailure
 }
 
 // ProtoErr represents protocol error type.
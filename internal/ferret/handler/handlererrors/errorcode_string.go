@@ -37,12 +37,130 @@ func _() {
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
 	_ = x[ErrDocumentValidationFailure-121]
+	_ = x[ErrJSInterpreterFailure-139]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidPipelineOperator-168]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
+	_ = x[ErrQueryFeatureNotAllowed-224]
 	_ = x[ErrNotImplemented-238]
+	_ = x[ErrConversionFailure-241]
 	_ = x[ErrIndexesWrongType-10065]
 	_ = x[ErrDuplicateKeyInsert-11000]
+	_ = x[ErrDateBadType-16006]
+	_ = x[ErrStringConversion-16007]
+	_ = x[ErrSubstrBytesStartType-16034]
+	_ = x[ErrSubstrBytesLengthType-16035]
+	_ = x[ErrConcatBadType-16702]
+	_ = x[ErrLetNotObject-16874]
+	_ = x[ErrLetUnknownField-16875]
+	_ = x[ErrLetMissingVars-16876]
+	_ = x[ErrLetMissingIn-16877]
+	_ = x[ErrMapNotObject-16878]
+	_ = x[ErrMapUnknownField-16879]
+	_ = x[ErrMapMissingInput-16880]
+	_ = x[ErrMapMissingIn-16882]
+	_ = x[ErrMapInputBadType-16883]
+	_ = x[ErrSetIsSubsetSecondNotArray-17042]
+	_ = x[ErrSetUnionNotArray-17043]
+	_ = x[ErrSetEqualsNotArray-17044]
+	_ = x[ErrSetEqualsArgsLen-17045]
+	_ = x[ErrSetIsSubsetFirstNotArray-17046]
+	_ = x[ErrSetIntersectionNotArray-17047]
+	_ = x[ErrSetDifferenceFirstNotArray-17048]
+	_ = x[ErrSetDifferenceSecondNotArray-17049]
+	_ = x[ErrCondMissingIf-17080]
+	_ = x[ErrCondMissingThen-17081]
+	_ = x[ErrCondMissingElse-17082]
+	_ = x[ErrCondUnknownField-17083]
+	_ = x[ErrSizeBadType-17124]
+	_ = x[ErrDateToStringFormatBadType-18533]
+	_ = x[ErrDateToStringUnknownField-18534]
+	_ = x[ErrDateFormatUnmatchedPercent-18535]
+	_ = x[ErrDateFormatInvalidChar-18536]
+	_ = x[ErrDateToStringMissingDate-18628]
+	_ = x[ErrDateToStringNotObject-18629]
+	_ = x[ErrFilterNotObject-28646]
+	_ = x[ErrFilterUnknownField-28647]
+	_ = x[ErrFilterMissingInput-28648]
+	_ = x[ErrFilterMissingCond-28650]
+	_ = x[ErrFilterInputBadType-28651]
+	_ = x[ErrSubstrBytesStartContinuation-28656]
+	_ = x[ErrSubstrBytesEndContinuation-28657]
+	_ = x[ErrConcatArraysBadType-28664]
+	_ = x[ErrArrayElemAtArrayBadType-28689]
+	_ = x[ErrArrayElemAtIndexBadType-28690]
+	_ = x[ErrArrayElemAtIndexNotInt-28691]
+	_ = x[ErrSliceSecondArgBadType-28725]
+	_ = x[ErrSliceSecondArgNotInt-28726]
+	_ = x[ErrSliceThirdArgBadType-28727]
+	_ = x[ErrSliceThirdArgNotInt-28728]
+	_ = x[ErrSliceThirdArgNotPositive-28729]
+	_ = x[ErrStageUnwindPathType-28808]
+	_ = x[ErrStageUnwindPreserveType-28809]
+	_ = x[ErrStageUnwindIndexType-28810]
+	_ = x[ErrStageUnwindUnknownOption-28811]
+	_ = x[ErrStageUnwindIndexPrefix-28822]
+	_ = x[ErrRegexMissingInput-31022]
+	_ = x[ErrRegexMissingRegex-31023]
+	_ = x[ErrRegexUnknownField-31024]
+	_ = x[ErrDateFromPartsValueRange-31034]
+	_ = x[ErrFunctionNotObject-31260]
+	_ = x[ErrFunctionMissingBody-31261]
+	_ = x[ErrFunctionBodyType-31262]
+	_ = x[ErrFunctionMissingArgs-31263]
+	_ = x[ErrFunctionArgsType-31264]
+	_ = x[ErrFunctionMissingLang-31418]
+	_ = x[ErrFunctionLang-31419]
+	_ = x[ErrReverseArrayBadType-34435]
+	_ = x[ErrRangeStartBadType-34443]
+	_ = x[ErrRangeStartNotInt-34444]
+	_ = x[ErrRangeEndBadType-34445]
+	_ = x[ErrRangeEndNotInt-34446]
+	_ = x[ErrRangeStepBadType-34447]
+	_ = x[ErrRangeStepNotInt-34448]
+	_ = x[ErrRangeStepZero-34449]
+	_ = x[ErrSubstrCPStartType-34450]
+	_ = x[ErrSubstrCPStartNotIntegral-34451]
+	_ = x[ErrSubstrCPLengthType-34452]
+	_ = x[ErrSubstrCPLengthNotIntegral-34453]
+	_ = x[ErrSubstrCPLengthNegative-34454]
+	_ = x[ErrSubstrCPStartNegative-34455]
+	_ = x[ErrZipNotObject-34460]
+	_ = x[ErrZipInputsNotArray-34461]
+	_ = x[ErrZipDefaultsNotArray-34462]
+	_ = x[ErrZipUseLongestLengthBadType-34463]
+	_ = x[ErrZipUnknownField-34464]
+	_ = x[ErrZipMissingInputs-34465]
+	_ = x[ErrZipDefaultsWithoutUseLongestLength-34466]
+	_ = x[ErrZipDefaultsLength-34467]
+	_ = x[ErrZipInputBadType-34468]
+	_ = x[ErrStrLenCPBadType-34471]
+	_ = x[ErrStrLenBytesBadType-34473]
+	_ = x[ErrSwitchNotObject-40060]
+	_ = x[ErrSwitchBranchesNotArray-40061]
+	_ = x[ErrSwitchBranchNotObject-40062]
+	_ = x[ErrSwitchBranchUnknownField-40063]
+	_ = x[ErrSwitchBranchMissingCase-40064]
+	_ = x[ErrSwitchBranchMissingThen-40065]
+	_ = x[ErrSwitchNoMatch-40066]
+	_ = x[ErrSwitchUnknownField-40067]
+	_ = x[ErrSwitchNoBranches-40068]
+	_ = x[ErrReduceNotObject-40075]
+	_ = x[ErrReduceUnknownField-40076]
+	_ = x[ErrReduceMissingInput-40077]
+	_ = x[ErrReduceMissingInitialValue-40078]
+	_ = x[ErrReduceMissingIn-40079]
+	_ = x[ErrReduceInputBadType-40080]
+	_ = x[ErrInArrayBadType-40081]
+	_ = x[ErrSplitInputBadType-40085]
+	_ = x[ErrSplitDelimiterBadType-40086]
+	_ = x[ErrSplitEmptyDelimiter-40087]
+	_ = x[ErrIndexOfArrayBadType-40090]
+	_ = x[ErrIndexOfCPInputBadType-40093]
+	_ = x[ErrIndexOfCPSubstringBadType-40094]
+	_ = x[ErrIndexOfCPIndexNotIntegral-40096]
+	_ = x[ErrIndexOfCPIndexNegative-40097]
+	_ = x[ErrTextScoreUnavailable-40218]
 	_ = x[ErrSetBadExpression-40272]
 	_ = x[ErrStageGroupInvalidFields-15947]
 	_ = x[ErrStageGroupID-15948]
@@ -60,10 +178,34 @@ func _() {
 	_ = x[ErrPathContainsEmptyElement-15998]
 	_ = x[ErrOperatorWrongLenOfArgs-16020]
 	_ = x[ErrFieldPathInvalidName-16410]
+	_ = x[ErrMultiplyBadType-16555]
+	_ = x[ErrSubtractBadType-16556]
+	_ = x[ErrDivideByZero-16608]
+	_ = x[ErrDivideBadType-16609]
+	_ = x[ErrModByZero-16610]
+	_ = x[ErrModBadType-16611]
+	_ = x[ErrAddMultipleDates-16612]
 	_ = x[ErrGroupInvalidFieldPath-16872]
 	_ = x[ErrGroupUndefinedVariable-17276]
 	_ = x[ErrInvalidArg-28667]
 	_ = x[ErrSliceFirstArg-28724]
+	_ = x[ErrStageSampleNotObject-28745]
+	_ = x[ErrStageSampleSizeNotNumber-28746]
+	_ = x[ErrStageSampleSizeNegative-28747]
+	_ = x[ErrStageSampleUnknownOption-28748]
+	_ = x[ErrStageSampleNoSize-28749]
+	_ = x[ErrAbsLongMin-28680]
+	_ = x[ErrSqrtNegative-28714]
+	_ = x[ErrLogBadType-28756]
+	_ = x[ErrLogBaseBadType-28757]
+	_ = x[ErrLogNotPositive-28758]
+	_ = x[ErrLogBadBase-28759]
+	_ = x[ErrLog10NotPositive-28761]
+	_ = x[ErrPowBaseBadType-28762]
+	_ = x[ErrPowExponentBadType-28763]
+	_ = x[ErrPowZeroNegativeExponent-28764]
+	_ = x[ErrUnaryNumericBadType-28765]
+	_ = x[ErrLnNotPositive-28766]
 	_ = x[ErrStageUnsetNoPath-31119]
 	_ = x[ErrStageUnsetArrElementInvalidType-31120]
 	_ = x[ErrStageUnsetInvalidType-31002]
@@ -88,28 +230,133 @@ func _() {
 	_ = x[ErrStageInvalid-40323]
 	_ = x[ErrEmptyFieldPath-40352]
 	_ = x[ErrInvalidFieldPath-40353]
+	_ = x[ErrArrayToObjectBadType-40386]
+	_ = x[ErrObjectToArrayBadType-40390]
+	_ = x[ErrArrayToObjectKeyCount-40392]
+	_ = x[ErrArrayToObjectMissingKey-40393]
+	_ = x[ErrArrayToObjectKeyBadType-40394]
+	_ = x[ErrArrayToObjectPairKeyBadType-40395]
+	_ = x[ErrArrayToObjectPairSize-40396]
+	_ = x[ErrArrayToObjectInconsistent-40397]
+	_ = x[ErrArrayToObjectElementBadType-40398]
+	_ = x[ErrMergeObjectsBadType-40400]
 	_ = x[ErrMissingField-40414]
 	_ = x[ErrFailedToParseInput-40415]
+	_ = x[ErrTimezoneUnrecognized-40485]
+	_ = x[ErrDateFromPartsMixed-40489]
+	_ = x[ErrDateFromPartsNotInteger-40515]
+	_ = x[ErrDateFromPartsMissingYear-40516]
+	_ = x[ErrTimezoneBadType-40517]
+	_ = x[ErrDateFromPartsUnknownField-40518]
+	_ = x[ErrDateFromPartsNotObject-40519]
+	_ = x[ErrDateToPartsUnknownField-40520]
+	_ = x[ErrDateToPartsISO8601BadType-40521]
+	_ = x[ErrDateToPartsMissingDate-40522]
+	_ = x[ErrDateFromPartsYearRange-40523]
+	_ = x[ErrDateToPartsNotObject-40524]
+	_ = x[ErrDatePartUnknownField-40535]
+	_ = x[ErrDatePartArrayLen-40536]
+	_ = x[ErrDatePartMissingDate-40539]
+	_ = x[ErrDateFromStringNotObject-40540]
+	_ = x[ErrDateFromStringUnknownField-40541]
+	_ = x[ErrDateFromStringMissingDateString-40542]
 	_ = x[ErrCollStatsIsNotFirstStage-40602]
+	_ = x[ErrDocumentsIsNotFirstStage-40602]
+	_ = x[ErrGeoNearNotFirstStage-40603]
+	_ = x[ErrDateFromStringFormatBadType-40684]
 	_ = x[ErrSetEmptyPassword-50687]
+	_ = x[ErrTrimUnknownField-50694]
+	_ = x[ErrTrimMissingInput-50695]
+	_ = x[ErrTrimNotObject-50696]
+	_ = x[ErrTrimInputBadType-50699]
+	_ = x[ErrTrimCharsBadType-50700]
 	_ = x[ErrFreeMonitoringDisabled-50840]
 	_ = x[ErrUserAlreadyExists-51003]
 	_ = x[ErrValueNegative-51024]
 	_ = x[ErrRegexOptions-51075]
+	_ = x[ErrRoundBadType-51081]
+	_ = x[ErrRoundBadPlace-51082]
+	_ = x[ErrRoundPlaceOutOfRange-51083]
 	_ = x[ErrRegexMissingParen-51091]
+	_ = x[ErrRegexNotObject-51103]
+	_ = x[ErrRegexInputBadType-51104]
+	_ = x[ErrRegexBadType-51105]
+	_ = x[ErrRegexOptionsBadType-51106]
+	_ = x[ErrRegexOptionsConflict-51107]
 	_ = x[ErrBadRegexOption-51108]
+	_ = x[ErrRegexInvalid-51111]
 	_ = x[ErrBadPositionalProjection-51246]
 	_ = x[ErrElementMismatchPositionalProjection-51247]
 	_ = x[ErrEmptySubProject-51270]
 	_ = x[ErrEmptyProject-51272]
+	_ = x[ErrReplaceBadType-51746]
+	_ = x[ErrReplaceMissingField-51749]
+	_ = x[ErrReplaceUnknownField-51750]
+	_ = x[ErrReplaceNotObject-51751]
+	_ = x[ErrFilterLimitNotPositive-327391]
+	_ = x[ErrFilterLimitNotInt-327392]
+	_ = x[ErrIfNullArgsLen-1257300]
+	_ = x[ErrSortArrayNotObject-2942500]
+	_ = x[ErrSortArrayUnknownField-2942501]
+	_ = x[ErrSortArrayMissingInput-2942502]
+	_ = x[ErrSortArrayMissingSortBy-2942503]
+	_ = x[ErrSortArrayInputBadType-2942504]
+	_ = x[ErrSortArrayBadSortBy-2942505]
+	_ = x[ErrRandArgument-3040501]
+	_ = x[ErrGetFieldUnknownField-3041702]
+	_ = x[ErrGetFieldMissingField-3041703]
+	_ = x[ErrGetFieldFieldType-3041704]
+	_ = x[ErrGetFieldInputType-3041705]
+	_ = x[ErrSetFieldNotObject-4161100]
+	_ = x[ErrSetFieldUnknownField-4161101]
+	_ = x[ErrSetFieldMissingField-4161102]
+	_ = x[ErrSetFieldMissingValue-4161103]
+	_ = x[ErrSetFieldMissingInput-4161104]
+	_ = x[ErrSetFieldFieldType-4161105]
+	_ = x[ErrSetFieldFieldNotConstant-4161106]
+	_ = x[ErrSetFieldInputType-4161107]
 	_ = x[ErrDuplicateField-4822819]
 	_ = x[ErrStageSkipBadValue-5107200]
 	_ = x[ErrStageLimitInvalidArg-5107201]
+	_ = x[ErrDateDiffNotObject-5166300]
+	_ = x[ErrDateDiffUnknownField-5166301]
+	_ = x[ErrDateDiffMissingField-5166302]
+	_ = x[ErrDateDiffDateBadType-5166307]
+	_ = x[ErrDateAddNotObject-5166400]
+	_ = x[ErrDateAddUnknownField-5166401]
+	_ = x[ErrDateAddMissingField-5166402]
+	_ = x[ErrDateAddDateBadType-5166403]
+	_ = x[ErrDateAddAmountBadType-5166404]
+	_ = x[ErrDateAddOverflow-5166406]
+	_ = x[ErrDateTruncNotObject-5439007]
+	_ = x[ErrDateTruncUnknownField-5439008]
+	_ = x[ErrDateTruncMissingField-5439009]
+	_ = x[ErrDateTruncDateBadType-5439012]
+	_ = x[ErrTimeUnitBadType-5439013]
+	_ = x[ErrTimeUnitInvalid-5439014]
+	_ = x[ErrStartOfWeekInvalid-5439015]
+	_ = x[ErrStartOfWeekBadType-5439016]
+	_ = x[ErrDateTruncBinSizeBadType-5439017]
+	_ = x[ErrDateTruncBinSizeNotPositive-5439018]
 	_ = x[ErrStageCollStatsInvalidArg-5447000]
+	_ = x[ErrGetFieldFieldNotConstant-5654601]
+	_ = x[ErrNAccumulatorUnknownField-5787901]
+	_ = x[ErrNAccumulatorNBadType-5787902]
+	_ = x[ErrNAccumulatorNNotIntegral-5787903]
+	_ = x[ErrNAccumulatorMissingN-5787906]
+	_ = x[ErrNAccumulatorMissingInput-5787907]
+	_ = x[ErrNAccumulatorNNotPositive-5787908]
+	_ = x[ErrTopBottomNotObject-5788001]
+	_ = x[ErrTopBottomUnknownField-5788002]
+	_ = x[ErrTopBottomMissingOutput-5788003]
+	_ = x[ErrTopBottomMissingSortBy-5788004]
+	_ = x[ErrTopBottomSortByNotObject-5788005]
 	_ = x[ErrStageIndexedStringVectorDuplicate-7582300]
+	_ = x[ErrNAccumulatorNotObject-5787900]
+	_ = x[ErrPercentileBadP-7750301]
 }
 
-const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIDEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedLocation10065Location11000Location15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location50687Location50840Location51003Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000Location7582300"
+const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIDEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureJSInterpreterFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionQueryFeatureNotAllowedNotImplementedConversionFailureLocation10065Location11000Location15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16006Location16007Location16020Location16034Location16035Location16406Location16410Location16555Location16556Location16608Location16609Location16610Location16611Location16612Location16702Location16872Location16874Location16875Location16876Location16877Location16878Location16879Location16880Location16882Location16883Location17042Location17043Location17044Location17045Location17046Location17047Location17048Location17049Location17080Location17081Location17082Location17083Location17124Location17276Location18533Location18534Location18535Location18536Location18628Location18629Location28646Location28647Location28648Location28650Location28651Location28656Location28657Location28664Location28667Location28680Location28689Location28690Location28691Location28714Location28724Location28725Location28726Location28727Location28728Location28729Location28745Location28746Location28747Location28748Location28749Location28756Location28757Location28758Location28759Location28761Location28762Location28763Location28764Location28765Location28766Location28808Location28809Location28810Location28811Location28812Location28818Location28822Location31002Location31022Location31023Location31024Location31034Location31119Location31120Location31249Location31250Location31253Location31254Location31260Location31261Location31262Location31263Location31264Location31324Location31325Location31394Location31395Location31418Location31419Location34435Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location34471Location34473Location40060Location40061Location40062Location40063Location40064Location40065Location40066Location40067Location40068Location40075Location40076Location40077Location40078Location40079Location40080Location40081Location40085Location40086Location40087Location40090Location40093Location40094Location40096Location40097Location40156Location40157Location40158Location40160Location40181Location40218Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40386Location40390Location40392Location40393Location40394Location40395Location40396Location40397Location40398Location40400Location40414Location40415Location40485Location40489Location40515Location40516Location40517Location40518Location40519Location40520Location40521Location40522Location40523Location40524Location40535Location40536Location40539Location40540Location40541Location40542Location40602Location40603Location40684Location50687Location50694Location50695Location50696Location50699Location50700Location50840Location51003Location51024Location51075Location51081Location51082Location51083Location51091Location51103Location51104Location51105Location51106Location51107Location51108Location51111Location51246Location51247Location51270Location51272Location51746Location51749Location51750Location51751Location327391Location327392Location1257300Location2942500Location2942501Location2942502Location2942503Location2942504Location2942505Location3040501Location3041702Location3041703Location3041704Location3041705Location4161100Location4161101Location4161102Location4161103Location4161104Location4161105Location4161106Location4161107Location4822819Location5107200Location5107201Location5166300Location5166301Location5166302Location5166307Location5166400Location5166401Location5166402Location5166403Location5166404Location5166406Location5439007Location5439008Location5439009Location5439012Location5439013Location5439014Location5439015Location5439016Location5439017Location5439018Location5447000Location5654601Location5787900Location5787901Location5787902Location5787903Location5787906Location5787907Location5787908Location5788001Location5788002Location5788003Location5788004Location5788005Location7582300Location7750301"
 
 var _ErrorCode_map = map[ErrorCode]string{
 	0:       _ErrorCode_name[0:5],
@@ -141,76 +388,322 @@ var _ErrorCode_map = map[ErrorCode]string{
 	86:      _ErrorCode_name[385:406],
 	96:      _ErrorCode_name[406:421],
 	121:     _ErrorCode_name[421:446],
-	168:     _ErrorCode_name[446:469],
-	186:     _ErrorCode_name[469:498],
-	197:     _ErrorCode_name[498:529],
-	238:     _ErrorCode_name[529:543],
-	10065:   _ErrorCode_name[543:556],
-	11000:   _ErrorCode_name[556:569],
-	15947:   _ErrorCode_name[569:582],
-	15948:   _ErrorCode_name[582:595],
-	15955:   _ErrorCode_name[595:608],
-	15958:   _ErrorCode_name[608:621],
-	15959:   _ErrorCode_name[621:634],
-	15969:   _ErrorCode_name[634:647],
-	15973:   _ErrorCode_name[647:660],
-	15974:   _ErrorCode_name[660:673],
-	15975:   _ErrorCode_name[673:686],
-	15976:   _ErrorCode_name[686:699],
-	15981:   _ErrorCode_name[699:712],
-	15983:   _ErrorCode_name[712:725],
-	15998:   _ErrorCode_name[725:738],
-	16020:   _ErrorCode_name[738:751],
-	16406:   _ErrorCode_name[751:764],
-	16410:   _ErrorCode_name[764:777],
-	16872:   _ErrorCode_name[777:790],
-	17276:   _ErrorCode_name[790:803],
-	28667:   _ErrorCode_name[803:816],
-	28724:   _ErrorCode_name[816:829],
-	28812:   _ErrorCode_name[829:842],
-	28818:   _ErrorCode_name[842:855],
-	31002:   _ErrorCode_name[855:868],
-	31119:   _ErrorCode_name[868:881],
-	31120:   _ErrorCode_name[881:894],
-	31249:   _ErrorCode_name[894:907],
-	31250:   _ErrorCode_name[907:920],
-	31253:   _ErrorCode_name[920:933],
-	31254:   _ErrorCode_name[933:946],
-	31324:   _ErrorCode_name[946:959],
-	31325:   _ErrorCode_name[959:972],
-	31394:   _ErrorCode_name[972:985],
-	31395:   _ErrorCode_name[985:998],
-	40156:   _ErrorCode_name[998:1011],
-	40157:   _ErrorCode_name[1011:1024],
-	40158:   _ErrorCode_name[1024:1037],
-	40160:   _ErrorCode_name[1037:1050],
-	40181:   _ErrorCode_name[1050:1063],
-	40234:   _ErrorCode_name[1063:1076],
-	40237:   _ErrorCode_name[1076:1089],
-	40238:   _ErrorCode_name[1089:1102],
-	40272:   _ErrorCode_name[1102:1115],
-	40323:   _ErrorCode_name[1115:1128],
-	40352:   _ErrorCode_name[1128:1141],
-	40353:   _ErrorCode_name[1141:1154],
-	40414:   _ErrorCode_name[1154:1167],
-	40415:   _ErrorCode_name[1167:1180],
-	40602:   _ErrorCode_name[1180:1193],
-	50687:   _ErrorCode_name[1193:1206],
-	50840:   _ErrorCode_name[1206:1219],
-	51003:   _ErrorCode_name[1219:1232],
-	51024:   _ErrorCode_name[1232:1245],
-	51075:   _ErrorCode_name[1245:1258],
-	51091:   _ErrorCode_name[1258:1271],
-	51108:   _ErrorCode_name[1271:1284],
-	51246:   _ErrorCode_name[1284:1297],
-	51247:   _ErrorCode_name[1297:1310],
-	51270:   _ErrorCode_name[1310:1323],
-	51272:   _ErrorCode_name[1323:1336],
-	4822819: _ErrorCode_name[1336:1351],
-	5107200: _ErrorCode_name[1351:1366],
-	5107201: _ErrorCode_name[1366:1381],
-	5447000: _ErrorCode_name[1381:1396],
-	7582300: _ErrorCode_name[1396:1411],
+	139:     _ErrorCode_name[446:466],
+	168:     _ErrorCode_name[466:489],
+	186:     _ErrorCode_name[489:518],
+	197:     _ErrorCode_name[518:549],
+	224:     _ErrorCode_name[549:571],
+	238:     _ErrorCode_name[571:585],
+	241:     _ErrorCode_name[585:602],
+	10065:   _ErrorCode_name[602:615],
+	11000:   _ErrorCode_name[615:628],
+	15947:   _ErrorCode_name[628:641],
+	15948:   _ErrorCode_name[641:654],
+	15955:   _ErrorCode_name[654:667],
+	15958:   _ErrorCode_name[667:680],
+	15959:   _ErrorCode_name[680:693],
+	15969:   _ErrorCode_name[693:706],
+	15973:   _ErrorCode_name[706:719],
+	15974:   _ErrorCode_name[719:732],
+	15975:   _ErrorCode_name[732:745],
+	15976:   _ErrorCode_name[745:758],
+	15981:   _ErrorCode_name[758:771],
+	15983:   _ErrorCode_name[771:784],
+	15998:   _ErrorCode_name[784:797],
+	16006:   _ErrorCode_name[797:810],
+	16007:   _ErrorCode_name[810:823],
+	16020:   _ErrorCode_name[823:836],
+	16034:   _ErrorCode_name[836:849],
+	16035:   _ErrorCode_name[849:862],
+	16406:   _ErrorCode_name[862:875],
+	16410:   _ErrorCode_name[875:888],
+	16555:   _ErrorCode_name[888:901],
+	16556:   _ErrorCode_name[901:914],
+	16608:   _ErrorCode_name[914:927],
+	16609:   _ErrorCode_name[927:940],
+	16610:   _ErrorCode_name[940:953],
+	16611:   _ErrorCode_name[953:966],
+	16612:   _ErrorCode_name[966:979],
+	16702:   _ErrorCode_name[979:992],
+	16872:   _ErrorCode_name[992:1005],
+	16874:   _ErrorCode_name[1005:1018],
+	16875:   _ErrorCode_name[1018:1031],
+	16876:   _ErrorCode_name[1031:1044],
+	16877:   _ErrorCode_name[1044:1057],
+	16878:   _ErrorCode_name[1057:1070],
+	16879:   _ErrorCode_name[1070:1083],
+	16880:   _ErrorCode_name[1083:1096],
+	16882:   _ErrorCode_name[1096:1109],
+	16883:   _ErrorCode_name[1109:1122],
+	17042:   _ErrorCode_name[1122:1135],
+	17043:   _ErrorCode_name[1135:1148],
+	17044:   _ErrorCode_name[1148:1161],
+	17045:   _ErrorCode_name[1161:1174],
+	17046:   _ErrorCode_name[1174:1187],
+	17047:   _ErrorCode_name[1187:1200],
+	17048:   _ErrorCode_name[1200:1213],
+	17049:   _ErrorCode_name[1213:1226],
+	17080:   _ErrorCode_name[1226:1239],
+	17081:   _ErrorCode_name[1239:1252],
+	17082:   _ErrorCode_name[1252:1265],
+	17083:   _ErrorCode_name[1265:1278],
+	17124:   _ErrorCode_name[1278:1291],
+	17276:   _ErrorCode_name[1291:1304],
+	18533:   _ErrorCode_name[1304:1317],
+	18534:   _ErrorCode_name[1317:1330],
+	18535:   _ErrorCode_name[1330:1343],
+	18536:   _ErrorCode_name[1343:1356],
+	18628:   _ErrorCode_name[1356:1369],
+	18629:   _ErrorCode_name[1369:1382],
+	28646:   _ErrorCode_name[1382:1395],
+	28647:   _ErrorCode_name[1395:1408],
+	28648:   _ErrorCode_name[1408:1421],
+	28650:   _ErrorCode_name[1421:1434],
+	28651:   _ErrorCode_name[1434:1447],
+	28656:   _ErrorCode_name[1447:1460],
+	28657:   _ErrorCode_name[1460:1473],
+	28664:   _ErrorCode_name[1473:1486],
+	28667:   _ErrorCode_name[1486:1499],
+	28680:   _ErrorCode_name[1499:1512],
+	28689:   _ErrorCode_name[1512:1525],
+	28690:   _ErrorCode_name[1525:1538],
+	28691:   _ErrorCode_name[1538:1551],
+	28714:   _ErrorCode_name[1551:1564],
+	28724:   _ErrorCode_name[1564:1577],
+	28725:   _ErrorCode_name[1577:1590],
+	28726:   _ErrorCode_name[1590:1603],
+	28727:   _ErrorCode_name[1603:1616],
+	28728:   _ErrorCode_name[1616:1629],
+	28729:   _ErrorCode_name[1629:1642],
+	28745:   _ErrorCode_name[1642:1655],
+	28746:   _ErrorCode_name[1655:1668],
+	28747:   _ErrorCode_name[1668:1681],
+	28748:   _ErrorCode_name[1681:1694],
+	28749:   _ErrorCode_name[1694:1707],
+	28756:   _ErrorCode_name[1707:1720],
+	28757:   _ErrorCode_name[1720:1733],
+	28758:   _ErrorCode_name[1733:1746],
+	28759:   _ErrorCode_name[1746:1759],
+	28761:   _ErrorCode_name[1759:1772],
+	28762:   _ErrorCode_name[1772:1785],
+	28763:   _ErrorCode_name[1785:1798],
+	28764:   _ErrorCode_name[1798:1811],
+	28765:   _ErrorCode_name[1811:1824],
+	28766:   _ErrorCode_name[1824:1837],
+	28808:   _ErrorCode_name[1837:1850],
+	28809:   _ErrorCode_name[1850:1863],
+	28810:   _ErrorCode_name[1863:1876],
+	28811:   _ErrorCode_name[1876:1889],
+	28812:   _ErrorCode_name[1889:1902],
+	28818:   _ErrorCode_name[1902:1915],
+	28822:   _ErrorCode_name[1915:1928],
+	31002:   _ErrorCode_name[1928:1941],
+	31022:   _ErrorCode_name[1941:1954],
+	31023:   _ErrorCode_name[1954:1967],
+	31024:   _ErrorCode_name[1967:1980],
+	31034:   _ErrorCode_name[1980:1993],
+	31119:   _ErrorCode_name[1993:2006],
+	31120:   _ErrorCode_name[2006:2019],
+	31249:   _ErrorCode_name[2019:2032],
+	31250:   _ErrorCode_name[2032:2045],
+	31253:   _ErrorCode_name[2045:2058],
+	31254:   _ErrorCode_name[2058:2071],
+	31260:   _ErrorCode_name[2071:2084],
+	31261:   _ErrorCode_name[2084:2097],
+	31262:   _ErrorCode_name[2097:2110],
+	31263:   _ErrorCode_name[2110:2123],
+	31264:   _ErrorCode_name[2123:2136],
+	31324:   _ErrorCode_name[2136:2149],
+	31325:   _ErrorCode_name[2149:2162],
+	31394:   _ErrorCode_name[2162:2175],
+	31395:   _ErrorCode_name[2175:2188],
+	31418:   _ErrorCode_name[2188:2201],
+	31419:   _ErrorCode_name[2201:2214],
+	34435:   _ErrorCode_name[2214:2227],
+	34443:   _ErrorCode_name[2227:2240],
+	34444:   _ErrorCode_name[2240:2253],
+	34445:   _ErrorCode_name[2253:2266],
+	34446:   _ErrorCode_name[2266:2279],
+	34447:   _ErrorCode_name[2279:2292],
+	34448:   _ErrorCode_name[2292:2305],
+	34449:   _ErrorCode_name[2305:2318],
+	34450:   _ErrorCode_name[2318:2331],
+	34451:   _ErrorCode_name[2331:2344],
+	34452:   _ErrorCode_name[2344:2357],
+	34453:   _ErrorCode_name[2357:2370],
+	34454:   _ErrorCode_name[2370:2383],
+	34455:   _ErrorCode_name[2383:2396],
+	34460:   _ErrorCode_name[2396:2409],
+	34461:   _ErrorCode_name[2409:2422],
+	34462:   _ErrorCode_name[2422:2435],
+	34463:   _ErrorCode_name[2435:2448],
+	34464:   _ErrorCode_name[2448:2461],
+	34465:   _ErrorCode_name[2461:2474],
+	34466:   _ErrorCode_name[2474:2487],
+	34467:   _ErrorCode_name[2487:2500],
+	34468:   _ErrorCode_name[2500:2513],
+	34471:   _ErrorCode_name[2513:2526],
+	34473:   _ErrorCode_name[2526:2539],
+	40060:   _ErrorCode_name[2539:2552],
+	40061:   _ErrorCode_name[2552:2565],
+	40062:   _ErrorCode_name[2565:2578],
+	40063:   _ErrorCode_name[2578:2591],
+	40064:   _ErrorCode_name[2591:2604],
+	40065:   _ErrorCode_name[2604:2617],
+	40066:   _ErrorCode_name[2617:2630],
+	40067:   _ErrorCode_name[2630:2643],
+	40068:   _ErrorCode_name[2643:2656],
+	40075:   _ErrorCode_name[2656:2669],
+	40076:   _ErrorCode_name[2669:2682],
+	40077:   _ErrorCode_name[2682:2695],
+	40078:   _ErrorCode_name[2695:2708],
+	40079:   _ErrorCode_name[2708:2721],
+	40080:   _ErrorCode_name[2721:2734],
+	40081:   _ErrorCode_name[2734:2747],
+	40085:   _ErrorCode_name[2747:2760],
+	40086:   _ErrorCode_name[2760:2773],
+	40087:   _ErrorCode_name[2773:2786],
+	40090:   _ErrorCode_name[2786:2799],
+	40093:   _ErrorCode_name[2799:2812],
+	40094:   _ErrorCode_name[2812:2825],
+	40096:   _ErrorCode_name[2825:2838],
+	40097:   _ErrorCode_name[2838:2851],
+	40156:   _ErrorCode_name[2851:2864],
+	40157:   _ErrorCode_name[2864:2877],
+	40158:   _ErrorCode_name[2877:2890],
+	40160:   _ErrorCode_name[2890:2903],
+	40181:   _ErrorCode_name[2903:2916],
+	40218:   _ErrorCode_name[2916:2929],
+	40234:   _ErrorCode_name[2929:2942],
+	40237:   _ErrorCode_name[2942:2955],
+	40238:   _ErrorCode_name[2955:2968],
+	40272:   _ErrorCode_name[2968:2981],
+	40323:   _ErrorCode_name[2981:2994],
+	40352:   _ErrorCode_name[2994:3007],
+	40353:   _ErrorCode_name[3007:3020],
+	40386:   _ErrorCode_name[3020:3033],
+	40390:   _ErrorCode_name[3033:3046],
+	40392:   _ErrorCode_name[3046:3059],
+	40393:   _ErrorCode_name[3059:3072],
+	40394:   _ErrorCode_name[3072:3085],
+	40395:   _ErrorCode_name[3085:3098],
+	40396:   _ErrorCode_name[3098:3111],
+	40397:   _ErrorCode_name[3111:3124],
+	40398:   _ErrorCode_name[3124:3137],
+	40400:   _ErrorCode_name[3137:3150],
+	40414:   _ErrorCode_name[3150:3163],
+	40415:   _ErrorCode_name[3163:3176],
+	40485:   _ErrorCode_name[3176:3189],
+	40489:   _ErrorCode_name[3189:3202],
+	40515:   _ErrorCode_name[3202:3215],
+	40516:   _ErrorCode_name[3215:3228],
+	40517:   _ErrorCode_name[3228:3241],
+	40518:   _ErrorCode_name[3241:3254],
+	40519:   _ErrorCode_name[3254:3267],
+	40520:   _ErrorCode_name[3267:3280],
+	40521:   _ErrorCode_name[3280:3293],
+	40522:   _ErrorCode_name[3293:3306],
+	40523:   _ErrorCode_name[3306:3319],
+	40524:   _ErrorCode_name[3319:3332],
+	40535:   _ErrorCode_name[3332:3345],
+	40536:   _ErrorCode_name[3345:3358],
+	40539:   _ErrorCode_name[3358:3371],
+	40540:   _ErrorCode_name[3371:3384],
+	40541:   _ErrorCode_name[3384:3397],
+	40542:   _ErrorCode_name[3397:3410],
+	40602:   _ErrorCode_name[3410:3423],
+	40603:   _ErrorCode_name[3423:3436],
+	40684:   _ErrorCode_name[3436:3449],
+	50687:   _ErrorCode_name[3449:3462],
+	50694:   _ErrorCode_name[3462:3475],
+	50695:   _ErrorCode_name[3475:3488],
+	50696:   _ErrorCode_name[3488:3501],
+	50699:   _ErrorCode_name[3501:3514],
+	50700:   _ErrorCode_name[3514:3527],
+	50840:   _ErrorCode_name[3527:3540],
+	51003:   _ErrorCode_name[3540:3553],
+	51024:   _ErrorCode_name[3553:3566],
+	51075:   _ErrorCode_name[3566:3579],
+	51081:   _ErrorCode_name[3579:3592],
+	51082:   _ErrorCode_name[3592:3605],
+	51083:   _ErrorCode_name[3605:3618],
+	51091:   _ErrorCode_name[3618:3631],
+	51103:   _ErrorCode_name[3631:3644],
+	51104:   _ErrorCode_name[3644:3657],
+	51105:   _ErrorCode_name[3657:3670],
+	51106:   _ErrorCode_name[3670:3683],
+	51107:   _ErrorCode_name[3683:3696],
+	51108:   _ErrorCode_name[3696:3709],
+	51111:   _ErrorCode_name[3709:3722],
+	51246:   _ErrorCode_name[3722:3735],
+	51247:   _ErrorCode_name[3735:3748],
+	51270:   _ErrorCode_name[3748:3761],
+	51272:   _ErrorCode_name[3761:3774],
+	51746:   _ErrorCode_name[3774:3787],
+	51749:   _ErrorCode_name[3787:3800],
+	51750:   _ErrorCode_name[3800:3813],
+	51751:   _ErrorCode_name[3813:3826],
+	327391:  _ErrorCode_name[3826:3840],
+	327392:  _ErrorCode_name[3840:3854],
+	1257300: _ErrorCode_name[3854:3869],
+	2942500: _ErrorCode_name[3869:3884],
+	2942501: _ErrorCode_name[3884:3899],
+	2942502: _ErrorCode_name[3899:3914],
+	2942503: _ErrorCode_name[3914:3929],
+	2942504: _ErrorCode_name[3929:3944],
+	2942505: _ErrorCode_name[3944:3959],
+	3040501: _ErrorCode_name[3959:3974],
+	3041702: _ErrorCode_name[3974:3989],
+	3041703: _ErrorCode_name[3989:4004],
+	3041704: _ErrorCode_name[4004:4019],
+	3041705: _ErrorCode_name[4019:4034],
+	4161100: _ErrorCode_name[4034:4049],
+	4161101: _ErrorCode_name[4049:4064],
+	4161102: _ErrorCode_name[4064:4079],
+	4161103: _ErrorCode_name[4079:4094],
+	4161104: _ErrorCode_name[4094:4109],
+	4161105: _ErrorCode_name[4109:4124],
+	4161106: _ErrorCode_name[4124:4139],
+	4161107: _ErrorCode_name[4139:4154],
+	4822819: _ErrorCode_name[4154:4169],
+	5107200: _ErrorCode_name[4169:4184],
+	5107201: _ErrorCode_name[4184:4199],
+	5166300: _ErrorCode_name[4199:4214],
+	5166301: _ErrorCode_name[4214:4229],
+	5166302: _ErrorCode_name[4229:4244],
+	5166307: _ErrorCode_name[4244:4259],
+	5166400: _ErrorCode_name[4259:4274],
+	5166401: _ErrorCode_name[4274:4289],
+	5166402: _ErrorCode_name[4289:4304],
+	5166403: _ErrorCode_name[4304:4319],
+	5166404: _ErrorCode_name[4319:4334],
+	5166406: _ErrorCode_name[4334:4349],
+	5439007: _ErrorCode_name[4349:4364],
+	5439008: _ErrorCode_name[4364:4379],
+	5439009: _ErrorCode_name[4379:4394],
+	5439012: _ErrorCode_name[4394:4409],
+	5439013: _ErrorCode_name[4409:4424],
+	5439014: _ErrorCode_name[4424:4439],
+	5439015: _ErrorCode_name[4439:4454],
+	5439016: _ErrorCode_name[4454:4469],
+	5439017: _ErrorCode_name[4469:4484],
+	5439018: _ErrorCode_name[4484:4499],
+	5447000: _ErrorCode_name[4499:4514],
+	5654601: _ErrorCode_name[4514:4529],
+	5787900: _ErrorCode_name[4529:4544],
+	5787901: _ErrorCode_name[4544:4559],
+	5787902: _ErrorCode_name[4559:4574],
+	5787903: _ErrorCode_name[4574:4589],
+	5787906: _ErrorCode_name[4589:4604],
+	5787907: _ErrorCode_name[4604:4619],
+	5787908: _ErrorCode_name[4619:4634],
+	5788001: _ErrorCode_name[4634:4649],
+	5788002: _ErrorCode_name[4649:4664],
+	5788003: _ErrorCode_name[4664:4679],
+	5788004: _ErrorCode_name[4679:4694],
+	5788005: _ErrorCode_name[4694:4709],
+	7582300: _ErrorCode_name[4709:4724],
+	7750301: _ErrorCode_name[4724:4739],
 }
 
 func (i ErrorCode) String() string {
@@ -29,7 +29,7 @@ import (
 // TypeCode represents BSON type codes.
 // BSON type codes represent corresponding codes in BSON specification.
 // They could be used to query fields with particular type values using $type operator.
-// Type code `number` is added to support MongoDB surrogate alias `number` which matches double, int and long type values.
+// Type code `number` is added to support MongoDB surrogate alias `number` which matches double, int, long and decimal type values.
 type TypeCode int32
 
 const (
@@ -59,9 +59,6 @@ const (
 	TypeCodeTimestamp = TypeCode(17) // timestamp
 	// TypeCodeLong is a long type code.
 	TypeCodeLong = TypeCode(18) // long
-
-	// Not implemented.
-
 	// TypeCodeDecimal is a decimal type code.
 	TypeCodeDecimal = TypeCode(19) // decimal
 	// TypeCodeMinKey is a minKey type code.
@@ -69,7 +66,20 @@ const (
 	// TypeCodeMaxKey is a maxKey type code.
 	TypeCodeMaxKey = TypeCode(127) // maxKey
 
-	// Not actual type code. `number` matches double, int and long.
+	// Deprecated types.
+
+	// TypeCodeUndefined is an undefined type code.
+	TypeCodeUndefined = TypeCode(6) // undefined
+	// TypeCodeDBPointer is a dbPointer type code.
+	TypeCodeDBPointer = TypeCode(12) // dbPointer
+	// TypeCodeJavaScript is a javascript type code.
+	TypeCodeJavaScript = TypeCode(13) // javascript
+	// TypeCodeSymbol is a symbol type code.
+	TypeCodeSymbol = TypeCode(14) // symbol
+	// TypeCodeJavaScriptWithScope is a javascriptWithScope type code.
+	TypeCodeJavaScriptWithScope = TypeCode(15) // javascriptWithScope
+
+	// Not actual type code. `number` matches double, int, long and decimal.
 
 	// TypeCodeNumber is a number type code.
 	TypeCodeNumber = TypeCode(-128) // number
@@ -81,14 +91,10 @@ func NewTypeCode(code int32) (TypeCode, error) {
 	switch c {
 	case TypeCodeDouble, TypeCodeString, TypeCodeObject, TypeCodeArray,
 		TypeCodeBinData, TypeCodeObjectID, TypeCodeBool, TypeCodeDate,
-		TypeCodeNull, TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeNumber:
+		TypeCodeNull, TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeDecimal, TypeCodeNumber,
+		TypeCodeMinKey, TypeCodeMaxKey, TypeCodeUndefined, TypeCodeDBPointer, TypeCodeJavaScript, TypeCodeSymbol,
+		TypeCodeJavaScriptWithScope:
 		return c, nil
-	case TypeCodeDecimal, TypeCodeMinKey, TypeCodeMaxKey:
-		return 0, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrNotImplemented,
-			fmt.Sprintf(`Type code %v not implemented`, code),
-			"$type",
-		)
 	default:
 		return 0, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrBadValue,
@@ -134,7 +140,9 @@ func init() {
 	for _, i := range []TypeCode{
 		TypeCodeDouble, TypeCodeString, TypeCodeObject, TypeCodeArray,
 		TypeCodeBinData, TypeCodeObjectID, TypeCodeBool, TypeCodeDate, TypeCodeNull,
-		TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeNumber,
+		TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeDecimal, TypeCodeNumber,
+		TypeCodeMinKey, TypeCodeMaxKey, TypeCodeUndefined, TypeCodeDBPointer, TypeCodeJavaScript, TypeCodeSymbol,
+		TypeCodeJavaScriptWithScope,
 	} {
 		aliasToTypeCode[i.String()] = i
 	}
@@ -169,6 +177,22 @@ func AliasFromType(v any) string {
 		return TypeCodeTimestamp.String()
 	case int64:
 		return TypeCodeLong.String()
+	case types.Decimal128:
+		return TypeCodeDecimal.String()
+	case types.MinKeyType:
+		return TypeCodeMinKey.String()
+	case types.MaxKeyType:
+		return TypeCodeMaxKey.String()
+	case types.UndefinedType:
+		return TypeCodeUndefined.String()
+	case types.DBPointer:
+		return TypeCodeDBPointer.String()
+	case types.JavaScript:
+		return TypeCodeJavaScript.String()
+	case types.Symbol:
+		return TypeCodeSymbol.String()
+	case types.JavaScriptScope:
+		return TypeCodeJavaScriptWithScope.String()
 	default:
 		panic(fmt.Sprintf("not supported type %T", v))
 	}
@@ -183,6 +207,9 @@ func isWholeNumber(v any) bool {
 		return true
 	case int64:
 		return true
+	case types.Decimal128:
+		r, ok := v.Rat()
+		return ok && r.IsInt()
 	default:
 		return false
 	}
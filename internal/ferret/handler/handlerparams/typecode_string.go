@@ -24,22 +24,23 @@ func _() {
 	_ = x[TypeCodeDecimal-19]
 	_ = x[TypeCodeMinKey - -1]
 	_ = x[TypeCodeMaxKey-127]
+	_ = x[TypeCodeUndefined-6]
+	_ = x[TypeCodeDBPointer-12]
+	_ = x[TypeCodeJavaScript-13]
+	_ = x[TypeCodeSymbol-14]
+	_ = x[TypeCodeJavaScriptWithScope-15]
 	_ = x[TypeCodeNumber - -128]
 }
 
 const (
 	_TypeCode_name_0 = "number"
 	_TypeCode_name_1 = "minKey"
-	_TypeCode_name_2 = "doublestringobjectarraybinData"
-	_TypeCode_name_3 = "objectIdbooldatenullregex"
-	_TypeCode_name_4 = "inttimestamplongdecimal"
-	_TypeCode_name_5 = "maxKey"
+	_TypeCode_name_2 = "doublestringobjectarraybinDataundefinedobjectIdbooldatenullregexdbPointerjavascriptsymboljavascriptWithScopeinttimestamplongdecimal"
+	_TypeCode_name_3 = "maxKey"
 )
 
 var (
-	_TypeCode_index_2 = [...]uint8{0, 6, 12, 18, 23, 30}
-	_TypeCode_index_3 = [...]uint8{0, 8, 12, 16, 20, 25}
-	_TypeCode_index_4 = [...]uint8{0, 3, 12, 16, 23}
+	_TypeCode_index_2 = [...]uint8{0, 6, 12, 18, 23, 30, 39, 47, 51, 55, 59, 64, 73, 83, 89, 108, 111, 120, 124, 131}
 )
 
 func (i TypeCode) String() string {
@@ -48,17 +49,11 @@ func (i TypeCode) String() string {
 		return _TypeCode_name_0
 	case i == -1:
 		return _TypeCode_name_1
-	case 1 <= i && i <= 5:
+	case 1 <= i && i <= 19:
 		i -= 1
 		return _TypeCode_name_2[_TypeCode_index_2[i]:_TypeCode_index_2[i+1]]
-	case 7 <= i && i <= 11:
-		i -= 7
-		return _TypeCode_name_3[_TypeCode_index_3[i]:_TypeCode_index_3[i+1]]
-	case 16 <= i && i <= 19:
-		i -= 16
-		return _TypeCode_name_4[_TypeCode_index_4[i]:_TypeCode_index_4[i+1]]
 	case i == 127:
-		return _TypeCode_name_5
+		return _TypeCode_name_3
 	default:
 		return "TypeCode(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -0,0 +1,195 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package javascript
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+// maxDepth limits the nesting of values returned by evaluated code; cyclic values exceed it.
+const maxDepth = 100
+
+// arrayBufferType is the export type of ArrayBuffer objects.
+var arrayBufferType = reflect.TypeOf(goja.ArrayBuffer{})
+
+// toValue converts a types package value to a JavaScript value of the runtime.
+//
+// Numbers (including Decimal128 and Timestamp) become JavaScript numbers, ObjectIDs become hex strings,
+// Binary values become ArrayBuffers, time.Time values become Dates and Regex values become RegExps.
+func toValue(vm *goja.Runtime, v any) (goja.Value, error) {
+	switch v := v.(type) {
+	case *types.Document:
+		obj := vm.NewObject()
+
+		keys, values := v.Keys(), v.Values()
+		for i, k := range keys {
+			value, err := toValue(vm, values[i])
+			if err != nil {
+				return nil, err
+			}
+
+			if err = obj.Set(k, value); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		return obj, nil
+
+	case *types.Array:
+		items := make([]any, v.Len())
+
+		for i := range items {
+			value, err := toValue(vm, must.NotFail(v.Get(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = value
+		}
+
+		return vm.NewArray(items...), nil
+
+	case float64, int32, int64, string, bool:
+		return vm.ToValue(v), nil
+
+	case types.Decimal128:
+		return vm.ToValue(v.Float64()), nil
+
+	case types.Timestamp:
+		return vm.ToValue(float64(v)), nil
+
+	case types.NullType:
+		return goja.Null(), nil
+
+	case types.ObjectID:
+		return vm.ToValue(hex.EncodeToString(v[:])), nil
+
+	case types.Binary:
+		return vm.ToValue(vm.NewArrayBuffer(append([]byte(nil), v.B...))), nil
+
+	case time.Time:
+		return vm.New(vm.Get("Date"), vm.ToValue(v.UnixMilli()))
+
+	case types.Regex:
+		var flags strings.Builder
+
+		for _, o := range v.Options {
+			if strings.ContainsRune("ims", o) {
+				flags.WriteRune(o)
+			}
+		}
+
+		return vm.New(vm.Get("RegExp"), vm.ToValue(v.Pattern), vm.ToValue(flags.String()))
+
+	default:
+		return nil, lazyerrors.Errorf("unexpected type %T", v)
+	}
+}
+
+// fromValue converts a JavaScript value to a types package value.
+//
+// All numbers become float64, like in MongoDB; undefined becomes null.
+// Functions and symbols can't be converted.
+func fromValue(v goja.Value, depth int) (any, error) {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return types.Null, nil
+	}
+
+	obj, ok := v.(*goja.Object)
+	if !ok {
+		switch e := v.Export().(type) {
+		case int64:
+			return float64(e), nil
+		case float64, string, bool:
+			return e, nil
+		default:
+			return nil, fmt.Errorf("can't convert %s to BSON", v.String())
+		}
+	}
+
+	if depth >= maxDepth {
+		return nil, errors.New("value is nested too deeply")
+	}
+
+	switch obj.ClassName() {
+	case "Array":
+		n := obj.Get("length").ToInteger()
+		arr := types.MakeArray(int(n))
+
+		for i := int64(0); i < n; i++ {
+			item, err := fromValue(obj.Get(strconv.FormatInt(i, 10)), depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			arr.Append(item)
+		}
+
+		return arr, nil
+
+	case "Date":
+		t, ok := obj.Export().(time.Time)
+		if !ok {
+			return nil, errors.New("can't convert Date to BSON")
+		}
+
+		// invalid dates are exported as zero time
+		if ms := obj.ToNumber().ToFloat(); math.IsNaN(ms) {
+			return nil, errors.New("can't convert Invalid Date to BSON")
+		}
+
+		return t.UTC(), nil
+
+	case "RegExp":
+		return types.Regex{
+			Pattern: obj.Get("source").String(),
+			Options: obj.Get("flags").String(),
+		}, nil
+
+	case "Function":
+		return nil, errors.New("can't convert a function to BSON")
+	}
+
+	if obj.ExportType() == arrayBufferType {
+		b := obj.Export().(goja.ArrayBuffer)
+		return types.Binary{B: append([]byte(nil), b.Bytes()...)}, nil
+	}
+
+	doc := types.MakeDocument(0)
+
+	for _, k := range obj.Keys() {
+		value, err := fromValue(obj.Get(k), depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Set(k, value)
+	}
+
+	return doc, nil
+}
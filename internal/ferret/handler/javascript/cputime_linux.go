@@ -0,0 +1,59 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build linux
+
+package javascript
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// threadCPUClock returns a function that reports the CPU time spent by the current OS thread
+// since threadCPUClock was called. The returned function may be called from any goroutine.
+//
+// The caller must keep the goroutine locked to its thread with runtime.LockOSThread
+// while the returned function is used.
+func threadCPUClock() func() time.Duration {
+	// the same clock as pthread_getcpuclockid returns: MAKE_THREAD_CPUCLOCK(tid, CPUCLOCK_SCHED)
+	clockID := int32((^unix.Gettid())<<3 | 6)
+
+	start, err := threadCPUTime(clockID)
+	if err != nil {
+		return wallClock()
+	}
+
+	startWall := time.Now()
+
+	return func() time.Duration {
+		now, err := threadCPUTime(clockID)
+		if err != nil {
+			// should not happen while the thread is locked, but the budget must still be enforced
+			return time.Since(startWall)
+		}
+
+		return now - start
+	}
+}
+
+// threadCPUTime returns the value of the thread CPU-time clock.
+func threadCPUTime(clockID int32) (time.Duration, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(clockID, &ts); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ts.Nano()), nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package javascript
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreadCPUClock(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	elapsed := threadCPUClock()
+
+	// sleeping does not spend CPU time
+	time.Sleep(100 * time.Millisecond)
+	assert.Less(t, elapsed(), 50*time.Millisecond)
+
+	for start := time.Now(); time.Since(start) < 20*time.Millisecond; {
+	}
+
+	assert.Greater(t, elapsed(), 10*time.Millisecond)
+}
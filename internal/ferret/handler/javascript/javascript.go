@@ -0,0 +1,291 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package javascript evaluates JavaScript of $where and $function in a sandboxed interpreter.
+//
+// Each evaluation runs in a new runtime that has only the standard ECMAScript built-ins:
+// there is no access to the host (files, network, environment, Go values),
+// and no state is shared between evaluations.
+package javascript
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// DefaultTimeout is the default CPU time budget of a single evaluation.
+const DefaultTimeout = time.Second
+
+// maxCallStackSize limits the recursion depth of evaluated code.
+const maxCallStackSize = 1024
+
+// errTimeout is the value used to interrupt evaluations that exceed the time budget.
+var errTimeout = errors.New("time budget exceeded")
+
+// Evaluator evaluates JavaScript functions.
+//
+// Compiled code is cached, so an Evaluator should be used for a whole query or pipeline.
+// It is safe for concurrent use.
+type Evaluator struct {
+	timeout time.Duration
+
+	m        sync.Mutex
+	programs map[string]*goja.Program // by source
+}
+
+// NewEvaluator returns a new Evaluator.
+//
+// Every evaluation is interrupted after it spends the given timeout of CPU time
+// (wall-clock time on systems other than Linux); zero timeout is DefaultTimeout.
+func NewEvaluator(timeout time.Duration) *Evaluator {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Evaluator{
+		timeout:  timeout,
+		programs: make(map[string]*goja.Program),
+	}
+}
+
+// Where evaluates the code of $where for the document.
+//
+// The code is a function or a function body, like MongoDB it may omit return for a single expression.
+// The document is available as this and as the global obj.
+// It returns whether the result is truthy.
+func (e *Evaluator) Where(code string, doc *types.Document) (bool, error) {
+	var res bool
+
+	err := e.run("$where", whereSource(code), func(vm *goja.Runtime, fn goja.Callable) error {
+		this, err := toValue(vm, doc)
+		if err != nil {
+			return err
+		}
+
+		if err = vm.GlobalObject().Set("obj", this); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		v, err := fn(this)
+		if err != nil {
+			return err
+		}
+
+		res = v.ToBoolean()
+
+		return nil
+	})
+
+	return res, err
+}
+
+// Call evaluates the body of $function with the given arguments and returns the result.
+//
+// The body is a function definition like "function(a, b) { return a + b }".
+// Numbers are returned as float64, undefined as null.
+func (e *Evaluator) Call(body string, args []any) (any, error) {
+	var res any
+
+	err := e.run("$function", "("+body+"\n)", func(vm *goja.Runtime, fn goja.Callable) error {
+		values := make([]goja.Value, len(args))
+		for i, arg := range args {
+			v, err := toValue(vm, arg)
+			if err != nil {
+				return err
+			}
+
+			values[i] = v
+		}
+
+		v, err := fn(goja.Undefined(), values...)
+		if err != nil {
+			return err
+		}
+
+		res, err = fromValue(v, 0)
+
+		return err
+	})
+
+	return res, err
+}
+
+// run evaluates the source that defines a function in a new runtime and calls f with that function.
+func (e *Evaluator) run(operator, src string, f func(vm *goja.Runtime, fn goja.Callable) error) error {
+	p, err := e.compile(src)
+	if err != nil {
+		return newInterpreterError(operator, err)
+	}
+
+	vm := goja.New()
+	vm.SetMaxCallStackSize(maxCallStackSize)
+
+	// the budget is the CPU time of the evaluating thread, so the goroutine must stay on it
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	defer e.watch(vm)()
+
+	v, err := vm.RunProgram(p)
+	if err != nil {
+		return e.newError(operator, err)
+	}
+
+	fn, ok := goja.AssertFunction(v)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrJSInterpreterFailure,
+			fmt.Sprintf("%s code must evaluate to a function", operator),
+			operator,
+		)
+	}
+
+	if err = f(vm, fn); err != nil {
+		return e.newError(operator, err)
+	}
+
+	return nil
+}
+
+// watch interrupts the runtime running on the current thread
+// when that thread spends more CPU time than the timeout.
+// It returns a function that stops watching.
+func (e *Evaluator) watch(vm *goja.Runtime) func() {
+	elapsed := threadCPUClock()
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(min(max(e.timeout/10, time.Millisecond), 10*time.Millisecond))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if elapsed() >= e.timeout {
+					vm.Interrupt(errTimeout)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// wallClock returns a function that reports the wall-clock time since wallClock was called.
+func wallClock() func() time.Duration {
+	start := time.Now()
+
+	return func() time.Duration {
+		return time.Since(start)
+	}
+}
+
+// compile returns the compiled program for the source.
+func (e *Evaluator) compile(src string) (*goja.Program, error) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if p, ok := e.programs[src]; ok {
+		return p, nil
+	}
+
+	p, err := goja.Compile("", src, false)
+	if err != nil {
+		return nil, err
+	}
+
+	e.programs[src] = p
+
+	return p, nil
+}
+
+// newError converts an error of the evaluation to a command error.
+// Command errors (like conversion errors) are returned as is.
+func (e *Evaluator) newError(operator string, err error) error {
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) {
+		return err
+	}
+
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) && interrupted.Value() == errTimeout {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMaxTimeMSExpired,
+			fmt.Sprintf("%s exceeded the CPU time limit of %s", operator, e.timeout),
+			operator,
+		)
+	}
+
+	return newInterpreterError(operator, err)
+}
+
+// newInterpreterError returns JSInterpreterFailure error for the JavaScript error.
+func newInterpreterError(operator string, err error) error {
+	msg := err.Error()
+
+	var ex *goja.Exception
+	if errors.As(err, &ex) {
+		msg = ex.Value().String()
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrJSInterpreterFailure,
+		fmt.Sprintf("%s failed :: caused by :: %s", operator, msg),
+		operator,
+	)
+}
+
+// whereSource returns the source of the $where function for the code.
+//
+// Like MongoDB, code that is not a function is used as a function body,
+// and return is added to single-line code that does not start with it and has no semicolons
+// other than a trailing one.
+func whereSource(code string) string {
+	code = strings.TrimSpace(code)
+
+	if strings.HasPrefix(code, "function") {
+		return "(" + code + "\n)"
+	}
+
+	var prefix string
+	if !strings.Contains(code, "\n") && !strings.HasPrefix(code, "return") {
+		if i := strings.Index(code, ";"); i < 0 || i == len(code)-1 {
+			prefix = "return "
+		}
+	}
+
+	return "(function() { " + prefix + code + "\n})"
+}
+
+// NewDisabledError returns the error for $where or $function used without an Evaluator.
+func NewDisabledError(operator string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrQueryFeatureNotAllowed,
+		fmt.Sprintf("%s is not allowed because JavaScript execution is disabled", operator),
+		operator,
+	)
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package javascript
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+func TestWhereSource(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		code     string
+		expected string
+	}{
+		"Expression": {
+			code:     "this.a > 1",
+			expected: "(function() { return this.a > 1\n})",
+		},
+		"TrailingSemicolon": {
+			code:     "this.a > 1;",
+			expected: "(function() { return this.a > 1;\n})",
+		},
+		"Return": {
+			code:     "return this.a > 1",
+			expected: "(function() { return this.a > 1\n})",
+		},
+		"Statements": {
+			code:     "var a = this.a; return a > 1",
+			expected: "(function() { var a = this.a; return a > 1\n})",
+		},
+		"Function": {
+			code:     " function() { return true }",
+			expected: "(function() { return true }\n)",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, whereSource(tc.code))
+		})
+	}
+}
+
+func TestCall(t *testing.T) {
+	t.Parallel()
+
+	e := NewEvaluator(0)
+
+	date := time.Date(2024, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+	doc := must.NotFail(types.NewDocument(
+		"b", true,
+		"a", must.NotFail(types.NewArray(int32(1), int64(2), 3.5, "s", types.Null)),
+		"d", date,
+		"r", types.Regex{Pattern: "^a", Options: "ix"},
+		"bin", types.Binary{B: []byte{1, 2}},
+	))
+
+	res, err := e.Call("function(doc) { return doc }", []any{doc})
+	require.NoError(t, err)
+
+	expected := must.NotFail(types.NewDocument(
+		"b", true,
+		"a", must.NotFail(types.NewArray(float64(1), float64(2), 3.5, "s", types.Null)),
+		"d", date,
+		"r", types.Regex{Pattern: "^a", Options: "i"},
+		"bin", types.Binary{B: []byte{1, 2}},
+	))
+	assert.Equal(t, expected, res)
+
+	id := types.ObjectID{0x65, 0xa1, 0xb2, 0xc3, 0xd4, 0xe5, 0xf6, 0x07, 0x18, 0x29, 0x3a, 0x4b}
+	res, err = e.Call("function(id) { return id }", []any{id})
+	require.NoError(t, err)
+	assert.Equal(t, "65a1b2c3d4e5f60718293a4b", res)
+
+	_, err = e.Call("function() { var o = {}; o.o = o; return o }", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "value is nested too deeply")
+
+	_, err = e.Call("function f() { return f() }", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JSInterpreterFailure")
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	e := NewEvaluator(10 * time.Millisecond)
+
+	_, err := e.Call("function() { for (;;) {} }", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MaxTimeMSExpired (50): $function exceeded the CPU time limit of 10ms")
+
+	// the evaluator is usable after the timeout
+	res, err := e.Call("function(a, b) { return a + b }", []any{int32(1), int32(2)})
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), res)
+}
@@ -41,6 +41,11 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 		return nil, err
 	}
 
+	coll, err := common.GetCollation(params.Collation)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -62,7 +67,7 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 	}
 
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.DisablePushdown && coll == nil {
 		qp.Filter = params.Filter
 	}
 
@@ -76,7 +81,7 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 	closer := iterator.NewMultiCloser(iter)
 	defer closer.Close()
 
-	iter = common.FilterIterator(iter, closer, params.Filter)
+	iter = common.FilterIterator(iter, closer, params.Filter, nil, coll)
 
 	iter = common.SkipIterator(iter, closer, params.Skip)
 
@@ -18,11 +18,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/backends"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -63,11 +65,16 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	vars, err := operators.NewVariables(time.Now(), params.Let)
+	if err != nil {
+		return nil, err
+	}
+
 	var deleted int32
 	writeErrors := types.MakeArray(0)
 
 	for i, p := range params.Deletes {
-		d, err := h.execDelete(ctx, c, &p)
+		d, err := h.execDelete(ctx, c, &p, vars)
 
 		deleted += d
 
@@ -115,9 +122,16 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 //
 // It returns a number of deleted documents or error.
 // The error is either a (wrapped) *handlererrors.CommandError or something fatal.
-func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *common.Delete) (int32, error) {
+func (h *Handler) execDelete(
+	ctx context.Context, c backends.Collection, p *common.Delete, vars *operators.Variables,
+) (int32, error) {
+	coll, err := common.GetCollation(p.Collation)
+	if err != nil {
+		return 0, err
+	}
+
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.DisablePushdown && coll == nil {
 		qp.Filter = p.Filter
 	}
 
@@ -141,7 +155,7 @@ func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *comm
 
 		var matches bool
 
-		if matches, err = common.FilterDocument(doc, p.Filter); err != nil {
+		if matches, err = common.FilterDocumentWithCollation(doc, p.Filter, vars, coll); err != nil {
 			q.Iter.Close()
 			return 0, lazyerrors.Error(err)
 		}
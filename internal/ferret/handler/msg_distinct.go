@@ -40,6 +40,11 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		return nil, err
 	}
 
+	coll, err := common.GetCollation(params.Collation)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -64,7 +69,7 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 	defer closer.Close()
 
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.DisablePushdown && coll == nil {
 		qp.Filter = params.Filter
 	}
 
@@ -76,9 +81,9 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter, nil, coll)
 
-	distinct, err := common.FilterDistinctValues(iter, params.Key)
+	distinct, err := common.FilterDistinctValues(iter, params.Key, coll)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
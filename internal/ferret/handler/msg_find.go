@@ -26,6 +26,7 @@ import (
 	"github.com/zaporter/go-update-mongo/internal/ferret/clientconn/conninfo"
 	"github.com/zaporter/go-update-mongo/internal/ferret/clientconn/cursor"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -46,6 +47,10 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, err
 	}
 
+	if _, err = common.GetCollation(params.Collation); err != nil {
+		return nil, err
+	}
+
 	username := conninfo.Get(ctx).Username()
 
 	db, err := h.b.Database(params.DB)
@@ -217,7 +222,8 @@ func (h *Handler) makeFindQueryParams(params *common.FindParams, cInfo *backends
 		}
 	}
 
-	if !h.DisablePushdown {
+	// filter pushdown compares strings with the simple collation
+	if !h.DisablePushdown && params.Collation == nil {
 		qp.Filter = params.Filter
 	}
 
@@ -280,9 +286,21 @@ func (h *Handler) makeFindQueryParams(params *common.FindParams, cInfo *backends
 func (h *Handler) makeFindIter(iter types.DocumentsIterator, closer *iterator.MultiCloser, params *common.FindParams) (types.DocumentsIterator, error) {
 	closer.Add(iter)
 
-	iter = common.FilterIterator(iter, closer, params.Filter)
+	coll, err := common.GetCollation(params.Collation)
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+
+	vars, err := operators.NewVariables(time.Now(), params.Let)
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+
+	iter = common.FilterIterator(iter, closer, params.Filter, vars, coll)
 
-	iter, err := common.SortIterator(iter, closer, params.Sort)
+	iter, err = common.SortIterator(iter, closer, params.Sort, vars, coll)
 	if err != nil {
 		closer.Close()
 
@@ -302,7 +320,7 @@ func (h *Handler) makeFindIter(iter types.DocumentsIterator, closer *iterator.Mu
 
 	iter = common.LimitIterator(iter, closer, params.Limit)
 
-	if iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter); err != nil {
+	if iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter, vars); err != nil {
 		closer.Close()
 		return nil, lazyerrors.Error(err)
 	}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/backends"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -100,6 +101,16 @@ func (h *Handler) MsgFindAndModify(ctx context.Context, msg *wire.OpMsg) (*wire.
 // otherwise it updates the document applying operators if any.
 // When no document is found, a document is inserted if `upsert` flag is set.
 func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.FindAndModifyParams) (*findAndModifyResult, error) {
+	coll, err := common.GetCollation(params.Collation)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := operators.NewVariables(time.Now(), params.Let)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2168
@@ -133,7 +144,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 	defer closer.Close()
 
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.DisablePushdown && coll == nil {
 		qp.Filter = params.Query
 	}
 
@@ -144,9 +155,9 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Query)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Query, vars, coll)
 
-	iter, err = common.SortIterator(iter, closer, params.Sort)
+	iter, err = common.SortIterator(iter, closer, params.Sort, nil, coll)
 	if err != nil {
 		var pathErr *types.PathError
 		if errors.As(err, &pathErr) && pathErr.Code() == types.ErrPathElementEmpty {
@@ -184,7 +195,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		doc := params.Update
 		if params.HasUpdateOperators {
 			doc = must.NotFail(types.NewDocument())
-			if _, err = common.UpdateDocument("findAndModify", doc, params.Update, true); err != nil {
+			if _, err = common.UpdateDocumentWithPaths("findAndModify", doc, params.Update, true, nil, vars, coll); err != nil {
 				// TODO https://github.com/FerretDB/FerretDB/issues/2168
 				return nil, err
 			}
@@ -280,7 +291,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 	doc := params.Update
 	if params.HasUpdateOperators {
 		doc = v.DeepCopy()
-		if _, err = common.UpdateDocument("findAndModify", doc, params.Update, false); err != nil {
+		if _, err = common.UpdateDocumentWithPaths("findAndModify", doc, params.Update, false, nil, vars, coll); err != nil {
 			return nil, err
 		}
 	}
@@ -18,11 +18,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/zaporter/go-update-mongo/internal/ferret/backends"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
 	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
@@ -118,6 +120,11 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		return 0, 0, nil, lazyerrors.Error(err)
 	}
 
+	vars, err := operators.NewVariables(time.Now(), params.Let)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
 	for _, u := range params.Updates {
 		c, err := db.Collection(params.Collection)
 		if err != nil {
@@ -129,8 +136,13 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 			return 0, 0, nil, lazyerrors.Error(err)
 		}
 
+		coll, err := common.GetCollation(u.Collation)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+
 		var qp backends.QueryParams
-		if !h.DisablePushdown {
+		if !h.DisablePushdown && coll == nil {
 			qp.Filter = u.Filter
 		}
 
@@ -157,7 +169,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 
 			var matches bool
 
-			matches, err = common.FilterDocument(doc, u.Filter)
+			matches, err = common.FilterDocumentWithCollation(doc, u.Filter, vars, coll)
 			if err != nil {
 				return 0, 0, nil, lazyerrors.Error(err)
 			}
@@ -197,7 +209,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 
 			if hasUpdateOperators {
 				// TODO https://github.com/FerretDB/FerretDB/issues/3044
-				if _, err = common.UpdateDocument("update", doc, u.Update, true); err != nil {
+				if _, err = common.UpdateDocumentWithPaths("update", doc, u.Update, true, nil, vars, coll); err != nil {
 					return 0, 0, nil, err
 				}
 			} else {
@@ -238,7 +250,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		matched += int32(len(resDocs))
 
 		for _, doc := range resDocs {
-			changed, err := common.UpdateDocument("update", doc, u.Update, false)
+			changed, err := common.UpdateDocumentWithPaths("update", doc, u.Update, false, nil, vars, coll)
 			if err != nil {
 				return 0, 0, nil, lazyerrors.Error(err)
 			}
@@ -0,0 +1,212 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/snowballstem"
+	"github.com/blevesearch/snowballstem/danish"
+	"github.com/blevesearch/snowballstem/dutch"
+	"github.com/blevesearch/snowballstem/english"
+	"github.com/blevesearch/snowballstem/finnish"
+	"github.com/blevesearch/snowballstem/french"
+	"github.com/blevesearch/snowballstem/german"
+	"github.com/blevesearch/snowballstem/hungarian"
+	"github.com/blevesearch/snowballstem/italian"
+	"github.com/blevesearch/snowballstem/norwegian"
+	"github.com/blevesearch/snowballstem/portuguese"
+	"github.com/blevesearch/snowballstem/romanian"
+	"github.com/blevesearch/snowballstem/russian"
+	"github.com/blevesearch/snowballstem/spanish"
+	"github.com/blevesearch/snowballstem/swedish"
+	"github.com/blevesearch/snowballstem/turkish"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// language describes how words of a text search language are stemmed and which of them are ignored.
+type language struct {
+	stem      func(env *snowballstem.Env) bool // nil for no stemming
+	stopWords map[string]struct{}
+}
+
+// languages maps supported language names and ISO 639-1 codes to languages,
+// like MongoDB text indexes do.
+var languages = func() map[string]*language {
+	res := map[string]*language{
+		"none": {},
+	}
+
+	for _, l := range []struct {
+		name, code string
+		stem       func(env *snowballstem.Env) bool
+		stopWords  map[string]struct{}
+	}{
+		{"danish", "da", danish.Stem, nil},
+		{"dutch", "nl", dutch.Stem, nil},
+		{"english", "en", english.Stem, englishStopWords},
+		{"finnish", "fi", finnish.Stem, nil},
+		{"french", "fr", french.Stem, nil},
+		{"german", "de", german.Stem, nil},
+		{"hungarian", "hu", hungarian.Stem, nil},
+		{"italian", "it", italian.Stem, nil},
+		{"norwegian", "nb", norwegian.Stem, nil},
+		{"portuguese", "pt", portuguese.Stem, nil},
+		{"romanian", "ro", romanian.Stem, nil},
+		{"russian", "ru", russian.Stem, nil},
+		{"spanish", "es", spanish.Stem, nil},
+		{"swedish", "sv", swedish.Stem, nil},
+		{"turkish", "tr", turkish.Stem, nil},
+	} {
+		lang := &language{stem: l.stem, stopWords: l.stopWords}
+		res[l.name] = lang
+		res[l.code] = lang
+	}
+
+	return res
+}()
+
+// lookupLanguage returns the language with the given name or code.
+func lookupLanguage(name string) (*language, bool) {
+	l, ok := languages[strings.ToLower(name)]
+	return l, ok
+}
+
+// term returns the index term for the given word,
+// or false if the word is a stop word.
+//
+// Terms are stemmed lowercase words without diacritics.
+// Case- and diacritic-sensitive terms keep the case and diacritics of the word.
+func (l *language) term(word string, caseSensitive, diacriticSensitive bool) (string, bool) {
+	folded := removeDiacritics(word)
+	if _, ok := l.stopWords[strings.ToLower(folded)]; ok {
+		return "", false
+	}
+
+	if !diacriticSensitive {
+		word = folded
+	}
+
+	res := strings.ToLower(word)
+
+	if l.stem != nil {
+		env := snowballstem.NewEnv(res)
+		l.stem(env)
+		res = env.Current()
+	}
+
+	if caseSensitive {
+		res = restoreCase(res, word)
+	}
+
+	return res, true
+}
+
+// restoreCase uppercases letters of the stemmed word that are uppercase in the original word.
+func restoreCase(stemmed, original string) string {
+	orig := []rune(original)
+	res := []rune(stemmed)
+
+	for i := range res {
+		if i < len(orig) && unicode.IsUpper(orig[i]) {
+			res[i] = unicode.ToUpper(res[i])
+		}
+	}
+
+	return string(res)
+}
+
+// normalizeText prepares the text for phrase matching:
+// it is lowercased and its diacritics are removed unless matching is sensitive to them.
+func normalizeText(s string, caseSensitive, diacriticSensitive bool) string {
+	if !diacriticSensitive {
+		s = removeDiacritics(s)
+	}
+
+	if !caseSensitive {
+		s = strings.ToLower(s)
+	}
+
+	return s
+}
+
+// removeDiacritics returns the string without combining marks.
+func removeDiacritics(s string) string {
+	ascii := true
+
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		return s
+	}
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	res, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+
+	return res
+}
+
+// isWordRune returns true if the rune is a part of a word rather than a delimiter.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// words returns the words of the text.
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !isWordRune(r) })
+}
+
+// englishStopWords are the words that English text indexes ignore.
+var englishStopWords = func() map[string]struct{} {
+	list := strings.Fields(`
+		a about above after again against all am an and any are as at
+		be because been before being below between both but by
+		can cannot could
+		did do does doing down during
+		each
+		few for from further
+		had has have having he her here hers herself him himself his how
+		i if in into is it its itself
+		just
+		me more most my myself
+		no nor not now
+		of off on once only or other our ours ourselves out over own
+		same she should so some such
+		than that the their theirs them themselves then there these they this those through to too
+		under until up
+		very
+		was we were what when where which while who whom why will with would
+		you your yours yourself yourselves
+	`)
+
+	res := make(map[string]struct{}, len(list))
+	for _, w := range list {
+		res[w] = struct{}{}
+	}
+
+	return res
+}()
@@ -0,0 +1,227 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textsearch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// Query is a parsed $text query.
+type Query struct {
+	lang *language
+
+	terms          []string
+	negatedTerms   []string
+	phrases        []string
+	negatedPhrases []string
+
+	caseSensitive      bool
+	diacriticSensitive bool
+}
+
+// ParseQuery parses the value of $text operator:
+//
+//	{$text: {$search: <string>, $language: <string>, $caseSensitive: <bool>, $diacriticSensitive: <bool>}}
+//
+// Words of $search are ORed; words prefixed with `-` exclude documents,
+// and quoted phrases must appear in the document as is.
+// If $language is not set, the default language of the index is used.
+func (idx *Index) ParseQuery(v any) (*Query, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$text needs an Object",
+			"$text",
+		)
+	}
+
+	q := &Query{
+		lang: idx.defaultLanguage,
+	}
+
+	var search *string
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch key {
+		case "$search":
+			s, ok := value.(string)
+			if !ok {
+				return nil, newQueryParseError("$search needs a String")
+			}
+
+			search = &s
+
+		case "$language":
+			s, ok := value.(string)
+			if !ok {
+				return nil, newQueryParseError("$language needs a String")
+			}
+
+			if q.lang, ok = lookupLanguage(s); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("unsupported language: %q for $language", s),
+					"$text",
+				)
+			}
+
+		case "$caseSensitive":
+			if q.caseSensitive, ok = value.(bool); !ok {
+				return nil, newQueryParseError("$caseSensitive needs a boolean")
+			}
+
+		case "$diacriticSensitive":
+			if q.diacriticSensitive, ok = value.(bool); !ok {
+				return nil, newQueryParseError("$diacriticSensitive needs a boolean")
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"extra fields in $text",
+				"$text",
+			)
+		}
+	}
+
+	if search == nil {
+		return nil, newQueryParseError("$search required")
+	}
+
+	q.parseSearch(*search)
+
+	return q, nil
+}
+
+// newQueryParseError returns FailedToParse error for the invalid $text query.
+func newQueryParseError(msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrFailedToParse, msg, "$text")
+}
+
+// parseSearch splits the $search string into terms and phrases.
+//
+// Like in MongoDB, `-` negates the following word or phrase only at the start of it,
+// so `mother-in-law` is three ordinary words.
+// Words of phrases are also added to terms, but words of negated phrases are not negated terms.
+func (q *Query) parseSearch(s string) {
+	var inPhrase, negatedPhrase, negateNext bool
+	var phraseStart int
+
+	prevWord := false
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == '"':
+			if inPhrase {
+				q.addPhrase(s[phraseStart:i], negatedPhrase)
+				inPhrase = false
+			} else {
+				inPhrase = true
+				negatedPhrase = negateNext
+				phraseStart = i + size
+			}
+
+			negateNext = false
+			prevWord = false
+			i += size
+
+		case r == '-' && !inPhrase && !prevWord:
+			negateNext = true
+			i += size
+
+		case isWordRune(r):
+			j := i + size
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !isWordRune(r) {
+					break
+				}
+
+				j += size
+			}
+
+			switch {
+			case inPhrase && negatedPhrase:
+			case negateNext:
+				q.negatedTerms = q.addTerm(q.negatedTerms, s[i:j])
+			default:
+				q.terms = q.addTerm(q.terms, s[i:j])
+			}
+
+			negateNext = false
+			prevWord = true
+			i = j
+
+		default:
+			negateNext = false
+			prevWord = false
+			i += size
+		}
+	}
+
+	if inPhrase {
+		// unterminated phrase continues to the end of the string
+		q.addPhrase(s[phraseStart:], negatedPhrase)
+	}
+}
+
+// addTerm adds the term for the given word to terms unless it is a stop word or already there.
+func (q *Query) addTerm(terms []string, word string) []string {
+	t, ok := q.lang.term(word, q.caseSensitive, q.diacriticSensitive)
+	if !ok || slices.Contains(terms, t) {
+		return terms
+	}
+
+	return append(terms, t)
+}
+
+// addPhrase adds the phrase to phrases or negated phrases.
+func (q *Query) addPhrase(phrase string, negated bool) {
+	if len(words(phrase)) == 0 {
+		return
+	}
+
+	phrase = normalizeText(phrase, q.caseSensitive, q.diacriticSensitive)
+
+	if negated {
+		q.negatedPhrases = append(q.negatedPhrases, phrase)
+		return
+	}
+
+	q.phrases = append(q.phrases, phrase)
+}
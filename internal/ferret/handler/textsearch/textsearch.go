@@ -0,0 +1,404 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package textsearch provides text indexes over in-memory documents for $text queries.
+package textsearch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlerparams"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// wildcardField is the index key that indexes all string fields.
+const wildcardField = "$**"
+
+// maxWeight is the exclusive upper bound of field weights.
+const maxWeight = 100_000
+
+// Index is a text index over a set of documents.
+//
+// It is safe for concurrent use after all documents are added.
+type Index struct {
+	paths            []string           // indexed fields in dot notation, in specification order
+	weights          map[string]float64 // by path
+	wildcard         bool
+	defaultLanguage  *language
+	languageOverride string
+
+	// entries cache case- and diacritic-insensitive entries of added documents
+	entries map[*types.Document]*entry
+}
+
+// entry contains what a document has for the index.
+type entry struct {
+	scores map[string]float64 // by term
+	texts  []string           // normalized strings of indexed fields, for phrase matching
+}
+
+// NewIndex returns a new empty text index.
+//
+// The keys document is the index key specification like {title: "text", body: "text"} or {"$**": "text"};
+// weights is like the weights option of createIndexes and may be nil.
+// Empty defaultLanguage is "english", and empty languageOverride is "language".
+func NewIndex(keys, weights *types.Document, defaultLanguage, languageOverride string) (*Index, error) {
+	if defaultLanguage == "" {
+		defaultLanguage = "english"
+	}
+
+	if languageOverride == "" {
+		languageOverride = "language"
+	}
+
+	lang, ok := lookupLanguage(defaultLanguage)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			fmt.Sprintf("default_language %q is not supported", defaultLanguage),
+			"createIndexes",
+		)
+	}
+
+	idx := &Index{
+		weights:          make(map[string]float64, keys.Len()),
+		defaultLanguage:  lang,
+		languageOverride: languageOverride,
+		entries:          make(map[*types.Document]*entry),
+	}
+
+	if keys.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"Index keys cannot be an empty field.",
+			"createIndexes",
+		)
+	}
+
+	iter := keys.Iterator()
+	defer iter.Close()
+
+	for {
+		key, value, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if value != "text" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				fmt.Sprintf("Index key %s must be \"text\", got %s", key, types.FormatAnyValue(value)),
+				"createIndexes",
+			)
+		}
+
+		if key == wildcardField {
+			idx.wildcard = true
+			continue
+		}
+
+		idx.addPath(key, 1)
+	}
+
+	if weights == nil {
+		return idx, nil
+	}
+
+	witer := weights.Iterator()
+	defer witer.Close()
+
+	for {
+		key, value, err := witer.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		w, err := handlerparams.GetWholeNumberParam(value)
+		if err != nil || w <= 0 || w >= maxWeight {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				fmt.Sprintf(
+					"text index weight must be in the exclusive interval (0,%d) but found: %s",
+					maxWeight, types.FormatAnyValue(value),
+				),
+				"createIndexes",
+			)
+		}
+
+		if key == wildcardField {
+			idx.wildcard = true
+			continue
+		}
+
+		// like in MongoDB, weighted fields are indexed even if they are not in keys
+		idx.addPath(key, float64(w))
+	}
+
+	return idx, nil
+}
+
+// addPath adds the indexed field or sets its weight.
+func (idx *Index) addPath(path string, weight float64) {
+	if _, ok := idx.weights[path]; !ok {
+		idx.paths = append(idx.paths, path)
+	}
+
+	idx.weights[path] = weight
+}
+
+// Add adds the document to the index.
+//
+// The document must not be modified afterwards.
+func (idx *Index) Add(doc *types.Document) error {
+	e, err := idx.entry(doc, false, false)
+	if err != nil {
+		return err
+	}
+
+	idx.entries[doc] = e
+
+	return nil
+}
+
+// Score returns the text score of the document for the query
+// and whether the document matches it.
+//
+// Documents that were not added to the index are scored too.
+func (idx *Index) Score(doc *types.Document, q *Query) (float64, bool, error) {
+	e := idx.entries[doc]
+
+	if e == nil || q.caseSensitive || q.diacriticSensitive {
+		var err error
+		if e, err = idx.entry(doc, q.caseSensitive, q.diacriticSensitive); err != nil {
+			return 0, false, err
+		}
+	}
+
+	for _, t := range q.negatedTerms {
+		if _, ok := e.scores[t]; ok {
+			return 0, false, nil
+		}
+	}
+
+	for _, p := range q.negatedPhrases {
+		if e.contains(p) {
+			return 0, false, nil
+		}
+	}
+
+	for _, p := range q.phrases {
+		if !e.contains(p) {
+			return 0, false, nil
+		}
+	}
+
+	var score float64
+	var matched bool
+
+	for _, t := range q.terms {
+		if s, ok := e.scores[t]; ok {
+			score += s
+			matched = true
+		}
+	}
+
+	return score, matched, nil
+}
+
+// contains returns true if any indexed string of the entry contains the normalized phrase.
+func (e *entry) contains(phrase string) bool {
+	for _, t := range e.texts {
+		if strings.Contains(t, phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// entry computes the entry of the document.
+func (idx *Index) entry(doc *types.Document, caseSensitive, diacriticSensitive bool) (*entry, error) {
+	lang := idx.defaultLanguage
+
+	if v, err := doc.Get(idx.languageOverride); err == nil {
+		s, ok := v.(string)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("found language override field in document with non-string type: %s", idx.languageOverride),
+				"$text",
+			)
+		}
+
+		if lang, ok = lookupLanguage(s); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("language override unsupported: %s", s),
+				"$text",
+			)
+		}
+	}
+
+	e := &entry{
+		scores: make(map[string]float64),
+	}
+
+	add := func(path, s string) {
+		weight, ok := idx.weights[path]
+		if !ok {
+			weight = 1
+		}
+
+		e.texts = append(e.texts, normalizeText(s, caseSensitive, diacriticSensitive))
+		scoreString(lang, s, weight, caseSensitive, diacriticSensitive, e.scores)
+	}
+
+	if idx.wildcard {
+		collectAllStrings("", doc, idx.languageOverride, add)
+		return e, nil
+	}
+
+	for _, path := range idx.paths {
+		collectStrings(path, strings.Split(path, "."), doc, add)
+	}
+
+	return e, nil
+}
+
+// scoreString adds scores of terms of the string to scores.
+//
+// It uses the same formula as MongoDB:
+// repeated terms add exponentially less, and terms of short strings weigh more.
+func scoreString(
+	lang *language, s string, weight float64, caseSensitive, diacriticSensitive bool, scores map[string]float64,
+) {
+	type termStats struct {
+		count int
+		exp   float64
+		freq  float64
+	}
+
+	stats := make(map[string]*termStats)
+
+	var tokens int
+
+	for _, w := range words(s) {
+		t, ok := lang.term(w, caseSensitive, diacriticSensitive)
+		if !ok {
+			continue
+		}
+
+		tokens++
+
+		st := stats[t]
+		if st == nil {
+			st = &termStats{exp: 1}
+			stats[t] = st
+		} else {
+			st.exp *= 2
+		}
+
+		st.count++
+		st.freq += 1 / st.exp
+	}
+
+	for t, st := range stats {
+		coeff := 0.5*float64(st.count)/float64(tokens) + 0.5
+
+		adjustment := 1.0
+		if len(s) == len(t) && strings.EqualFold(s, t) {
+			// the whole string is the term
+			adjustment += 0.1
+		}
+
+		scores[t] += weight * st.freq * coeff * adjustment
+	}
+}
+
+// collectStrings calls add for all strings at the given path of the value,
+// traversing arrays like MongoDB indexes do.
+func collectStrings(path string, keys []string, v any, add func(path, s string)) {
+	switch v := v.(type) {
+	case string:
+		if len(keys) == 0 {
+			add(path, v)
+		}
+
+	case *types.Document:
+		if len(keys) == 0 {
+			return
+		}
+
+		if fv, err := v.Get(keys[0]); err == nil {
+			collectStrings(path, keys[1:], fv, add)
+		}
+
+	case *types.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem, _ := v.Get(i)
+			collectStrings(path, keys, elem, add)
+		}
+	}
+}
+
+// collectAllStrings calls add for all strings of the value with their dot notation paths
+// skipping array indexes.
+// The top-level language override field is skipped.
+func collectAllStrings(path string, v any, languageOverride string, add func(path, s string)) {
+	switch v := v.(type) {
+	case string:
+		add(path, v)
+
+	case *types.Document:
+		iter := v.Iterator()
+		defer iter.Close()
+
+		for {
+			k, fv, err := iter.Next()
+			if err != nil {
+				return
+			}
+
+			if path == "" && k == languageOverride {
+				continue
+			}
+
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			collectAllStrings(p, fv, "", add)
+		}
+
+	case *types.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem, _ := v.Get(i)
+			collectAllStrings(path, elem, "", add)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+func TestParseSearch(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		search         string
+		terms          []string
+		negatedTerms   []string
+		phrases        []string
+		negatedPhrases []string
+	}{
+		"Words": {
+			search: "Running the shops",
+			terms:  []string{"run", "shop"},
+		},
+		"Negation": {
+			search:       "coffee -tea -",
+			terms:        []string{"coffe"},
+			negatedTerms: []string{"tea"},
+		},
+		"Hyphen": {
+			search: "mother-in-law",
+			terms:  []string{"mother", "law"},
+		},
+		"Phrases": {
+			search:         `"Green Tea" -"black tea" cafe`,
+			terms:          []string{"green", "tea", "cafe"},
+			phrases:        []string{"green tea"},
+			negatedPhrases: []string{"black tea"},
+		},
+		"Unterminated": {
+			search:  `"fresh coffee`,
+			terms:   []string{"fresh", "coffe"},
+			phrases: []string{"fresh coffee"},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &Query{lang: languages["english"]}
+			q.parseSearch(tc.search)
+
+			assert.Equal(t, tc.terms, q.terms)
+			assert.Equal(t, tc.negatedTerms, q.negatedTerms)
+			assert.Equal(t, tc.phrases, q.phrases)
+			assert.Equal(t, tc.negatedPhrases, q.negatedPhrases)
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	idx, err := NewIndex(must.NotFail(types.NewDocument("title", "text", "tags", "text")), nil, "", "")
+	require.NoError(t, err)
+
+	doc := must.NotFail(types.NewDocument(
+		"title", "tea and more tea",
+		"tags", must.NotFail(types.NewArray("tea", "green")),
+	))
+	require.NoError(t, idx.Add(doc))
+
+	q, err := idx.ParseQuery(must.NotFail(types.NewDocument("$search", "tea")))
+	require.NoError(t, err)
+
+	score, ok, err := idx.Score(doc, q)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// title: 1.5 * (0.5*2/2 + 0.5), "tea" tag: 1 * 1 * 1.1, "green" tag does not contain the term
+	assert.Equal(t, 2.6, score)
+}
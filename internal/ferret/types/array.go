@@ -149,6 +149,11 @@ func (a *Array) RemoveByPath(path Path) {
 
 // Min returns the minimum value from the array.
 func (a *Array) Min() any {
+	return a.min(nil)
+}
+
+// min returns the minimum value from the array, comparing strings according to the given collation.
+func (a *Array) min(c *Collation) any {
 	if a == nil || a.Len() == 0 {
 		panic("cannot get Min value; array is nil or empty")
 	}
@@ -156,7 +161,7 @@ func (a *Array) Min() any {
 	min := must.NotFail(a.Get(0))
 	for i := 1; i < a.Len(); i++ {
 		value := must.NotFail(a.Get(i))
-		if CompareOrder(min, value, Ascending) == Greater {
+		if compareOrder(min, value, Ascending, c) == Greater {
 			min = value
 		}
 	}
@@ -166,6 +171,11 @@ func (a *Array) Min() any {
 
 // Max returns the maximum value from the array.
 func (a *Array) Max() any {
+	return a.max(nil)
+}
+
+// max returns the maximum value from the array, comparing strings according to the given collation.
+func (a *Array) max(c *Collation) any {
 	if a == nil || a.Len() == 0 {
 		panic("cannot get Max value; array is nil or empty")
 	}
@@ -173,7 +183,7 @@ func (a *Array) Max() any {
 	max := must.NotFail(a.Get(0))
 	for i := 1; i < a.Len(); i++ {
 		value := must.NotFail(a.Get(i))
-		if CompareOrder(max, value, Ascending) == Less {
+		if compareOrder(max, value, Ascending, c) == Less {
 			max = value
 		}
 	}
@@ -204,6 +214,11 @@ func (a *Array) FilterArrayByType(ref any) *Array {
 //
 // It panics if the filterValue is not a valid BSON type.
 func (a *Array) Contains(filterValue any) bool {
+	return a.contains(filterValue, nil)
+}
+
+// contains checks if the Array contains the given value, comparing strings according to the given collation.
+func (a *Array) contains(filterValue any, c *Collation) bool {
 	assertType(filterValue)
 
 	switch filterValue := filterValue.(type) {
@@ -211,12 +226,12 @@ func (a *Array) Contains(filterValue any) bool {
 		// filterValue is a composite type, so either a and filterValue must be equal
 		// or at least one element of a must be equal with filterValue.
 
-		if res := Compare(a, filterValue); res == Equal {
+		if res := compare(a, filterValue, c); res == Equal {
 			return true
 		}
 
 		for _, elem := range a.s {
-			if res := Compare(elem, filterValue); res == Equal {
+			if res := compare(elem, filterValue, c); res == Equal {
 				return true
 			}
 		}
@@ -229,7 +244,7 @@ func (a *Array) Contains(filterValue any) bool {
 			case *Document, *Array:
 				// we need elem and filterValue to be exactly equal, so we do nothing here
 			default:
-				if compareScalars(elem, filterValue) == Equal {
+				if compareScalars(elem, filterValue, c) == Equal {
 					return true
 				}
 			}
@@ -242,8 +257,14 @@ func (a *Array) Contains(filterValue any) bool {
 // Currently, this algorithm is O(n^2) without any performance tuning.
 // This place can be significantly improved if a more performant algorithm is chosen.
 func (a *Array) ContainsAll(b *Array) bool {
+	return a.containsAll(b, nil)
+}
+
+// containsAll checks if Array a contains all the given values of Array b,
+// comparing strings according to the given collation.
+func (a *Array) containsAll(b *Array, c *Collation) bool {
 	for _, v := range b.s {
-		if !a.Contains(v) {
+		if !a.contains(v, c) {
 			return false
 		}
 	}
@@ -0,0 +1,225 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// CollationOptions represents fields of the collation document that affect string comparison.
+//
+// Zero values of Strength and Alternate mean defaults: 3 and "non-ignorable".
+type CollationOptions struct {
+	Locale          string
+	Alternate       string
+	Strength        int
+	CaseLevel       bool
+	NumericOrdering bool
+}
+
+// Collation compares strings according to language-specific rules.
+//
+// A nil *Collation compares strings by their UTF-8 bytes, like the "simple" collation;
+// methods of Collation and package-level functions like Compare are then equivalent.
+//
+// It is safe for concurrent use.
+type Collation struct {
+	// collators are not safe for concurrent use, so each comparison takes them from the pool
+	collators sync.Pool
+	strength  int
+	caseLevel bool
+	shifted   bool
+}
+
+// collators holds collators used by a single comparison.
+type collators struct {
+	strength *collate.Collator // compares with the configured strength
+	tertiary *collate.Collator // compares case for caseLevel
+}
+
+// NewCollation returns a new Collation for the given options.
+//
+// It returns nil for the "simple" locale.
+func NewCollation(opts CollationOptions) (*Collation, error) {
+	if opts.Locale == "simple" {
+		return nil, nil
+	}
+
+	tag, err := language.Parse(opts.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported collation locale: %q", opts.Locale)
+	}
+
+	if _, _, confidence := language.NewMatcher(collate.Supported()).Match(tag); confidence == language.No {
+		return nil, fmt.Errorf("unsupported collation locale: %q", opts.Locale)
+	}
+
+	c := &Collation{
+		strength: opts.Strength,
+	}
+
+	if c.strength == 0 {
+		c.strength = 3
+	}
+
+	var level string
+
+	switch c.strength {
+	case 1, 2, 3, 4:
+		level = fmt.Sprintf("level%d", c.strength)
+	case 5:
+		level = "identic"
+	default:
+		return nil, fmt.Errorf("collation strength must be an integer 1 through 5, got %d", opts.Strength)
+	}
+
+	switch opts.Alternate {
+	case "", "non-ignorable":
+	case "shifted":
+		c.shifted = true
+	default:
+		return nil, fmt.Errorf(`collation alternate must be "non-ignorable" or "shifted", got %q`, opts.Alternate)
+	}
+
+	// case level only matters when case is not compared anyway
+	c.caseLevel = opts.CaseLevel && c.strength < 3
+
+	if opts.NumericOrdering {
+		if tag, err = tag.SetTypeForKey("kn", "true"); err != nil {
+			return nil, err
+		}
+	}
+
+	strengthTag, err := tag.SetTypeForKey("ks", level)
+	if err != nil {
+		return nil, err
+	}
+
+	c.collators.New = func() any {
+		return &collators{
+			strength: collate.New(strengthTag),
+			tertiary: collate.New(tag),
+		}
+	}
+
+	return c, nil
+}
+
+// compareStrings compares strings according to the collation.
+//
+// Alternate "shifted" and case level are implemented here
+// because collate package does not handle them the way MongoDB does.
+func (c *Collation) compareStrings(a, b string) CompareResult {
+	if c == nil {
+		return compareOrdered(a, b)
+	}
+
+	cs := c.collators.Get().(*collators)
+	defer c.collators.Put(cs)
+
+	sa, sb := a, b
+	if c.shifted {
+		sa, sb = removeVariable(sa), removeVariable(sb)
+	}
+
+	res := cs.strength.CompareString(sa, sb)
+
+	if res == 0 && c.caseLevel {
+		if c.strength == 1 {
+			sa, sb = removeDiacritics(sa), removeDiacritics(sb)
+		}
+
+		res = cs.tertiary.CompareString(sa, sb)
+	}
+
+	if res == 0 && c.shifted && c.strength >= 4 {
+		// ignored whitespace and punctuation are compared on the quaternary level
+		res = cs.strength.CompareString(a, b)
+	}
+
+	if res == 0 && c.strength == 5 {
+		return compareOrdered(a, b)
+	}
+
+	return CompareResult(res)
+}
+
+// removeVariable removes whitespace and punctuation,
+// the characters MongoDB ignores with alternate "shifted" and default maxVariable "punct".
+func removeVariable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+// removeDiacritics removes combining marks from the decomposed string.
+func removeDiacritics(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+
+		return r
+	}, norm.NFD.String(s))
+}
+
+// Compare is like the package-level Compare, but compares strings according to the collation.
+func (c *Collation) Compare(docValue, filterValue any) CompareResult {
+	return compare(docValue, filterValue, c)
+}
+
+// CompareForAggregation is like the package-level CompareForAggregation,
+// but compares strings according to the collation.
+func (c *Collation) CompareForAggregation(docValue, filterValue any) CompareResult {
+	return compareForAggregation(docValue, filterValue, c)
+}
+
+// CompareOrder is like the package-level CompareOrder, but compares strings according to the collation.
+func (c *Collation) CompareOrder(a, b any, order SortType) CompareResult {
+	return compareOrder(a, b, order, c)
+}
+
+// CompareOrderForSort is like the package-level CompareOrderForSort,
+// but compares strings according to the collation.
+func (c *Collation) CompareOrderForSort(a, b any, order SortType) CompareResult {
+	return compareOrderForSort(a, b, order, c)
+}
+
+// CompareOrderForOperator is like the package-level CompareOrderForOperator,
+// but compares strings according to the collation.
+func (c *Collation) CompareOrderForOperator(a, b any, order SortType) CompareResult {
+	return compareOrderForOperator(a, b, order, c)
+}
+
+// Contains is like Array.Contains, but compares strings according to the collation.
+func (c *Collation) Contains(arr *Array, filterValue any) bool {
+	return arr.contains(filterValue, c)
+}
+
+// ContainsAll is like Array.ContainsAll, but compares strings according to the collation.
+func (c *Collation) ContainsAll(arr, values *Array) bool {
+	return arr.containsAll(values, c)
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+func TestCollation(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		opts     CollationOptions
+		a        any
+		b        any
+		expected CompareResult
+	}{
+		"Simple": {
+			opts:     CollationOptions{Locale: "simple"},
+			a:        "a",
+			b:        "B",
+			expected: Greater,
+		},
+		"Tertiary": {
+			opts:     CollationOptions{Locale: "en"},
+			a:        "a",
+			b:        "B",
+			expected: Less,
+		},
+		"TertiaryCase": {
+			opts:     CollationOptions{Locale: "en"},
+			a:        "a",
+			b:        "A",
+			expected: Less,
+		},
+		"PrimaryCase": {
+			opts:     CollationOptions{Locale: "en", Strength: 1},
+			a:        "a",
+			b:        "A",
+			expected: Equal,
+		},
+		"PrimaryDiacritics": {
+			opts:     CollationOptions{Locale: "fr", Strength: 1},
+			a:        "cote",
+			b:        "Côté",
+			expected: Equal,
+		},
+		"SecondaryCase": {
+			opts:     CollationOptions{Locale: "en", Strength: 2},
+			a:        "Hello",
+			b:        "hello",
+			expected: Equal,
+		},
+		"SecondaryDiacritics": {
+			opts:     CollationOptions{Locale: "fr", Strength: 2},
+			a:        "cote",
+			b:        "côte",
+			expected: Less,
+		},
+		"CaseLevel": {
+			opts:     CollationOptions{Locale: "en", Strength: 1, CaseLevel: true},
+			a:        "a",
+			b:        "A",
+			expected: Less,
+		},
+		"CaseLevelDiacritics": {
+			opts:     CollationOptions{Locale: "fr", Strength: 1, CaseLevel: true},
+			a:        "cote",
+			b:        "côte",
+			expected: Equal,
+		},
+		"Numeric": {
+			opts:     CollationOptions{Locale: "en", NumericOrdering: true},
+			a:        "item10",
+			b:        "item9",
+			expected: Greater,
+		},
+		"NotNumeric": {
+			opts:     CollationOptions{Locale: "en"},
+			a:        "item10",
+			b:        "item9",
+			expected: Less,
+		},
+		"Shifted": {
+			opts:     CollationOptions{Locale: "en", Alternate: "shifted"},
+			a:        "black bird",
+			b:        "blackbird",
+			expected: Equal,
+		},
+		"NonIgnorable": {
+			opts:     CollationOptions{Locale: "en", Alternate: "non-ignorable"},
+			a:        "black bird",
+			b:        "blackbird",
+			expected: Less,
+		},
+		"Locale": {
+			opts:     CollationOptions{Locale: "sv"},
+			a:        "ä",
+			b:        "z",
+			expected: Greater,
+		},
+		"OtherTypes": {
+			opts:     CollationOptions{Locale: "en", Strength: 2},
+			a:        int32(1),
+			b:        "A",
+			expected: Less,
+		},
+		"Array": {
+			opts:     CollationOptions{Locale: "en", Strength: 2},
+			a:        must.NotFail(NewArray("x", "A")),
+			b:        "a",
+			expected: Equal,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCollation(tc.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, c.Compare(tc.a, tc.b))
+		})
+	}
+}
+
+func TestCollationContains(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCollation(CollationOptions{Locale: "en", Strength: 2})
+	require.NoError(t, err)
+
+	arr := must.NotFail(NewArray("Alice", "Bob"))
+	assert.True(t, c.Contains(arr, "alice"))
+	assert.False(t, c.Contains(arr, "carol"))
+	assert.True(t, c.ContainsAll(arr, must.NotFail(NewArray("BOB", "alice"))))
+
+	var simple *Collation
+	assert.False(t, simple.Contains(arr, "alice"))
+	assert.True(t, simple.Contains(arr, "Alice"))
+}
+
+func TestCollationErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string]CollationOptions{
+		"Locale":    {Locale: "xx-unknown"},
+		"Strength":  {Locale: "en", Strength: 6},
+		"Alternate": {Locale: "en", Alternate: "blank"},
+	} {
+		name, opts := name, opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewCollation(opts)
+			assert.Error(t, err)
+		})
+	}
+}
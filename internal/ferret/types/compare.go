@@ -45,20 +45,25 @@ const (
 //
 // Compare and contrast with test helpers in testutil package.
 func Compare(docValue, filterValue any) CompareResult {
+	return compare(docValue, filterValue, nil)
+}
+
+// compare compares any BSON values, comparing strings according to the given collation.
+func compare(docValue, filterValue any, c *Collation) CompareResult {
 	assertType(docValue)
 	assertType(filterValue)
 
 	switch docValue := docValue.(type) {
 	case *Document:
 		if filterDoc, ok := filterValue.(*Document); ok {
-			return compareDocuments(docValue, filterDoc)
+			return compareDocuments(docValue, filterDoc, c)
 		}
 
 		return compareTypeOrder(docValue, filterValue)
 	case *Array:
-		return compareArray(docValue, filterValue)
+		return compareArray(docValue, filterValue, c)
 	default:
-		return compareScalars(docValue, filterValue)
+		return compareScalars(docValue, filterValue, c)
 	}
 }
 
@@ -66,29 +71,34 @@ func Compare(docValue, filterValue any) CompareResult {
 // an array and non array would not result in Equal.
 // This is specially used for aggregation grouping comparison.
 func CompareForAggregation(docValue, filterValue any) CompareResult {
+	return compareForAggregation(docValue, filterValue, nil)
+}
+
+// compareForAggregation is like CompareForAggregation, but compares strings according to the given collation.
+func compareForAggregation(docValue, filterValue any, c *Collation) CompareResult {
 	assertType(docValue)
 	assertType(filterValue)
 
 	switch docValue := docValue.(type) {
 	case *Document:
 		if filterDoc, ok := filterValue.(*Document); ok {
-			return compareDocuments(docValue, filterDoc)
+			return compareDocuments(docValue, filterDoc, c)
 		}
 
 		return compareTypeOrder(docValue, filterValue)
 	case *Array:
 		if filterDoc, ok := filterValue.(*Array); ok {
-			return compareArrays(docValue, filterDoc)
+			return compareArrays(docValue, filterDoc, c)
 		}
 
 		return compareTypeOrder(docValue, filterValue)
 	default:
-		return compareScalars(docValue, filterValue)
+		return compareScalars(docValue, filterValue, c)
 	}
 }
 
-// compareScalars compares BSON scalar values.
-func compareScalars(v1, v2 any) CompareResult {
+// compareScalars compares BSON scalar values, comparing strings according to the given collation.
+func compareScalars(v1, v2 any, c *Collation) CompareResult {
 	assertType(v1)
 	assertType(v2)
 
@@ -96,6 +106,13 @@ func compareScalars(v1, v2 any) CompareResult {
 		return compareTypeOrder(v1, v2)
 	}
 
+	_, isDecimal1 := v1.(Decimal128)
+	_, isDecimal2 := v2.(Decimal128)
+
+	if (isDecimal1 || isDecimal2) && detectDataType(v1) == detectDataType(v2) {
+		return compareDecimal128(v1, v2)
+	}
+
 	switch v1 := v1.(type) {
 	case float64:
 		switch v2 := v2.(type) {
@@ -113,13 +130,15 @@ func compareScalars(v1, v2 any) CompareResult {
 		}
 
 	case string:
-		v, ok := v2.(string)
-		if ok {
-			return compareOrdered(v1, v)
+		switch v := v2.(type) {
+		case string:
+			return c.compareStrings(v1, v)
+		case Symbol:
+			return c.compareStrings(v1, string(v))
+		default:
+			return compareTypeOrder(v1, v2)
 		}
 
-		return compareTypeOrder(v1, v2)
-
 	case Binary:
 		v, ok := v2.(Binary)
 		if !ok {
@@ -217,6 +236,58 @@ func compareScalars(v1, v2 any) CompareResult {
 		default:
 			return compareTypeOrder(v1, v2)
 		}
+
+	case Decimal128:
+		return compareTypeOrder(v1, v2)
+
+	case MinKeyType, MaxKeyType, UndefinedType:
+		return compareTypeOrder(v1, v2)
+
+	case Symbol:
+		switch v := v2.(type) {
+		case string:
+			return c.compareStrings(string(v1), v)
+		case Symbol:
+			return c.compareStrings(string(v1), string(v))
+		default:
+			return compareTypeOrder(v1, v2)
+		}
+
+	case JavaScript:
+		v, ok := v2.(JavaScript)
+		if ok {
+			return compareOrdered(v1, v)
+		}
+
+		return compareTypeOrder(v1, v2)
+
+	case JavaScriptScope:
+		v, ok := v2.(JavaScriptScope)
+		if !ok {
+			return compareTypeOrder(v1, v2)
+		}
+
+		if res := compareOrdered(v1.Code, v.Code); res != Equal {
+			return res
+		}
+
+		return compareDocuments(v1.Scope, v.Scope, c)
+
+	case DBPointer:
+		v, ok := v2.(DBPointer)
+		if !ok {
+			return compareTypeOrder(v1, v2)
+		}
+
+		if res := compareOrdered(len(v1.Namespace), len(v.Namespace)); res != Equal {
+			return res
+		}
+
+		if res := compareOrdered(v1.Namespace, v.Namespace); res != Equal {
+			return res
+		}
+
+		return CompareResult(bytes.Compare(v1.ID[:], v.ID[:]))
 	}
 
 	panic("not reached")
@@ -262,7 +333,7 @@ func compareNumbers(a float64, b int64) CompareResult {
 // returns Equal when an array equals to filter array;
 // returns Less when an index of the document array is less than the index of the filter array;
 // returns Greater when an index of the document array is greater than the index of the filter array.
-func compareArrays(docArr, filterArr *Array) CompareResult {
+func compareArrays(docArr, filterArr *Array, c *Collation) CompareResult {
 	if filterArr.Len() == 0 && docArr.Len() == 0 {
 		return Equal
 	}
@@ -287,12 +358,12 @@ func compareArrays(docArr, filterArr *Array) CompareResult {
 			continue
 		}
 
-		orderResult := CompareOrder(docValue, filterValue, Ascending)
+		orderResult := compareOrder(docValue, filterValue, Ascending, c)
 		if orderResult != Equal {
 			return orderResult
 		}
 
-		iterationResult := Compare(docValue, filterValue)
+		iterationResult := compare(docValue, filterValue, c)
 		if iterationResult != Equal {
 			return iterationResult
 		}
@@ -307,7 +378,7 @@ func compareArrays(docArr, filterArr *Array) CompareResult {
 
 // compareDocuments compares documents recursively by
 // comparing them in the order of types, field names and field values.
-func compareDocuments(a, b *Document) CompareResult {
+func compareDocuments(a, b *Document, c *Collation) CompareResult {
 	if a.Len() == 0 && b.Len() == 0 {
 		return Equal
 	}
@@ -335,13 +406,13 @@ func compareDocuments(a, b *Document) CompareResult {
 			return result
 		}
 
-		// compare keys
-		if result := compareScalars(aKey, bKeys[i]); result != Equal {
+		// compare keys; collation applies only to values
+		if result := compareScalars(aKey, bKeys[i], nil); result != Equal {
 			return result
 		}
 
 		// compare values
-		if result := Compare(aValues[i], bValues[i]); result != Equal {
+		if result := compare(aValues[i], bValues[i], c); result != Equal {
 			return result
 		}
 	}
@@ -354,11 +425,11 @@ func compareDocuments(a, b *Document) CompareResult {
 }
 
 // compareArray compares array to any value.
-func compareArray(as *Array, b any) CompareResult {
+func compareArray(as *Array, b any, c *Collation) CompareResult {
 	assertType(b)
 
 	if bs, ok := b.(*Array); ok {
-		return compareArrays(as, bs)
+		return compareArrays(as, bs, c)
 	}
 
 	var result CompareResult
@@ -372,7 +443,7 @@ func compareArray(as *Array, b any) CompareResult {
 			continue
 		}
 
-		result = Compare(a, b)
+		result = compare(a, b, c)
 		if result == Equal {
 			return result
 		}
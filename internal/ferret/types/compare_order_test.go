@@ -54,3 +54,37 @@ func TestCompareOrderForSort(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareOrderMinMaxKey(t *testing.T) {
+	t.Parallel()
+
+	values := []any{
+		MinKey,
+		Undefined,
+		Null,
+		int32(42),
+		Symbol("foo"),
+		must.NotFail(NewDocument()),
+		ObjectID{},
+		false,
+		Timestamp(1),
+		Regex{Pattern: "foo"},
+		DBPointer{Namespace: "db.c"},
+		JavaScript("f"),
+		JavaScriptScope{Code: "f", Scope: must.NotFail(NewDocument())},
+		MaxKey,
+	}
+
+	for i := 1; i < len(values); i++ {
+		require.Equal(t, Less, CompareOrder(values[i-1], values[i], Ascending), "%v < %v", values[i-1], values[i])
+		require.Equal(t, Greater, CompareOrder(values[i], values[i-1], Ascending), "%v > %v", values[i], values[i-1])
+	}
+
+	require.Equal(t, Equal, CompareOrder(MinKey, MinKey, Ascending))
+	require.Equal(t, Equal, CompareOrder(MaxKey, MaxKey, Ascending))
+	require.Equal(t, Equal, CompareOrder(Symbol("foo"), "foo", Ascending))
+	require.Equal(t, Less, CompareOrder("foo", Symbol("goo"), Ascending))
+
+	require.Equal(t, Greater, CompareOrderForOperator("foo", MinKey, Ascending))
+	require.Equal(t, Less, CompareOrderForOperator(int32(42), MaxKey, Descending))
+}
@@ -8,23 +8,29 @@ func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
-	_ = x[nullDataType-1]
-	_ = x[nanDataType-2]
-	_ = x[numbersDataType-3]
-	_ = x[stringDataType-4]
-	_ = x[documentDataType-5]
-	_ = x[arrayDataType-6]
-	_ = x[binDataType-7]
-	_ = x[objectIDDataType-8]
-	_ = x[booleanDataType-9]
-	_ = x[dateDataType-10]
-	_ = x[timestampDataType-11]
-	_ = x[regexDataType-12]
+	_ = x[minKeyDataType-1]
+	_ = x[undefinedDataType-2]
+	_ = x[nullDataType-3]
+	_ = x[nanDataType-4]
+	_ = x[numbersDataType-5]
+	_ = x[stringDataType-6]
+	_ = x[documentDataType-7]
+	_ = x[arrayDataType-8]
+	_ = x[binDataType-9]
+	_ = x[objectIDDataType-10]
+	_ = x[booleanDataType-11]
+	_ = x[dateDataType-12]
+	_ = x[timestampDataType-13]
+	_ = x[regexDataType-14]
+	_ = x[dbPointerDataType-15]
+	_ = x[javaScriptDataType-16]
+	_ = x[javaScriptScopeDataType-17]
+	_ = x[maxKeyDataType-18]
 }
 
-const _compareTypeOrderResult_name = "nullDataTypenanDataTypenumbersDataTypestringDataTypedocumentDataTypearrayDataTypebinDataTypeobjectIDDataTypebooleanDataTypedateDataTypetimestampDataTyperegexDataType"
+const _compareTypeOrderResult_name = "minKeyDataTypeundefinedDataTypenullDataTypenanDataTypenumbersDataTypestringDataTypedocumentDataTypearrayDataTypebinDataTypeobjectIDDataTypebooleanDataTypedateDataTypetimestampDataTyperegexDataTypedbPointerDataTypejavaScriptDataTypejavaScriptScopeDataTypemaxKeyDataType"
 
-var _compareTypeOrderResult_index = [...]uint8{0, 12, 23, 38, 52, 68, 81, 92, 108, 123, 135, 152, 165}
+var _compareTypeOrderResult_index = [...]uint16{0, 14, 31, 43, 54, 69, 83, 99, 112, 123, 139, 154, 166, 183, 196, 213, 231, 254, 268}
 
 func (i compareTypeOrderResult) String() string {
 	i -= 1
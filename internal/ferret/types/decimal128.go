@@ -0,0 +1,490 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Decimal128 represents BSON type Decimal128, a 128-bit IEEE 754-2008 decimal floating point number.
+//
+// H and L are the high and low 64 bits of the binary integer decimal (BID) encoding.
+type Decimal128 struct {
+	H uint64
+	L uint64
+}
+
+const (
+	// decimal128Digits is the maximum number of significant digits of Decimal128.
+	decimal128Digits = 34
+
+	// decimal128SignBit is the sign bit of the high part.
+	decimal128SignBit = uint64(1) << 63
+)
+
+var (
+	// decimal128NaN is Decimal128 NaN value.
+	decimal128NaN = Decimal128{H: 0x1f << 58}
+
+	// decimal128Inf is Decimal128 positive infinity value.
+	decimal128Inf = Decimal128{H: 0x1e << 58}
+
+	// bigTen is 10 as big.Int.
+	bigTen = big.NewInt(10)
+)
+
+// ParseDecimal128 parses the string representation of a decimal number, such as "1.5", "-2E+3", "NaN" or "Infinity".
+//
+// It returns an error if the value can't be represented exactly.
+func ParseDecimal128(s string) (Decimal128, error) {
+	d, err := primitive.ParseDecimal128(s)
+	if err != nil {
+		return Decimal128{}, err
+	}
+
+	h, l := d.GetBytes()
+
+	return Decimal128{H: h, L: l}, nil
+}
+
+// NewDecimal128FromInt64 returns Decimal128 with the given integer value.
+func NewDecimal128FromInt64(v int64) Decimal128 {
+	return decimal{coef: new(big.Int).Abs(big.NewInt(v)), neg: v < 0}.pack()
+}
+
+// NewDecimal128FromFloat64 returns Decimal128 with the value of v rounded to 15 significant digits,
+// like MongoDB does.
+func NewDecimal128FromFloat64(v float64) Decimal128 {
+	switch {
+	case math.IsNaN(v):
+		return decimal128NaN
+	case math.IsInf(v, 1):
+		return decimal128Inf
+	case math.IsInf(v, -1):
+		return decimal128Inf.Neg()
+	case v == 0:
+		return decimal{coef: new(big.Int), neg: math.Signbit(v)}.pack()
+	}
+
+	// 15 significant digits is the maximum that round-trips any decimal through float64
+	d, err := ParseDecimal128(strconv.FormatFloat(v, 'e', 14, 64))
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// NewDecimal128FromBigInt returns Decimal128 with the value coef * 10^exp rounded to 34 significant digits.
+func NewDecimal128FromBigInt(coef *big.Int, exp int) Decimal128 {
+	return decimal{coef: new(big.Int).Abs(coef), exp: exp, neg: coef.Sign() < 0}.pack()
+}
+
+// NewDecimal128FromNumber converts float64, int32, int64 or Decimal128 value to Decimal128.
+// It returns false for other types.
+func NewDecimal128FromNumber(v any) (Decimal128, bool) {
+	switch v := v.(type) {
+	case float64:
+		return NewDecimal128FromFloat64(v), true
+	case int32:
+		return NewDecimal128FromInt64(int64(v)), true
+	case int64:
+		return NewDecimal128FromInt64(v), true
+	case Decimal128:
+		return v, true
+	default:
+		return Decimal128{}, false
+	}
+}
+
+// String returns the string representation of d.
+func (d Decimal128) String() string {
+	return primitive.NewDecimal128(d.H, d.L).String()
+}
+
+// IsNaN returns true if d is NaN.
+func (d Decimal128) IsNaN() bool {
+	return primitive.NewDecimal128(d.H, d.L).IsNaN()
+}
+
+// IsInf returns 1 for positive infinity, -1 for negative infinity and 0 otherwise.
+func (d Decimal128) IsInf() int {
+	return primitive.NewDecimal128(d.H, d.L).IsInf()
+}
+
+// IsZero returns true if d is positive or negative zero with any exponent.
+func (d Decimal128) IsZero() bool {
+	x := d.unpack()
+	return x.finite() && x.coef.Sign() == 0
+}
+
+// Signbit returns true if d is negative or negative zero.
+func (d Decimal128) Signbit() bool {
+	return d.H&decimal128SignBit != 0
+}
+
+// Neg returns d with the opposite sign.
+func (d Decimal128) Neg() Decimal128 {
+	d.H ^= decimal128SignBit
+	return d
+}
+
+// Rat returns the exact value of d as big.Rat.
+// It returns false for NaN and infinities.
+func (d Decimal128) Rat() (*big.Rat, bool) {
+	x := d.unpack()
+	if !x.finite() {
+		return nil, false
+	}
+
+	return x.rat(), true
+}
+
+// Float64 returns the float64 value nearest to d.
+func (d Decimal128) Float64() float64 {
+	x := d.unpack()
+
+	switch {
+	case x.nan:
+		return math.NaN()
+	case x.inf && x.neg:
+		return math.Inf(-1)
+	case x.inf:
+		return math.Inf(1)
+	}
+
+	// out of range values are returned as infinities together with an error that we ignore
+	f, _ := strconv.ParseFloat(d.String(), 64)
+
+	return f
+}
+
+// Add returns the sum of d and other rounded to 34 significant digits.
+func (d Decimal128) Add(other Decimal128) Decimal128 {
+	a, b := d.unpack(), other.unpack()
+
+	switch {
+	case a.nan || b.nan:
+		return decimal128NaN
+	case a.inf && b.inf && a.neg != b.neg:
+		return decimal128NaN
+	case a.inf:
+		return d
+	case b.inf:
+		return other
+	}
+
+	exp := min(a.exp, b.exp)
+	sum := new(big.Int).Add(a.scaled(exp), b.scaled(exp))
+
+	return decimal{
+		coef: new(big.Int).Abs(sum),
+		exp:  exp,
+		neg:  sum.Sign() < 0 || (sum.Sign() == 0 && a.neg && b.neg),
+	}.pack()
+}
+
+// Sub returns the difference of d and other rounded to 34 significant digits.
+func (d Decimal128) Sub(other Decimal128) Decimal128 {
+	return d.Add(other.Neg())
+}
+
+// Mul returns the product of d and other rounded to 34 significant digits.
+func (d Decimal128) Mul(other Decimal128) Decimal128 {
+	a, b := d.unpack(), other.unpack()
+	neg := a.neg != b.neg
+
+	switch {
+	case a.nan || b.nan:
+		return decimal128NaN
+	case (a.inf && b.isZero()) || (b.inf && a.isZero()):
+		return decimal128NaN
+	case a.inf || b.inf:
+		return decimal{inf: true, neg: neg}.pack()
+	}
+
+	return decimal{
+		coef: new(big.Int).Mul(a.coef, b.coef),
+		exp:  a.exp + b.exp,
+		neg:  neg,
+	}.pack()
+}
+
+// Quo returns the quotient of d and other rounded to 34 significant digits.
+//
+// Division of non-zero value by zero returns an infinity, division of zero by zero returns NaN.
+func (d Decimal128) Quo(other Decimal128) Decimal128 {
+	a, b := d.unpack(), other.unpack()
+	neg := a.neg != b.neg
+
+	switch {
+	case a.nan || b.nan:
+		return decimal128NaN
+	case a.inf && b.inf:
+		return decimal128NaN
+	case a.inf:
+		return decimal{inf: true, neg: neg}.pack()
+	case b.inf:
+		return decimal{coef: new(big.Int), exp: primitive.MinDecimal128Exp, neg: neg}.pack()
+	case b.isZero() && a.isZero():
+		return decimal128NaN
+	case b.isZero():
+		return decimal{inf: true, neg: neg}.pack()
+	}
+
+	// the preferred exponent of the exact result
+	exp := a.exp - b.exp
+
+	// scale the dividend so the quotient has more digits than we need for rounding
+	scale := max(0, decimal128Digits+1+numDigits(b.coef)-numDigits(a.coef))
+	dividend := new(big.Int).Mul(a.coef, pow10(scale))
+
+	q, r := new(big.Int).QuoRem(dividend, b.coef, new(big.Int))
+
+	if r.Sign() != 0 {
+		// append a sticky digit so the rounding knows the result is inexact
+		q.Mul(q, bigTen)
+		q.Add(q, big.NewInt(1))
+		scale++
+	} else {
+		// the result is exact, remove trailing zeros up to the preferred exponent
+		for scale > 0 && new(big.Int).Rem(q, bigTen).Sign() == 0 {
+			q.Quo(q, bigTen)
+			scale--
+		}
+	}
+
+	return decimal{
+		coef: q,
+		exp:  exp - scale,
+		neg:  neg,
+	}.pack()
+}
+
+// Rem returns the remainder of d divided by other truncated toward zero; it has the sign of d.
+//
+// The remainder of an infinity or by zero is NaN.
+func (d Decimal128) Rem(other Decimal128) Decimal128 {
+	a, b := d.unpack(), other.unpack()
+
+	switch {
+	case a.nan || b.nan || a.inf || b.isZero():
+		return decimal128NaN
+	case b.inf:
+		return d
+	}
+
+	exp := min(a.exp, b.exp)
+	rem := new(big.Int).Rem(a.scaled(exp), b.scaled(exp))
+
+	return decimal{
+		coef: new(big.Int).Abs(rem),
+		exp:  exp,
+		neg:  a.neg,
+	}.pack()
+}
+
+// decimal represents an unpacked Decimal128 value used for arithmetic.
+type decimal struct {
+	coef *big.Int // absolute value of the coefficient of a finite value
+	exp  int
+	neg  bool
+	nan  bool
+	inf  bool
+}
+
+// unpack returns an unpacked value of d.
+func (d Decimal128) unpack() decimal {
+	neg := d.Signbit()
+
+	switch {
+	case d.IsNaN():
+		return decimal{nan: true}
+	case d.IsInf() != 0:
+		return decimal{inf: true, neg: neg}
+	}
+
+	coef, exp, err := primitive.NewDecimal128(d.H, d.L).BigInt()
+	if err != nil {
+		panic(err)
+	}
+
+	return decimal{coef: coef.Abs(coef), exp: exp, neg: neg}
+}
+
+// pack returns Decimal128 value for x, rounding it to 34 significant digits (half to even).
+// Values too large to be represented are returned as infinities.
+func (x decimal) pack() Decimal128 {
+	var res Decimal128
+
+	switch {
+	case x.nan:
+		return decimal128NaN
+	case x.inf:
+		res = decimal128Inf
+	default:
+		coef, exp := x.coef, x.exp
+
+		drop := max(numDigits(coef)-decimal128Digits, primitive.MinDecimal128Exp-exp)
+		if drop > 0 {
+			coef = quoRoundHalfEven(coef, pow10(drop))
+			exp += drop
+
+			// rounding up 99...9 adds a digit
+			if numDigits(coef) > decimal128Digits {
+				coef.Quo(coef, bigTen)
+				exp++
+			}
+		}
+
+		d, ok := primitive.ParseDecimal128FromBigInt(coef, exp)
+		if !ok {
+			res = decimal128Inf
+			break
+		}
+
+		res.H, res.L = d.GetBytes()
+	}
+
+	if x.neg {
+		res.H |= decimal128SignBit
+	}
+
+	return res
+}
+
+// finite returns true if x is neither NaN nor infinity.
+func (x decimal) finite() bool {
+	return !x.nan && !x.inf
+}
+
+// isZero returns true if x is a finite zero.
+func (x decimal) isZero() bool {
+	return x.finite() && x.coef.Sign() == 0
+}
+
+// scaled returns the signed coefficient of finite x for the given exponent that is not greater than x.exp.
+func (x decimal) scaled(exp int) *big.Int {
+	res := new(big.Int).Mul(x.coef, pow10(x.exp-exp))
+	if x.neg {
+		res.Neg(res)
+	}
+
+	return res
+}
+
+// rat returns the value of finite x as big.Rat.
+func (x decimal) rat() *big.Rat {
+	var res *big.Rat
+
+	if x.exp >= 0 {
+		res = new(big.Rat).SetInt(new(big.Int).Mul(x.coef, pow10(x.exp)))
+	} else {
+		res = new(big.Rat).SetFrac(x.coef, pow10(-x.exp))
+	}
+
+	if x.neg {
+		res.Neg(res)
+	}
+
+	return res
+}
+
+// compareDecimal128 compares numbers when at least one of them is Decimal128.
+//
+// NaN is equal to NaN and less than any other number.
+func compareDecimal128(a, b any) CompareResult {
+	aNaN, aInf, aRat := numberValue(a)
+	bNaN, bInf, bRat := numberValue(b)
+
+	switch {
+	case aNaN && bNaN:
+		return Equal
+	case aNaN:
+		return Less
+	case bNaN:
+		return Greater
+	case aInf != 0 || bInf != 0:
+		return compareOrdered(aInf, bInf)
+	default:
+		return CompareResult(aRat.Cmp(bRat))
+	}
+}
+
+// numberValue returns the exact value of float64, int32, int64 or Decimal128 number.
+// For NaN, nan is true; for infinities, inf is 1 or -1; otherwise, rat is set.
+func numberValue(v any) (nan bool, inf int, rat *big.Rat) {
+	switch v := v.(type) {
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return true, 0, nil
+		case math.IsInf(v, 1):
+			return false, 1, nil
+		case math.IsInf(v, -1):
+			return false, -1, nil
+		}
+
+		return false, 0, new(big.Rat).SetFloat64(v)
+	case int32:
+		return false, 0, new(big.Rat).SetInt64(int64(v))
+	case int64:
+		return false, 0, new(big.Rat).SetInt64(v)
+	case Decimal128:
+		if v.IsNaN() {
+			return true, 0, nil
+		}
+
+		if inf := v.IsInf(); inf != 0 {
+			return false, inf, nil
+		}
+
+		r, _ := v.Rat()
+
+		return false, 0, r
+	default:
+		panic("not a number")
+	}
+}
+
+// numDigits returns the number of decimal digits of non-negative v; zero has one digit.
+func numDigits(v *big.Int) int {
+	return len(v.String())
+}
+
+// pow10 returns 10 to the power of non-negative n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// quoRoundHalfEven returns non-negative a divided by positive b rounded half to even.
+func quoRoundHalfEven(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+
+	switch new(big.Int).Lsh(r, 1).Cmp(b) {
+	case 1:
+		q.Add(q, big.NewInt(1))
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+func TestDecimal128Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	d := func(s string) Decimal128 {
+		return must.NotFail(ParseDecimal128(s))
+	}
+
+	for name, tc := range map[string]struct {
+		actual   Decimal128
+		expected string
+	}{
+		"Add":               {d("0.1").Add(d("0.2")), "0.3"},
+		"AddKeepsScale":     {d("1.50").Add(d("1")), "2.50"},
+		"AddNegativeZero":   {d("-0").Add(d("-0")), "-0"},
+		"AddZeroSign":       {d("1").Add(d("-1")), "0"},
+		"AddRoundsHalfEven": {d("1E+34").Add(d("0.5")), "1.000000000000000000000000000000000E+34"},
+		"AddInfinities":     {d("Infinity").Add(d("-Infinity")), "NaN"},
+		"Sub":               {d("10.25").Sub(d("0.25")), "10.00"},
+		"Mul":               {d("1.5").Mul(d("-2")), "-3.0"},
+		"MulOverflow":       {d("1E+6000").Mul(d("1E+6000")), "Infinity"},
+		"MulInfinityZero":   {d("Infinity").Mul(d("0")), "NaN"},
+		"Quo":               {d("1").Quo(d("3")), "0.3333333333333333333333333333333333"},
+		"QuoRoundsUp":       {d("2").Quo(d("3")), "0.6666666666666666666666666666666667"},
+		"QuoExact":          {d("10").Quo(d("4")), "2.5"},
+		"QuoPreferredScale": {d("1.00").Quo(d("1")), "1.00"},
+		"QuoByZero":         {d("-1").Quo(d("0")), "-Infinity"},
+		"QuoZeroByZero":     {d("0").Quo(d("0")), "NaN"},
+		"Rem":               {d("-7.5").Rem(d("2")), "-1.5"},
+		"RemByZero":         {d("1").Rem(d("0")), "NaN"},
+		"FromBigInt":        {NewDecimal128FromBigInt(big.NewInt(-12345), -2), "-123.45"},
+		"FromInt64":         {NewDecimal128FromInt64(math.MinInt64), "-9223372036854775808"},
+		"FromFloat64":       {NewDecimal128FromFloat64(0.1), "0.100000000000000"},
+		"FromFloat64Zero":   {NewDecimal128FromFloat64(math.Copysign(0, -1)), "-0"},
+		"FromFloat64Large":  {NewDecimal128FromFloat64(1e300), "1.00000000000000E+300"},
+		"FromFloat64Inf":    {NewDecimal128FromFloat64(math.Inf(-1)), "-Infinity"},
+		"Subnormal":         {d("1E-6176").Quo(d("10")), "0E-6176"},
+		"SubnormalHalfEven": {d("15E-6176").Mul(d("0.1")), "2E-6176"},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, tc.actual.String())
+		})
+	}
+}
+
+func TestDecimal128Compare(t *testing.T) {
+	t.Parallel()
+
+	d := func(s string) Decimal128 {
+		return must.NotFail(ParseDecimal128(s))
+	}
+
+	for name, tc := range map[string]struct {
+		a        any
+		b        any
+		expected CompareResult
+	}{
+		"SameValueDifferentScale": {d("1.50"), d("1.5"), Equal},
+		"Int32":                   {d("1.0"), int32(1), Equal},
+		"Int64":                   {int64(math.MaxInt64), d("9223372036854775807.5"), Less},
+		"Double":                  {d("0.5"), 0.5, Equal},
+		"DoubleInexact":           {d("0.1"), 0.1, Less},
+		"Infinity":                {d("Infinity"), math.Inf(1), Equal},
+		"NegativeInfinity":        {d("-Infinity"), d("-1E+6111"), Less},
+		"NaN":                     {d("NaN"), math.NaN(), Equal},
+		"NaNLessThanNumbers":      {d("NaN"), d("-Infinity"), Less},
+		"String":                  {d("1"), "1", Less},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, Compare(tc.a, tc.b))
+			assert.Equal(t, -tc.expected, Compare(tc.b, tc.a))
+		})
+	}
+
+	assert.Equal(t, 1.5, d("1.50").Float64())
+	assert.True(t, Identical(d("1.5"), d("1.5")))
+	assert.False(t, Identical(d("1.5"), d("1.50")))
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fjson
+
+import (
+	"testing"
+
+	"github.com/AlekSi/pointer"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+)
+
+var decimal128TestCases = []testCase{{
+	name: "1.50",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("1.50")))),
+	j:    `{"$n":"1.50"}`,
+}, {
+	name: "negative exponent",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("-1E-20")))),
+	j:    `{"$n":"-1E-20"}`,
+}, {
+	name: "NaN",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("NaN")))),
+	j:    `{"$n":"NaN"}`,
+}, {
+	name: "-Infinity",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("-Infinity")))),
+	j:    `{"$n":"-Infinity"}`,
+}}
+
+func TestDecimal128(t *testing.T) {
+	t.Parallel()
+	testJSON(t, decimal128TestCases, func() fjsontype { return new(decimal128Type) })
+}
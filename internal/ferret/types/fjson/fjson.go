@@ -40,6 +40,19 @@
 //	int        int32            *fjson.int32Type      JSON number
 //	timestamp  types.Timestamp  *fjson.timestampType  {"$t": "<number as string>"}
 //	long       int64            *fjson.int64Type      {"$l": "<number as string>"}
+//	decimal    types.Decimal128 *fjson.decimal128Type {"$n": "<number as string>"}
+//	minKey     types.MinKeyType *fjson.minKeyType     {"$minKey": 1}
+//	maxKey     types.MaxKeyType *fjson.maxKeyType     {"$maxKey": 1}
+//
+// Deprecated scalar types
+//
+//	Alias                types package          fjson package               JSON representation
+//
+//	undefined            types.UndefinedType    *fjson.undefinedType        {"$undefined": true}
+//	symbol               types.Symbol           *fjson.symbolType           {"$symbol": "<string>"}
+//	javascript           types.JavaScript       *fjson.javaScriptType       {"$code": "<string>"}
+//	javascriptWithScope  types.JavaScriptScope  *fjson.javaScriptScopeType  {"$code": "<string>", "$scope": <document>}
+//	dbPointer            types.DBPointer        *fjson.dbPointerType        {"$dbPointer": {"$ref": "<string>", "$id": <objectId>}}
 //
 //nolint:lll // for readability
 //nolint:dupword // false positive
@@ -94,6 +107,22 @@ func fromFJSON(v fjsontype) any {
 		return types.Timestamp(*v)
 	case *int64Type:
 		return int64(*v)
+	case *decimal128Type:
+		return types.Decimal128(*v)
+	case *minKeyType:
+		return types.MinKey
+	case *maxKeyType:
+		return types.MaxKey
+	case *undefinedType:
+		return types.Undefined
+	case *symbolType:
+		return types.Symbol(*v)
+	case *javaScriptType:
+		return types.JavaScript(*v)
+	case *javaScriptScopeType:
+		return types.JavaScriptScope(*v)
+	case *dbPointerType:
+		return types.DBPointer(*v)
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
@@ -128,6 +157,22 @@ func toFJSON(v any) fjsontype {
 		return pointer.To(timestampType(v))
 	case int64:
 		return pointer.To(int64Type(v))
+	case types.Decimal128:
+		return pointer.To(decimal128Type(v))
+	case types.MinKeyType:
+		return pointer.To(minKeyType(v))
+	case types.MaxKeyType:
+		return pointer.To(maxKeyType(v))
+	case types.UndefinedType:
+		return pointer.To(undefinedType(v))
+	case types.Symbol:
+		return pointer.To(symbolType(v))
+	case types.JavaScript:
+		return pointer.To(javaScriptType(v))
+	case types.JavaScriptScope:
+		return pointer.To(javaScriptScopeType(v))
+	case types.DBPointer:
+		return pointer.To(dbPointerType(v))
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
@@ -0,0 +1,155 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fjson
+
+import (
+	"encoding/json"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/lazyerrors"
+)
+
+// minKeyType represents BSON MinKey type.
+type minKeyType types.MinKeyType
+
+// fjsontype implements fjsontype interface.
+func (*minKeyType) fjsontype() {}
+
+// MarshalJSON implements fjsontype interface.
+func (*minKeyType) MarshalJSON() ([]byte, error) {
+	return []byte(`{"$minKey":1}`), nil
+}
+
+// maxKeyType represents BSON MaxKey type.
+type maxKeyType types.MaxKeyType
+
+// fjsontype implements fjsontype interface.
+func (*maxKeyType) fjsontype() {}
+
+// MarshalJSON implements fjsontype interface.
+func (*maxKeyType) MarshalJSON() ([]byte, error) {
+	return []byte(`{"$maxKey":1}`), nil
+}
+
+// undefinedType represents deprecated BSON Undefined type.
+type undefinedType types.UndefinedType
+
+// fjsontype implements fjsontype interface.
+func (*undefinedType) fjsontype() {}
+
+// MarshalJSON implements fjsontype interface.
+func (*undefinedType) MarshalJSON() ([]byte, error) {
+	return []byte(`{"$undefined":true}`), nil
+}
+
+// symbolType represents deprecated BSON Symbol type.
+type symbolType types.Symbol
+
+// fjsontype implements fjsontype interface.
+func (s *symbolType) fjsontype() {}
+
+// symbolJSON is a JSON object representation of the symbolType.
+type symbolJSON struct {
+	S string `json:"$symbol"`
+}
+
+// MarshalJSON implements fjsontype interface.
+func (s *symbolType) MarshalJSON() ([]byte, error) {
+	res, err := json.Marshal(symbolJSON{S: string(*s)})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	return res, nil
+}
+
+// javaScriptType represents BSON JavaScript code type.
+type javaScriptType types.JavaScript
+
+// fjsontype implements fjsontype interface.
+func (js *javaScriptType) fjsontype() {}
+
+// javaScriptJSON is a JSON object representation of the javaScriptType and javaScriptScopeType.
+type javaScriptJSON struct {
+	Code  string          `json:"$code"`
+	Scope json.RawMessage `json:"$scope,omitempty"`
+}
+
+// MarshalJSON implements fjsontype interface.
+func (js *javaScriptType) MarshalJSON() ([]byte, error) {
+	res, err := json.Marshal(javaScriptJSON{Code: string(*js)})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	return res, nil
+}
+
+// javaScriptScopeType represents deprecated BSON JavaScript code with scope type.
+type javaScriptScopeType types.JavaScriptScope
+
+// fjsontype implements fjsontype interface.
+func (js *javaScriptScopeType) fjsontype() {}
+
+// MarshalJSON implements fjsontype interface.
+func (js *javaScriptScopeType) MarshalJSON() ([]byte, error) {
+	scope, err := Marshal(js.Scope)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := json.Marshal(javaScriptJSON{Code: js.Code, Scope: scope})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	return res, nil
+}
+
+// dbPointerType represents deprecated BSON DBPointer type.
+type dbPointerType types.DBPointer
+
+// fjsontype implements fjsontype interface.
+func (p *dbPointerType) fjsontype() {}
+
+// dbPointerJSON is a JSON object representation of the dbPointerType.
+type dbPointerJSON struct {
+	Ref string          `json:"$ref"`
+	ID  json.RawMessage `json:"$id"`
+}
+
+// MarshalJSON implements fjsontype interface.
+func (p *dbPointerType) MarshalJSON() ([]byte, error) {
+	id, err := Marshal(p.ID)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := json.Marshal(struct {
+		P dbPointerJSON `json:"$dbPointer"`
+	}{P: dbPointerJSON{Ref: p.Namespace, ID: id}})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ fjsontype = (*minKeyType)(nil)
+	_ fjsontype = (*maxKeyType)(nil)
+	_ fjsontype = (*undefinedType)(nil)
+	_ fjsontype = (*symbolType)(nil)
+	_ fjsontype = (*javaScriptType)(nil)
+	_ fjsontype = (*javaScriptScopeType)(nil)
+	_ fjsontype = (*dbPointerType)(nil)
+)
@@ -74,6 +74,22 @@ func FormatAnyValue(value any) string {
 		return fmt.Sprintf("Timestamp(%v, %v)", int64(value)>>32, int32(value))
 	case int64:
 		return fmt.Sprintf("%d", value)
+	case Decimal128:
+		return fmt.Sprintf("NumberDecimal(%q)", value.String())
+	case MinKeyType:
+		return "MinKey"
+	case MaxKeyType:
+		return "MaxKey"
+	case UndefinedType:
+		return "undefined"
+	case Symbol:
+		return fmt.Sprintf(`"%v"`, string(value))
+	case JavaScript:
+		return fmt.Sprintf("Code(%q)", string(value))
+	case JavaScriptScope:
+		return fmt.Sprintf("Code(%q, %s)", value.Code, formatDocument(value.Scope))
+	case DBPointer:
+		return fmt.Sprintf("DBPointer(%q, ObjectId('%x'))", value.Namespace, value.ID)
 	default:
 		panic(fmt.Sprintf("unknown type %T", value))
 	}
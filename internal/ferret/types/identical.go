@@ -172,6 +172,22 @@ func Identical(a, b any) bool {
 		}
 
 		return a == b
+	case Decimal128:
+		b, ok := b.(Decimal128)
+		if !ok {
+			return false
+		}
+
+		return a == b
+	case MinKeyType, MaxKeyType, UndefinedType, Symbol, JavaScript, DBPointer:
+		return a == b
+	case JavaScriptScope:
+		b, ok := b.(JavaScriptScope)
+		if !ok {
+			return false
+		}
+
+		return a.Code == b.Code && Identical(a.Scope, b.Scope)
 	}
 
 	panic("not reached")
@@ -0,0 +1,33 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+type (
+	// MinKeyType represents BSON type MinKey that compares lower than all other values.
+	//
+	// Most callers should use types.MinKey value instead.
+	MinKeyType struct{}
+
+	// MaxKeyType represents BSON type MaxKey that compares higher than all other values.
+	//
+	// Most callers should use types.MaxKey value instead.
+	MaxKeyType struct{}
+)
+
+// MinKey represents BSON value MinKey.
+var MinKey = MinKeyType{}
+
+// MaxKey represents BSON value MaxKey.
+var MaxKey = MaxKeyType{}
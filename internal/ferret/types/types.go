@@ -48,6 +48,19 @@
 //	int        int32            32-bit integer
 //	timestamp  types.Timestamp  Timestamp
 //	long       int64            64-bit integer
+//	decimal    types.Decimal128 128-bit decimal floating point
+//	minKey     types.MinKeyType MinKey
+//	maxKey     types.MaxKeyType MaxKey
+//
+// Deprecated scalar types (passed by values)
+//
+//	Alias                types package          Description
+//
+//	undefined            types.UndefinedType    Undefined
+//	symbol               types.Symbol           Symbol
+//	javascript           types.JavaScript       JavaScript code
+//	javascriptWithScope  types.JavaScriptScope  JavaScript code with scope
+//	dbPointer            types.DBPointer        DBPointer
 //
 //nolint:dupword // false positive
 package types
@@ -66,7 +79,8 @@ const MaxSafeDouble = float64(1<<53 - 1) // 52bit mantissa max value = 900719925
 
 // ScalarType represents scalar type.
 type ScalarType interface {
-	float64 | string | Binary | ObjectID | bool | time.Time | NullType | Regex | int32 | Timestamp | int64
+	float64 | string | Binary | ObjectID | bool | time.Time | NullType | Regex | int32 | Timestamp | int64 | Decimal128 |
+		MinKeyType | MaxKeyType | UndefinedType | Symbol | JavaScript | JavaScriptScope | DBPointer
 }
 
 // CompositeType represents composite type - *Document or *Array.
@@ -101,7 +115,8 @@ func assertType(value any) {
 	switch value := value.(type) {
 	case *Document, *Array:
 		return
-	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64:
+	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64, Decimal128,
+		MinKeyType, MaxKeyType, UndefinedType, Symbol, JavaScript, JavaScriptScope, DBPointer:
 		return
 	case nil:
 		panic("types: unexpected nil type")
@@ -117,7 +132,8 @@ func isScalar(value any) bool {
 	assertType(value)
 
 	switch value.(type) {
-	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64:
+	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64, Decimal128,
+		MinKeyType, MaxKeyType, UndefinedType, Symbol, JavaScript, JavaScriptScope, DBPointer:
 		return true
 	}
 
@@ -178,6 +194,15 @@ func deepCopy(value any) any {
 		return value
 	case int64:
 		return value
+	case Decimal128:
+		return value
+	case MinKeyType, MaxKeyType, UndefinedType, Symbol, JavaScript, DBPointer:
+		return value
+	case JavaScriptScope:
+		return JavaScriptScope{
+			Code:  value.Code,
+			Scope: value.Scope.DeepCopy(),
+		}
 
 	default:
 		panic(fmt.Sprintf("types.deepCopy: unexpected type %[1]T (%#[1]v)", value))
@@ -297,6 +297,16 @@ func equalScalars(tb testtb.TB, v1, v2 any) bool {
 		}
 		return s1.Pattern == s2.Pattern && s1.Options == s2.Options
 
+	case types.MinKeyType, types.MaxKeyType, types.UndefinedType, types.Symbol, types.JavaScript, types.DBPointer:
+		return v1 == v2
+
+	case types.JavaScriptScope:
+		s2, ok := v2.(types.JavaScriptScope)
+		if !ok {
+			return false
+		}
+		return s1.Code == s2.Code && equalDocuments(tb, s1.Scope, s2.Scope)
+
 	case int32:
 		s2, ok := v2.(int32)
 		if !ok {
@@ -318,6 +328,13 @@ func equalScalars(tb testtb.TB, v1, v2 any) bool {
 		}
 		return s1 == s2
 
+	case types.Decimal128:
+		s2, ok := v2.(types.Decimal128)
+		if !ok {
+			return false
+		}
+		return s1 == s2
+
 	default:
 		tb.Fatalf("unhandled types %T, %T", v1, v2)
 		panic("not reached")
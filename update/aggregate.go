@@ -0,0 +1,198 @@
+package update
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/stages"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AggregateOptions configures Aggregate.
+type AggregateOptions struct {
+	// Rand is the random source used by stages like $sample and by $rand and $sampleRate.
+	// Set it to a seeded source to get reproducible results.
+	// If nil, a new time-seeded source is used.
+	Rand *rand.Rand
+
+	// Collections are the in-memory collections that stages like $unionWith can read, by name.
+	// Unknown collections are treated as empty, like in MongoDB.
+	Collections map[string][]bson.D
+
+	// Let defines user variables that expressions can access as $$name,
+	// like the let option of the aggregate command.
+	// Values are expressions evaluated once, before the pipeline runs.
+	Let bson.D
+
+	// Now returns the value of $$NOW and $$CLUSTER_TIME.
+	// It is called once per Aggregate call.
+	// If nil, time.Now is used.
+	Now func() time.Time
+
+	// Collation defines how $match and $sort compare strings,
+	// like the collation option of the aggregate command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+
+	// JavaScript enables $where and $function, see JavaScriptOptions.
+	// If nil, JavaScript is disabled and they return an error,
+	// so filters and pipelines from untrusted input can't run code.
+	JavaScript *JavaScriptOptions
+}
+
+// Aggregate runs the aggregation pipeline against the provided documents
+// and returns the resulting documents.
+//
+// The pipeline must conform to the mongodb Aggregation Pipeline spec
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation-pipeline/
+//
+// A pipeline that starts with $documents does not need any input documents,
+// like db.aggregate() in mongo.
+//
+// opts may be nil.
+func Aggregate(documents []bson.D, pipeline []bson.D, opts *AggregateOptions) ([]bson.D, error) {
+	return aggregate(documents, pipeline, opts, nil)
+}
+
+// aggregate implements Aggregate.
+// meta may be nil; otherwise, it is available to the pipeline stages.
+func aggregate(documents []bson.D, pipeline []bson.D, opts *AggregateOptions, meta *operators.Metadata) ([]bson.D, error) {
+	if opts == nil {
+		opts = new(AggregateOptions)
+	}
+
+	docs, err := convertDsToDocuments(documents)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert documents")
+	}
+
+	stageDocs, err := convertDsToDocuments(pipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert pipeline")
+	}
+
+	stageValues := make([]any, len(stageDocs))
+	for i, d := range stageDocs {
+		stageValues[i] = d
+	}
+
+	stageArr, err := types.NewArray(stageValues...)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert pipeline")
+	}
+
+	p, err := stages.NewPipeline(stageArr, "aggregate")
+	if err != nil {
+		return nil, err
+	}
+
+	coll, err := newCollation(opts.Collation)
+	if err != nil {
+		return nil, err
+	}
+
+	aggOpts := &aggregations.Options{
+		Rand:        opts.Rand,
+		Collections: make(map[string][]*types.Document, len(opts.Collections)),
+		Collation:   coll,
+	}
+
+	// stages and operators share the source, so a seeded source gives reproducible results
+	aggOpts.Rand = aggOpts.RandSource()
+
+	for name, coll := range opts.Collections {
+		if aggOpts.Collections[name], err = convertDsToDocuments(coll); err != nil {
+			return nil, errors.Wrapf(err, "convert collection %q", name)
+		}
+	}
+
+	vars, err := newVariables(opts.Now, opts.Let)
+	if err != nil {
+		return nil, err
+	}
+
+	// $geoNear records distances in metadata
+	if meta == nil {
+		meta = new(operators.Metadata)
+	}
+
+	meta.JavaScript = opts.JavaScript.newEvaluator()
+	meta.Rand = aggOpts.Rand
+	meta.Collation = coll
+	vars = vars.WithMetadata(meta)
+
+	ctx := aggregations.WithOptions(context.Background(), aggOpts)
+	ctx = operators.WithVariables(ctx, vars)
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	var iter types.DocumentsIterator = iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	if iter, err = p.Process(ctx, iter, closer); err != nil {
+		return nil, err
+	}
+
+	res, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDocumentsToDs(res)
+}
+
+// newVariables returns the top-level scope of variables for the given clock and let document.
+func newVariables(now func() time.Time, let bson.D) (*operators.Variables, error) {
+	if now == nil {
+		now = time.Now
+	}
+
+	var letDoc *types.Document
+
+	if let != nil {
+		var err error
+		if letDoc, err = convertDToDocument(let); err != nil {
+			return nil, errors.Wrap(err, "convert let")
+		}
+	}
+
+	return operators.NewVariables(now(), letDoc)
+}
+
+func convertDsToDocuments(ds []bson.D) ([]*types.Document, error) {
+	docs := make([]*types.Document, len(ds))
+
+	for i, d := range ds {
+		doc, err := convertDToDocument(d)
+		if err != nil {
+			return nil, err
+		}
+
+		docs[i] = doc
+	}
+
+	return docs, nil
+}
+
+func convertDocumentsToDs(docs []*types.Document) ([]bson.D, error) {
+	ds := make([]bson.D, len(docs))
+
+	for i, doc := range docs {
+		d, err := convertDocumentToD(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		ds[i] = d
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,2263 @@
+package update_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+)
+
+func TestAggregate(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"v", "a"}},
+		{{"_id", int32(2)}, {"v", "b"}},
+		{{"_id", int32(3)}, {"v", "c"}},
+	}
+	collections := map[string][]bson.D{
+		"other": {
+			{{"_id", int32(10)}, {"v", "x"}},
+			{{"_id", int32(11)}, {"v", "y"}},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		documents        []bson.D
+		pipeline         []bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:      "empty pipeline",
+			documents: input,
+			pipeline:  []bson.D{},
+			expected:  input,
+		},
+		{
+			name: "documents without input",
+			pipeline: []bson.D{
+				{{"$documents", bson.A{bson.D{{"a", int32(1)}}, bson.D{{"a", int32(2)}}}}},
+				{{"$match", bson.D{{"a", int32(2)}}}},
+			},
+			expected: []bson.D{{{"a", int32(2)}}},
+		},
+		{
+			name:      "documents ignores input",
+			documents: input,
+			pipeline: []bson.D{
+				{{"$documents", bson.A{bson.D{{"a", int32(1)}}}}},
+			},
+			expected: []bson.D{{{"a", int32(1)}}},
+		},
+		{
+			name: "documents not first",
+			pipeline: []bson.D{
+				{{"$match", bson.D{}}},
+				{{"$documents", bson.A{}}},
+			},
+			shouldContainErr: "Location40602 (40602): $documents is only valid as the first stage in a pipeline",
+		},
+		{
+			name: "documents not array",
+			pipeline: []bson.D{
+				{{"$documents", int32(1)}},
+			},
+			shouldContainErr: "an array is expected",
+		},
+		{
+			name: "documents element not object",
+			pipeline: []bson.D{
+				{{"$documents", bson.A{int32(1)}}},
+			},
+			shouldContainErr: "$documents elements must be objects",
+		},
+		{
+			name:      "unionWith collection name",
+			documents: input[:1],
+			pipeline: []bson.D{
+				{{"$unionWith", "other"}},
+			},
+			expected: []bson.D{input[0], collections["other"][0], collections["other"][1]},
+		},
+		{
+			name:      "unionWith pipeline",
+			documents: input[:1],
+			pipeline: []bson.D{
+				{{"$unionWith", bson.D{
+					{"coll", "other"},
+					{"pipeline", bson.A{bson.D{{"$match", bson.D{{"v", "y"}}}}}},
+				}}},
+				{{"$project", bson.D{{"_id", int32(1)}}}},
+			},
+			expected: []bson.D{{{"_id", int32(1)}}, {{"_id", int32(11)}}},
+		},
+		{
+			name:      "unionWith unknown collection",
+			documents: input[:1],
+			pipeline: []bson.D{
+				{{"$unionWith", "missing"}},
+			},
+			expected: input[:1],
+		},
+		{
+			name: "unionWith documents without collection",
+			pipeline: []bson.D{
+				{{"$documents", bson.A{bson.D{{"a", int32(1)}}}}},
+				{{"$unionWith", bson.D{
+					{"pipeline", bson.A{bson.D{{"$documents", bson.A{bson.D{{"a", int32(2)}}}}}}},
+				}}},
+			},
+			expected: []bson.D{{{"a", int32(1)}}, {{"a", int32(2)}}},
+		},
+		{
+			name: "unionWith without collection and documents",
+			pipeline: []bson.D{
+				{{"$unionWith", bson.D{{"pipeline", bson.A{}}}}},
+			},
+			shouldContainErr: "must have a pipeline with $documents as first stage",
+		},
+		{
+			name: "unionWith unknown field",
+			pipeline: []bson.D{
+				{{"$unionWith", bson.D{{"coll", "other"}, {"foo", int32(1)}}}},
+			},
+			shouldContainErr: "BSON field '$unionWith.foo' is an unknown field.",
+		},
+		{
+			name: "unionWith wrong type",
+			pipeline: []bson.D{
+				{{"$unionWith", int32(1)}},
+			},
+			shouldContainErr: "the $unionWith stage specification must be an object or string, but found int",
+		},
+		{
+			name:      "sample more than available",
+			documents: input,
+			pipeline: []bson.D{
+				{{"$sample", bson.D{{"size", int32(10)}}}},
+				{{"$sort", bson.D{{"_id", int32(1)}}}},
+			},
+			expected: input,
+		},
+		{
+			name:      "sample zero",
+			documents: input,
+			pipeline: []bson.D{
+				{{"$sample", bson.D{{"size", int32(0)}}}},
+			},
+			expected: []bson.D{},
+		},
+		{
+			name: "sample without size",
+			pipeline: []bson.D{
+				{{"$sample", bson.D{}}},
+			},
+			shouldContainErr: "$sample stage must specify a size",
+		},
+		{
+			name: "sample negative size",
+			pipeline: []bson.D{
+				{{"$sample", bson.D{{"size", int32(-1)}}}},
+			},
+			shouldContainErr: "size argument to $sample must not be negative",
+		},
+		{
+			name: "sample size not a number",
+			pipeline: []bson.D{
+				{{"$sample", bson.D{{"size", "1"}}}},
+			},
+			shouldContainErr: "size argument to $sample must be a number",
+		},
+		{
+			name:      "match expr",
+			documents: input,
+			pipeline: []bson.D{
+				{{"$match", bson.D{{"$expr", bson.D{{"$and", bson.A{
+					bson.D{{"$gt", bson.A{"$_id", int32(1)}}},
+					bson.D{{"$ne", bson.A{"$v", "c"}}},
+				}}}}}}},
+			},
+			expected: []bson.D{{{"_id", int32(2)}, {"v", "b"}}},
+		},
+		{
+			name:      "project cond",
+			documents: input,
+			pipeline: []bson.D{
+				{{"$project", bson.D{{"big", bson.D{{"$cond", bson.A{bson.D{{"$gte", bson.A{"$_id", int32(2)}}}, "yes", "no"}}}}}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"big", "no"}},
+				{{"_id", int32(2)}, {"big", "yes"}},
+				{{"_id", int32(3)}, {"big", "yes"}},
+			},
+		},
+		{
+			name: "sample unknown option",
+			pipeline: []bson.D{
+				{{"$sample", bson.D{{"size", int32(1)}, {"foo", int32(1)}}}},
+			},
+			shouldContainErr: "unrecognized option to $sample: foo",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Aggregate(tc.documents, tc.pipeline, &self.AggregateOptions{
+				Rand:        rand.New(rand.NewSource(1)),
+				Collections: collections,
+			})
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestAggregateSampleSeeded(t *testing.T) {
+	var input []bson.D
+	for i := int32(0); i < 100; i++ {
+		input = append(input, bson.D{{"_id", i}})
+	}
+
+	pipeline := []bson.D{{{"$sample", bson.D{{"size", int32(5)}}}}}
+
+	first, err := self.Aggregate(input, pipeline, &self.AggregateOptions{Rand: rand.New(rand.NewSource(42))})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, first, test.ShouldHaveLength, 5)
+
+	second, err := self.Aggregate(input, pipeline, &self.AggregateOptions{Rand: rand.New(rand.NewSource(42))})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, second, test.ShouldResemble, first)
+
+	seen := map[int32]struct{}{}
+	for _, doc := range first {
+		seen[doc[0].Value.(int32)] = struct{}{}
+	}
+	test.That(t, seen, test.ShouldHaveLength, 5)
+}
+
+func TestAggregateRandomSeeded(t *testing.T) {
+	var input []bson.D
+	for i := int32(0); i < 100; i++ {
+		input = append(input, bson.D{{"_id", i}})
+	}
+
+	pipeline := []bson.D{
+		{{"$match", bson.D{{"$sampleRate", 0.5}}}},
+		{{"$set", bson.D{{"r", bson.D{{"$rand", bson.D{}}}}}}},
+	}
+
+	res, err := self.Aggregate(input, pipeline, &self.AggregateOptions{Rand: rand.New(rand.NewSource(42))})
+	test.That(t, err, test.ShouldBeNil)
+
+	// replay the source: each document takes one number for $sampleRate, and each sampled one takes another for $rand
+	r := rand.New(rand.NewSource(42))
+
+	var expected []bson.D
+	for _, doc := range input {
+		if r.Float64() < 0.5 {
+			expected = append(expected, bson.D{doc[0], {"r", r.Float64()}})
+		}
+	}
+
+	test.That(t, res, test.ShouldResemble, expected)
+	test.That(t, len(res), test.ShouldBeBetween, 30, 70)
+
+	for _, tc := range []struct {
+		name             string
+		pipeline         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:             "rand arguments",
+			pipeline:         []bson.D{{{"$set", bson.D{{"r", bson.D{{"$rand", bson.D{{"seed", int32(1)}}}}}}}}},
+			shouldContainErr: "$rand does not currently accept arguments",
+		},
+		{
+			name:             "sampleRate type",
+			pipeline:         []bson.D{{{"$match", bson.D{{"$sampleRate", "0.5"}}}}},
+			shouldContainErr: "argument to $sampleRate must be a numeric type",
+		},
+		{
+			name:             "sampleRate range",
+			pipeline:         []bson.D{{{"$match", bson.D{{"$sampleRate", 1.5}}}}},
+			shouldContainErr: "numeric argument to $sampleRate must be in [0, 1]",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := self.Aggregate(input, tc.pipeline, nil)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+		})
+	}
+}
+
+func TestAggregateUnwind(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", bson.A{"S", "M"}}, {"price", int32(5)}},
+		{{"_id", int32(2)}, {"item", "EFG"}, {"sizes", bson.A{}}},
+		{{"_id", int32(3)}, {"item", "IJK"}, {"sizes", "M"}},
+		{{"_id", int32(4)}, {"item", "LMN"}},
+		{{"_id", int32(5)}, {"item", "XYZ"}, {"sizes", nil}},
+		{{"_id", int32(6)}, {"item", "NST"}, {"info", bson.D{{"sizes", bson.A{"L"}}, {"color", "red"}}}},
+	}
+
+	tests := []struct {
+		name             string
+		unwind           any
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:   "string path",
+			unwind: "$sizes",
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "S"}, {"price", int32(5)}},
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "M"}, {"price", int32(5)}},
+				{{"_id", int32(3)}, {"item", "IJK"}, {"sizes", "M"}},
+			},
+		},
+		{
+			name:   "document path",
+			unwind: bson.D{{"path", "$sizes"}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "S"}, {"price", int32(5)}},
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "M"}, {"price", int32(5)}},
+				{{"_id", int32(3)}, {"item", "IJK"}, {"sizes", "M"}},
+			},
+		},
+		{
+			name:   "embedded path",
+			unwind: bson.D{{"path", "$info.sizes"}},
+			expected: []bson.D{
+				{{"_id", int32(6)}, {"item", "NST"}, {"info", bson.D{{"sizes", "L"}, {"color", "red"}}}},
+			},
+		},
+		{
+			name:   "includeArrayIndex",
+			unwind: bson.D{{"path", "$sizes"}, {"includeArrayIndex", "arrayIndex"}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "S"}, {"price", int32(5)}, {"arrayIndex", int64(0)}},
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "M"}, {"price", int32(5)}, {"arrayIndex", int64(1)}},
+				{{"_id", int32(3)}, {"item", "IJK"}, {"sizes", "M"}, {"arrayIndex", nil}},
+			},
+		},
+		{
+			name:   "preserveNullAndEmptyArrays",
+			unwind: bson.D{{"path", "$sizes"}, {"preserveNullAndEmptyArrays", true}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "S"}, {"price", int32(5)}},
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "M"}, {"price", int32(5)}},
+				{{"_id", int32(2)}, {"item", "EFG"}},
+				{{"_id", int32(3)}, {"item", "IJK"}, {"sizes", "M"}},
+				{{"_id", int32(4)}, {"item", "LMN"}},
+				{{"_id", int32(5)}, {"item", "XYZ"}, {"sizes", nil}},
+				{{"_id", int32(6)}, {"item", "NST"}, {"info", bson.D{{"sizes", bson.A{"L"}}, {"color", "red"}}}},
+			},
+		},
+		{
+			name: "both options",
+			unwind: bson.D{
+				{"path", "$sizes"}, {"includeArrayIndex", "idx.n"}, {"preserveNullAndEmptyArrays", true},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "S"}, {"price", int32(5)}, {"idx", bson.D{{"n", int64(0)}}}},
+				{{"_id", int32(1)}, {"item", "ABC"}, {"sizes", "M"}, {"price", int32(5)}, {"idx", bson.D{{"n", int64(1)}}}},
+				{{"_id", int32(2)}, {"item", "EFG"}, {"idx", bson.D{{"n", nil}}}},
+				{{"_id", int32(3)}, {"item", "IJK"}, {"sizes", "M"}, {"idx", bson.D{{"n", nil}}}},
+				{{"_id", int32(4)}, {"item", "LMN"}, {"idx", bson.D{{"n", nil}}}},
+				{{"_id", int32(5)}, {"item", "XYZ"}, {"sizes", nil}, {"idx", bson.D{{"n", nil}}}},
+				{{"_id", int32(6)}, {"item", "NST"}, {"info", bson.D{{"sizes", bson.A{"L"}}, {"color", "red"}}}, {"idx", bson.D{{"n", nil}}}},
+			},
+		},
+		{
+			name:             "missing path",
+			unwind:           bson.D{{"includeArrayIndex", "i"}},
+			shouldContainErr: "no path specified to $unwind stage",
+		},
+		{
+			name:             "path not string",
+			unwind:           bson.D{{"path", int32(1)}},
+			shouldContainErr: "expected a string as the path for $unwind stage, got int",
+		},
+		{
+			name:             "path without prefix",
+			unwind:           bson.D{{"path", "sizes"}},
+			shouldContainErr: "path option to $unwind stage should be prefixed with a '$': sizes",
+		},
+		{
+			name:             "preserveNullAndEmptyArrays not bool",
+			unwind:           bson.D{{"path", "$sizes"}, {"preserveNullAndEmptyArrays", int32(1)}},
+			shouldContainErr: "expected a boolean for the preserveNullAndEmptyArrays option to $unwind stage, got int",
+		},
+		{
+			name:             "includeArrayIndex empty",
+			unwind:           bson.D{{"path", "$sizes"}, {"includeArrayIndex", ""}},
+			shouldContainErr: "expected a non-empty string for the includeArrayIndex  option to $unwind stage, got string",
+		},
+		{
+			name:             "includeArrayIndex prefix",
+			unwind:           bson.D{{"path", "$sizes"}, {"includeArrayIndex", "$i"}},
+			shouldContainErr: "includeArrayIndex option to $unwind stage should not be prefixed with a '$': $i",
+		},
+		{
+			name:             "unknown option",
+			unwind:           bson.D{{"path", "$sizes"}, {"foo", true}},
+			shouldContainErr: "unrecognized option to $unwind stage: foo",
+		},
+		{
+			name:             "wrong type",
+			unwind:           int32(1),
+			shouldContainErr: "expected either a string or an object as specification for $unwind stage, got int",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Aggregate(input, []bson.D{{{"$unwind", tc.unwind}}}, nil)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestAggregateProject(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"item", "abc"}, {"sizes", bson.A{bson.D{{"s", "M"}}, bson.D{{"s", "L"}}}}, {"qty", int32(3)}},
+		{{"item", "xyz"}, {"qty", int32(0)}},
+	}
+
+	tests := []struct {
+		name             string
+		project          bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:    "computed fields after included fields",
+			project: bson.D{{"double", bson.D{{"$multiply", bson.A{"$qty", int32(2)}}}}, {"item", int32(1)}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"item", "abc"}, {"double", int32(6)}},
+				{{"item", "xyz"}, {"double", int32(0)}},
+			},
+		},
+		{
+			name:    "field path",
+			project: bson.D{{"_id", int32(0)}, {"name", "$item"}, {"first", "$sizes.s"}},
+			expected: []bson.D{
+				{{"name", "abc"}, {"first", bson.A{"M", "L"}}},
+				{{"name", "xyz"}},
+			},
+		},
+		{
+			name: "literal",
+			project: bson.D{
+				{"_id", int32(0)},
+				{"price", bson.D{{"$literal", "$1"}}},
+				{"flag", bson.D{{"$literal", true}}},
+				{"arr", bson.D{{"$literal", bson.A{"$item", int32(1)}}}},
+				{"obj", bson.D{{"$literal", bson.D{{"$add", int32(1)}}}}},
+			},
+			expected: []bson.D{
+				{{"price", "$1"}, {"flag", true}, {"arr", bson.A{"$item", int32(1)}}, {"obj", bson.D{{"$add", int32(1)}}}},
+				{{"price", "$1"}, {"flag", true}, {"arr", bson.A{"$item", int32(1)}}, {"obj", bson.D{{"$add", int32(1)}}}},
+			},
+		},
+		{
+			name:    "nested projection",
+			project: bson.D{{"_id", false}, {"info", bson.D{{"name", "$item"}, {"n", bson.D{{"$add", bson.A{"$qty", int32(1)}}}}}}},
+			expected: []bson.D{
+				{{"info", bson.D{{"name", "abc"}, {"n", int32(4)}}}},
+				{{"info", bson.D{{"name", "xyz"}, {"n", int32(1)}}}},
+			},
+		},
+		{
+			name:    "dotted computed field in array",
+			project: bson.D{{"sizes.item", "$item"}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"sizes", bson.A{bson.D{{"item", "abc"}}, bson.D{{"item", "abc"}}}}},
+				{{"sizes", bson.D{{"item", "xyz"}}}},
+			},
+		},
+		{
+			name:    "dotted computed field with included field",
+			project: bson.D{{"sizes.item", "$item"}, {"sizes.s", int32(1)}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"sizes", bson.A{bson.D{{"s", "M"}, {"item", "abc"}}, bson.D{{"s", "L"}, {"item", "abc"}}}}},
+				{{"sizes", bson.D{{"item", "xyz"}}}},
+			},
+		},
+		{
+			name:    "computed _id",
+			project: bson.D{{"_id", bson.D{{"$toUpper", "$item"}}}, {"qty", true}},
+			expected: []bson.D{
+				{{"_id", "ABC"}, {"qty", int32(3)}},
+				{{"_id", "XYZ"}, {"qty", int32(0)}},
+			},
+		},
+		{
+			name:             "empty sub-projection",
+			project:          bson.D{{"info", bson.D{}}},
+			shouldContainErr: "An empty sub-projection is not a valid value",
+		},
+		{
+			name:     "literal arguments",
+			project:  bson.D{{"x", bson.D{{"$literal", bson.A{int32(1), int32(2)}}}}, {"y", bson.D{{"$literal", nil}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"x", bson.A{int32(1), int32(2)}}, {"y", nil}}, {{"x", bson.A{int32(1), int32(2)}}, {"y", nil}}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Aggregate(input, []bson.D{{{"$project", tc.project}}}, nil)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+// evaluateExpression evaluates the aggregation expression against the document
+// with $addFields and returns the result.
+func evaluateExpression(t *testing.T, doc bson.D, expression any) (any, error) {
+	t.Helper()
+
+	if doc == nil {
+		doc = bson.D{}
+	}
+
+	res, err := self.Aggregate([]bson.D{doc}, []bson.D{
+		{{"$addFields", bson.D{{"result", expression}}}},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	test.That(t, res, test.ShouldHaveLength, 1)
+
+	for _, e := range res[0] {
+		if e.Key == "result" {
+			return e.Value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type expressionTestCase struct {
+	name             string
+	doc              bson.D
+	expression       any
+	expected         any
+	shouldContainErr string
+}
+
+func runExpressionTests(t *testing.T, tests []expressionTestCase) {
+	t.Helper()
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := evaluateExpression(t, tc.doc, tc.expression)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestAggregateArithmeticOperators(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	runExpressionTests(t, []expressionTestCase{
+		{name: "add ints", doc: bson.D{{"a", int32(1)}}, expression: bson.D{{"$add", bson.A{"$a", int32(2)}}}, expected: int32(3)},
+		{name: "add int overflow", expression: bson.D{{"$add", bson.A{int32(math.MaxInt32), int32(1)}}}, expected: int64(math.MaxInt32) + 1},
+		{name: "add long overflow", expression: bson.D{{"$add", bson.A{int64(math.MaxInt64), int64(1)}}}, expected: float64(math.MaxInt64) + 1},
+		{name: "add double", expression: bson.D{{"$add", bson.A{int32(1), 1.5}}}, expected: 2.5},
+		{name: "add missing", expression: bson.D{{"$add", bson.A{"$missing", int32(1)}}}, expected: nil},
+		{name: "add empty", expression: bson.D{{"$add", bson.A{}}}, expected: int32(0)},
+		{
+			name:       "add date",
+			doc:        bson.D{{"d", date}},
+			expression: bson.D{{"$add", bson.A{"$d", int32(1000)}}},
+			expected:   primitive.NewDateTimeFromTime(date.Add(time.Second)),
+		},
+		{
+			name:             "add two dates",
+			doc:              bson.D{{"d", date}},
+			expression:       bson.D{{"$add", bson.A{"$d", "$d"}}},
+			shouldContainErr: "only one date allowed in an $add expression",
+		},
+		{
+			name:             "add string",
+			doc:              bson.D{{"s", "x"}},
+			expression:       bson.D{{"$add", bson.A{"$s", int32(1)}}},
+			shouldContainErr: "$add only supports numeric or date types, not string",
+		},
+		{name: "subtract", expression: bson.D{{"$subtract", bson.A{int32(5), int32(7)}}}, expected: int32(-2)},
+		{name: "subtract int overflow", expression: bson.D{{"$subtract", bson.A{int32(math.MinInt32), int32(1)}}}, expected: int64(math.MinInt32) - 1},
+		{
+			name:       "subtract dates",
+			doc:        bson.D{{"d", date}},
+			expression: bson.D{{"$subtract", bson.A{"$d", bson.D{{"$add", bson.A{"$d", int32(-1500)}}}}}},
+			expected:   int64(1500),
+		},
+		{
+			name:       "subtract from date",
+			doc:        bson.D{{"d", date}},
+			expression: bson.D{{"$subtract", bson.A{"$d", int64(60000)}}},
+			expected:   primitive.NewDateTimeFromTime(date.Add(-time.Minute)),
+		},
+		{
+			name:             "subtract date from number",
+			doc:              bson.D{{"d", date}},
+			expression:       bson.D{{"$subtract", bson.A{int32(1), "$d"}}},
+			shouldContainErr: "can't $subtract date from int",
+		},
+		{
+			name:             "subtract wrong args",
+			expression:       bson.D{{"$subtract", bson.A{int32(1)}}},
+			shouldContainErr: "Expression $subtract takes exactly 2 arguments. 1 were passed in.",
+		},
+		{name: "multiply", expression: bson.D{{"$multiply", bson.A{int32(2), int32(3), int64(4)}}}, expected: int64(24)},
+		{name: "multiply overflow", expression: bson.D{{"$multiply", bson.A{int32(math.MaxInt32), int32(2)}}}, expected: int64(math.MaxInt32) * 2},
+		{name: "multiply null", expression: bson.D{{"$multiply", bson.A{int32(2), nil}}}, expected: nil},
+		{name: "divide", expression: bson.D{{"$divide", bson.A{int32(7), int32(2)}}}, expected: 3.5},
+		{name: "divide by zero", expression: bson.D{{"$divide", bson.A{int32(1), int32(0)}}}, shouldContainErr: "can't $divide by zero"},
+		{
+			name:             "divide string",
+			expression:       bson.D{{"$divide", bson.A{"a", int32(2)}}},
+			shouldContainErr: "$divide only supports numeric types, not string and int",
+		},
+		{name: "mod", expression: bson.D{{"$mod", bson.A{int32(7), int32(3)}}}, expected: int32(1)},
+		{name: "mod long", expression: bson.D{{"$mod", bson.A{int64(-7), int32(3)}}}, expected: int64(-1)},
+		{name: "mod double", expression: bson.D{{"$mod", bson.A{7.5, int32(2)}}}, expected: 1.5},
+		{name: "mod by zero", expression: bson.D{{"$mod", bson.A{int32(1), int32(0)}}}, shouldContainErr: "can't $mod by zero"},
+		{name: "abs", expression: bson.D{{"$abs", int32(-5)}}, expected: int32(5)},
+		{name: "abs int min", expression: bson.D{{"$abs", int32(math.MinInt32)}}, expected: -int64(math.MinInt32)},
+		{name: "abs long min", expression: bson.D{{"$abs", int64(math.MinInt64)}}, shouldContainErr: "can't take $abs of long long min"},
+		{name: "abs array form", expression: bson.D{{"$abs", bson.A{-2.5}}}, expected: 2.5},
+		{name: "abs string", expression: bson.D{{"$abs", "a"}}, shouldContainErr: "$abs only supports numeric types, not string"},
+		{name: "abs missing", expression: bson.D{{"$abs", "$missing"}}, expected: nil},
+		{name: "ceil", expression: bson.D{{"$ceil", 1.2}}, expected: 2.0},
+		{name: "ceil int", expression: bson.D{{"$ceil", int64(3)}}, expected: int64(3)},
+		{name: "floor", expression: bson.D{{"$floor", -1.2}}, expected: -2.0},
+		{name: "sqrt", expression: bson.D{{"$sqrt", int32(16)}}, expected: 4.0},
+		{name: "sqrt negative", expression: bson.D{{"$sqrt", int32(-1)}}, shouldContainErr: "$sqrt's argument must be greater than or equal to 0"},
+		{name: "exp", expression: bson.D{{"$exp", int32(0)}}, expected: 1.0},
+		{name: "ln", expression: bson.D{{"$ln", int32(1)}}, expected: 0.0},
+		{name: "ln zero", expression: bson.D{{"$ln", int32(0)}}, shouldContainErr: "$ln's argument must be a positive number, but is 0"},
+		{name: "log10", expression: bson.D{{"$log10", int32(1000)}}, expected: 3.0},
+		{name: "log", expression: bson.D{{"$log", bson.A{int32(8), int32(2)}}}, expected: 3.0},
+		{name: "log base one", expression: bson.D{{"$log", bson.A{int32(8), int32(1)}}}, shouldContainErr: "$log's base must be a positive number not equal to 1"},
+		{name: "pow", expression: bson.D{{"$pow", bson.A{int32(2), int32(10)}}}, expected: int32(1024)},
+		{name: "pow int overflow", expression: bson.D{{"$pow", bson.A{int32(2), int32(40)}}}, expected: int64(1) << 40},
+		{name: "pow long overflow", expression: bson.D{{"$pow", bson.A{int64(2), int32(70)}}}, expected: math.Pow(2, 70)},
+		{name: "pow negative exponent", expression: bson.D{{"$pow", bson.A{int32(2), int32(-1)}}}, expected: 0.5},
+		{name: "pow minus one", expression: bson.D{{"$pow", bson.A{int32(-1), int32(-3)}}}, expected: int32(-1)},
+		{name: "pow zero base", expression: bson.D{{"$pow", bson.A{int32(0), int32(-1)}}}, shouldContainErr: "$pow cannot take a base of 0 and a negative exponent"},
+		{name: "round", expression: bson.D{{"$round", bson.A{2.675, int32(2)}}}, expected: 2.68},
+		{name: "round half to even", expression: bson.D{{"$round", bson.A{2.5}}}, expected: 2.0},
+		{name: "round int negative place", expression: bson.D{{"$round", bson.A{int32(1250), int32(-2)}}}, expected: int32(1200)},
+		{name: "round bad place", expression: bson.D{{"$round", bson.A{1.5, 1.5}}}, shouldContainErr: "precision argument to $round must be a integral value"},
+		{name: "round place out of range", expression: bson.D{{"$round", bson.A{1.5, int32(101)}}}, shouldContainErr: "value must be in [-20, 100]"},
+		{name: "trunc", expression: bson.D{{"$trunc", bson.A{-2.678, int32(2)}}}, expected: -2.67},
+		{name: "trunc int negative place", expression: bson.D{{"$trunc", bson.A{int64(1299), int32(-2)}}}, expected: int64(1200)},
+		{
+			name:       "nested",
+			doc:        bson.D{{"a", int32(3)}, {"b", int32(4)}},
+			expression: bson.D{{"$sqrt", bson.D{{"$add", bson.A{bson.D{{"$multiply", bson.A{"$a", "$a"}}}, bson.D{{"$pow", bson.A{"$b", int32(2)}}}}}}}},
+			expected:   5.0,
+		},
+	})
+}
+
+func TestAggregateStringOperators(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	runExpressionTests(t, []expressionTestCase{
+		{name: "concat", doc: bson.D{{"a", "foo"}}, expression: bson.D{{"$concat", bson.A{"$a", "-", "bar"}}}, expected: "foo-bar"},
+		{name: "concat null", expression: bson.D{{"$concat", bson.A{"a", nil}}}, expected: nil},
+		{name: "concat missing", expression: bson.D{{"$concat", bson.A{"a", "$missing"}}}, expected: nil},
+		{name: "concat int", expression: bson.D{{"$concat", bson.A{"a", int32(1)}}}, shouldContainErr: "$concat only supports strings, not int"},
+		{name: "toLower", expression: bson.D{{"$toLower", "HeLLo ÉÀ"}}, expected: "hello ÉÀ"},
+		{name: "toUpper", expression: bson.D{{"$toUpper", "héllo"}}, expected: "HéLLO"},
+		{name: "toUpper number", expression: bson.D{{"$toUpper", int32(42)}}, expected: "42"},
+		{name: "toLower null", expression: bson.D{{"$toLower", nil}}, expected: ""},
+		{name: "toUpper date", expression: bson.D{{"$toUpper", date}}, expected: "2024-01-02T03:04:05.000Z"},
+		{name: "toLower array", expression: bson.D{{"$toLower", bson.A{bson.A{}}}}, shouldContainErr: "can't convert from BSON type array to String"},
+		{name: "strcasecmp", expression: bson.D{{"$strcasecmp", bson.A{"ABC", "abc"}}}, expected: int32(0)},
+		{name: "strcasecmp less", expression: bson.D{{"$strcasecmp", bson.A{"abc", "ABD"}}}, expected: int32(-1)},
+		{name: "strLenCP", expression: bson.D{{"$strLenCP", "héllo"}}, expected: int32(5)},
+		{name: "strLenBytes", expression: bson.D{{"$strLenBytes", "héllo"}}, expected: int32(6)},
+		{name: "strLenCP emoji", expression: bson.D{{"$strLenCP", "a😀"}}, expected: int32(2)},
+		{name: "strLenBytes emoji", expression: bson.D{{"$strLenBytes", "a😀"}}, expected: int32(5)},
+		{name: "strLenCP missing", expression: bson.D{{"$strLenCP", "$missing"}}, shouldContainErr: "$strLenCP requires a string argument, found: missing"},
+		{name: "substrCP", expression: bson.D{{"$substrCP", bson.A{"héllo", int32(1), int32(3)}}}, expected: "éll"},
+		{name: "substrCP past end", expression: bson.D{{"$substrCP", bson.A{"héllo", int32(3), int32(10)}}}, expected: "lo"},
+		{name: "substrCP double index", expression: bson.D{{"$substrCP", bson.A{"hello", 1.0, 2.0}}}, expected: "el"},
+		{
+			name:             "substrCP fractional index",
+			expression:       bson.D{{"$substrCP", bson.A{"hello", 1.5, int32(2)}}},
+			shouldContainErr: "$substrCP: starting index cannot be represented as a 32-bit integral value",
+		},
+		{
+			name:             "substrCP negative length",
+			expression:       bson.D{{"$substrCP", bson.A{"hello", int32(1), int32(-2)}}},
+			shouldContainErr: "$substrCP: length must be a nonnegative integer.",
+		},
+		{name: "substrBytes", expression: bson.D{{"$substrBytes", bson.A{"héllo", int32(1), int32(2)}}}, expected: "é"},
+		{name: "substrBytes negative length", expression: bson.D{{"$substrBytes", bson.A{"héllo", int32(3), int32(-1)}}}, expected: "llo"},
+		{
+			name:             "substrBytes continuation start",
+			expression:       bson.D{{"$substrBytes", bson.A{"héllo", int32(2), int32(2)}}},
+			shouldContainErr: "starting index is a UTF-8 continuation byte",
+		},
+		{
+			name:             "substrBytes continuation end",
+			expression:       bson.D{{"$substrBytes", bson.A{"héllo", int32(0), int32(2)}}},
+			shouldContainErr: "ending index is in the middle of a UTF-8 character",
+		},
+		{name: "trim", expression: bson.D{{"$trim", bson.D{{"input", " \t hi \n"}}}}, expected: "hi"},
+		{name: "trim chars", expression: bson.D{{"$trim", bson.D{{"input", "xxhixy"}, {"chars", "xy"}}}}, expected: "hi"},
+		{name: "ltrim", expression: bson.D{{"$ltrim", bson.D{{"input", "  hi  "}}}}, expected: "hi  "},
+		{name: "rtrim", expression: bson.D{{"$rtrim", bson.D{{"input", "  hi  "}}}}, expected: "  hi"},
+		{name: "trim unicode chars", expression: bson.D{{"$trim", bson.D{{"input", "éhié"}, {"chars", "é"}}}}, expected: "hi"},
+		{name: "trim null", expression: bson.D{{"$trim", bson.D{{"input", nil}}}}, expected: nil},
+		{name: "trim not object", expression: bson.D{{"$trim", "x"}}, shouldContainErr: "$trim only supports an object as its argument"},
+		{name: "trim missing input", expression: bson.D{{"$trim", bson.D{{"chars", "x"}}}}, shouldContainErr: "$trim requires an 'input' field"},
+		{
+			name:             "trim unknown field",
+			expression:       bson.D{{"$trim", bson.D{{"input", "x"}, {"foo", "x"}}}},
+			shouldContainErr: "Unrecognized argument to $trim: foo",
+		},
+		{name: "split", expression: bson.D{{"$split", bson.A{"a,b,,c", ","}}}, expected: bson.A{"a", "b", "", "c"}},
+		{name: "split non-ASCII", expression: bson.D{{"$split", bson.A{"aébéc", "é"}}}, expected: bson.A{"a", "b", "c"}},
+		{name: "split null", expression: bson.D{{"$split", bson.A{nil, ","}}}, expected: nil},
+		{name: "split empty delimiter", expression: bson.D{{"$split", bson.A{"abc", ""}}}, shouldContainErr: "$split requires a non-empty separator"},
+		{name: "indexOfCP", expression: bson.D{{"$indexOfCP", bson.A{"héllo", "l"}}}, expected: int32(2)},
+		{name: "indexOfCP start", expression: bson.D{{"$indexOfCP", bson.A{"héllo", "l", int32(3)}}}, expected: int32(3)},
+		{name: "indexOfCP end", expression: bson.D{{"$indexOfCP", bson.A{"héllo", "o", int32(0), int32(4)}}}, expected: int32(-1)},
+		{name: "indexOfCP not found", expression: bson.D{{"$indexOfCP", bson.A{"héllo", "x"}}}, expected: int32(-1)},
+		{name: "indexOfCP start after end", expression: bson.D{{"$indexOfCP", bson.A{"hello", "", int32(3), int32(1)}}}, expected: int32(-1)},
+		{name: "indexOfCP null", expression: bson.D{{"$indexOfCP", bson.A{nil, "x"}}}, expected: nil},
+		{
+			name:             "indexOfCP negative start",
+			expression:       bson.D{{"$indexOfCP", bson.A{"hello", "l", int32(-1)}}},
+			shouldContainErr: "$indexOfCP requires a nonnegative start index, found: -1",
+		},
+		{
+			name:             "indexOfCP substring type",
+			expression:       bson.D{{"$indexOfCP", bson.A{"hello", int32(1)}}},
+			shouldContainErr: "$indexOfCP requires a string as the second argument, found: int",
+		},
+		{
+			name:       "replaceOne",
+			expression: bson.D{{"$replaceOne", bson.D{{"input", "a-b-c"}, {"find", "-"}, {"replacement", "+"}}}},
+			expected:   "a+b-c",
+		},
+		{
+			name:       "replaceAll",
+			expression: bson.D{{"$replaceAll", bson.D{{"input", "a-b-c"}, {"find", "-"}, {"replacement", "+"}}}},
+			expected:   "a+b+c",
+		},
+		{
+			name:       "replaceAll null",
+			expression: bson.D{{"$replaceAll", bson.D{{"input", "a-b-c"}, {"find", nil}, {"replacement", "+"}}}},
+			expected:   nil,
+		},
+		{
+			name:             "replaceOne bad type",
+			expression:       bson.D{{"$replaceOne", bson.D{{"input", int32(1)}, {"find", "-"}, {"replacement", "+"}}}},
+			shouldContainErr: "$replaceOne requires that 'input' be a string, found: 1",
+		},
+		{
+			name:             "replaceOne missing field",
+			expression:       bson.D{{"$replaceOne", bson.D{{"input", "x"}, {"find", "-"}}}},
+			shouldContainErr: "$replaceOne requires 'replacement' to be specified",
+		},
+		{
+			name:       "regexMatch",
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "Hello"}, {"regex", "^h"}, {"options", "i"}}}},
+			expected:   true,
+		},
+		{
+			name:       "regexMatch regex type",
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "Hello"}, {"regex", primitive.Regex{Pattern: "^h", Options: "i"}}}}},
+			expected:   true,
+		},
+		{
+			name:       "regexMatch extended",
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "abc"}, {"regex", "a b # comment\n c"}, {"options", "x"}}}},
+			expected:   true,
+		},
+		{name: "regexMatch null input", expression: bson.D{{"$regexMatch", bson.D{{"input", nil}, {"regex", "a"}}}}, expected: false},
+		{
+			name:       "regexFind",
+			expression: bson.D{{"$regexFind", bson.D{{"input", "héllo wörld"}, {"regex", "(w)(x)?(ö)"}}}},
+			expected:   bson.D{{"match", "wö"}, {"idx", int32(6)}, {"captures", bson.A{"w", nil, "ö"}}},
+		},
+		{name: "regexFind no match", expression: bson.D{{"$regexFind", bson.D{{"input", "abc"}, {"regex", "x"}}}}, expected: nil},
+		{
+			name:       "regexFindAll",
+			expression: bson.D{{"$regexFindAll", bson.D{{"input", "a1é2"}, {"regex", "[0-9]"}}}},
+			expected: bson.A{
+				bson.D{{"match", "1"}, {"idx", int32(1)}, {"captures", bson.A{}}},
+				bson.D{{"match", "2"}, {"idx", int32(3)}, {"captures", bson.A{}}},
+			},
+		},
+		{name: "regexFindAll null regex", expression: bson.D{{"$regexFindAll", bson.D{{"input", "abc"}, {"regex", nil}}}}, expected: bson.A{}},
+		{
+			name:             "regexMatch options conflict",
+			expression:       bson.D{{"$regexMatch", bson.D{{"input", "a"}, {"regex", primitive.Regex{Pattern: "a", Options: "i"}}, {"options", "m"}}}},
+			shouldContainErr: "$regexMatch found regex option(s) specified in both 'regex' and 'option' fields",
+		},
+		{
+			name:             "regexMatch bad option",
+			expression:       bson.D{{"$regexMatch", bson.D{{"input", "a"}, {"regex", "a"}, {"options", "z"}}}},
+			shouldContainErr: "$regexMatch invalid flag in regex options: z",
+		},
+		{
+			name:             "regexMatch invalid regex",
+			expression:       bson.D{{"$regexMatch", bson.D{{"input", "a"}, {"regex", "("}}}},
+			shouldContainErr: "Invalid Regex in $regexMatch",
+		},
+		{
+			name:             "regexMatch input type",
+			expression:       bson.D{{"$regexMatch", bson.D{{"input", int32(1)}, {"regex", "a"}}}},
+			shouldContainErr: "$regexMatch needs 'input' to be of type string",
+		},
+	})
+}
+
+func TestAggregateDateOperators(t *testing.T) {
+	// 01:30 EST, half an hour before daylight saving time starts in New York
+	date := time.Date(2024, 3, 10, 6, 30, 0, 123000000, time.UTC)
+	dt := func(t time.Time) primitive.DateTime { return primitive.NewDateTimeFromTime(t) }
+	utc := func(year int, month time.Month, day, hour, minute int) primitive.DateTime {
+		return dt(time.Date(year, month, day, hour, minute, 0, 0, time.UTC))
+	}
+	doc := bson.D{{"d", date}}
+
+	runExpressionTests(t, []expressionTestCase{
+		{name: "year", doc: doc, expression: bson.D{{"$year", "$d"}}, expected: int32(2024)},
+		{name: "year array", doc: doc, expression: bson.D{{"$year", bson.A{"$d"}}}, expected: int32(2024)},
+		{name: "month", doc: doc, expression: bson.D{{"$month", "$d"}}, expected: int32(3)},
+		{name: "dayOfMonth", doc: doc, expression: bson.D{{"$dayOfMonth", "$d"}}, expected: int32(10)},
+		{name: "dayOfYear", doc: doc, expression: bson.D{{"$dayOfYear", "$d"}}, expected: int32(70)},
+		{name: "dayOfWeek", doc: doc, expression: bson.D{{"$dayOfWeek", "$d"}}, expected: int32(1)},
+		{name: "week", doc: doc, expression: bson.D{{"$week", "$d"}}, expected: int32(10)},
+		{name: "isoWeek", doc: doc, expression: bson.D{{"$isoWeek", "$d"}}, expected: int32(10)},
+		{name: "isoWeekYear", doc: doc, expression: bson.D{{"$isoWeekYear", "$d"}}, expected: int64(2024)},
+		{name: "isoDayOfWeek", doc: doc, expression: bson.D{{"$isoDayOfWeek", "$d"}}, expected: int32(7)},
+		{name: "hour", doc: doc, expression: bson.D{{"$hour", "$d"}}, expected: int32(6)},
+		{name: "millisecond", doc: doc, expression: bson.D{{"$millisecond", "$d"}}, expected: int32(123)},
+		{
+			name:       "hour timezone",
+			doc:        doc,
+			expression: bson.D{{"$hour", bson.D{{"date", "$d"}, {"timezone", "America/New_York"}}}},
+			expected:   int32(1),
+		},
+		{
+			name:       "minute offset timezone",
+			doc:        doc,
+			expression: bson.D{{"$minute", bson.D{{"date", "$d"}, {"timezone", "+05:45"}}}},
+			expected:   int32(15),
+		},
+		{
+			name:       "dayOfMonth negative offset",
+			doc:        doc,
+			expression: bson.D{{"$dayOfMonth", bson.D{{"date", "$d"}, {"timezone", "-0800"}}}},
+			expected:   int32(9),
+		},
+		{name: "year null", expression: bson.D{{"$year", nil}}, expected: nil},
+		{name: "year string", expression: bson.D{{"$year", "2024"}}, shouldContainErr: "can't convert from BSON type string to Date"},
+		{
+			name:             "unknown timezone",
+			doc:              doc,
+			expression:       bson.D{{"$year", bson.D{{"date", "$d"}, {"timezone", "Mars/Olympus_Mons"}}}},
+			shouldContainErr: `unrecognized time zone identifier: "Mars/Olympus_Mons"`,
+		},
+		{
+			name:             "local timezone",
+			doc:              doc,
+			expression:       bson.D{{"$year", bson.D{{"date", "$d"}, {"timezone", "Local"}}}},
+			shouldContainErr: `unrecognized time zone identifier: "Local"`,
+		},
+		{
+			name:             "timezone type",
+			doc:              doc,
+			expression:       bson.D{{"$year", bson.D{{"date", "$d"}, {"timezone", int32(1)}}}},
+			shouldContainErr: "timezone must evaluate to a string, found int",
+		},
+		{
+			name:       "dateToParts",
+			doc:        doc,
+			expression: bson.D{{"$dateToParts", bson.D{{"date", "$d"}, {"timezone", "Asia/Kolkata"}}}},
+			expected: bson.D{
+				{"year", int32(2024)}, {"month", int32(3)}, {"day", int32(10)},
+				{"hour", int32(12)}, {"minute", int32(0)}, {"second", int32(0)}, {"millisecond", int32(123)},
+			},
+		},
+		{
+			name:       "dateToParts iso8601",
+			doc:        doc,
+			expression: bson.D{{"$dateToParts", bson.D{{"date", "$d"}, {"iso8601", true}}}},
+			expected: bson.D{
+				{"isoWeekYear", int32(2024)}, {"isoWeek", int32(10)}, {"isoDayOfWeek", int32(7)},
+				{"hour", int32(6)}, {"minute", int32(30)}, {"second", int32(0)}, {"millisecond", int32(123)},
+			},
+		},
+		{
+			name:       "dateFromParts",
+			expression: bson.D{{"$dateFromParts", bson.D{{"year", int32(2024)}, {"month", int32(7)}, {"day", int32(4)}, {"hour", 12.0}}}},
+			expected:   utc(2024, 7, 4, 12, 0),
+		},
+		{
+			name:       "dateFromParts overflow",
+			expression: bson.D{{"$dateFromParts", bson.D{{"year", int32(2024)}, {"month", int32(14)}, {"day", int32(1)}}}},
+			expected:   utc(2025, 2, 1, 0, 0),
+		},
+		{
+			name: "dateFromParts timezone",
+			expression: bson.D{{"$dateFromParts", bson.D{
+				{"year", int32(2024)}, {"month", int32(7)}, {"day", int32(4)}, {"hour", int32(12)}, {"timezone", "America/New_York"},
+			}}},
+			expected: utc(2024, 7, 4, 16, 0),
+		},
+		{
+			name:       "dateFromParts iso",
+			expression: bson.D{{"$dateFromParts", bson.D{{"isoWeekYear", int32(2024)}, {"isoWeek", int32(10)}, {"isoDayOfWeek", int32(7)}}}},
+			expected:   utc(2024, 3, 10, 0, 0),
+		},
+		{
+			name:             "dateFromParts mixed",
+			expression:       bson.D{{"$dateFromParts", bson.D{{"year", int32(2024)}, {"isoWeek", int32(1)}}}},
+			shouldContainErr: "$dateFromParts does not allow mixing natural dates with ISO dates",
+		},
+		{
+			name:             "dateFromParts year range",
+			expression:       bson.D{{"$dateFromParts", bson.D{{"year", int32(10000)}}}},
+			shouldContainErr: "'year' must evaluate to an integer in the range 1 to 9999, found 10000",
+		},
+		{
+			name:             "dateFromParts not integer",
+			expression:       bson.D{{"$dateFromParts", bson.D{{"year", int32(2024)}, {"month", 1.5}}}},
+			shouldContainErr: "'month' must evaluate to an integer, found double with value 1.5",
+		},
+		{name: "dateToString", doc: doc, expression: bson.D{{"$dateToString", bson.D{{"date", "$d"}}}}, expected: "2024-03-10T06:30:00.123Z"},
+		{
+			name:       "dateToString timezone",
+			doc:        doc,
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$d"}, {"timezone", "America/New_York"}}}},
+			expected:   "2024-03-10T01:30:00.123",
+		},
+		{
+			name: "dateToString format",
+			doc:  doc,
+			expression: bson.D{{"$dateToString", bson.D{
+				{"date", "$d"}, {"format", "%d/%m/%Y %H:%M %z (%Z)"}, {"timezone", "Asia/Kolkata"},
+			}}},
+			expected: "10/03/2024 12:00 +0530 (+330)",
+		},
+		{
+			name:       "dateToString weeks",
+			doc:        doc,
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$d"}, {"format", "%j %U %u %w %V %G %b %B %%"}}}},
+			expected:   "070 10 7 1 10 2024 Mar March %",
+		},
+		{name: "dateToString onNull", expression: bson.D{{"$dateToString", bson.D{{"date", "$missing"}, {"onNull", "none"}}}}, expected: "none"},
+		{
+			name:             "dateToString invalid format",
+			doc:              doc,
+			expression:       bson.D{{"$dateToString", bson.D{{"date", "$d"}, {"format", "%Q"}}}},
+			shouldContainErr: "Invalid format character '%Q' in format string",
+		},
+		{
+			name:             "dateToString unmatched percent",
+			doc:              doc,
+			expression:       bson.D{{"$dateToString", bson.D{{"date", "$d"}, {"format", "%Y%"}}}},
+			shouldContainErr: "Unmatched '%' at end of format string",
+		},
+		{
+			name:       "dateFromString",
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10T06:30:00.123Z"}}}},
+			expected:   dt(date),
+		},
+		{
+			name:       "dateFromString timezone",
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10T01:30:00.123"}, {"timezone", "America/New_York"}}}},
+			expected:   dt(date),
+		},
+		{
+			name:       "dateFromString offset",
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10T12:00:00.123+05:30"}}}},
+			expected:   dt(date),
+		},
+		{
+			name:       "dateFromString olson suffix",
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10 01:30:00.123 America/New_York"}}}},
+			expected:   dt(date),
+		},
+		{
+			name: "dateFromString format",
+			expression: bson.D{{"$dateFromString", bson.D{
+				{"dateString", "10/03/2024 12:00:00.123 +0530"}, {"format", "%d/%m/%Y %H:%M:%S.%L %z"},
+			}}},
+			expected: dt(date),
+		},
+		{
+			name: "dateFromString month name",
+			expression: bson.D{{"$dateFromString", bson.D{
+				{"dateString", "mar 10 2024"}, {"format", "%b %d %Y"}, {"timezone", "Europe/Berlin"},
+			}}},
+			expected: utc(2024, 3, 9, 23, 0),
+		},
+		{
+			name:             "dateFromString invalid date",
+			expression:       bson.D{{"$dateFromString", bson.D{{"dateString", "2021-02-30"}, {"format", "%Y-%m-%d"}}}},
+			shouldContainErr: "Error parsing date string '2021-02-30'",
+		},
+		{
+			name:       "dateFromString onError",
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "not a date"}, {"onError", "bad"}}}},
+			expected:   "bad",
+		},
+		{
+			name:       "dateFromString onNull",
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", nil}, {"onNull", int32(0)}}}},
+			expected:   int32(0),
+		},
+		{
+			name: "dateFromString timezone conflict",
+			expression: bson.D{{"$dateFromString", bson.D{
+				{"dateString", "2024-03-10T06:30:00Z"}, {"timezone", "Europe/Berlin"},
+			}}},
+			shouldContainErr: "you cannot pass in a date/time string with time zone information",
+		},
+		{
+			name:       "dateAdd day across DST",
+			doc:        doc,
+			expression: bson.D{{"$dateAdd", bson.D{{"startDate", "$d"}, {"unit", "day"}, {"amount", int32(1)}, {"timezone", "America/New_York"}}}},
+			expected:   dt(date.Add(23 * time.Hour)),
+		},
+		{
+			name:       "dateAdd hour across DST",
+			doc:        doc,
+			expression: bson.D{{"$dateAdd", bson.D{{"startDate", "$d"}, {"unit", "hour"}, {"amount", int64(1)}, {"timezone", "America/New_York"}}}},
+			expected:   dt(date.Add(time.Hour)),
+		},
+		{
+			name:       "dateAdd month clamps day",
+			expression: bson.D{{"$dateAdd", bson.D{{"startDate", utc(2024, 1, 31, 0, 0)}, {"unit", "month"}, {"amount", int32(1)}}}},
+			expected:   utc(2024, 2, 29, 0, 0),
+		},
+		{
+			name:       "dateSubtract year",
+			expression: bson.D{{"$dateSubtract", bson.D{{"startDate", utc(2024, 2, 29, 0, 0)}, {"unit", "year"}, {"amount", int32(1)}}}},
+			expected:   utc(2023, 2, 28, 0, 0),
+		},
+		{
+			name:       "dateSubtract quarter",
+			expression: bson.D{{"$dateSubtract", bson.D{{"startDate", utc(2024, 5, 15, 0, 0)}, {"unit", "quarter"}, {"amount", int32(2)}}}},
+			expected:   utc(2023, 11, 15, 0, 0),
+		},
+		{
+			name:             "dateAdd bad unit",
+			expression:       bson.D{{"$dateAdd", bson.D{{"startDate", utc(2024, 1, 1, 0, 0)}, {"unit", "fortnight"}, {"amount", int32(1)}}}},
+			shouldContainErr: "$dateAdd parameter 'unit' value cannot be recognized as a time unit: fortnight",
+		},
+		{
+			name:             "dateAdd fractional amount",
+			expression:       bson.D{{"$dateAdd", bson.D{{"startDate", utc(2024, 1, 1, 0, 0)}, {"unit", "day"}, {"amount", 1.5}}}},
+			shouldContainErr: "$dateAdd expects integer amount of time units",
+		},
+		{
+			name:             "dateAdd missing amount",
+			expression:       bson.D{{"$dateAdd", bson.D{{"startDate", utc(2024, 1, 1, 0, 0)}, {"unit", "day"}}}},
+			shouldContainErr: "$dateAdd requires startDate, unit, and amount to be present",
+		},
+		{
+			name:             "dateAdd overflow",
+			expression:       bson.D{{"$dateAdd", bson.D{{"startDate", utc(2024, 1, 1, 0, 0)}, {"unit", "millisecond"}, {"amount", int64(math.MaxInt64)}}}},
+			shouldContainErr: "$dateAdd overflowed",
+		},
+		{
+			name:       "dateDiff month",
+			expression: bson.D{{"$dateDiff", bson.D{{"startDate", utc(2024, 1, 31, 0, 0)}, {"endDate", utc(2024, 3, 1, 0, 0)}, {"unit", "month"}}}},
+			expected:   int64(2),
+		},
+		{
+			name:       "dateDiff day",
+			expression: bson.D{{"$dateDiff", bson.D{{"startDate", utc(2024, 1, 31, 0, 0)}, {"endDate", utc(2024, 3, 1, 0, 0)}, {"unit", "day"}}}},
+			expected:   int64(30),
+		},
+		{
+			name:       "dateDiff week",
+			expression: bson.D{{"$dateDiff", bson.D{{"startDate", utc(2024, 1, 31, 0, 0)}, {"endDate", utc(2024, 3, 1, 0, 0)}, {"unit", "week"}}}},
+			expected:   int64(4),
+		},
+		{
+			name: "dateDiff week startOfWeek",
+			expression: bson.D{{"$dateDiff", bson.D{
+				{"startDate", utc(2024, 3, 9, 0, 0)}, {"endDate", utc(2024, 3, 10, 0, 0)}, {"unit", "week"}, {"startOfWeek", "SAT"},
+			}}},
+			expected: int64(0),
+		},
+		{
+			name: "dateDiff negative",
+			expression: bson.D{{"$dateDiff", bson.D{
+				{"startDate", utc(2024, 3, 1, 0, 0)}, {"endDate", utc(2023, 12, 31, 0, 0)}, {"unit", "quarter"},
+			}}},
+			expected: int64(-1),
+		},
+		{
+			name: "dateDiff day timezone",
+			expression: bson.D{{"$dateDiff", bson.D{
+				{"startDate", utc(2024, 3, 1, 3, 0)}, {"endDate", utc(2024, 3, 1, 6, 0)}, {"unit", "day"}, {"timezone", "America/New_York"},
+			}}},
+			expected: int64(1),
+		},
+		{
+			name: "dateDiff hour across DST",
+			expression: bson.D{{"$dateDiff", bson.D{
+				{"startDate", utc(2024, 3, 10, 5, 0)}, {"endDate", utc(2024, 3, 10, 8, 0)}, {"unit", "hour"}, {"timezone", "America/New_York"},
+			}}},
+			expected: int64(3),
+		},
+		{
+			name: "dateDiff bad date",
+			expression: bson.D{{"$dateDiff", bson.D{
+				{"startDate", "2024-01-01"}, {"endDate", utc(2024, 3, 10, 8, 0)}, {"unit", "hour"},
+			}}},
+			shouldContainErr: "$dateDiff requires 'startDate' to be a date, but got string",
+		},
+		{
+			name:       "dateTrunc month timezone",
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", utc(2024, 3, 1, 3, 0)}, {"unit", "month"}, {"timezone", "America/New_York"}}}},
+			expected:   utc(2024, 2, 1, 5, 0),
+		},
+		{
+			name:       "dateTrunc quarter",
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", utc(2024, 5, 15, 3, 0)}, {"unit", "quarter"}}}},
+			expected:   utc(2024, 4, 1, 0, 0),
+		},
+		{
+			name:       "dateTrunc year binSize",
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", utc(2024, 5, 15, 3, 0)}, {"unit", "year"}, {"binSize", int32(5)}}}},
+			expected:   utc(2020, 1, 1, 0, 0),
+		},
+		{
+			name:       "dateTrunc week",
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", utc(2024, 3, 13, 12, 0)}, {"unit", "week"}}}},
+			expected:   utc(2024, 3, 10, 0, 0),
+		},
+		{
+			name:       "dateTrunc week startOfWeek",
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", utc(2024, 3, 13, 12, 0)}, {"unit", "week"}, {"startOfWeek", "monday"}}}},
+			expected:   utc(2024, 3, 11, 0, 0),
+		},
+		{
+			name:       "dateTrunc day timezone",
+			doc:        doc,
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", "$d"}, {"unit", "day"}, {"timezone", "Asia/Tokyo"}}}},
+			expected:   utc(2024, 3, 9, 15, 0),
+		},
+		{
+			name:       "dateTrunc hour offset timezone",
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", utc(2024, 3, 10, 7, 10)}, {"unit", "hour"}, {"binSize", int32(2)}, {"timezone", "+05:30"}}}},
+			expected:   utc(2024, 3, 10, 6, 30),
+		},
+		{
+			name:       "dateTrunc minute",
+			doc:        doc,
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", "$d"}, {"unit", "minute"}, {"binSize", int32(15)}}}},
+			expected:   utc(2024, 3, 10, 6, 30),
+		},
+		{
+			name:             "dateTrunc binSize",
+			doc:              doc,
+			expression:       bson.D{{"$dateTrunc", bson.D{{"date", "$d"}, {"unit", "day"}, {"binSize", int32(0)}}}},
+			shouldContainErr: "$dateTrunc requires 'binSize' to be greater than 0, but got value 0",
+		},
+		{
+			name:             "dateTrunc startOfWeek",
+			doc:              doc,
+			expression:       bson.D{{"$dateTrunc", bson.D{{"date", "$d"}, {"unit", "week"}, {"startOfWeek", "someday"}}}},
+			shouldContainErr: "$dateTrunc parameter 'startOfWeek' value cannot be recognized as a day of a week: someday",
+		},
+	})
+}
+
+func TestAggregateArrayOperators(t *testing.T) {
+	doc := bson.D{
+		{"a", bson.A{int32(1), int32(2), int32(3)}},
+		{"docs", bson.A{bson.D{{"x", int32(2)}, {"y", "b"}}, bson.D{{"x", int32(1)}, {"y", "a"}}, bson.D{{"y", "c"}}}},
+		{"s", "str"},
+	}
+
+	runExpressionTests(t, []expressionTestCase{
+		{
+			name:       "map",
+			doc:        doc,
+			expression: bson.D{{"$map", bson.D{{"input", "$a"}, {"in", bson.D{{"$multiply", bson.A{"$$this", int32(10)}}}}}}},
+			expected:   bson.A{int32(10), int32(20), int32(30)},
+		},
+		{
+			name:       "map as",
+			doc:        doc,
+			expression: bson.D{{"$map", bson.D{{"input", "$docs"}, {"as", "d"}, {"in", "$$d.y"}}}},
+			expected:   bson.A{"b", "a", "c"},
+		},
+		{
+			name:       "map missing field",
+			doc:        doc,
+			expression: bson.D{{"$map", bson.D{{"input", "$docs"}, {"in", "$$this.x"}}}},
+			expected:   bson.A{int32(2), int32(1), nil},
+		},
+		{
+			name: "map nested shadowing",
+			doc:  doc,
+			expression: bson.D{{"$map", bson.D{
+				{"input", bson.A{int32(1), int32(2)}},
+				{"as", "v"},
+				{"in", bson.D{{"$map", bson.D{
+					{"input", bson.A{int32(10), int32(20)}},
+					{"as", "v"},
+					{"in", bson.D{{"$multiply", bson.A{"$$v", int32(2)}}}},
+				}}}},
+			}}},
+			expected: bson.A{bson.A{int32(20), int32(40)}, bson.A{int32(20), int32(40)}},
+		},
+		{
+			name: "map outer variable",
+			doc:  doc,
+			expression: bson.D{{"$map", bson.D{
+				{"input", bson.A{int32(1), int32(2)}},
+				{"as", "outer"},
+				{"in", bson.D{{"$map", bson.D{
+					{"input", bson.A{int32(10), int32(20)}},
+					{"in", bson.D{{"$add", bson.A{"$$outer", "$$this"}}}},
+				}}}},
+			}}},
+			expected: bson.A{bson.A{int32(11), int32(21)}, bson.A{int32(12), int32(22)}},
+		},
+		{name: "map null", expression: bson.D{{"$map", bson.D{{"input", "$missing"}, {"in", "$$this"}}}}, expected: nil},
+		{
+			name:             "map not array",
+			doc:              doc,
+			expression:       bson.D{{"$map", bson.D{{"input", "$s"}, {"in", "$$this"}}}},
+			shouldContainErr: "input to $map must be an array not string",
+		},
+		{
+			name:             "map invalid as",
+			expression:       bson.D{{"$map", bson.D{{"input", bson.A{}}, {"as", "Bad"}, {"in", "$$this"}}}},
+			shouldContainErr: "'Bad' starts with an invalid character for a user variable name",
+		},
+		{
+			name:             "map missing in",
+			expression:       bson.D{{"$map", bson.D{{"input", bson.A{}}}}},
+			shouldContainErr: "Missing 'in' parameter to $map",
+		},
+		{
+			name:             "undefined variable",
+			doc:              doc,
+			expression:       bson.D{{"$map", bson.D{{"input", "$a"}, {"as", "v"}, {"in", "$$this"}}}},
+			shouldContainErr: "Use of undefined variable: this",
+		},
+		{
+			name:       "filter",
+			doc:        doc,
+			expression: bson.D{{"$filter", bson.D{{"input", "$a"}, {"cond", bson.D{{"$mod", bson.A{"$$this", int32(2)}}}}}}},
+			expected:   bson.A{int32(1), int32(3)},
+		},
+		{
+			name: "filter limit",
+			doc:  doc,
+			expression: bson.D{{"$filter", bson.D{
+				{"input", "$docs"}, {"as", "d"}, {"cond", "$$d.y"}, {"limit", int32(2)},
+			}}},
+			expected: bson.A{bson.D{{"x", int32(2)}, {"y", "b"}}, bson.D{{"x", int32(1)}, {"y", "a"}}},
+		},
+		{
+			name:             "filter bad limit",
+			doc:              doc,
+			expression:       bson.D{{"$filter", bson.D{{"input", "$a"}, {"cond", true}, {"limit", int32(0)}}}},
+			shouldContainErr: "$filter: limit must be greater than 0: 0",
+		},
+		{
+			name: "reduce",
+			doc:  doc,
+			expression: bson.D{{"$reduce", bson.D{
+				{"input", "$a"},
+				{"initialValue", int32(0)},
+				{"in", bson.D{{"$add", bson.A{"$$value", "$$this"}}}},
+			}}},
+			expected: int32(6),
+		},
+		{
+			name: "reduce concat",
+			doc:  doc,
+			expression: bson.D{{"$reduce", bson.D{
+				{"input", "$docs"},
+				{"initialValue", ""},
+				{"in", bson.D{{"$concat", bson.A{"$$value", "$$this.y"}}}},
+			}}},
+			expected: "bac",
+		},
+		{
+			name:             "reduce missing initialValue",
+			expression:       bson.D{{"$reduce", bson.D{{"input", bson.A{}}, {"in", "$$value"}}}},
+			shouldContainErr: "$reduce requires 'initialValue' to be specified",
+		},
+		{name: "arrayElemAt", doc: doc, expression: bson.D{{"$arrayElemAt", bson.A{"$a", int32(1)}}}, expected: int32(2)},
+		{name: "arrayElemAt negative", doc: doc, expression: bson.D{{"$arrayElemAt", bson.A{"$a", int64(-1)}}}, expected: int32(3)},
+		{name: "arrayElemAt out of bounds", doc: doc, expression: bson.D{{"$arrayElemAt", bson.A{"$a", int32(3)}}}, expected: nil},
+		{
+			name:             "arrayElemAt fractional index",
+			doc:              doc,
+			expression:       bson.D{{"$arrayElemAt", bson.A{"$a", 1.5}}},
+			shouldContainErr: "$arrayElemAt's second argument must be representable as a 32-bit integer: 1.5",
+		},
+		{name: "first", doc: doc, expression: bson.D{{"$first", "$a"}}, expected: int32(1)},
+		{name: "last", doc: doc, expression: bson.D{{"$last", "$a"}}, expected: int32(3)},
+		{name: "first empty", expression: bson.D{{"$first", bson.A{bson.A{}}}}, expected: nil},
+		{
+			name:             "last not array",
+			doc:              doc,
+			expression:       bson.D{{"$last", "$s"}},
+			shouldContainErr: "$last's argument must be an array, but is string",
+		},
+		{name: "slice", doc: doc, expression: bson.D{{"$slice", bson.A{"$a", int32(2)}}}, expected: bson.A{int32(1), int32(2)}},
+		{name: "slice negative", doc: doc, expression: bson.D{{"$slice", bson.A{"$a", int32(-2)}}}, expected: bson.A{int32(2), int32(3)}},
+		{
+			name:       "slice position",
+			doc:        doc,
+			expression: bson.D{{"$slice", bson.A{"$a", int32(-2), int32(5)}}},
+			expected:   bson.A{int32(2), int32(3)},
+		},
+		{
+			name:             "slice zero count",
+			doc:              doc,
+			expression:       bson.D{{"$slice", bson.A{"$a", int32(0), int32(0)}}},
+			shouldContainErr: "Third argument to $slice must be positive: 0",
+		},
+		{
+			name:       "concatArrays",
+			doc:        doc,
+			expression: bson.D{{"$concatArrays", bson.A{"$a", bson.A{"x"}}}},
+			expected:   bson.A{int32(1), int32(2), int32(3), "x"},
+		},
+		{name: "concatArrays missing", doc: doc, expression: bson.D{{"$concatArrays", bson.A{"$a", "$missing"}}}, expected: nil},
+		{name: "reverseArray", doc: doc, expression: bson.D{{"$reverseArray", "$a"}}, expected: bson.A{int32(3), int32(2), int32(1)}},
+		{name: "range", expression: bson.D{{"$range", bson.A{int32(0), int32(5), int32(2)}}}, expected: bson.A{int32(0), int32(2), int32(4)}},
+		{name: "range negative step", expression: bson.D{{"$range", bson.A{int32(3), int32(0), int32(-1)}}}, expected: bson.A{int32(3), int32(2), int32(1)}},
+		{
+			name:             "range zero step",
+			expression:       bson.D{{"$range", bson.A{int32(0), int32(5), int32(0)}}},
+			shouldContainErr: "$range requires a non-zero step value",
+		},
+		{
+			name:       "zip",
+			doc:        doc,
+			expression: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$a", bson.A{"x", "y"}}}}}},
+			expected:   bson.A{bson.A{int32(1), "x"}, bson.A{int32(2), "y"}},
+		},
+		{
+			name: "zip longest",
+			doc:  doc,
+			expression: bson.D{{"$zip", bson.D{
+				{"inputs", bson.A{"$a", bson.A{"x"}}},
+				{"useLongestLength", true},
+				{"defaults", bson.A{int32(0), "z"}},
+			}}},
+			expected: bson.A{bson.A{int32(1), "x"}, bson.A{int32(2), "z"}, bson.A{int32(3), "z"}},
+		},
+		{
+			name:             "zip defaults without useLongestLength",
+			expression:       bson.D{{"$zip", bson.D{{"inputs", bson.A{bson.A{}}}, {"defaults", bson.A{int32(0)}}}}},
+			shouldContainErr: "cannot specify defaults unless useLongestLength is true",
+		},
+		{name: "in", doc: doc, expression: bson.D{{"$in", bson.A{2.0, "$a"}}}, expected: true},
+		{name: "in not found", doc: doc, expression: bson.D{{"$in", bson.A{"2", "$a"}}}, expected: false},
+		{
+			name:             "in missing array",
+			expression:       bson.D{{"$in", bson.A{int32(1), "$missing"}}},
+			shouldContainErr: "$in requires an array as a second argument, found: missing",
+		},
+		{name: "indexOfArray", doc: doc, expression: bson.D{{"$indexOfArray", bson.A{"$a", int32(3)}}}, expected: int32(2)},
+		{name: "indexOfArray range", doc: doc, expression: bson.D{{"$indexOfArray", bson.A{"$a", int32(1), int32(1)}}}, expected: int32(-1)},
+		{
+			name:             "indexOfArray negative start",
+			doc:              doc,
+			expression:       bson.D{{"$indexOfArray", bson.A{"$a", int32(1), int32(-1)}}},
+			shouldContainErr: "$indexOfArray requires a nonnegative starting index, found: -1",
+		},
+		{name: "isArray", doc: doc, expression: bson.D{{"$isArray", "$a"}}, expected: true},
+		{name: "isArray missing", expression: bson.D{{"$isArray", "$missing"}}, expected: false},
+		{name: "size", doc: doc, expression: bson.D{{"$size", "$a"}}, expected: int32(3)},
+		{
+			name:             "size missing",
+			expression:       bson.D{{"$size", "$missing"}},
+			shouldContainErr: "The argument to $size must be an array. Type of argument: missing",
+		},
+		{
+			name:       "arrayToObject pairs",
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1)}, bson.A{"b", int32(2)}, bson.A{"a", int32(3)}}}}},
+			expected:   bson.D{{"a", int32(3)}, {"b", int32(2)}},
+		},
+		{
+			name: "arrayToObject documents",
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{
+				bson.D{{"k", "a"}, {"v", int32(1)}},
+				bson.D{{"v", int32(2)}, {"k", "b"}},
+			}}}},
+			expected: bson.D{{"a", int32(1)}, {"b", int32(2)}},
+		},
+		{
+			name:             "arrayToObject inconsistent",
+			expression:       bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1)}, bson.D{{"k", "b"}, {"v", int32(2)}}}}}},
+			shouldContainErr: "Array was detected, now found: object",
+		},
+		{
+			name:       "objectToArray",
+			expression: bson.D{{"$objectToArray", bson.D{{"a", int32(1)}, {"b", "x"}}}},
+			expected:   bson.A{bson.D{{"k", "a"}, {"v", int32(1)}}, bson.D{{"k", "b"}, {"v", "x"}}},
+		},
+		{
+			name:       "sortArray values",
+			expression: bson.D{{"$sortArray", bson.D{{"input", bson.A{int32(3), "a", 1.5, nil}}, {"sortBy", int32(-1)}}}},
+			expected:   bson.A{"a", int32(3), 1.5, nil},
+		},
+		{
+			name:       "sortArray documents",
+			doc:        doc,
+			expression: bson.D{{"$sortArray", bson.D{{"input", "$docs"}, {"sortBy", bson.D{{"x", int32(1)}}}}}},
+			expected: bson.A{
+				bson.D{{"y", "c"}},
+				bson.D{{"x", int32(1)}, {"y", "a"}},
+				bson.D{{"x", int32(2)}, {"y", "b"}},
+			},
+		},
+		{
+			name:             "sortArray bad sortBy",
+			expression:       bson.D{{"$sortArray", bson.D{{"input", bson.A{}}, {"sortBy", int32(2)}}}},
+			shouldContainErr: "$sortArray sortBy must be 1, -1 or a document with 1 or -1 values",
+		},
+	})
+}
+
+func TestAggregateConditionalOperators(t *testing.T) {
+	doc := bson.D{{"a", int32(1)}, {"b", 1.0}, {"n", nil}, {"s", "x"}}
+
+	runExpressionTests(t, []expressionTestCase{
+		{name: "cond array", doc: doc, expression: bson.D{{"$cond", bson.A{"$a", "yes", "no"}}}, expected: "yes"},
+		{
+			name:       "cond document",
+			doc:        doc,
+			expression: bson.D{{"$cond", bson.D{{"if", "$missing"}, {"then", "yes"}, {"else", "no"}}}},
+			expected:   "no",
+		},
+		{
+			name:       "cond not evaluated branch",
+			doc:        doc,
+			expression: bson.D{{"$cond", bson.A{true, int32(1), bson.D{{"$divide", bson.A{int32(1), int32(0)}}}}}},
+			expected:   int32(1),
+		},
+		{
+			name:             "cond missing else",
+			expression:       bson.D{{"$cond", bson.D{{"if", true}, {"then", int32(1)}}}},
+			shouldContainErr: "Missing 'else' parameter to $cond",
+		},
+		{
+			name:             "cond wrong number of arguments",
+			expression:       bson.D{{"$cond", bson.A{true, int32(1)}}},
+			shouldContainErr: "Expression $cond takes exactly 3 arguments. 2 were passed in.",
+		},
+		{name: "ifNull null", doc: doc, expression: bson.D{{"$ifNull", bson.A{"$n", "$missing", "$s", "z"}}}, expected: "x"},
+		{name: "ifNull replacement", doc: doc, expression: bson.D{{"$ifNull", bson.A{"$missing", "z"}}}, expected: "z"},
+		{
+			name:             "ifNull one argument",
+			expression:       bson.D{{"$ifNull", bson.A{int32(1)}}},
+			shouldContainErr: "$ifNull needs at least two arguments, had: 1",
+		},
+		{
+			name: "switch",
+			doc:  doc,
+			expression: bson.D{{"$switch", bson.D{
+				{"branches", bson.A{
+					bson.D{{"case", bson.D{{"$eq", bson.A{"$a", int32(0)}}}}, {"then", "zero"}},
+					bson.D{{"case", bson.D{{"$eq", bson.A{"$a", int32(1)}}}}, {"then", "one"}},
+				}},
+				{"default", "many"},
+			}}},
+			expected: "one",
+		},
+		{
+			name: "switch default",
+			doc:  doc,
+			expression: bson.D{{"$switch", bson.D{
+				{"branches", bson.A{bson.D{{"case", false}, {"then", "no"}}}},
+				{"default", "$s"},
+			}}},
+			expected: "x",
+		},
+		{
+			name:             "switch no match",
+			expression:       bson.D{{"$switch", bson.D{{"branches", bson.A{bson.D{{"case", false}, {"then", "no"}}}}}}},
+			shouldContainErr: "$switch could not find a matching branch for an input, and no default was specified.",
+		},
+		{
+			name:             "switch no branches",
+			expression:       bson.D{{"$switch", bson.D{{"branches", bson.A{}}}}},
+			shouldContainErr: "$switch requires at least one branch.",
+		},
+		{name: "and", doc: doc, expression: bson.D{{"$and", bson.A{"$a", "$s", true}}}, expected: true},
+		{name: "and false", doc: doc, expression: bson.D{{"$and", bson.A{"$a", int32(0)}}}, expected: false},
+		{name: "and empty", expression: bson.D{{"$and", bson.A{}}}, expected: true},
+		{name: "or", doc: doc, expression: bson.D{{"$or", bson.A{"$n", "$missing", "$a"}}}, expected: true},
+		{name: "or false", doc: doc, expression: bson.D{{"$or", bson.A{"$n", false}}}, expected: false},
+		{name: "not", doc: doc, expression: bson.D{{"$not", bson.A{"$n"}}}, expected: true},
+		{name: "eq numbers", doc: doc, expression: bson.D{{"$eq", bson.A{"$a", "$b"}}}, expected: true},
+		{name: "eq null missing", doc: doc, expression: bson.D{{"$eq", bson.A{"$n", "$missing"}}}, expected: false},
+		{name: "ne", doc: doc, expression: bson.D{{"$ne", bson.A{"$a", "$s"}}}, expected: true},
+		{name: "gt type order", doc: doc, expression: bson.D{{"$gt", bson.A{"$s", "$a"}}}, expected: true},
+		{name: "gte", doc: doc, expression: bson.D{{"$gte", bson.A{"$a", "$b"}}}, expected: true},
+		{name: "lt missing", doc: doc, expression: bson.D{{"$lt", bson.A{"$missing", "$n"}}}, expected: true},
+		{name: "lte", doc: doc, expression: bson.D{{"$lte", bson.A{int32(2), "$a"}}}, expected: false},
+		{name: "cmp less", doc: doc, expression: bson.D{{"$cmp", bson.A{"$a", int32(2)}}}, expected: int32(-1)},
+		{name: "cmp equal", doc: doc, expression: bson.D{{"$cmp", bson.A{"$a", "$b"}}}, expected: int32(0)},
+		{name: "cmp arrays", expression: bson.D{{"$cmp", bson.A{bson.A{int32(1), int32(3)}, bson.A{int32(1), int32(2)}}}}, expected: int32(1)},
+		{
+			name:             "eq wrong number of arguments",
+			expression:       bson.D{{"$eq", bson.A{int32(1)}}},
+			shouldContainErr: "Expression $eq takes exactly 2 arguments. 1 were passed in.",
+		},
+	})
+}
+
+func TestAggregateAccumulators(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"g", "a"}, {"v", int32(3)}, {"o", bson.D{{"x", int32(1)}}}},
+		{{"_id", int32(2)}, {"g", "a"}, {"v", int32(1)}, {"o", bson.D{{"y", int32(2)}}}},
+		{{"_id", int32(3)}, {"g", "a"}, {"v", nil}},
+		{{"_id", int32(4)}, {"g", "a"}, {"v", int32(1)}, {"o", bson.D{{"x", int32(4)}}}},
+		{{"_id", int32(5)}, {"g", "b"}, {"v", int32(5)}},
+	}
+
+	tests := []struct {
+		name             string
+		group            bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name: "avg sum and count",
+			group: bson.D{
+				{"avg", bson.D{{"$avg", "$v"}}},
+				{"sum", bson.D{{"$sum", "$v"}}},
+				{"count", bson.D{{"$count", bson.D{}}}},
+			},
+			expected: []bson.D{
+				{{"_id", "a"}, {"avg", 5.0 / 3}, {"sum", int32(5)}, {"count", int32(4)}},
+				{{"_id", "b"}, {"avg", 5.0}, {"sum", int32(5)}, {"count", int32(1)}},
+			},
+		},
+		{
+			name: "min max",
+			group: bson.D{
+				{"min", bson.D{{"$min", "$v"}}},
+				{"max", bson.D{{"$max", "$v"}}},
+				{"none", bson.D{{"$max", "$missing"}}},
+			},
+			expected: []bson.D{
+				{{"_id", "a"}, {"min", int32(1)}, {"max", int32(3)}, {"none", nil}},
+				{{"_id", "b"}, {"min", int32(5)}, {"max", int32(5)}, {"none", nil}},
+			},
+		},
+		{
+			name: "first last",
+			group: bson.D{
+				{"first", bson.D{{"$first", "$o"}}},
+				{"last", bson.D{{"$last", "$o"}}},
+			},
+			expected: []bson.D{
+				{{"_id", "a"}, {"first", bson.D{{"x", int32(1)}}}, {"last", bson.D{{"x", int32(4)}}}},
+				{{"_id", "b"}, {"first", nil}, {"last", nil}},
+			},
+		},
+		{
+			name: "push addToSet mergeObjects",
+			group: bson.D{
+				{"push", bson.D{{"$push", "$v"}}},
+				{"set", bson.D{{"$addToSet", "$v"}}},
+				{"merged", bson.D{{"$mergeObjects", "$o"}}},
+			},
+			expected: []bson.D{
+				{
+					{"_id", "a"},
+					{"push", bson.A{int32(3), int32(1), nil, int32(1)}},
+					{"set", bson.A{int32(3), int32(1), nil}},
+					{"merged", bson.D{{"x", int32(4)}, {"y", int32(2)}}},
+				},
+				{{"_id", "b"}, {"push", bson.A{int32(5)}}, {"set", bson.A{int32(5)}}, {"merged", bson.D{}}},
+			},
+		},
+		{
+			name: "stdDev",
+			group: bson.D{
+				{"pop", bson.D{{"$stdDevPop", "$_id"}}},
+				{"samp", bson.D{{"$stdDevSamp", "$_id"}}},
+			},
+			expected: []bson.D{
+				{{"_id", "a"}, {"pop", math.Sqrt(1.25)}, {"samp", math.Sqrt(5.0 / 3)}},
+				{{"_id", "b"}, {"pop", 0.0}, {"samp", nil}},
+			},
+		},
+		{
+			name: "n accumulators",
+			group: bson.D{
+				{"firstN", bson.D{{"$firstN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}},
+				{"lastN", bson.D{{"$lastN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}},
+				{"minN", bson.D{{"$minN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}},
+				{"maxN", bson.D{{"$maxN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}},
+			},
+			expected: []bson.D{
+				{
+					{"_id", "a"},
+					{"firstN", bson.A{int32(3), int32(1)}},
+					{"lastN", bson.A{nil, int32(1)}},
+					{"minN", bson.A{int32(1), int32(1)}},
+					{"maxN", bson.A{int32(3), int32(1)}},
+				},
+				{
+					{"_id", "b"},
+					{"firstN", bson.A{int32(5)}},
+					{"lastN", bson.A{int32(5)}},
+					{"minN", bson.A{int32(5)}},
+					{"maxN", bson.A{int32(5)}},
+				},
+			},
+		},
+		{
+			name: "top bottom",
+			group: bson.D{
+				{"top", bson.D{{"$top", bson.D{{"output", "$_id"}, {"sortBy", bson.D{{"_id", int32(-1)}}}}}}},
+				{"bottom", bson.D{{"$bottom", bson.D{{"output", "$_id"}, {"sortBy", bson.D{{"_id", int32(-1)}}}}}}},
+				{"topN", bson.D{{"$topN", bson.D{
+					{"output", bson.A{"$_id", "$v"}},
+					{"sortBy", bson.D{{"_id", int32(1)}}},
+					{"n", int32(2)},
+				}}}},
+				{"bottomN", bson.D{{"$bottomN", bson.D{
+					{"output", "$v"},
+					{"sortBy", bson.D{{"_id", int32(1)}}},
+					{"n", int32(2)},
+				}}}},
+			},
+			expected: []bson.D{
+				{
+					{"_id", "a"},
+					{"top", int32(4)},
+					{"bottom", int32(1)},
+					{"topN", bson.A{bson.A{int32(1), int32(3)}, bson.A{int32(2), int32(1)}}},
+					{"bottomN", bson.A{nil, int32(1)}},
+				},
+				{
+					{"_id", "b"},
+					{"top", int32(5)},
+					{"bottom", int32(5)},
+					{"topN", bson.A{bson.A{int32(5), int32(5)}}},
+					{"bottomN", bson.A{int32(5)}},
+				},
+			},
+		},
+		{
+			name: "percentile median",
+			group: bson.D{
+				{"p", bson.D{{"$percentile", bson.D{
+					{"input", "$_id"},
+					{"p", bson.A{0.0, 0.5, 1.0}},
+					{"method", "approximate"},
+				}}}},
+				{"median", bson.D{{"$median", bson.D{{"input", "$_id"}, {"method", "continuous"}}}}},
+				{"none", bson.D{{"$median", bson.D{{"input", "$missing"}, {"method", "approximate"}}}}},
+			},
+			expected: []bson.D{
+				{{"_id", "a"}, {"p", bson.A{1.0, 2.0, 4.0}}, {"median", 2.5}, {"none", nil}},
+				{{"_id", "b"}, {"p", bson.A{5.0, 5.0, 5.0}}, {"median", 5.0}, {"none", nil}},
+			},
+		},
+		{
+			name:             "unary operator",
+			group:            bson.D{{"avg", bson.D{{"$avg", bson.A{"$v", "$v"}}}}},
+			shouldContainErr: "The $avg accumulator is a unary operator",
+		},
+		{
+			name:             "n not positive",
+			group:            bson.D{{"x", bson.D{{"$firstN", bson.D{{"input", "$v"}, {"n", int32(0)}}}}}},
+			shouldContainErr: "'n' must be greater than 0, found 0",
+		},
+		{
+			name:             "n missing",
+			group:            bson.D{{"x", bson.D{{"$maxN", bson.D{{"input", "$v"}}}}}},
+			shouldContainErr: "Missing value for 'n'",
+		},
+		{
+			name:             "top missing sortBy",
+			group:            bson.D{{"x", bson.D{{"$top", bson.D{{"output", "$v"}}}}}},
+			shouldContainErr: "Missing value for 'sortBy' in $top",
+		},
+		{
+			name: "percentile bad p",
+			group: bson.D{{"x", bson.D{{"$percentile", bson.D{
+				{"input", "$v"},
+				{"p", bson.A{1.5}},
+				{"method", "approximate"},
+			}}}}},
+			shouldContainErr: "The 'p' field must be an array of numbers from [0.0, 1.0]",
+		},
+		{
+			name:             "percentile missing method",
+			group:            bson.D{{"x", bson.D{{"$median", bson.D{{"input", "$v"}}}}}},
+			shouldContainErr: "BSON field '$median.method' is missing but a required field",
+		},
+		{
+			name:             "mergeObjects bad type",
+			group:            bson.D{{"x", bson.D{{"$mergeObjects", "$v"}}}},
+			shouldContainErr: "$mergeObjects requires object inputs, but input 3 is of type int",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			group := append(bson.D{{"_id", "$g"}}, tc.group...)
+
+			res, err := self.Aggregate(input, []bson.D{
+				{{"$group", group}},
+				{{"$sort", bson.D{{"_id", int32(1)}}}},
+			}, nil)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestAggregateVariables(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	input := []bson.D{{{"_id", int32(1)}, {"a", int32(2)}, {"o", bson.D{{"q", int32(5)}}}}}
+
+	opts := &self.AggregateOptions{
+		Let: bson.D{
+			{"x", int32(2)},
+			{"y", bson.D{{"$add", bson.A{"$$NOW", int32(1000)}}}},
+		},
+		Now: func() time.Time { return now },
+	}
+
+	tests := []struct {
+		name             string
+		pipeline         []bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:     "root",
+			pipeline: []bson.D{{{"$addFields", bson.D{{"b", int32(3)}, {"root", "$$ROOT"}}}}},
+			expected: []bson.D{{
+				{"_id", int32(1)}, {"a", int32(2)}, {"o", bson.D{{"q", int32(5)}}}, {"b", int32(3)},
+				{"root", bson.D{{"_id", int32(1)}, {"a", int32(2)}, {"o", bson.D{{"q", int32(5)}}}}},
+			}},
+		},
+		{
+			name:     "current path",
+			pipeline: []bson.D{{{"$project", bson.D{{"q", bson.D{{"$add", bson.A{"$$CURRENT.o.q", int32(1)}}}}}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"q", int32(6)}}},
+		},
+		{
+			name: "remove",
+			pipeline: []bson.D{{{"$set", bson.D{
+				{"a", "$$REMOVE"},
+				{"o", bson.D{{"$cond", bson.A{true, "$$REMOVE", int32(1)}}}},
+			}}}},
+			expected: []bson.D{{{"_id", int32(1)}}},
+		},
+		{
+			name: "now and cluster time",
+			pipeline: []bson.D{{{"$project", bson.D{
+				{"now", bson.D{{"$add", bson.A{"$$NOW", int32(0)}}}},
+				{"ts", bson.D{{"$ifNull", bson.A{"$$CLUSTER_TIME", int32(0)}}}},
+			}}}},
+			expected: []bson.D{{
+				{"_id", int32(1)},
+				{"now", primitive.NewDateTimeFromTime(now)},
+				{"ts", primitive.Timestamp{T: uint32(now.Unix()), I: 1}},
+			}},
+		},
+		{
+			name: "let option",
+			pipeline: []bson.D{
+				{{"$match", bson.D{{"$expr", bson.D{{"$eq", bson.A{"$a", "$$x"}}}}}}},
+				{{"$project", bson.D{{"y", bson.D{{"$ifNull", bson.A{"$$y", int32(0)}}}}}}},
+			},
+			expected: []bson.D{{{"_id", int32(1)}, {"y", primitive.NewDateTimeFromTime(now.Add(time.Second))}}},
+		},
+		{
+			name: "let operator",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$let", bson.D{
+				{"vars", bson.D{{"a", int32(10)}, {"x", bson.D{{"$multiply", bson.A{"$$x", "$a"}}}}}},
+				{"in", bson.D{{"$add", bson.A{"$$a", "$$x"}}}},
+			}}}}}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"r", int32(14)}}},
+		},
+		{
+			name: "let current",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$let", bson.D{
+				{"vars", bson.D{{"CURRENT", "$o"}}},
+				{"in", bson.D{{"$add", bson.A{"$q", "$$ROOT.a"}}}},
+			}}}}}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"r", int32(7)}}},
+		},
+		{
+			name: "group by root",
+			pipeline: []bson.D{{{"$group", bson.D{
+				{"_id", "$$ROOT.a"},
+				{"docs", bson.D{{"$push", "$$ROOT"}}},
+				{"sum", bson.D{{"$sum", "$$CURRENT.o.q"}}},
+			}}}},
+			expected: []bson.D{{
+				{"_id", int32(2)},
+				{"docs", bson.A{bson.D{{"_id", int32(1)}, {"a", int32(2)}, {"o", bson.D{{"q", int32(5)}}}}}},
+				{"sum", int32(5)},
+			}},
+		},
+		{
+			name: "let missing in",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$let", bson.D{
+				{"vars", bson.D{}},
+			}}}}}}}},
+			shouldContainErr: "Missing 'in' parameter to $let",
+		},
+		{
+			name: "let unknown parameter",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$let", bson.D{
+				{"vars", bson.D{}}, {"in", int32(1)}, {"foo", int32(1)},
+			}}}}}}}},
+			shouldContainErr: "Unrecognized parameter to $let: foo",
+		},
+		{
+			name: "let invalid variable name",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$let", bson.D{
+				{"vars", bson.D{{"Foo", int32(1)}}}, {"in", int32(1)},
+			}}}}}}}},
+			shouldContainErr: "'Foo' starts with an invalid character for a user variable name",
+		},
+		{
+			name: "let variable out of scope",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$add", bson.A{
+				bson.D{{"$let", bson.D{{"vars", bson.D{{"v", int32(1)}}}, {"in", "$$v"}}}},
+				"$$v",
+			}}}}}}}},
+			shouldContainErr: "Use of undefined variable: v",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Aggregate(input, tc.pipeline, opts)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+
+	t.Run("invalid let option", func(t *testing.T) {
+		_, err := self.Aggregate(input, nil, &self.AggregateOptions{Let: bson.D{{"$x", int32(1)}}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "'$x' starts with an invalid character for a user variable name")
+	})
+}
+
+func TestAggregateConvertOperators(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	oid := primitive.ObjectID{0x65, 0x93, 0x7d, 0x2d, 1, 2, 3, 4, 5, 6, 7, 8}
+	doc := bson.D{{"s", "42"}, {"f", 2.9}, {"l", int64(1) << 40}, {"d", date}, {"n", nil}}
+
+	runExpressionTests(t, []expressionTestCase{
+		{name: "toInt string", doc: doc, expression: bson.D{{"$toInt", "$s"}}, expected: int32(42)},
+		{name: "toInt double truncates", doc: doc, expression: bson.D{{"$toInt", "$f"}}, expected: int32(2)},
+		{name: "toInt bool", expression: bson.D{{"$toInt", true}}, expected: int32(1)},
+		{name: "toInt null", doc: doc, expression: bson.D{{"$toInt", "$n"}}, expected: nil},
+		{name: "toInt missing", doc: doc, expression: bson.D{{"$toInt", "$missing"}}, expected: nil},
+		{
+			name:             "toInt overflow",
+			doc:              doc,
+			expression:       bson.D{{"$toInt", "$l"}},
+			shouldContainErr: "Conversion would overflow target type in $convert with no onError value: 1099511627776",
+		},
+		{
+			name:             "toInt bad digit",
+			expression:       bson.D{{"$toInt", "1.5"}},
+			shouldContainErr: "Failed to parse number '1.5' in $convert with no onError value: Bad digit \".\" while parsing 1.5",
+		},
+		{
+			name:             "toInt NaN",
+			expression:       bson.D{{"$toInt", math.NaN()}},
+			shouldContainErr: "Attempt to convert NaN value to integer type in $convert with no onError value: nan",
+		},
+		{
+			name:             "toInt date",
+			doc:              doc,
+			expression:       bson.D{{"$toInt", "$d"}},
+			shouldContainErr: "Unsupported conversion from date to int in $convert with no onError value",
+		},
+		{name: "toLong date", doc: doc, expression: bson.D{{"$toLong", "$d"}}, expected: date.UnixMilli()},
+		{name: "toLong string", expression: bson.D{{"$toLong", "-9000000000"}}, expected: int64(-9000000000)},
+		{name: "toDouble string", expression: bson.D{{"$toDouble", "-1.5e3"}}, expected: -1500.0},
+		{name: "toDouble int", expression: bson.D{{"$toDouble", int32(3)}}, expected: 3.0},
+		{
+			name:             "toDouble hex",
+			expression:       bson.D{{"$toDouble", "0x1p4"}},
+			shouldContainErr: "Failed to parse number '0x1p4' in $convert with no onError value",
+		},
+		{name: "toBool zero", expression: bson.D{{"$toBool", int64(0)}}, expected: false},
+		{name: "toBool string", expression: bson.D{{"$toBool", "false"}}, expected: true},
+		{name: "toBool empty string", expression: bson.D{{"$toBool", ""}}, expected: true},
+		{name: "toString double", doc: doc, expression: bson.D{{"$toString", "$f"}}, expected: "2.9"},
+		{name: "toString bool", expression: bson.D{{"$toString", false}}, expected: "false"},
+		{name: "toString date", doc: doc, expression: bson.D{{"$toString", "$d"}}, expected: "2024-01-02T03:04:05.006Z"},
+		{name: "toString objectId", expression: bson.D{{"$toString", oid}}, expected: oid.Hex()},
+		{
+			name:             "toString array",
+			expression:       bson.D{{"$toString", bson.A{bson.A{}}}},
+			shouldContainErr: "Unsupported conversion from array to string in $convert with no onError value",
+		},
+		{name: "toObjectId", expression: bson.D{{"$toObjectId", oid.Hex()}}, expected: oid},
+		{
+			name:             "toObjectId short",
+			expression:       bson.D{{"$toObjectId", "abc"}},
+			shouldContainErr: "Invalid string length for parsing to OID, expected 24 but found 3",
+		},
+		{name: "toDate string", expression: bson.D{{"$toDate", "2024-01-02T03:04:05.006Z"}}, expected: primitive.NewDateTimeFromTime(date)},
+		{name: "toDate long", expression: bson.D{{"$toDate", date.UnixMilli()}}, expected: primitive.NewDateTimeFromTime(date)},
+		{
+			name:       "toDate objectId",
+			expression: bson.D{{"$toDate", oid}},
+			expected:   primitive.NewDateTimeFromTime(time.Unix(int64(0x65937d2d), 0)),
+		},
+		{
+			name:       "convert type code",
+			doc:        doc,
+			expression: bson.D{{"$convert", bson.D{{"input", "$s"}, {"to", int32(18)}}}},
+			expected:   int64(42),
+		},
+		{
+			name:       "convert onError",
+			expression: bson.D{{"$convert", bson.D{{"input", "abc"}, {"to", "int"}, {"onError", int32(-1)}}}},
+			expected:   int32(-1),
+		},
+		{
+			name:       "convert onNull",
+			doc:        doc,
+			expression: bson.D{{"$convert", bson.D{{"input", "$missing"}, {"to", "int"}, {"onNull", "none"}}}},
+			expected:   "none",
+		},
+		{
+			name:       "convert null to",
+			doc:        doc,
+			expression: bson.D{{"$convert", bson.D{{"input", "$s"}, {"to", nil}}}},
+			expected:   nil,
+		},
+		{
+			name:             "convert unknown type",
+			expression:       bson.D{{"$convert", bson.D{{"input", int32(1)}, {"to", "foo"}, {"onError", int32(0)}}}},
+			shouldContainErr: "Unknown type name: foo",
+		},
+		{
+			name:             "convert invalid type code",
+			expression:       bson.D{{"$convert", bson.D{{"input", int32(1)}, {"to", int32(100)}}}},
+			shouldContainErr: "In $convert, numeric value for 'to' does not correspond to a BSON type: 100",
+		},
+		{
+			name:             "convert missing to",
+			expression:       bson.D{{"$convert", bson.D{{"input", int32(1)}}}},
+			shouldContainErr: "Missing 'to' parameter to $convert",
+		},
+		{
+			name:             "convert unknown argument",
+			expression:       bson.D{{"$convert", bson.D{{"input", int32(1)}, {"to", "int"}, {"foo", int32(1)}}}},
+			shouldContainErr: "$convert found an unknown argument: foo",
+		},
+		{name: "isNumber", doc: doc, expression: bson.D{{"$isNumber", "$l"}}, expected: true},
+		{name: "isNumber string", doc: doc, expression: bson.D{{"$isNumber", "$s"}}, expected: false},
+		{name: "isNumber missing", doc: doc, expression: bson.D{{"$isNumber", "$missing"}}, expected: false},
+	})
+}
+
+func TestAggregateSetOperators(t *testing.T) {
+	doc := bson.D{
+		{"a", bson.A{int32(1), int32(2), int32(2), int32(3)}},
+		{"b", bson.A{2.0, int64(4)}},
+		{"o", bson.D{{"x", int32(1)}, {"a.b", "dotted"}, {"$price", int32(5)}}},
+		{"n", nil},
+	}
+
+	runExpressionTests(t, []expressionTestCase{
+		{
+			name:       "setUnion",
+			doc:        doc,
+			expression: bson.D{{"$setUnion", bson.A{"$a", "$b"}}},
+			expected:   bson.A{int32(1), int32(2), int32(3), int64(4)},
+		},
+		{name: "setUnion empty", expression: bson.D{{"$setUnion", bson.A{}}}, expected: bson.A{}},
+		{name: "setUnion null", doc: doc, expression: bson.D{{"$setUnion", bson.A{"$a", "$n"}}}, expected: nil},
+		{
+			name:             "setUnion not array",
+			doc:              doc,
+			expression:       bson.D{{"$setUnion", bson.A{"$a", "$o"}}},
+			shouldContainErr: "All operands of $setUnion must be arrays. One argument is of type: object",
+		},
+		{
+			name:       "setIntersection numeric equality",
+			doc:        doc,
+			expression: bson.D{{"$setIntersection", bson.A{"$a", "$b"}}},
+			expected:   bson.A{int32(2)},
+		},
+		{
+			name:       "setDifference",
+			doc:        doc,
+			expression: bson.D{{"$setDifference", bson.A{"$a", "$b"}}},
+			expected:   bson.A{int32(1), int32(3)},
+		},
+		{name: "setDifference missing", doc: doc, expression: bson.D{{"$setDifference", bson.A{"$a", "$missing"}}}, expected: nil},
+		{
+			name:             "setDifference second not array",
+			doc:              doc,
+			expression:       bson.D{{"$setDifference", bson.A{"$a", int32(1)}}},
+			shouldContainErr: "both operands of $setDifference must be arrays. Second argument is of type: int",
+		},
+		{
+			name:       "setEquals",
+			expression: bson.D{{"$setEquals", bson.A{bson.A{int32(1), int32(2), int32(1)}, bson.A{2.0, int64(1)}}}},
+			expected:   true,
+		},
+		{
+			name:       "setEquals not equal",
+			doc:        doc,
+			expression: bson.D{{"$setEquals", bson.A{"$a", "$b"}}},
+			expected:   false,
+		},
+		{
+			name:             "setEquals one argument",
+			doc:              doc,
+			expression:       bson.D{{"$setEquals", bson.A{"$a"}}},
+			shouldContainErr: "$setEquals needs at least two arguments had: 1",
+		},
+		{
+			name:             "setEquals null",
+			doc:              doc,
+			expression:       bson.D{{"$setEquals", bson.A{"$a", "$n"}}},
+			shouldContainErr: "All operands of $setEquals must be arrays. One argument is of type: null",
+		},
+		{
+			name:       "setIsSubset",
+			expression: bson.D{{"$setIsSubset", bson.A{bson.A{1.0}, bson.A{int32(1), int32(2)}}}},
+			expected:   true,
+		},
+		{
+			name:             "setIsSubset missing",
+			doc:              doc,
+			expression:       bson.D{{"$setIsSubset", bson.A{"$missing", "$a"}}},
+			shouldContainErr: "both operands of $setIsSubset must be arrays. First argument is of type: missing",
+		},
+		{
+			name:       "mergeObjects",
+			doc:        doc,
+			expression: bson.D{{"$mergeObjects", bson.A{"$o", "$n", bson.D{{"x", int32(2)}, {"y", true}}}}},
+			expected:   bson.D{{"x", int32(2)}, {"a.b", "dotted"}, {"$price", int32(5)}, {"y", true}},
+		},
+		{
+			name:             "mergeObjects not object",
+			doc:              doc,
+			expression:       bson.D{{"$mergeObjects", bson.A{"$o", "$a"}}},
+			shouldContainErr: "$mergeObjects requires object inputs, but input [ 1, 2, 2, 3 ] is of type array",
+		},
+		{
+			name:       "getField dotted name",
+			doc:        doc,
+			expression: bson.D{{"$getField", bson.D{{"field", "a.b"}, {"input", "$o"}}}},
+			expected:   "dotted",
+		},
+		{
+			name:       "getField literal dollar name",
+			doc:        doc,
+			expression: bson.D{{"$getField", bson.D{{"field", bson.D{{"$literal", "$price"}}}, {"input", "$o"}}}},
+			expected:   int32(5),
+		},
+		{name: "getField shorthand", doc: doc, expression: bson.D{{"$getField", "n"}}, expected: nil},
+		{
+			name:       "getField null input",
+			doc:        doc,
+			expression: bson.D{{"$getField", bson.D{{"field", "x"}, {"input", "$n"}}}},
+			expected:   nil,
+		},
+		{
+			name:             "getField non-constant",
+			doc:              doc,
+			expression:       bson.D{{"$getField", "$x"}},
+			shouldContainErr: "$getField requires 'field' to evaluate to a constant, but got a non-constant argument",
+		},
+		{
+			name:             "getField field type",
+			expression:       bson.D{{"$getField", bson.D{{"field", int32(1)}}}},
+			shouldContainErr: "$getField requires 'field' to evaluate to type String, but got int",
+		},
+		{
+			name:             "getField input type",
+			doc:              doc,
+			expression:       bson.D{{"$getField", bson.D{{"field", "x"}, {"input", "$a"}}}},
+			shouldContainErr: "$getField requires 'input' to evaluate to type Object, but got array",
+		},
+		{
+			name:       "setField",
+			doc:        doc,
+			expression: bson.D{{"$setField", bson.D{{"field", "a.b"}, {"input", "$o"}, {"value", "replaced"}}}},
+			expected:   bson.D{{"x", int32(1)}, {"a.b", "replaced"}, {"$price", int32(5)}},
+		},
+		{
+			name:       "setField remove",
+			doc:        doc,
+			expression: bson.D{{"$setField", bson.D{{"field", "x"}, {"input", "$o"}, {"value", "$$REMOVE"}}}},
+			expected:   bson.D{{"a.b", "dotted"}, {"$price", int32(5)}},
+		},
+		{
+			name:       "setField null input",
+			doc:        doc,
+			expression: bson.D{{"$setField", bson.D{{"field", "x"}, {"input", "$n"}, {"value", int32(1)}}}},
+			expected:   nil,
+		},
+		{
+			name:             "setField missing value",
+			expression:       bson.D{{"$setField", bson.D{{"field", "x"}, {"input", bson.D{}}}}},
+			shouldContainErr: "$setField requires 'value' to be specified",
+		},
+		{
+			name:       "unsetField",
+			doc:        doc,
+			expression: bson.D{{"$unsetField", bson.D{{"field", bson.D{{"$literal", "$price"}}}, {"input", "$o"}}}},
+			expected:   bson.D{{"x", int32(1)}, {"a.b", "dotted"}},
+		},
+		{
+			name:             "unsetField unknown argument",
+			expression:       bson.D{{"$unsetField", bson.D{{"field", "x"}, {"input", bson.D{}}, {"value", int32(1)}}}},
+			shouldContainErr: "$unsetField found an unknown argument: value",
+		},
+	})
+}
+
+func TestAggregateDecimalOperators(t *testing.T) {
+	dec := func(s string) primitive.Decimal128 {
+		d, err := primitive.ParseDecimal128(s)
+		test.That(t, err, test.ShouldBeNil)
+		return d
+	}
+
+	doc := bson.D{{"a", dec("1.5")}, {"b", dec("0.1")}, {"c", dec("0.2")}, {"i", int32(2)}}
+
+	runExpressionTests(t, []expressionTestCase{
+		{name: "add", doc: doc, expression: bson.D{{"$add", bson.A{"$b", "$c"}}}, expected: dec("0.3")},
+		{name: "add int", doc: doc, expression: bson.D{{"$add", bson.A{"$a", "$i"}}}, expected: dec("3.5")},
+		{name: "add double", doc: doc, expression: bson.D{{"$add", bson.A{"$a", 0.5}}}, expected: dec("2.000000000000000")},
+		{name: "subtract", doc: doc, expression: bson.D{{"$subtract", bson.A{"$c", "$b"}}}, expected: dec("0.1")},
+		{name: "multiply", doc: doc, expression: bson.D{{"$multiply", bson.A{"$a", "$i"}}}, expected: dec("3.0")},
+		{
+			name:       "divide",
+			doc:        doc,
+			expression: bson.D{{"$divide", bson.A{int32(1), dec("3")}}},
+			expected:   dec("0.3333333333333333333333333333333333"),
+		},
+		{name: "mod", doc: doc, expression: bson.D{{"$mod", bson.A{"$a", dec("0.4")}}}, expected: dec("0.3")},
+		{name: "abs", expression: bson.D{{"$abs", dec("-2.5")}}, expected: dec("2.5")},
+		{name: "round", expression: bson.D{{"$round", bson.A{dec("2.345"), int32(2)}}}, expected: dec("2.34")},
+		{name: "trunc", expression: bson.D{{"$trunc", bson.A{dec("-2.789"), int32(1)}}}, expected: dec("-2.7")},
+		{name: "sqrt", expression: bson.D{{"$sqrt", dec("2.25")}}, expected: dec("1.5")},
+		{name: "sum", doc: doc, expression: bson.D{{"$sum", bson.A{"$a", "$b", "$i"}}}, expected: dec("3.6")},
+		{name: "eq int", expression: bson.D{{"$eq", bson.A{dec("1.0"), int32(1)}}}, expected: true},
+		{name: "lt double", expression: bson.D{{"$lt", bson.A{dec("0.1"), 0.1}}}, expected: true},
+		{name: "type", doc: doc, expression: bson.D{{"$type", "$a"}}, expected: "decimal"},
+		{name: "isNumber", doc: doc, expression: bson.D{{"$isNumber", "$a"}}, expected: true},
+		{name: "toDecimal", expression: bson.D{{"$toDecimal", "12.50"}}, expected: dec("12.50")},
+		{name: "toDecimal double", expression: bson.D{{"$toDecimal", 0.1}}, expected: dec("0.100000000000000")},
+		{name: "toDouble", doc: doc, expression: bson.D{{"$toDouble", "$a"}}, expected: 1.5},
+		{name: "toInt", expression: bson.D{{"$toInt", dec("-7.9")}}, expected: int32(-7)},
+		{name: "toString", doc: doc, expression: bson.D{{"$toString", "$a"}}, expected: "1.5"},
+		{
+			name:             "toLong NaN",
+			expression:       bson.D{{"$toLong", dec("NaN")}},
+			shouldContainErr: "Attempt to convert NaN value to integer type",
+		},
+	})
+
+	t.Run("group", func(t *testing.T) {
+		input := []bson.D{
+			{{"_id", int32(1)}, {"g", dec("1.0")}, {"v", dec("0.1")}},
+			{{"_id", int32(2)}, {"g", int32(1)}, {"v", dec("0.2")}},
+			{{"_id", int32(3)}, {"g", int32(1)}, {"v", int32(1)}},
+		}
+		pipeline := []bson.D{{{"$group", bson.D{
+			{"_id", "$g"},
+			{"sum", bson.D{{"$sum", "$v"}}},
+			{"avg", bson.D{{"$avg", "$v"}}},
+			{"max", bson.D{{"$max", "$v"}}},
+		}}}}
+
+		res, err := self.Aggregate(input, pipeline, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", dec("1.0")}, {"sum", dec("1.3")}, {"avg", dec("0.4333333333333333333333333333333333")}, {"max", int32(1)}},
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package update
+
+import (
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newCollation validates the driver's collation options and returns the collation to compare strings with.
+//
+// Locale, Strength, CaseLevel, NumericOrdering and Alternate are supported.
+// It returns nil for nil options and for the "simple" locale.
+func newCollation(c *options.Collation) (*types.Collation, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	// build the same document the driver sends to the server, so it is validated like the collation command field
+	d := bson.D{{Key: "locale", Value: c.Locale}}
+	if c.CaseLevel {
+		d = append(d, bson.E{Key: "caseLevel", Value: true})
+	}
+	if c.CaseFirst != "" {
+		d = append(d, bson.E{Key: "caseFirst", Value: c.CaseFirst})
+	}
+	if c.Strength != 0 {
+		d = append(d, bson.E{Key: "strength", Value: int32(c.Strength)})
+	}
+	if c.NumericOrdering {
+		d = append(d, bson.E{Key: "numericOrdering", Value: true})
+	}
+	if c.Alternate != "" {
+		d = append(d, bson.E{Key: "alternate", Value: c.Alternate})
+	}
+	if c.MaxVariable != "" {
+		d = append(d, bson.E{Key: "maxVariable", Value: c.MaxVariable})
+	}
+	if c.Normalization {
+		d = append(d, bson.E{Key: "normalization", Value: true})
+	}
+	if c.Backwards {
+		d = append(d, bson.E{Key: "backwards", Value: true})
+	}
+
+	doc, err := convertDToDocument(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert collation")
+	}
+
+	return common.GetCollation(doc)
+}
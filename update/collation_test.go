@@ -0,0 +1,167 @@
+package update_test
+
+import (
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.viam.com/test"
+)
+
+func TestCollation(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "bob"}},
+		{{"_id", int32(2)}, {"name", "Alice"}},
+		{{"_id", int32(3)}, {"name", "alice"}},
+		{{"_id", int32(4)}, {"name", "Émile"}},
+		{{"_id", int32(5)}, {"name", "file10"}},
+		{{"_id", int32(6)}, {"name", "file9"}},
+	}
+	caseInsensitive := &options.Collation{Locale: "en", Strength: 2}
+
+	t.Run("find", func(t *testing.T) {
+		res, err := self.Find(input, bson.D{{"name", "ALICE"}}, &self.FindOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, input[1:3])
+
+		res, err = self.Find(input, bson.D{{"name", bson.D{{"$in", bson.A{"BOB", "emile"}}}}},
+			&self.FindOptions{Collation: &options.Collation{Locale: "fr", Strength: 1}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{input[0], input[3]})
+
+		res, err = self.Find(input, bson.D{{"name", "ALICE"}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldBeEmpty)
+	})
+
+	t.Run("aggregate sort", func(t *testing.T) {
+		pipeline := []bson.D{
+			{{"$sort", bson.D{{"name", int32(1)}, {"_id", int32(1)}}}},
+			{{"$project", bson.D{{"_id", int32(1)}}}},
+		}
+
+		res, err := self.Aggregate(input, pipeline, &self.AggregateOptions{
+			Collation: &options.Collation{Locale: "en", NumericOrdering: true},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", int32(3)}},
+			{{"_id", int32(2)}},
+			{{"_id", int32(1)}},
+			{{"_id", int32(4)}},
+			{{"_id", int32(6)}},
+			{{"_id", int32(5)}},
+		})
+	})
+
+	t.Run("aggregate match", func(t *testing.T) {
+		res, err := self.Aggregate(input, []bson.D{{{"$match", bson.D{{"name", "BOB"}}}}},
+			&self.AggregateOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, input[:1])
+	})
+
+	t.Run("find expr", func(t *testing.T) {
+		filter := bson.D{{"$expr", bson.D{{"$eq", bson.A{"$name", "ALICE"}}}}}
+
+		res, err := self.Find(input, filter, &self.FindOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, input[1:3])
+
+		res, err = self.Find(input, filter, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldBeEmpty)
+	})
+
+	t.Run("aggregate expressions", func(t *testing.T) {
+		res, err := self.Aggregate(input[:3], []bson.D{
+			{{"$project", bson.D{{"isAlice", bson.D{{"$eq", bson.A{"$name", "ALICE"}}}}}}},
+		}, &self.AggregateOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", int32(1)}, {"isAlice", false}},
+			{{"_id", int32(2)}, {"isAlice", true}},
+			{{"_id", int32(3)}, {"isAlice", true}},
+		})
+	})
+
+	t.Run("aggregate group", func(t *testing.T) {
+		pipeline := []bson.D{
+			{{"$group", bson.D{{"_id", "$name"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			{{"$sort", bson.D{{"count", int32(-1)}}}},
+		}
+
+		res, err := self.Aggregate(input[:3], pipeline, &self.AggregateOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", "Alice"}, {"count", int32(2)}},
+			{{"_id", "bob"}, {"count", int32(1)}},
+		})
+
+		res, err = self.Aggregate(input[:3], pipeline, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldHaveLength, 3)
+	})
+
+	t.Run("count and distinct", func(t *testing.T) {
+		filter := bson.D{{"name", "ALICE"}}
+
+		n, err := self.CountWithOptions(input, filter, 0, 0, &self.CountOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, 2)
+
+		n, err = self.Count(input, filter, 0, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, 0)
+
+		values, err := self.DistinctWithOptions(input, "name", bson.D{{"name", bson.D{{"$lt", "C"}}}},
+			&self.DistinctOptions{Collation: caseInsensitive})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, values, test.ShouldResemble, []any{"Alice", "bob"})
+
+		values, err = self.Distinct(input, "name", bson.D{{"name", bson.D{{"$lt", "C"}}}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, values, test.ShouldResemble, []any{"Alice"})
+	})
+
+	t.Run("update", func(t *testing.T) {
+		doc := bson.D{{"_id", int32(1)}, {"names", bson.A{"Alice", "Bob"}}}
+		opts := &self.UpdateOptions{Collation: caseInsensitive}
+
+		res, err := self.UpdateDocumentWithOptions(doc, bson.D{
+			{"$addToSet", bson.D{{"names", bson.D{{"$each", bson.A{"alice", "Carol"}}}}}},
+		}, opts)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, bson.D{{"_id", int32(1)}, {"names", bson.A{"Alice", "Bob", "Carol"}}})
+
+		res, err = self.UpdateDocumentWithOptions(doc, bson.D{{"$pull", bson.D{{"names", "BOB"}}}}, opts)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, bson.D{{"_id", int32(1)}, {"names", bson.A{"Alice"}}})
+
+		res, err = self.UpdateDocumentWithOptions(doc, bson.D{{"$pullAll", bson.D{{"names", bson.A{"alice"}}}}}, opts)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, bson.D{{"_id", int32(1)}, {"names", bson.A{"Bob"}}})
+
+		res, err = self.UpdateDocument(doc, bson.D{{"$pull", bson.D{{"names", "BOB"}}}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, doc)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for name, c := range map[string]*options.Collation{
+			"missing locale": {Strength: 2},
+			"strength":       {Locale: "en", Strength: 7},
+			"caseFirst":      {Locale: "en", CaseFirst: "upper"},
+		} {
+			c := c
+			t.Run(name, func(t *testing.T) {
+				_, err := self.Find(input, bson.D{}, &self.FindOptions{Collation: c})
+				test.That(t, err, test.ShouldNotBeNil)
+
+				_, err = self.CompileWithOptions(bson.D{{"$set", bson.D{{"a", int32(1)}}}}, &self.UpdateOptions{Collation: c})
+				test.That(t, err, test.ShouldNotBeNil)
+			})
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package update
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/bson2"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CompiledUpdate is an update document that was converted and validated once
+// and can be applied to many documents.
+//
+// It is safe for concurrent use.
+type CompiledUpdate struct {
+	updates  []common.Update
+	paths    []common.UpdatePaths
+	rawPaths rawPaths
+	coll     *types.Collation
+	now      func() time.Time
+
+	validator           *common.Validator
+	onValidationWarning func(err error)
+}
+
+// UpdateOptions configures Compile and UpdateDocument.
+type UpdateOptions struct {
+	// Collation defines how $addToSet, $pull and $pullAll compare strings,
+	// like the collation option of the update command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+
+	// Now returns the time set by $currentDate.
+	// It is called once per Apply or UpdateDocument call.
+	// If nil, time.Now is used.
+	Now func() time.Time
+
+	// Validator is a query filter that updated documents must match,
+	// like the validator option of the create command.
+	// It may use $jsonSchema and other query operators except $near, $nearSphere, $text and $where.
+	// Documents that do not match it are rejected with a mongo.WriteError
+	// with DocumentValidationFailure code (121) and errInfo in Details.
+	Validator bson.D
+
+	// ValidationLevel is "strict" (the default), "moderate" or "off".
+	// With "moderate", updates of documents that did not match the validator before the update are not validated.
+	ValidationLevel string
+
+	// ValidationAction is "error" (the default) or "warn".
+	// With "warn", documents that fail validation are returned anyway
+	// and the validation error is passed to OnValidationWarning.
+	ValidationAction string
+
+	// OnValidationWarning is called for documents that fail validation with the "warn" action.
+	// It may be nil.
+	OnValidationWarning func(err error)
+}
+
+// Compile converts and validates the passed updateDoc and parses its paths.
+//
+// The passed updateDoc must conform to the mongodb Update Operator spec
+// https://www.mongodb.com/docs/manual/reference/operator/update/
+func Compile(updateDoc bson.D) (*CompiledUpdate, error) {
+	return CompileWithOptions(updateDoc, nil)
+}
+
+// CompileWithOptions is Compile with the given options.
+//
+// opts may be nil.
+func CompileWithOptions(updateDoc bson.D, opts *UpdateOptions) (*CompiledUpdate, error) {
+	if opts == nil {
+		opts = new(UpdateOptions)
+	}
+	if len(updateDoc) == 0 {
+		return nil, errors.New("update document must have at least one element")
+	}
+	convertedUpdates, err := convertUpdateParams(updateDoc)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert update operations to update params")
+	}
+
+	coll, err := newCollation(opts.Collation)
+	if err != nil {
+		return nil, err
+	}
+
+	validator, err := newValidator(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CompiledUpdate{
+		updates:  convertedUpdates,
+		paths:    make([]common.UpdatePaths, len(convertedUpdates)),
+		rawPaths: rawPaths{"_id": {whole: true}},
+		coll:     coll,
+		now:      opts.Now,
+
+		validator:           validator,
+		onValidationWarning: opts.OnValidationWarning,
+	}
+	for i, update := range convertedUpdates {
+		// from ferret/handler/msg_update.go
+		if _, err := common.HasSupportedUpdateModifiers("update", update.Update); err != nil {
+			return nil, err
+		}
+		c.paths[i] = common.ParseUpdatePaths(update.Update)
+		collectUpdatePaths(update.Update, c.rawPaths)
+	}
+	return c, nil
+}
+
+// Apply updates the provided bson.D document and returns the new document
+// like UpdateDocument does.
+func (c *CompiledUpdate) Apply(document bson.D) (bson.D, error) {
+	doc, err := convertDToDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.ValidateData(); err != nil {
+		return nil, errors.Wrap(err, "validating document")
+	}
+
+	var oldDoc *types.Document
+	if c.validator != nil && c.validator.Level == common.ValidationLevelModerate {
+		oldDoc = doc.DeepCopy()
+	}
+
+	if err := c.apply(doc); err != nil {
+		return nil, err
+	}
+
+	if c.validator != nil {
+		if err := c.validate(oldDoc, doc); err != nil {
+			return nil, err
+		}
+	}
+	return convertDocumentToD(doc)
+}
+
+// ApplyRaw updates the provided raw BSON document and returns the new raw BSON document
+// like UpdateRaw does.
+func (c *CompiledUpdate) ApplyRaw(document bson.Raw) (bson.Raw, error) {
+	orig, partial, err := decodePartial(bson2.RawDocument(document), c.rawPaths)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode raw document")
+	}
+	doc, err := partial.Convert()
+	if err != nil {
+		return nil, errors.Wrap(err, "decode raw document")
+	}
+	if err := doc.ValidateData(); err != nil {
+		return nil, errors.Wrap(err, "validating document")
+	}
+	if err := c.apply(doc); err != nil {
+		return nil, err
+	}
+
+	updated, err := bson2.ConvertDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergePartial(orig, c.rawPaths, updated)
+	if err != nil {
+		return nil, err
+	}
+	result, err := merged.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.validator != nil {
+		// the validator may reference any field, so it needs the whole documents
+		var oldDoc *types.Document
+		if c.validator.Level == common.ValidationLevelModerate {
+			if oldDoc, err = bson2.RawDocument(document).Convert(); err != nil {
+				return nil, errors.Wrap(err, "decode raw document")
+			}
+		}
+
+		newDoc, err := result.Convert()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.validate(oldDoc, newDoc); err != nil {
+			return nil, err
+		}
+	}
+	return bson.Raw(result), nil
+}
+
+// apply applies the compiled updates to the document in place.
+func (c *CompiledUpdate) apply(doc *types.Document) error {
+	// all updates of a single application see the same time
+	vars, err := newVariables(c.now, nil)
+	if err != nil {
+		return err
+	}
+
+	for i, update := range c.updates {
+		// update values are inserted into the document as is, so each application needs its own copy
+		_, err := common.UpdateDocumentWithPaths("update", doc, update.Update.DeepCopy(), true, c.paths[i], vars, c.coll)
+		if err != nil {
+			return errors.Wrap(err, "failed to update document")
+		}
+
+		if !doc.Has("_id") {
+			doc.Set("_id", types.NewObjectID())
+		}
+		if err := doc.ValidateData(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
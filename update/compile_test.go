@@ -0,0 +1,158 @@
+package update
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+)
+
+func TestCompile(t *testing.T) {
+	update := bson.D{
+		{"$inc", bson.D{{"count", int32(1)}, {"stats.views", int64(2)}}},
+		{"$set", bson.D{{"meta", bson.D{{"_id", "m"}, {"tags", bson.A{-0.0}}}}}},
+		{"$push", bson.D{{"log", bson.D{{"event", "inc"}}}}},
+		{"$rename", bson.D{{"old", "new"}}},
+	}
+
+	c, err := Compile(update)
+	test.That(t, err, test.ShouldBeNil)
+
+	documents := make([]bson.D, 10)
+	for i := range documents {
+		documents[i] = bson.D{
+			{"_id", int32(i)},
+			{"count", int32(i)},
+			{"stats", bson.D{{"views", int64(i * 10)}}},
+			{"old", fmt.Sprint(i)},
+		}
+	}
+
+	for _, document := range documents {
+		expected, err := UpdateDocument(document, update)
+		test.That(t, err, test.ShouldBeNil)
+
+		actual, err := c.Apply(document)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, actual, test.ShouldResemble, expected)
+
+		// applying again must not observe changes made by the previous application
+		actual, err = c.Apply(document)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, actual, test.ShouldResemble, expected)
+
+		raw, err := bson.Marshal(document)
+		test.That(t, err, test.ShouldBeNil)
+
+		res, err := c.ApplyRaw(raw)
+		test.That(t, err, test.ShouldBeNil)
+
+		var actualRaw bson.D
+		test.That(t, bson.Unmarshal(res, &actualRaw), test.ShouldBeNil)
+		test.That(t, actualRaw, test.ShouldResemble, expected)
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for _, document := range documents {
+			document := document
+			expected, err := UpdateDocument(document, update)
+			test.That(t, err, test.ShouldBeNil)
+
+			for j := 0; j < 4; j++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					actual, err := c.Apply(document)
+					test.That(t, err, test.ShouldBeNil)
+					test.That(t, actual, test.ShouldResemble, expected)
+				}()
+			}
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, update := range []bson.D{
+			{},
+			{{"$foo", bson.D{{"a", int32(1)}}}},
+			{{"$set", bson.D{{"a", int32(1)}}}, {"$inc", bson.D{{"a", int32(1)}}}},
+		} {
+			_, err := Compile(update)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+
+		// errors that depend on the document are reported by Apply
+		c, err := Compile(bson.D{{"$inc", bson.D{{"a", int32(1)}}}})
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = c.Apply(bson.D{{"_id", int32(1)}, {"a", "string"}})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		c, err = Compile(bson.D{{"$inc", bson.D{{"a", "b"}}}})
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = c.Apply(bson.D{{"_id", int32(1)}})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		maxDecimal, err := primitive.ParseDecimal128("9.999999999999999999999999999999999E6144")
+		test.That(t, err, test.ShouldBeNil)
+
+		c, err = Compile(bson.D{{"$inc", bson.D{{"a", maxDecimal}}}})
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = c.Apply(bson.D{{"_id", int32(1)}, {"a", maxDecimal}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "infinity values are not allowed")
+	})
+
+	t.Run("Now", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+		calls := 0
+
+		c, err := CompileWithOptions(bson.D{
+			{"$currentDate", bson.D{{"at", true}}},
+			{"$set", bson.D{{"v", "$$NOW"}}},
+		}, &UpdateOptions{Now: func() time.Time {
+			calls++
+			return now.Add(time.Duration(calls) * time.Hour)
+		}})
+		test.That(t, err, test.ShouldBeNil)
+
+		for i := 1; i <= 2; i++ {
+			actual, err := c.Apply(bson.D{{"_id", int32(1)}})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, actual, test.ShouldResemble, bson.D{
+				{"_id", int32(1)}, {"at", primitive.NewDateTimeFromTime(now.Add(time.Duration(i) * time.Hour))}, {"v", "$$NOW"},
+			})
+		}
+	})
+}
+
+func BenchmarkCompiledUpdate(b *testing.B) {
+	d := benchmarkDocument()
+	update := bson.D{
+		{"$set", bson.D{{"profile.age", int32(43)}}},
+		{"$inc", bson.D{{"count", int32(1)}}},
+		{"$push", bson.D{{"items", bson.D{{"sku", "new"}}}}},
+	}
+
+	c, err := Compile(update)
+	test.That(b, err, test.ShouldBeNil)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Apply(d); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
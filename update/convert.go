@@ -0,0 +1,268 @@
+package update
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/bson2"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// convertDToDocument converts the driver document to *types.Document.
+//
+// Values are converted directly, without encoding the document to BSON and decoding it back.
+// The result is the same as for bson.Marshal followed by decoding the raw BSON.
+func convertDToDocument(d bson.D) (*types.Document, error) {
+	pairs := make([]any, 0, len(d)*2)
+
+	for _, e := range d {
+		if err := validateKey(e.Key); err != nil {
+			return nil, err
+		}
+
+		v, err := convertToTypes(e.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "convert field %q", e.Key)
+		}
+
+		pairs = append(pairs, e.Key, v)
+	}
+
+	return types.NewDocument(pairs...)
+}
+
+// convertDocumentToD converts *types.Document to the driver document.
+//
+// The result is the same as for bson.Unmarshal of the encoded document into bson.D.
+func convertDocumentToD(document *types.Document) (bson.D, error) {
+	keys := document.Keys()
+	values := document.Values()
+
+	res := make(bson.D, len(keys))
+	for i, k := range keys {
+		res[i] = bson.E{Key: k, Value: convertFromTypes(values[i])}
+	}
+
+	return res, nil
+}
+
+// convertToTypes converts the driver value to types package value.
+//
+// Values of types without a direct conversion (structs, custom marshalers, etc.)
+// are encoded by the driver; see convertByMarshaling.
+func convertToTypes(v any) (any, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.Null, nil
+	case primitive.D:
+		return convertDToDocument(v)
+	case primitive.M:
+		d := make(bson.D, 0, len(v))
+		for k, v := range v {
+			d = append(d, bson.E{Key: k, Value: v})
+		}
+
+		return convertDToDocument(d)
+	case primitive.A:
+		return convertSliceToArray(v)
+	case []any:
+		return convertSliceToArray(v)
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case string:
+		return v, nil
+	case bool:
+		return v, nil
+	case int32:
+		return v, nil
+	case int64:
+		return v, nil
+	case int:
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			return int32(v), nil
+		}
+
+		return int64(v), nil
+	case int8:
+		return int32(v), nil
+	case int16:
+		return int32(v), nil
+	case uint8:
+		return int32(v), nil
+	case uint16:
+		return int32(v), nil
+	case uint32:
+		return int64(v), nil
+	case time.Time:
+		return time.UnixMilli(v.UnixMilli()).UTC(), nil
+	case primitive.DateTime:
+		return time.UnixMilli(int64(v)).UTC(), nil
+	case primitive.ObjectID:
+		return types.ObjectID(v), nil
+	case primitive.Binary:
+		if v.Subtype == bson.TypeBinaryBinaryOld {
+			// the old binary subtype has an additional length prefix in the encoded form
+			return convertByMarshaling(v)
+		}
+
+		return types.Binary{Subtype: types.BinarySubtype(v.Subtype), B: v.Data}, nil
+	case []byte:
+		if v == nil {
+			return types.Null, nil
+		}
+
+		return types.Binary{Subtype: types.BinaryGeneric, B: v}, nil
+	case primitive.Regex:
+		if err := validateKey(v.Pattern); err != nil {
+			return nil, err
+		}
+
+		return types.Regex{Pattern: v.Pattern, Options: sortRegexOptions(v.Options)}, nil
+	case primitive.Timestamp:
+		return types.Timestamp(uint64(v.T)<<32 | uint64(v.I)), nil
+	case primitive.Decimal128:
+		h, l := v.GetBytes()
+		return types.Decimal128{H: h, L: l}, nil
+	case primitive.Null:
+		return types.Null, nil
+	case primitive.MinKey:
+		return types.MinKey, nil
+	case primitive.MaxKey:
+		return types.MaxKey, nil
+	case primitive.Undefined:
+		return types.Undefined, nil
+	case primitive.Symbol:
+		return types.Symbol(v), nil
+	case primitive.JavaScript:
+		return types.JavaScript(v), nil
+	case primitive.DBPointer:
+		return types.DBPointer{Namespace: v.DB, ID: types.ObjectID(v.Pointer)}, nil
+	default:
+		return convertByMarshaling(v)
+	}
+}
+
+// convertSliceToArray converts the driver array to *types.Array.
+func convertSliceToArray(s []any) (*types.Array, error) {
+	values := make([]any, len(s))
+
+	for i, v := range s {
+		var err error
+		if values[i], err = convertToTypes(v); err != nil {
+			return nil, errors.Wrapf(err, "convert array element %d", i)
+		}
+	}
+
+	return types.NewArray(values...)
+}
+
+// convertByMarshaling converts the driver value to types package value
+// by encoding it with the driver and decoding the raw BSON.
+func convertByMarshaling(v any) (any, error) {
+	b, err := bson.Marshal(bson.D{{Key: "v", Value: v}})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshall bson.D")
+	}
+
+	doc, err := bson2.RawDocument(b).Convert()
+	if err != nil {
+		return nil, errors.Wrap(err, "decode raw bson bytes")
+	}
+
+	return must.NotFail(doc.Get("v")), nil
+}
+
+// convertFromTypes converts types package value to the driver value.
+//
+// It panics for invalid types.
+func convertFromTypes(v any) any {
+	switch v := v.(type) {
+	case *types.Document:
+		return must.NotFail(convertDocumentToD(v))
+	case *types.Array:
+		res := make(bson.A, v.Len())
+		for i := range res {
+			res[i] = convertFromTypes(must.NotFail(v.Get(i)))
+		}
+
+		return res
+	case float64:
+		return v
+	case string:
+		return v
+	case types.Binary:
+		if v.Subtype == types.BinarySubtype(bson.TypeBinaryBinaryOld) && len(v.B) >= 4 {
+			// the driver strips the additional length prefix of the old binary subtype
+			return primitive.Binary{Subtype: byte(v.Subtype), Data: v.B[4:]}
+		}
+
+		return primitive.Binary{Subtype: byte(v.Subtype), Data: v.B}
+	case types.ObjectID:
+		return primitive.ObjectID(v)
+	case bool:
+		return v
+	case time.Time:
+		return primitive.NewDateTimeFromTime(v)
+	case types.NullType:
+		return nil
+	case types.Regex:
+		return primitive.Regex{Pattern: v.Pattern, Options: v.Options}
+	case int32:
+		return v
+	case types.Timestamp:
+		return primitive.Timestamp{T: uint32(v >> 32), I: uint32(v)}
+	case int64:
+		return v
+	case types.Decimal128:
+		return primitive.NewDecimal128(v.H, v.L)
+	case types.MinKeyType:
+		return primitive.MinKey{}
+	case types.MaxKeyType:
+		return primitive.MaxKey{}
+	case types.UndefinedType:
+		return primitive.Undefined{}
+	case types.Symbol:
+		return primitive.Symbol(v)
+	case types.JavaScript:
+		return primitive.JavaScript(v)
+	case types.JavaScriptScope:
+		return primitive.CodeWithScope{
+			Code:  primitive.JavaScript(v.Code),
+			Scope: must.NotFail(convertDocumentToD(v.Scope)),
+		}
+	case types.DBPointer:
+		return primitive.DBPointer{DB: v.Namespace, Pointer: primitive.ObjectID(v.ID)}
+	default:
+		panic(fmt.Sprintf("invalid type %T", v))
+	}
+}
+
+// validateKey returns an error if the string can't be encoded as BSON cstring, like the driver does.
+func validateKey(s string) error {
+	if strings.IndexByte(s, 0) >= 0 {
+		return errors.Errorf("BSON element key cannot contain null bytes: %q", s)
+	}
+
+	return nil
+}
+
+// sortRegexOptions returns regular expression options in the alphabetical order, as the driver encodes them.
+func sortRegexOptions(options string) string {
+	b := []byte(options)
+
+	for i := 1; i < len(b); i++ {
+		for j := i; j > 0 && b[j] < b[j-1]; j-- {
+			b[j], b[j-1] = b[j-1], b[j]
+		}
+	}
+
+	return string(b)
+}
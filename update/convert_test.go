@@ -0,0 +1,207 @@
+package update
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/bson2"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+)
+
+// marshalDToDocument is the reference conversion through BSON encoding.
+func marshalDToDocument(tb testing.TB, d bson.D) *types.Document {
+	tb.Helper()
+
+	b, err := bson.Marshal(d)
+	test.That(tb, err, test.ShouldBeNil)
+
+	doc, err := bson2.RawDocument(b).Convert()
+	test.That(tb, err, test.ShouldBeNil)
+
+	return doc
+}
+
+// marshalDocumentToD is the reference conversion through BSON encoding.
+func marshalDocumentToD(tb testing.TB, doc *types.Document) bson.D {
+	tb.Helper()
+
+	bson2Doc, err := bson2.ConvertDocument(doc)
+	test.That(tb, err, test.ShouldBeNil)
+
+	b, err := bson2Doc.Encode()
+	test.That(tb, err, test.ShouldBeNil)
+
+	var res bson.D
+	test.That(tb, bson.Unmarshal(b, &res), test.ShouldBeNil)
+
+	return res
+}
+
+type convertStruct struct {
+	Name  string `bson:"name"`
+	Count uint64 `bson:"count"`
+}
+
+func convertTestDocument() bson.D {
+	oid := primitive.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}
+	dec, _ := primitive.ParseDecimal128("12.50")
+
+	return bson.D{
+		{"_id", oid},
+		{"double", 42.13},
+		{"float", float32(1.5)},
+		{"string", "foo"},
+		{"int", 42},
+		{"bigint", math.MaxInt32 + 1},
+		{"int8", int8(-8)},
+		{"uint16", uint16(16)},
+		{"uint32", uint32(32)},
+		{"int32", int32(-32)},
+		{"int64", int64(64)},
+		{"bool", true},
+		{"time", time.Date(2024, 1, 2, 3, 4, 5, 6789, time.FixedZone("", 3600))},
+		{"datetime", primitive.DateTime(1704164645006)},
+		{"null", nil},
+		{"nullType", primitive.Null{}},
+		{"binary", primitive.Binary{Subtype: 0x80, Data: []byte{1, 2, 3}}},
+		{"oldBinary", primitive.Binary{Subtype: 0x02, Data: []byte{1, 2, 3}}},
+		{"bytes", []byte{4, 5}},
+		{"regex", primitive.Regex{Pattern: "^foo", Options: "smi"}},
+		{"timestamp", primitive.Timestamp{T: 1704164645, I: 7}},
+		{"decimal", dec},
+		{"minKey", primitive.MinKey{}},
+		{"maxKey", primitive.MaxKey{}},
+		{"undefined", primitive.Undefined{}},
+		{"symbol", primitive.Symbol("sym")},
+		{"js", primitive.JavaScript("function() {}")},
+		{"jsScope", primitive.CodeWithScope{Code: "x", Scope: bson.D{{"x", int32(1)}}}},
+		{"dbPointer", primitive.DBPointer{DB: "db.c", Pointer: oid}},
+		{"doc", bson.D{{"a", bson.A{int32(1), "b", bson.D{}}}, {"empty", bson.A{}}}},
+		{"map", bson.M{"k": "v"}},
+		{"slice", []any{int32(1), bson.M{"x": nil}}},
+		{"ints", []int{1, 2}},
+		{"struct", convertStruct{Name: "s", Count: 3}},
+		{"pointer", &convertStruct{Name: "p"}},
+	}
+}
+
+func TestConvert(t *testing.T) {
+	d := convertTestDocument()
+
+	doc, err := convertDToDocument(d)
+	test.That(t, err, test.ShouldBeNil)
+
+	expected := marshalDToDocument(t, d)
+	test.That(t, types.FormatAnyValue(doc), test.ShouldEqual, types.FormatAnyValue(expected))
+	test.That(t, types.Identical(doc, expected), test.ShouldBeTrue)
+
+	res, err := convertDocumentToD(doc)
+	test.That(t, err, test.ShouldBeNil)
+
+	expectedD := marshalDocumentToD(t, expected)
+	test.That(t, res, test.ShouldResemble, expectedD)
+
+	t.Run("NaN", func(t *testing.T) {
+		doc, err := convertDToDocument(bson.D{{"nan", math.NaN()}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, math.IsNaN(doc.Remove("nan").(float64)), test.ShouldBeTrue)
+	})
+
+	t.Run("NullByteKey", func(t *testing.T) {
+		_, err := convertDToDocument(bson.D{{"a\x00b", int32(1)}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot contain null bytes")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := convertDToDocument(bson.D{{"ch", make(chan int)}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func benchmarkDocument() bson.D {
+	items := make(bson.A, 20)
+	for i := range items {
+		items[i] = bson.D{
+			{"sku", "item"},
+			{"qty", int32(i)},
+			{"price", float64(i) * 1.5},
+			{"tags", bson.A{"a", "b", "c"}},
+		}
+	}
+
+	return bson.D{
+		{"_id", primitive.NewObjectID()},
+		{"name", "benchmark"},
+		{"createdAt", primitive.NewDateTimeFromTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))},
+		{"count", int64(42)},
+		{"profile", bson.D{{"first", "John"}, {"last", "Doe"}, {"age", int32(42)}}},
+		{"items", items},
+	}
+}
+
+func BenchmarkConvertDToDocument(b *testing.B) {
+	d := benchmarkDocument()
+
+	b.Run("Direct", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := convertDToDocument(d); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			marshalDToDocument(b, d)
+		}
+	})
+}
+
+func BenchmarkConvertDocumentToD(b *testing.B) {
+	doc, err := convertDToDocument(benchmarkDocument())
+	test.That(b, err, test.ShouldBeNil)
+
+	b.Run("Direct", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := convertDocumentToD(doc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			marshalDocumentToD(b, doc)
+		}
+	})
+}
+
+func BenchmarkUpdateDocument(b *testing.B) {
+	d := benchmarkDocument()
+	update := bson.D{
+		{"$set", bson.D{{"profile.age", int32(43)}}},
+		{"$inc", bson.D{{"count", int32(1)}}},
+		{"$push", bson.D{{"items", bson.D{{"sku", "new"}}}}},
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := UpdateDocument(d, update); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
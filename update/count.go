@@ -0,0 +1,90 @@
+package update
+
+import (
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Count returns the number of documents that match the filter,
+// like the count command and the driver's CountDocuments.
+//
+// The first skip matching documents are not counted, and at most limit documents are counted.
+// Zero skip and limit mean no skip and no limit; negative values return an error.
+//
+// filter may be nil to count all documents.
+func Count(documents []bson.D, filter bson.D, skip, limit int64) (int64, error) {
+	return CountWithOptions(documents, filter, skip, limit, nil)
+}
+
+// CountOptions configures CountWithOptions.
+type CountOptions struct {
+	// Collation defines how strings are compared, like the collation option of the count command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+}
+
+// CountWithOptions is Count with the given options.
+//
+// opts may be nil.
+func CountWithOptions(documents []bson.D, filter bson.D, skip, limit int64, opts *CountOptions) (int64, error) {
+	if opts == nil {
+		opts = new(CountOptions)
+	}
+
+	filterDoc, err := convertDToDocument(filter)
+	if err != nil {
+		return 0, errors.Wrap(err, "convert filter")
+	}
+
+	command, err := types.NewDocument(
+		"count", "documents", "query", filterDoc, "skip", skip, "limit", limit, "$db", "update",
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	params, err := common.GetCountParams(command, zap.NewNop())
+	if err != nil {
+		return 0, err
+	}
+
+	docs, err := convertDsToDocuments(documents)
+	if err != nil {
+		return 0, errors.Wrap(err, "convert documents")
+	}
+
+	coll, err := newCollation(opts.Collation)
+	if err != nil {
+		return 0, err
+	}
+
+	vars, err := newVariables(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	vars = vars.WithMetadata(&operators.Metadata{Collation: coll})
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	var iter types.DocumentsIterator = iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	iter = common.FilterIterator(iter, closer, params.Filter, vars, coll)
+	iter = common.SkipIterator(iter, closer, params.Skip)
+	iter = common.LimitIterator(iter, closer, params.Limit)
+
+	counted, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(counted)), nil
+}
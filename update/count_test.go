@@ -0,0 +1,52 @@
+package update_test
+
+import (
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.viam.com/test"
+)
+
+func TestCount(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"status", "A"}},
+		{{"_id", int32(2)}, {"status", "B"}},
+		{{"_id", int32(3)}, {"status", "A"}},
+		{{"_id", int32(4)}, {"status", "A"}},
+	}
+
+	tests := []struct {
+		name             string
+		filter           bson.D
+		skip             int64
+		limit            int64
+		expected         int64
+		shouldContainErr string
+	}{
+		{name: "all", expected: 4},
+		{name: "filter", filter: bson.D{{"status", "A"}}, expected: 3},
+		{name: "skip", filter: bson.D{{"status", "A"}}, skip: 1, expected: 2},
+		{name: "limit", limit: 2, expected: 2},
+		{name: "skip and limit", skip: 3, limit: 2, expected: 1},
+		{name: "skip all", skip: 10, expected: 0},
+		{name: "expr", filter: bson.D{{"$expr", bson.D{{"$gt", bson.A{"$_id", int32(2)}}}}}, expected: 2},
+		{name: "negative skip", skip: -1, shouldContainErr: "skip"},
+		{name: "invalid filter", filter: bson.D{{"$foo", int32(1)}}, shouldContainErr: "unknown top level operator: $foo"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Count(input, tc.filter, tc.skip, tc.limit)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldEqual, tc.expected)
+		})
+	}
+}
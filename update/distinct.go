@@ -0,0 +1,93 @@
+package update
+
+import (
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/iterator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Distinct returns the distinct values of the key in the documents that match the filter,
+// like the distinct command.
+//
+// The key may use dot notation. Elements of array values are distinct values themselves,
+// and documents without the key are ignored. Values are returned in ascending order.
+//
+// filter may be nil to use all documents.
+func Distinct(documents []bson.D, key string, filter bson.D) ([]any, error) {
+	return DistinctWithOptions(documents, key, filter, nil)
+}
+
+// DistinctOptions configures DistinctWithOptions.
+type DistinctOptions struct {
+	// Collation defines how strings are compared by the filter and when values are deduplicated,
+	// like the collation option of the distinct command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+}
+
+// DistinctWithOptions is Distinct with the given options.
+//
+// opts may be nil.
+func DistinctWithOptions(documents []bson.D, key string, filter bson.D, opts *DistinctOptions) ([]any, error) {
+	if opts == nil {
+		opts = new(DistinctOptions)
+	}
+
+	filterDoc, err := convertDToDocument(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert filter")
+	}
+
+	command, err := types.NewDocument("distinct", "documents", "key", key, "query", filterDoc, "$db", "update")
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := common.GetDistinctParams(command, zap.NewNop())
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := convertDsToDocuments(documents)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert documents")
+	}
+
+	coll, err := newCollation(opts.Collation)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := newVariables(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	vars = vars.WithMetadata(&operators.Metadata{Collation: coll})
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	var iter types.DocumentsIterator = iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	iter = common.FilterIterator(iter, closer, params.Filter, vars, coll)
+
+	distinct, err := common.FilterDistinctValues(iter, params.Key, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]any, distinct.Len())
+	for i := range res {
+		v, _ := distinct.Get(i)
+		res[i] = convertFromTypes(v)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package update_test
+
+import (
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.viam.com/test"
+)
+
+func TestDistinct(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"dept", "A"}, {"item", bson.D{{"sku", "111"}}}, {"sizes", bson.A{"M", "S"}}},
+		{{"_id", int32(2)}, {"dept", "A"}, {"item", bson.D{{"sku", "111"}}}, {"sizes", bson.A{"M", "L"}}},
+		{{"_id", int32(3)}, {"dept", "B"}, {"item", bson.D{{"sku", "222"}}}, {"sizes", "S"}},
+		{{"_id", int32(4)}, {"dept", "A"}, {"item", bson.D{{"sku", "333"}}}, {"sizes", bson.A{"S", int32(10)}}},
+		{{"_id", int32(5)}, {"dept", "B"}},
+	}
+
+	tests := []struct {
+		name             string
+		key              string
+		filter           bson.D
+		expected         []any
+		shouldContainErr string
+	}{
+		{name: "field", key: "dept", expected: []any{"A", "B"}},
+		{name: "embedded field", key: "item.sku", expected: []any{"111", "222", "333"}},
+		{name: "array values are flattened", key: "sizes", expected: []any{int32(10), "L", "M", "S"}},
+		{name: "filter", key: "sizes", filter: bson.D{{"dept", "A"}}, expected: []any{int32(10), "L", "M", "S"}},
+		{name: "filter embedded", key: "item.sku", filter: bson.D{{"dept", "B"}}, expected: []any{"222"}},
+		{name: "no matches", key: "dept", filter: bson.D{{"dept", "C"}}, expected: []any{}},
+		{name: "missing key", key: "color", expected: []any{}},
+		{name: "empty key", key: "", shouldContainErr: "FieldPath cannot be constructed with empty string"},
+		{name: "invalid filter", key: "dept", filter: bson.D{{"$foo", int32(1)}}, shouldContainErr: "unknown top level operator: $foo"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Distinct(input, tc.key, tc.filter)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package update
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindOptions configures Find.
+type FindOptions struct {
+	// Let defines user variables that $expr in the filter can access as $$name,
+	// like the let option of the find command.
+	// Values are expressions evaluated once, before filtering.
+	Let bson.D
+
+	// Now returns the value of $$NOW and $$CLUSTER_TIME.
+	// It is called once per Find call.
+	// If nil, time.Now is used.
+	Now func() time.Time
+
+	// Rand is the random source of $sampleRate and $rand.
+	// Set it to a seeded source to get reproducible results.
+	// If nil, a new time-seeded source is used.
+	Rand *rand.Rand
+
+	// Collation defines how strings are compared, like the collation option of the find command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+
+	// Sort orders the matching documents, like the sort option of the find command.
+	// If nil, documents are returned in their original order.
+	Sort bson.D
+
+	// Projection selects the fields of the returned documents, like the projection option of the find command.
+	// If nil, the matching documents are returned as is.
+	Projection bson.D
+
+	// JavaScript enables $where and $function, see JavaScriptOptions.
+	// If nil, JavaScript is disabled and they return an error,
+	// so filters and projections from untrusted input can't run code.
+	JavaScript *JavaScriptOptions
+}
+
+// Find returns the documents that match the filter, in their original order
+// unless opts.Sort is set.
+// Documents matched by $near or $nearSphere are ordered from the nearest to the farthest.
+//
+// The filter must conform to the mongodb Query Predicate spec
+// https://www.mongodb.com/docs/manual/reference/operator/query/
+//
+// opts may be nil.
+func Find(documents []bson.D, filter bson.D, opts *FindOptions) ([]bson.D, error) {
+	docs, err := convertDsToDocuments(documents)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert documents")
+	}
+
+	return find(documents, docs, filter, opts, nil)
+}
+
+// find implements Find for the converted documents.
+// meta may be nil; otherwise, it is available to the filter, the sort and the projection.
+func find(
+	documents []bson.D, docs []*types.Document, filter bson.D, opts *FindOptions, meta *operators.Metadata,
+) ([]bson.D, error) {
+	if opts == nil {
+		opts = new(FindOptions)
+	}
+
+	filterDoc, err := convertDToDocument(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert filter")
+	}
+
+	if err = common.ValidateNearOperator(filterDoc); err != nil {
+		return nil, err
+	}
+
+	vars, err := newVariables(opts.Now, opts.Let)
+	if err != nil {
+		return nil, err
+	}
+
+	// $near and $nearSphere record distances in metadata
+	if meta == nil {
+		meta = new(operators.Metadata)
+	}
+
+	coll, err := newCollation(opts.Collation)
+	if err != nil {
+		return nil, err
+	}
+
+	meta.JavaScript = opts.JavaScript.newEvaluator()
+	meta.Rand = opts.Rand
+	if meta.Rand == nil {
+		meta.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	meta.Collation = coll
+	vars = vars.WithMetadata(meta)
+
+	matched := make([]*types.Document, 0, len(docs))
+	originals := make(map[*types.Document]bson.D, len(docs))
+
+	for i, doc := range docs {
+		matches, err := common.FilterDocumentWithCollation(doc, filterDoc, vars, coll)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			matched = append(matched, doc)
+			originals[doc] = documents[i]
+		}
+	}
+
+	if common.HasNearOperator(filterDoc) {
+		slices.SortStableFunc(matched, func(a, b *types.Document) int {
+			da, _ := meta.GeoNearDistance(a)
+			db, _ := meta.GeoNearDistance(b)
+
+			return cmp.Compare(da, db)
+		})
+	}
+
+	if opts.Sort != nil {
+		sortDoc, err := convertDToDocument(opts.Sort)
+		if err != nil {
+			return nil, errors.Wrap(err, "convert sort")
+		}
+
+		if sortDoc, err = common.ValidateSortDocument(sortDoc); err != nil {
+			return nil, err
+		}
+
+		if err = common.SortDocumentsWithCollation(matched, sortDoc, vars, coll); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]bson.D, len(matched))
+
+	if opts.Projection == nil {
+		for i, doc := range matched {
+			res[i] = originals[doc]
+		}
+
+		return res, nil
+	}
+
+	projection, err := convertDToDocument(opts.Projection)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert projection")
+	}
+
+	projection, inclusion, err := common.ValidateProjection(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, doc := range matched {
+		projected, err := common.ProjectDocument(doc, projection, filterDoc, inclusion, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if res[i], err = convertDocumentToD(projected); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
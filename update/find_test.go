@@ -0,0 +1,250 @@
+package update_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+)
+
+func TestFind(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	input := []bson.D{
+		{{"_id", int32(1)}, {"v", int32(1)}, {"at", now.Add(-time.Hour)}},
+		{{"_id", int32(2)}, {"v", int32(2)}, {"at", now.Add(time.Hour)}},
+		{{"_id", int32(3)}, {"v", int32(3)}, {"at", now.Add(-time.Minute)}},
+	}
+
+	opts := &self.FindOptions{
+		Let: bson.D{{"min", int32(2)}},
+		Now: func() time.Time { return now },
+	}
+
+	tests := []struct {
+		name             string
+		filter           bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:     "query operators",
+			filter:   bson.D{{"v", bson.D{{"$lt", int32(3)}}}},
+			expected: input[:2],
+		},
+		{
+			name:     "let variable",
+			filter:   bson.D{{"$expr", bson.D{{"$gte", bson.A{"$v", "$$min"}}}}},
+			expected: input[1:],
+		},
+		{
+			name: "now in nested expr",
+			filter: bson.D{{"$or", bson.A{
+				bson.D{{"v", int32(1)}},
+				bson.D{{"$expr", bson.D{{"$gt", bson.A{"$at", "$$NOW"}}}}},
+			}}},
+			expected: input[:2],
+		},
+		{
+			name:             "undefined variable",
+			filter:           bson.D{{"$expr", bson.D{{"$eq", bson.A{"$v", "$$max"}}}}},
+			shouldContainErr: "Use of undefined variable: max",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Find(input, tc.filter, opts)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestFindLegacyTypes(t *testing.T) {
+	oid := primitive.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}
+	input := []bson.D{
+		{{"_id", int32(1)}, {"v", primitive.MinKey{}}},
+		{{"_id", int32(2)}, {"v", primitive.MaxKey{}}},
+		{{"_id", int32(3)}, {"v", primitive.Undefined{}}},
+		{{"_id", int32(4)}, {"v", primitive.Symbol("foo")}},
+		{{"_id", int32(5)}, {"v", primitive.JavaScript("function() {}")}},
+		{{"_id", int32(6)}, {"v", primitive.CodeWithScope{Code: "x", Scope: bson.D{{"x", int32(1)}}}}},
+		{{"_id", int32(7)}, {"v", primitive.DBPointer{DB: "db.c", Pointer: oid}}},
+		{{"_id", int32(8)}, {"v", int32(42)}},
+	}
+
+	tests := []struct {
+		name     string
+		filter   bson.D
+		expected []bson.D
+	}{
+		{name: "type minKey", filter: bson.D{{"v", bson.D{{"$type", "minKey"}}}}, expected: input[0:1]},
+		{name: "type maxKey code", filter: bson.D{{"v", bson.D{{"$type", int32(127)}}}}, expected: input[1:2]},
+		{name: "type undefined", filter: bson.D{{"v", bson.D{{"$type", "undefined"}}}}, expected: input[2:3]},
+		{name: "type symbol", filter: bson.D{{"v", bson.D{{"$type", "symbol"}}}}, expected: input[3:4]},
+		{name: "type javascript", filter: bson.D{{"v", bson.D{{"$type", "javascript"}}}}, expected: input[4:5]},
+		{name: "type javascriptWithScope", filter: bson.D{{"v", bson.D{{"$type", "javascriptWithScope"}}}}, expected: input[5:6]},
+		{name: "type dbPointer", filter: bson.D{{"v", bson.D{{"$type", "dbPointer"}}}}, expected: input[6:7]},
+		{name: "symbol equals string", filter: bson.D{{"v", "foo"}}, expected: input[3:4]},
+		{name: "less than MaxKey", filter: bson.D{{"v", bson.D{{"$lt", primitive.MaxKey{}}}}}, expected: append(input[:1:1], input[2:]...)},
+		{name: "greater than MinKey", filter: bson.D{{"v", bson.D{{"$gt", primitive.MinKey{}}}}}, expected: input[1:]},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Find(input, tc.filter, nil)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+
+	t.Run("sort", func(t *testing.T) {
+		res, err := self.Aggregate(input, []bson.D{{{"$sort", bson.D{{"v", int32(-1)}}}}, {{"$project", bson.D{{"_id", int32(1)}}}}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", int32(2)}}, {{"_id", int32(6)}}, {{"_id", int32(5)}}, {{"_id", int32(7)}},
+			{{"_id", int32(4)}}, {{"_id", int32(8)}}, {{"_id", int32(3)}}, {{"_id", int32(1)}},
+		})
+	})
+
+	t.Run("update round-trip", func(t *testing.T) {
+		doc := append(bson.D{{"_id", int32(1)}}, bson.E{Key: "legacy", Value: bson.A{
+			input[0][1].Value, input[1][1].Value, input[2][1].Value, input[3][1].Value,
+			input[4][1].Value, input[5][1].Value, input[6][1].Value,
+		}})
+
+		res, err := self.UpdateDocument(doc, bson.D{{"$set", bson.D{{"x", int32(1)}}}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, append(doc, bson.E{Key: "x", Value: int32(1)}))
+	})
+}
+
+func TestFindProjection(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", bson.D{{"first", "Ada"}, {"last", "Lovelace"}}}, {"qty", int32(3)}, {"price", 2.5}},
+		{{"name", bson.D{{"first", "Alan"}, {"last", "Turing"}}}, {"qty", int32(1)}},
+	}
+
+	tests := []struct {
+		name             string
+		projection       bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:       "computed fields",
+			projection: bson.D{{"total", bson.D{{"$multiply", bson.A{"$qty", "$price"}}}}, {"qty", int32(1)}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"qty", int32(3)}, {"total", 7.5}},
+				{{"qty", int32(1)}, {"total", nil}},
+			},
+		},
+		{
+			name: "field path and literal",
+			projection: bson.D{
+				{"_id", int32(0)},
+				{"display", bson.D{{"$concat", bson.A{"$name.first", " ", "$name.last"}}}},
+				{"last", "$name.last"},
+				{"dollar", bson.D{{"$literal", "$name"}}},
+				{"one", bson.D{{"$literal", int32(1)}}},
+			},
+			expected: []bson.D{
+				{{"display", "Ada Lovelace"}, {"last", "Lovelace"}, {"dollar", "$name"}, {"one", int32(1)}},
+				{{"display", "Alan Turing"}, {"last", "Turing"}, {"dollar", "$name"}, {"one", int32(1)}},
+			},
+		},
+		{
+			name:       "missing field path",
+			projection: bson.D{{"_id", false}, {"p", "$price"}},
+			expected:   []bson.D{{{"p", 2.5}}, {}},
+		},
+		{
+			name:       "array expression",
+			projection: bson.D{{"_id", false}, {"pair", bson.A{"$qty", "$name.first"}}},
+			expected: []bson.D{
+				{{"pair", bson.A{int32(3), "Ada"}}},
+				{{"pair", bson.A{int32(1), "Alan"}}},
+			},
+		},
+		{
+			name:       "nested projection",
+			projection: bson.D{{"_id", false}, {"name", bson.D{{"last", true}, {"initial", bson.D{{"$substrCP", bson.A{"$name.first", 0, 1}}}}}}},
+			expected: []bson.D{
+				{{"name", bson.D{{"last", "Lovelace"}, {"initial", "A"}}}},
+				{{"name", bson.D{{"last", "Turing"}, {"initial", "A"}}}},
+			},
+		},
+		{
+			name:       "computed _id",
+			projection: bson.D{{"_id", "$name.last"}},
+			expected:   []bson.D{{{"_id", "Lovelace"}}, {{"_id", "Turing"}}},
+		},
+		{
+			name:             "empty sub-projection",
+			projection:       bson.D{{"name", bson.D{}}},
+			shouldContainErr: "An empty sub-projection is not a valid value",
+		},
+		{
+			name:             "expression in exclusion",
+			projection:       bson.D{{"qty", int32(0)}, {"total", bson.D{{"$add", bson.A{"$qty", 1}}}}},
+			shouldContainErr: "Cannot do inclusion on field total in exclusion projection",
+		},
+		{
+			name:             "unknown operator",
+			projection:       bson.D{{"x", bson.D{{"$foo", int32(1)}}}},
+			shouldContainErr: "Unrecognized expression '$foo'",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Find(input, bson.D{}, &self.FindOptions{Projection: tc.projection})
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestFindSampleRate(t *testing.T) {
+	var input []bson.D
+	for i := int32(0); i < 100; i++ {
+		input = append(input, bson.D{{"_id", i}})
+	}
+
+	filter := bson.D{{"$sampleRate", 0.25}}
+
+	first, err := self.Find(input, filter, &self.FindOptions{Rand: rand.New(rand.NewSource(7))})
+	test.That(t, err, test.ShouldBeNil)
+
+	second, err := self.Find(input, filter, &self.FindOptions{Rand: rand.New(rand.NewSource(7))})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, second, test.ShouldResemble, first)
+	test.That(t, len(first), test.ShouldBeBetween, 10, 40)
+
+	none, err := self.Find(input, bson.D{{"$sampleRate", int32(0)}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, none, test.ShouldBeEmpty)
+
+	all, err := self.Find(input, bson.D{{"$sampleRate", int32(1)}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, all, test.ShouldResemble, input)
+}
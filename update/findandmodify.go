@@ -0,0 +1,511 @@
+package update
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// FindOneAndUpdateOptions configures FindOneAndUpdate.
+type FindOneAndUpdateOptions struct {
+	// Collation defines how strings are compared, like the collation option of the findAndModify command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+
+	// Sort selects the document to update if the filter matches several documents.
+	// If nil, the first matching document is updated.
+	Sort bson.D
+
+	// Projection selects the fields of the returned document.
+	// If nil, the whole document is returned.
+	Projection bson.D
+
+	// ReturnDocument is options.Before (the default) to return the document as it was before the update
+	// or options.After to return it after the update.
+	ReturnDocument options.ReturnDocument
+
+	// Upsert inserts a new document if the filter matches no documents.
+	// The new document is built from the equality conditions of the filter and the update.
+	Upsert bool
+}
+
+// FindOneAndReplaceOptions configures FindOneAndReplace.
+type FindOneAndReplaceOptions struct {
+	// Collation defines how strings are compared, like the collation option of the findAndModify command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+
+	// Sort selects the document to replace if the filter matches several documents.
+	// If nil, the first matching document is replaced.
+	Sort bson.D
+
+	// Projection selects the fields of the returned document.
+	// If nil, the whole document is returned.
+	Projection bson.D
+
+	// ReturnDocument is options.Before (the default) to return the document as it was before the replacement
+	// or options.After to return the replacement.
+	ReturnDocument options.ReturnDocument
+
+	// Upsert inserts the replacement if the filter matches no documents.
+	// Its _id is taken from the filter if the replacement does not have one.
+	Upsert bool
+}
+
+// FindOneAndDeleteOptions configures FindOneAndDelete.
+type FindOneAndDeleteOptions struct {
+	// Collation defines how strings are compared, like the collation option of the findAndModify command.
+	// If nil, strings are compared by their bytes.
+	Collation *options.Collation
+
+	// Sort selects the document to delete if the filter matches several documents.
+	// If nil, the first matching document is deleted.
+	Sort bson.D
+
+	// Projection selects the fields of the returned document.
+	// If nil, the whole document is returned.
+	Projection bson.D
+}
+
+// findAndModifyOptions holds the options shared by FindOneAndUpdate, FindOneAndReplace and FindOneAndDelete.
+type findAndModifyOptions struct {
+	collation      *options.Collation
+	sort           bson.D
+	projection     bson.D
+	returnDocument options.ReturnDocument
+	upsert         bool
+}
+
+// FindOneAndUpdate updates a single document that matches the filter, like the driver's
+// Collection.FindOneAndUpdate.
+//
+// It returns the documents after the update and the result the driver would return:
+// the document before or after the update (see FindOneAndUpdateOptions.ReturnDocument),
+// or mongo.ErrNoDocuments if there is no such document.
+// If the result has any other error, the returned documents are the passed ones.
+// The passed documents are never modified.
+//
+// The update must conform to the mongodb Update Operator spec
+// https://www.mongodb.com/docs/manual/reference/operator/update/
+//
+// filter may be nil to match all documents; opts may be nil.
+func FindOneAndUpdate(
+	documents []bson.D, filter, update bson.D, opts *FindOneAndUpdateOptions,
+) ([]bson.D, *mongo.SingleResult) {
+	if opts == nil {
+		opts = new(FindOneAndUpdateOptions)
+	}
+
+	if len(update) == 0 || !strings.HasPrefix(update[0].Key, "$") {
+		return documents, newSingleResult(nil, errors.New("update document must contain key beginning with '$'"))
+	}
+
+	return findAndModify(documents, filter, update, false, &findAndModifyOptions{
+		collation:      opts.Collation,
+		sort:           opts.Sort,
+		projection:     opts.Projection,
+		returnDocument: opts.ReturnDocument,
+		upsert:         opts.Upsert,
+	})
+}
+
+// FindOneAndReplace replaces a single document that matches the filter, like the driver's
+// Collection.FindOneAndReplace.
+// The _id of the replaced document is kept.
+//
+// It returns the documents and the result like FindOneAndUpdate does.
+//
+// filter may be nil to match all documents; opts may be nil.
+func FindOneAndReplace(
+	documents []bson.D, filter, replacement bson.D, opts *FindOneAndReplaceOptions,
+) ([]bson.D, *mongo.SingleResult) {
+	if opts == nil {
+		opts = new(FindOneAndReplaceOptions)
+	}
+
+	if len(replacement) > 0 && strings.HasPrefix(replacement[0].Key, "$") {
+		return documents, newSingleResult(nil, errors.New("replacement document cannot contain keys beginning with '$'"))
+	}
+
+	if replacement == nil {
+		replacement = bson.D{}
+	}
+
+	return findAndModify(documents, filter, replacement, false, &findAndModifyOptions{
+		collation:      opts.Collation,
+		sort:           opts.Sort,
+		projection:     opts.Projection,
+		returnDocument: opts.ReturnDocument,
+		upsert:         opts.Upsert,
+	})
+}
+
+// FindOneAndDelete deletes a single document that matches the filter, like the driver's
+// Collection.FindOneAndDelete.
+//
+// It returns the documents without the deleted one and the result the driver would return:
+// the deleted document or mongo.ErrNoDocuments if there is no such document.
+// If the result has any other error, the returned documents are the passed ones.
+// The passed documents are never modified.
+//
+// filter may be nil to match all documents; opts may be nil.
+func FindOneAndDelete(documents []bson.D, filter bson.D, opts *FindOneAndDeleteOptions) ([]bson.D, *mongo.SingleResult) {
+	if opts == nil {
+		opts = new(FindOneAndDeleteOptions)
+	}
+
+	return findAndModify(documents, filter, nil, true, &findAndModifyOptions{
+		collation:  opts.Collation,
+		sort:       opts.Sort,
+		projection: opts.Projection,
+	})
+}
+
+// findAndModify implements FindOneAndUpdate, FindOneAndReplace and FindOneAndDelete
+// like the findAndModify command.
+func findAndModify(
+	documents []bson.D, filter, update bson.D, remove bool, opts *findAndModifyOptions,
+) ([]bson.D, *mongo.SingleResult) {
+	res, value, err := findAndModifyDocuments(documents, filter, update, remove, opts)
+	if err != nil {
+		return documents, newSingleResult(nil, err)
+	}
+
+	return res, newSingleResult(value, nil)
+}
+
+// newSingleResult returns the driver's result for the document,
+// or for the error if it is not nil.
+// A nil document without an error results in mongo.ErrNoDocuments.
+func newSingleResult(document bson.D, err error) *mongo.SingleResult {
+	if err == nil && document == nil {
+		err = mongo.ErrNoDocuments
+	}
+
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
+	return mongo.NewSingleResultFromDocument(document, nil, nil)
+}
+
+// findAndModifyDocuments returns the documents after the modification and the document to return.
+// The returned document is nil if there is none.
+func findAndModifyDocuments(
+	documents []bson.D, filter, update bson.D, remove bool, opts *findAndModifyOptions,
+) ([]bson.D, bson.D, error) {
+	filterDoc, err := convertDToDocument(filter)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "convert filter")
+	}
+
+	command := must.NotFail(types.NewDocument("findAndModify", "documents", "query", filterDoc))
+
+	if opts.sort != nil {
+		sortDoc, err := convertDToDocument(opts.sort)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "convert sort")
+		}
+
+		command.Set("sort", sortDoc)
+	}
+
+	if remove {
+		command.Set("remove", true)
+	} else {
+		updateDoc, err := convertDToDocument(update)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "convert update")
+		}
+
+		command.Set("update", updateDoc)
+		command.Set("upsert", opts.upsert)
+		command.Set("new", opts.returnDocument == options.After)
+	}
+
+	command.Set("$db", "update")
+
+	params, err := common.GetFindAndModifyParams(command, zap.NewNop())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if params.Update != nil {
+		if err = common.ValidateUpdateOperators(command.Command(), params.Update); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	docs, err := convertDsToDocuments(documents)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "convert documents")
+	}
+
+	vars, err := newVariables(nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coll, err := newCollation(opts.collation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vars = vars.WithMetadata(&operators.Metadata{Collation: coll})
+
+	indexes := make(map[*types.Document]int, len(docs))
+	matched := make([]*types.Document, 0, len(docs))
+
+	for i, doc := range docs {
+		matches, err := common.FilterDocumentWithCollation(doc, params.Query, vars, coll)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if matches {
+			matched = append(matched, doc)
+			indexes[doc] = i
+		}
+	}
+
+	if params.Sort != nil {
+		sortDoc, err := common.ValidateSortDocument(params.Sort)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err = common.SortDocumentsWithCollation(matched, sortDoc, vars, coll); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(matched) == 0 {
+		if !params.Upsert {
+			return documents, nil, nil
+		}
+
+		doc, err := newUpsertDocument(params, vars, coll)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted, err := convertDocumentToD(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res := append(slices.Clip(documents), upserted)
+
+		if !params.ReturnNewDocument {
+			return res, nil, nil
+		}
+
+		value, err := projectFoundDocument(doc, params.Query, opts.projection, vars)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return res, value, nil
+	}
+
+	found := matched[0]
+	i := indexes[found]
+
+	value, err := projectFoundDocument(found, params.Query, opts.projection, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if params.Remove {
+		return slices.Delete(slices.Clone(documents), i, i+1), value, nil
+	}
+
+	doc, err := modifyFoundDocument(found, params, vars, coll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modified, err := convertDocumentToD(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := slices.Clone(documents)
+	res[i] = modified
+
+	if params.ReturnNewDocument {
+		if value, err = projectFoundDocument(doc, params.Query, opts.projection, vars); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return res, value, nil
+}
+
+// modifyFoundDocument returns the found document updated or replaced by params.Update.
+func modifyFoundDocument(
+	found *types.Document, params *common.FindAndModifyParams, vars *operators.Variables, coll *types.Collation,
+) (*types.Document, error) {
+	// from ferret/handler/msg_findandmodify.go
+	doc := params.Update.DeepCopy()
+	if params.HasUpdateOperators {
+		doc = found.DeepCopy()
+		if _, err := common.UpdateDocumentWithPaths("findAndModify", doc, params.Update, false, nil, vars, coll); err != nil {
+			return nil, err
+		}
+	}
+
+	id := must.NotFail(found.Get("_id"))
+
+	updateID, _ := doc.Get("_id")
+	if updateID == nil {
+		doc.Set("_id", id)
+	}
+
+	if updateID != nil && !types.Identical(updateID, id) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrImmutableField,
+			fmt.Sprintf(
+				`Plan executor error during findAndModify :: caused `+
+					`by :: After applying the update, the (immutable) field `+
+					`'_id' was found to have been altered to _id: %s`,
+				types.FormatAnyValue(updateID),
+			),
+			"findAndModify",
+		)
+	}
+
+	// ValidateData also moves _id field to the first index
+	if err := doc.ValidateData(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// newUpsertDocument returns the document inserted by an upsert.
+//
+// Like MongoDB, an update with operators is applied to the equality conditions of the query,
+// and a replacement gets the _id of the query if it does not have one.
+func newUpsertDocument(
+	params *common.FindAndModifyParams, vars *operators.Variables, coll *types.Collation,
+) (*types.Document, error) {
+	base := must.NotFail(types.NewDocument())
+	if params.Query != nil {
+		if err := setEqualityFields(base, params.Query.DeepCopy()); err != nil {
+			return nil, err
+		}
+	}
+
+	doc := params.Update.DeepCopy()
+	if params.HasUpdateOperators {
+		doc = base
+		_, err := common.UpdateDocumentWithPaths("findAndModify", doc, params.Update.DeepCopy(), true, nil, vars, coll)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !doc.Has("_id") {
+		id, _ := base.Get("_id")
+		if id == nil {
+			id = types.NewObjectID()
+		}
+
+		doc.Set("_id", id)
+	}
+
+	// ValidateData also moves _id field to the first index
+	if err := doc.ValidateData(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// setEqualityFields sets the fields of the query that are compared for equality
+// (directly, with $eq or inside $and) in doc.
+func setEqualityFields(doc, query *types.Document) error {
+	for _, key := range query.Keys() {
+		value := must.NotFail(query.Get(key))
+
+		if key == "$and" {
+			conditions, ok := value.(*types.Array)
+			if !ok {
+				continue
+			}
+
+			for i := 0; i < conditions.Len(); i++ {
+				if condition, ok := must.NotFail(conditions.Get(i)).(*types.Document); ok {
+					if err := setEqualityFields(doc, condition); err != nil {
+						return err
+					}
+				}
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+
+		if expr, ok := value.(*types.Document); ok && expr.Len() > 0 && strings.HasPrefix(expr.Keys()[0], "$") {
+			eq, err := expr.Get("$eq")
+			if err != nil {
+				continue
+			}
+
+			value = eq
+		}
+
+		path, err := types.NewPathFromString(key)
+		if err != nil {
+			return err
+		}
+
+		if err = doc.SetByPath(path, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// projectFoundDocument returns the document with the projection applied.
+// projection may be nil to return the whole document.
+func projectFoundDocument(
+	doc, query *types.Document, projection bson.D, vars *operators.Variables,
+) (bson.D, error) {
+	if projection == nil {
+		return convertDocumentToD(doc)
+	}
+
+	projectionDoc, err := convertDToDocument(projection)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert projection")
+	}
+
+	projectionDoc, inclusion, err := common.ValidateProjection(projectionDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	projected, err := common.ProjectDocument(doc, projectionDoc, query, inclusion, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDocumentToD(projected)
+}
@@ -0,0 +1,189 @@
+package update_test
+
+import (
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.viam.com/test"
+)
+
+func TestFindOneAndUpdate(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "a"}, {"score", int32(5)}},
+		{{"_id", int32(2)}, {"name", "b"}, {"score", int32(9)}},
+		{{"_id", int32(3)}, {"name", "c"}, {"score", int32(7)}},
+	}
+
+	tests := []struct {
+		name             string
+		filter           bson.D
+		update           bson.D
+		opts             *self.FindOneAndUpdateOptions
+		expected         bson.D
+		expectedDocs     []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:     "first match before",
+			filter:   bson.D{{"score", bson.D{{"$gt", int32(6)}}}},
+			update:   bson.D{{"$inc", bson.D{{"score", int32(1)}}}},
+			expected: input[1],
+			expectedDocs: []bson.D{
+				input[0],
+				{{"_id", int32(2)}, {"name", "b"}, {"score", int32(10)}},
+				input[2],
+			},
+		},
+		{
+			name:   "sort after",
+			update: bson.D{{"$set", bson.D{{"top", true}}}},
+			opts: &self.FindOneAndUpdateOptions{
+				Sort:           bson.D{{"score", int32(-1)}},
+				ReturnDocument: options.After,
+			},
+			expected: bson.D{{"_id", int32(2)}, {"name", "b"}, {"score", int32(9)}, {"top", true}},
+			expectedDocs: []bson.D{
+				input[0],
+				{{"_id", int32(2)}, {"name", "b"}, {"score", int32(9)}, {"top", true}},
+				input[2],
+			},
+		},
+		{
+			name:   "projection",
+			filter: bson.D{{"name", "c"}},
+			update: bson.D{{"$unset", bson.D{{"score", ""}}}},
+			opts: &self.FindOneAndUpdateOptions{
+				Projection: bson.D{{"score", int32(1)}, {"_id", int32(0)}},
+			},
+			expected:     bson.D{{"score", int32(7)}},
+			expectedDocs: []bson.D{input[0], input[1], {{"_id", int32(3)}, {"name", "c"}}},
+		},
+		{
+			name:   "upsert after",
+			filter: bson.D{{"_id", int32(4)}, {"name", bson.D{{"$eq", "d"}}}, {"score", bson.D{{"$gt", int32(1)}}}},
+			update: bson.D{{"$set", bson.D{{"score", int32(1)}}}},
+			opts: &self.FindOneAndUpdateOptions{
+				Upsert:         true,
+				ReturnDocument: options.After,
+			},
+			expected: bson.D{{"_id", int32(4)}, {"name", "d"}, {"score", int32(1)}},
+			expectedDocs: []bson.D{
+				input[0], input[1], input[2],
+				{{"_id", int32(4)}, {"name", "d"}, {"score", int32(1)}},
+			},
+		},
+		{
+			name:   "upsert before",
+			filter: bson.D{{"_id", int32(4)}},
+			update: bson.D{{"$setOnInsert", bson.D{{"name", "d"}}}},
+			opts:   &self.FindOneAndUpdateOptions{Upsert: true},
+			expectedDocs: []bson.D{
+				input[0], input[1], input[2],
+				{{"_id", int32(4)}, {"name", "d"}},
+			},
+			shouldContainErr: mongo.ErrNoDocuments.Error(),
+		},
+		{
+			name:             "no match",
+			filter:           bson.D{{"name", "z"}},
+			update:           bson.D{{"$set", bson.D{{"score", int32(0)}}}},
+			expectedDocs:     input,
+			shouldContainErr: mongo.ErrNoDocuments.Error(),
+		},
+		{
+			name:             "replacement",
+			update:           bson.D{{"score", int32(0)}},
+			expectedDocs:     input,
+			shouldContainErr: "update document must contain key beginning with '$'",
+		},
+		{
+			name:             "immutable _id",
+			update:           bson.D{{"$set", bson.D{{"_id", int32(10)}}}},
+			expectedDocs:     input,
+			shouldContainErr: "the (immutable) field '_id' was found to have been altered to _id: 10",
+		},
+		{
+			name:             "invalid sort",
+			update:           bson.D{{"$set", bson.D{{"top", true}}}},
+			opts:             &self.FindOneAndUpdateOptions{Sort: bson.D{{"score", int32(2)}}},
+			expectedDocs:     input,
+			shouldContainErr: "$sort key ordering must be 1 (for ascending) or -1 (for descending)",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			docs, res := self.FindOneAndUpdate(input, tc.filter, tc.update, tc.opts)
+			test.That(t, docs, test.ShouldResemble, tc.expectedDocs)
+
+			var value bson.D
+			err := res.Decode(&value)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, value, test.ShouldResemble, tc.expected)
+		})
+	}
+
+	// the passed documents are not modified
+	test.That(t, input[1], test.ShouldResemble, bson.D{{"_id", int32(2)}, {"name", "b"}, {"score", int32(9)}})
+}
+
+func TestFindOneAndReplace(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "a"}},
+		{{"_id", int32(2)}, {"name", "b"}},
+	}
+
+	docs, res := self.FindOneAndReplace(input, bson.D{{"name", "b"}}, bson.D{{"title", "B"}}, &self.FindOneAndReplaceOptions{
+		ReturnDocument: options.After,
+	})
+	test.That(t, res.Err(), test.ShouldBeNil)
+	test.That(t, docs, test.ShouldResemble, []bson.D{input[0], {{"_id", int32(2)}, {"title", "B"}}})
+
+	var value bson.D
+	test.That(t, res.Decode(&value), test.ShouldBeNil)
+	test.That(t, value, test.ShouldResemble, bson.D{{"_id", int32(2)}, {"title", "B"}})
+
+	docs, res = self.FindOneAndReplace(input, bson.D{{"_id", int32(3)}}, bson.D{{"name", "c"}}, &self.FindOneAndReplaceOptions{
+		Upsert: true,
+	})
+	test.That(t, res.Err(), test.ShouldEqual, mongo.ErrNoDocuments)
+	test.That(t, docs, test.ShouldResemble, []bson.D{input[0], input[1], {{"_id", int32(3)}, {"name", "c"}}})
+
+	docs, res = self.FindOneAndReplace(input, nil, bson.D{{"$set", bson.D{{"name", "c"}}}}, nil)
+	test.That(t, res.Err(), test.ShouldNotBeNil)
+	test.That(t, res.Err().Error(), test.ShouldContainSubstring, "replacement document cannot contain keys beginning with '$'")
+	test.That(t, docs, test.ShouldResemble, input)
+}
+
+func TestFindOneAndDelete(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "a"}, {"score", int32(5)}},
+		{{"_id", int32(2)}, {"name", "b"}, {"score", int32(9)}},
+		{{"_id", int32(3)}, {"name", "c"}, {"score", int32(7)}},
+	}
+
+	docs, res := self.FindOneAndDelete(input, bson.D{{"score", bson.D{{"$gt", int32(6)}}}}, &self.FindOneAndDeleteOptions{
+		Sort:       bson.D{{"score", int32(1)}},
+		Projection: bson.D{{"name", int32(1)}},
+	})
+	test.That(t, docs, test.ShouldResemble, []bson.D{input[0], input[1]})
+
+	var value bson.D
+	test.That(t, res.Decode(&value), test.ShouldBeNil)
+	test.That(t, value, test.ShouldResemble, bson.D{{"_id", int32(3)}, {"name", "c"}})
+
+	docs, res = self.FindOneAndDelete(input, bson.D{{"name", "z"}}, nil)
+	test.That(t, res.Err(), test.ShouldEqual, mongo.ErrNoDocuments)
+	test.That(t, docs, test.ShouldResemble, input)
+	test.That(t, len(input), test.ShouldEqual, 3)
+}
@@ -0,0 +1,270 @@
+package update_test
+
+import (
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.viam.com/test"
+)
+
+func geoPoint(lng, lat float64) bson.D {
+	return bson.D{{"type", "Point"}, {"coordinates", bson.A{lng, lat}}}
+}
+
+func TestGeoFind(t *testing.T) {
+	places := []bson.D{
+		{{"_id", int32(1)}, {"loc", geoPoint(-73.97, 40.77)}},               // Central Park
+		{{"_id", int32(2)}, {"loc", geoPoint(-73.88, 40.78)}},               // LaGuardia
+		{{"_id", int32(3)}, {"loc", geoPoint(-0.13, 51.51)}},                // London
+		{{"_id", int32(4)}, {"loc", bson.A{-73.99, 40.75}}},                 // legacy pair
+		{{"_id", int32(5)}, {"loc", bson.D{{"lng", 2.35}, {"lat", 48.86}}}}, // legacy document
+		{{"_id", int32(6)}, {"name", "no location"}},
+	}
+
+	manhattan := bson.D{{"type", "Polygon"}, {"coordinates", bson.A{bson.A{
+		bson.A{-74.02, 40.70}, bson.A{-73.93, 40.70}, bson.A{-73.93, 40.80}, bson.A{-74.02, 40.80}, bson.A{-74.02, 40.70},
+	}}}}
+
+	ids := func(docs []bson.D) []any {
+		res := make([]any, len(docs))
+		for i, d := range docs {
+			res[i] = d[0].Value
+		}
+		return res
+	}
+
+	tests := []struct {
+		name             string
+		filter           bson.D
+		expected         []any
+		shouldContainErr string
+	}{
+		{
+			name:     "geoWithin geometry",
+			filter:   bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$geometry", manhattan}}}}}},
+			expected: []any{int32(1), int32(4)},
+		},
+		{
+			name: "geoWithin box",
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{
+				{"$box", bson.A{bson.A{-74.0, 40.7}, bson.A{-73.8, 40.8}}},
+			}}}}},
+			expected: []any{int32(1), int32(2), int32(4)},
+		},
+		{
+			name: "geoWithin polygon",
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{
+				{"$polygon", bson.A{bson.A{0.0, 45.0}, bson.A{5.0, 45.0}, bson.A{5.0, 50.0}, bson.A{0.0, 50.0}}},
+			}}}}},
+			expected: []any{int32(5)},
+		},
+		{
+			name: "geoWithin center",
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{
+				{"$center", bson.A{bson.A{-73.97, 40.77}, 0.05}},
+			}}}}},
+			expected: []any{int32(1), int32(4)},
+		},
+		{
+			name: "geoWithin centerSphere",
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{
+				// 500 km around Paris
+				{"$centerSphere", bson.A{bson.A{2.35, 48.86}, 500.0 / 6378.1}},
+			}}}}},
+			expected: []any{int32(3), int32(5)},
+		},
+		{
+			name: "geoIntersects line",
+			filter: bson.D{{"loc", bson.D{{"$geoIntersects", bson.D{{"$geometry", bson.D{
+				{"type", "LineString"},
+				{"coordinates", bson.A{bson.A{-73.97, 40.77}, bson.A{-73.97, 40.70}}},
+			}}}}}}},
+			expected: []any{int32(1)},
+		},
+		{
+			name:     "geoIntersects polygon",
+			filter:   bson.D{{"loc", bson.D{{"$geoIntersects", bson.D{{"$geometry", manhattan}}}}}},
+			expected: []any{int32(1), int32(4)},
+		},
+		{
+			name: "near orders by distance",
+			filter: bson.D{{"loc", bson.D{{"$near", bson.D{
+				{"$geometry", geoPoint(-73.98, 40.755)},
+				{"$maxDistance", 20000},
+			}}}}},
+			expected: []any{int32(4), int32(1), int32(2)},
+		},
+		{
+			name: "near min distance",
+			filter: bson.D{{"loc", bson.D{{"$near", bson.D{
+				{"$geometry", geoPoint(-73.98, 40.76)},
+				{"$minDistance", 5000},
+			}}}}},
+			expected: []any{int32(2), int32(3), int32(5)},
+		},
+		{
+			name:     "nearSphere legacy",
+			filter:   bson.D{{"loc", bson.D{{"$nearSphere", bson.A{2.35, 48.86}}, {"$maxDistance", 0.1}}}},
+			expected: []any{int32(5), int32(3)},
+		},
+		{
+			name:     "near legacy planar",
+			filter:   bson.D{{"loc", bson.D{{"$near", bson.A{-73.99, 40.75}}, {"$maxDistance", 0.05}}}},
+			expected: []any{int32(4), int32(1)},
+		},
+		{
+			name: "near in top-level and",
+			filter: bson.D{{"$and", bson.A{
+				bson.D{{"loc", bson.D{{"$near", bson.A{-73.99, 40.75}}, {"$maxDistance", 0.05}}}},
+				bson.D{{"_id", bson.D{{"$ne", int32(1)}}}},
+			}}},
+			expected: []any{int32(4)},
+		},
+		{
+			name: "near in or",
+			filter: bson.D{{"$or", bson.A{
+				bson.D{{"loc", bson.D{{"$near", bson.A{-73.99, 40.75}}}}},
+				bson.D{{"_id", int32(3)}},
+			}}},
+			shouldContainErr: "geoNear must be top-level expr",
+		},
+		{
+			name: "near in and within nor",
+			filter: bson.D{{"$nor", bson.A{bson.D{{"$and", bson.A{
+				bson.D{{"loc", bson.D{{"$nearSphere", bson.A{2.35, 48.86}}}}},
+			}}}}}},
+			shouldContainErr: "geoNear must be top-level expr",
+		},
+		{
+			name: "several near",
+			filter: bson.D{
+				{"loc", bson.D{{"$near", bson.A{-73.99, 40.75}}}},
+				{"$and", bson.A{bson.D{{"loc", bson.D{{"$near", bson.A{2.35, 48.86}}}}}}},
+			},
+			shouldContainErr: "Too many geoNear expressions",
+		},
+		{
+			name: "invalid polygon",
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$geometry", bson.D{
+				{"type", "Polygon"},
+				{"coordinates", bson.A{bson.A{bson.A{0.0, 0.0}, bson.A{1.0, 0.0}, bson.A{1.0, 1.0}}}},
+			}}}}}}},
+			shouldContainErr: "Loop must have at least 3 different vertices",
+		},
+		{
+			name: "invalid latitude",
+			filter: bson.D{{"loc", bson.D{{"$near", bson.D{
+				{"$geometry", geoPoint(0, 91)},
+			}}}}},
+			shouldContainErr: "latitude",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Find(places, tc.filter, nil)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ids(res), test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestGeoNear(t *testing.T) {
+	places := []bson.D{
+		{{"_id", int32(1)}, {"kind", "park"}, {"loc", geoPoint(-73.97, 40.77)}},
+		{{"_id", int32(2)}, {"kind", "airport"}, {"loc", geoPoint(-73.88, 40.78)}},
+		{{"_id", int32(3)}, {"kind", "park"}, {"loc", geoPoint(-73.99, 40.75)}},
+		{{"_id", int32(4)}, {"kind", "park"}},
+	}
+
+	res, err := self.Aggregate(places, []bson.D{
+		{{"$geoNear", bson.D{
+			{"near", geoPoint(-73.99, 40.75)},
+			{"distanceField", "dist.calculated"},
+			{"includeLocs", "dist.location"},
+			{"key", "loc"},
+			{"query", bson.D{{"kind", "park"}}},
+			{"distanceMultiplier", 0.001},
+		}}},
+		{{"$project", bson.D{{"dist", 1}}}},
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(res), test.ShouldEqual, 2)
+	test.That(t, res[0], test.ShouldResemble, bson.D{
+		{"_id", int32(3)},
+		{"dist", bson.D{{"calculated", 0.0}, {"location", geoPoint(-73.99, 40.75)}}},
+	})
+	test.That(t, res[1][0].Value, test.ShouldEqual, int32(1))
+	km := res[1][1].Value.(bson.D)[0].Value.(float64)
+	test.That(t, km, test.ShouldBeBetween, 2.5, 2.9)
+
+	t.Run("geoNearDistance metadata", func(t *testing.T) {
+		res, err := self.Aggregate(places, []bson.D{
+			{{"$geoNear", bson.D{
+				{"near", geoPoint(-73.99, 40.75)},
+				{"distanceField", "d"},
+				{"key", "loc"},
+				{"maxDistance", 5000},
+			}}},
+			{{"$project", bson.D{{"same", bson.D{{"$eq", bson.A{"$d", bson.D{{"$meta", "geoNearDistance"}}}}}}}}},
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", int32(3)}, {"same", true}},
+			{{"_id", int32(1)}, {"same", true}},
+		})
+	})
+
+	errTests := []struct {
+		name             string
+		pipeline         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name: "not first stage",
+			pipeline: []bson.D{
+				{{"$match", bson.D{}}},
+				{{"$geoNear", bson.D{{"near", geoPoint(0, 0)}, {"distanceField", "d"}, {"key", "loc"}}}},
+			},
+			shouldContainErr: "$geoNear was not the first stage in the pipeline.",
+		},
+		{
+			name:             "missing key",
+			pipeline:         []bson.D{{{"$geoNear", bson.D{{"near", geoPoint(0, 0)}, {"distanceField", "d"}}}}},
+			shouldContainErr: "'key' option",
+		},
+		{
+			name:             "missing distanceField",
+			pipeline:         []bson.D{{{"$geoNear", bson.D{{"near", geoPoint(0, 0)}, {"key", "loc"}}}}},
+			shouldContainErr: "distanceField",
+		},
+		{
+			name: "unknown option",
+			pipeline: []bson.D{{{"$geoNear", bson.D{
+				{"near", geoPoint(0, 0)}, {"distanceField", "d"}, {"key", "loc"}, {"foo", 1},
+			}}}},
+			shouldContainErr: "Unknown argument to $geoNear: foo",
+		},
+		{
+			name:             "near in match",
+			pipeline:         []bson.D{{{"$match", bson.D{{"loc", bson.D{{"$near", bson.A{0.0, 0.0}}}}}}}},
+			shouldContainErr: "$geoNear, $near, and $nearSphere are not allowed in this context",
+		},
+	}
+
+	for _, tc := range errTests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := self.Aggregate(places, tc.pipeline, nil)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package update
+
+import (
+	"time"
+
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/javascript"
+)
+
+// JavaScriptOptions enables $where and $function.
+//
+// Their code runs in a pure-Go JavaScript interpreter with only the standard ECMAScript built-ins:
+// it has no access to the host, and every evaluation starts from a fresh runtime.
+// In $where, the document is bound to this and obj. Documents and arguments are converted
+// to JavaScript values: numbers become JavaScript numbers, ObjectIDs become hex strings,
+// and dates become Date objects; numbers returned by $function are float64.
+type JavaScriptOptions struct {
+	// Timeout limits the CPU time of each evaluation of $where or $function;
+	// time spent waiting for the CPU on a busy host does not count.
+	// CPU time is measured on Linux; on other systems, wall-clock time is used instead.
+	// Evaluations that exceed it fail with the MaxTimeMSExpired code (50).
+	// If zero, one second is used.
+	Timeout time.Duration
+}
+
+// newEvaluator returns the evaluator for the options, or nil if they are nil.
+func (o *JavaScriptOptions) newEvaluator() *javascript.Evaluator {
+	if o == nil {
+		return nil
+	}
+
+	return javascript.NewEvaluator(o.Timeout)
+}
@@ -0,0 +1,237 @@
+package update_test
+
+import (
+	"testing"
+	"time"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+)
+
+func TestFindWhere(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "a"}, {"scores", bson.A{int32(1), int32(2)}}},
+		{{"_id", int32(2)}, {"name", "bb"}, {"scores", bson.A{int32(5), int32(7)}}},
+		{{"_id", int32(3)}, {"name", "ccc"}, {"at", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	opts := &self.FindOptions{JavaScript: &self.JavaScriptOptions{Timeout: 100 * time.Millisecond}}
+
+	tests := []struct {
+		name             string
+		filter           bson.D
+		opts             *self.FindOptions
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name:     "expression",
+			filter:   bson.D{{"$where", "this.name.length > 1"}},
+			expected: input[1:],
+		},
+		{
+			name:     "trailing semicolon",
+			filter:   bson.D{{"$where", "this.name.length > 1;"}},
+			expected: input[1:],
+		},
+		{
+			name:     "code type",
+			filter:   bson.D{{"$where", primitive.JavaScript("this._id > 1")}},
+			expected: input[1:],
+		},
+		{
+			name:     "obj",
+			filter:   bson.D{{"$where", "obj.scores && obj.scores.reduce((a, b) => a + b, 0) > 10"}},
+			expected: input[1:2],
+		},
+		{
+			name:     "function",
+			filter:   bson.D{{"$where", "function() { return this._id % 2 == 1; }"}},
+			expected: []bson.D{input[0], input[2]},
+		},
+		{
+			name:     "body",
+			filter:   bson.D{{"$where", "var n = this.name; return n == 'a';"}},
+			expected: input[:1],
+		},
+		{
+			name:     "date",
+			filter:   bson.D{{"$where", "this.at instanceof Date && this.at.getUTCFullYear() == 2024"}},
+			expected: input[2:],
+		},
+		{
+			name:     "with other conditions",
+			filter:   bson.D{{"_id", bson.D{{"$gt", int32(1)}}}, {"$where", "this.name != 'ccc'"}},
+			expected: input[1:2],
+		},
+		{
+			name:     "no host access",
+			filter:   bson.D{{"$where", "typeof require == 'undefined' && typeof process == 'undefined'"}},
+			expected: input,
+		},
+		{
+			name:             "disabled",
+			filter:           bson.D{{"$where", "true"}},
+			opts:             &self.FindOptions{},
+			shouldContainErr: "$where is not allowed because JavaScript execution is disabled",
+		},
+		{
+			name:             "bad type",
+			filter:           bson.D{{"$where", int32(1)}},
+			shouldContainErr: "$where got bad type",
+		},
+		{
+			name:             "exception",
+			filter:           bson.D{{"$where", "this.missing.field"}},
+			shouldContainErr: "$where failed :: caused by :: TypeError: Cannot read property 'field' of undefined",
+		},
+		{
+			name:             "syntax error",
+			filter:           bson.D{{"$where", "function() {"}},
+			shouldContainErr: "$where failed :: caused by :: SyntaxError",
+		},
+		{
+			name:             "timeout",
+			filter:           bson.D{{"$where", "function() { while (true) {} }"}},
+			shouldContainErr: "$where exceeded the CPU time limit of 100ms",
+		},
+		{
+			name:             "in $elemMatch",
+			filter:           bson.D{{"scores", bson.D{{"$elemMatch", bson.D{{"$where", "true"}}}}}},
+			shouldContainErr: "$where can only be applied to the top-level document",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			o := opts
+			if tc.opts != nil {
+				o = tc.opts
+			}
+
+			res, err := self.Find(input, tc.filter, o)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestAggregateFunction(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "a"}, {"scores", bson.A{int32(1), int32(2)}}},
+		{{"_id", int32(2)}, {"name", "bb"}, {"scores", bson.A{int32(5), int32(7)}}},
+	}
+
+	opts := &self.AggregateOptions{JavaScript: &self.JavaScriptOptions{}}
+
+	tests := []struct {
+		name             string
+		pipeline         []bson.D
+		opts             *self.AggregateOptions
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name: "add fields",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", bson.D{
+				{"body", "function(name, scores) { return {upper: name.toUpperCase(), total: scores.reduce((a, b) => a + b, 0), tags: [name, null]}; }"},
+				{"args", bson.A{"$name", "$scores"}},
+				{"lang", "js"},
+			}}}}}}}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"r", bson.D{{"upper", "A"}, {"total", float64(3)}, {"tags", bson.A{"a", nil}}}}},
+				{{"_id", int32(2)}, {"r", bson.D{{"upper", "BB"}, {"total", float64(12)}, {"tags", bson.A{"bb", nil}}}}},
+			},
+		},
+		{
+			name: "in $match $expr",
+			pipeline: []bson.D{{{"$match", bson.D{{"$expr", bson.D{{"$function", bson.D{
+				{"body", "function(id) { return id > 1; }"},
+				{"args", bson.A{"$_id"}},
+				{"lang", "js"},
+			}}}}}}}},
+			expected: input[1:],
+		},
+		{
+			name: "code type body",
+			pipeline: []bson.D{{{"$match", bson.D{{"$expr", bson.D{{"$function", bson.D{
+				{"body", primitive.JavaScript("function(id) { return id > 1; }")},
+				{"args", bson.A{"$_id"}},
+				{"lang", "js"},
+			}}}}}}}},
+			expected: input[1:],
+		},
+		{
+			name: "missing argument is null",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", bson.D{
+				{"body", "function(x) { return x === null; }"},
+				{"args", bson.A{"$missing"}},
+				{"lang", "js"},
+			}}}}}}}},
+			expected: []bson.D{{{"_id", int32(1)}, {"r", true}}, {{"_id", int32(2)}, {"r", true}}},
+		},
+		{
+			name: "disabled",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", bson.D{
+				{"body", "function() { return 1; }"}, {"args", bson.A{}}, {"lang", "js"},
+			}}}}}}}},
+			opts:             &self.AggregateOptions{},
+			shouldContainErr: "$function is not allowed because JavaScript execution is disabled",
+		},
+		{
+			name:             "not object",
+			pipeline:         []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", "function() {}"}}}}}}},
+			shouldContainErr: "$function requires an object as an argument, found: string",
+		},
+		{
+			name: "missing args",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", bson.D{
+				{"body", "function() { return 1; }"}, {"lang", "js"},
+			}}}}}}}},
+			shouldContainErr: "The args field must be specified.",
+		},
+		{
+			name: "lang",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", bson.D{
+				{"body", "function() { return 1; }"}, {"args", bson.A{}}, {"lang", "python"},
+			}}}}}}}},
+			shouldContainErr: "Currently the only supported language specifier is 'js'.",
+		},
+		{
+			name: "function result",
+			pipeline: []bson.D{{{"$project", bson.D{{"r", bson.D{{"$function", bson.D{
+				{"body", "function() { return function() {}; }"}, {"args", bson.A{}}, {"lang", "js"},
+			}}}}}}}},
+			shouldContainErr: "$function failed :: caused by :: can't convert a function to BSON",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			o := opts
+			if tc.opts != nil {
+				o = tc.opts
+			}
+
+			res, err := self.Aggregate(input, tc.pipeline, o)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
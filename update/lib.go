@@ -3,9 +3,7 @@ package update
 
 import (
 	"github.com/pkg/errors"
-	"github.com/zaporter/go-update-mongo/internal/ferret/bson2"
 	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
-	"github.com/zaporter/go-update-mongo/internal/ferret/types"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -19,75 +17,20 @@ import (
 //
 //nolint:revive
 func UpdateDocument(document, updateDoc bson.D) (bson.D, error) {
-	if len(updateDoc) == 0 {
-		return nil, errors.New("update document must have at least one element")
-	}
-	doc, err := convertDToDocument(document)
-	if err != nil {
-		return nil, err
-	}
-	if err := doc.ValidateData(); err != nil {
-		return nil, errors.Wrap(err, "validating document")
-	}
-	convertedUpdates, err := convertUpdateParams(updateDoc)
-	if err != nil {
-		return nil, errors.Wrap(err, "convert update operations to update params")
-	}
-	for _, update := range convertedUpdates {
-		// from ferret/handler/msg_update.go
-		if _, err := common.HasSupportedUpdateModifiers("update", update.Update); err != nil {
-			return nil, err
-		}
-
-		if _, err = common.UpdateDocument("update", doc, update.Update, true); err != nil {
-			return nil, errors.Wrap(err, "failed to update document")
-		}
-
-		if !doc.Has("_id") {
-			doc.Set("_id", types.NewObjectID())
-		}
-		if err = doc.ValidateData(); err != nil {
-			return nil, err
-		}
-	}
-	result, err := convertDocumentToD(doc)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
-}
-
-func convertDToDocument(d bson.D) (*types.Document, error) {
-	// from ferret/bson2/document_test.go
-	bytes, err := bson.Marshal(d)
-	if err != nil {
-		return nil, errors.Wrap(err, "marshall bson.D")
-	}
-
-	rawDoc, err := bson2.RawDocument(bytes).DecodeDeep()
-	if err != nil {
-		return nil, errors.Wrap(err, "decode raw bson bytes")
-	}
-
-	doc, err := rawDoc.Convert()
-	return doc, errors.Wrap(err, "converting to parsed bson")
+	return UpdateDocumentWithOptions(document, updateDoc, nil)
 }
 
-func convertDocumentToD(document *types.Document) (bson.D, error) {
-	bson2Doc, err := bson2.ConvertDocument(document)
-	if err != nil {
-		return nil, errors.Wrap(err, "convert from types.Document to bson2.Document")
-	}
-	bytes, err := bson2Doc.Encode()
-	if err != nil {
-		return nil, errors.Wrap(err, "encode bson2.Document")
-	}
-	decoded := bson.D{}
-	err = bson.Unmarshal(bytes, &decoded)
+// UpdateDocumentWithOptions is UpdateDocument with the given options.
+//
+// opts may be nil.
+//
+//nolint:revive
+func UpdateDocumentWithOptions(document, updateDoc bson.D, opts *UpdateOptions) (bson.D, error) {
+	c, err := CompileWithOptions(updateDoc, opts)
 	if err != nil {
-		return nil, errors.Wrap(err, "decode internal bson2 back to bson.D")
+		return nil, err
 	}
-	return decoded, nil
+	return c.Apply(document)
 }
 
 func convertUpdateParams(updates bson.D) ([]common.Update, error) {
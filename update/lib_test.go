@@ -717,6 +717,52 @@ func TestBehaviorParity(t *testing.T) {
 			}},
 			shouldContainErr: "failed to update document",
 		},
+		//
+		// decimal
+		//
+		{
+			name:   "inc decimal",
+			object: objT{{"field", primitive.NewDecimal128(0x3040000000000000, 15)}},
+			update: upT{{
+				"$inc", mapT{"field": 1.5},
+			}},
+		},
+		{
+			name:   "inc int by decimal",
+			object: objT{{"field", 1}},
+			update: upT{{
+				"$inc", mapT{"field": primitive.NewDecimal128(0x303e000000000000, 15)},
+			}},
+		},
+		{
+			name:   "inc decimal overflow",
+			object: objT{{"field", primitive.NewDecimal128(0x5fffed09bead87c0, 0x378d8e63ffffffff)}},
+			update: upT{{
+				"$inc", mapT{"field": primitive.NewDecimal128(0x5fffed09bead87c0, 0x378d8e63ffffffff)},
+			}},
+			shouldContainErr: "infinity values are not allowed",
+		},
+		{
+			name:   "mul decimal",
+			object: objT{{"field", primitive.NewDecimal128(0x303e000000000000, 15)}},
+			update: upT{{
+				"$mul", mapT{"field": int64(3)},
+			}},
+		},
+		{
+			name:   "min decimal",
+			object: objT{{"field", 2}},
+			update: upT{{
+				"$min", mapT{"field": primitive.NewDecimal128(0x303e000000000000, 15)},
+			}},
+		},
+		{
+			name:   "max decimal equal to int",
+			object: objT{{"field", 1}},
+			update: upT{{
+				"$max", mapT{"field": primitive.NewDecimal128(0x303e000000000000, 10)},
+			}},
+		},
 		//// ---------------------- Bitwise Operators -----------------------------
 		////
 		//// $bit
@@ -0,0 +1,206 @@
+package update
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/bson2"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"github.com/zaporter/go-update-mongo/internal/ferret/util/must"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpdateRaw updates the provided raw BSON document using the passed updateDoc
+// and returns the new raw BSON document.
+//
+// It works like UpdateDocument, but decodes only the parts of the document referenced
+// by the update paths. Untouched fields and subdocuments are copied to the result byte by byte,
+// so updating a few fields of a large document is much cheaper than with UpdateDocument.
+// For the same reason untouched fields are not validated or normalized
+// (for example, -0.0 is not converted to 0.0 and nested _id fields are not moved).
+//
+//nolint:revive
+func UpdateRaw(document bson.Raw, updateDoc bson.D) (bson.Raw, error) {
+	c, err := Compile(updateDoc)
+	if err != nil {
+		return nil, err
+	}
+	return c.ApplyRaw(document)
+}
+
+// rawPaths is a tree of field names referenced by update paths.
+type rawPaths map[string]*rawPath
+
+// rawPath is a node of rawPaths.
+type rawPath struct {
+	fields rawPaths // nested fields referenced by update paths, if the whole value is not needed
+	whole  bool     // the whole value is needed
+}
+
+// add adds the dot notation path to the tree.
+func (p rawPaths) add(path string) {
+	segments := strings.Split(path, ".")
+
+	for i, s := range segments {
+		node := p[s]
+		if node == nil {
+			node = new(rawPath)
+			p[s] = node
+		}
+
+		if node.whole {
+			return
+		}
+
+		// positional operators ($, $[] and $[<identifier>]) may match any element
+		if i == len(segments)-1 || strings.HasPrefix(segments[i+1], "$") {
+			node.whole = true
+			node.fields = nil
+
+			return
+		}
+
+		if node.fields == nil {
+			node.fields = rawPaths{}
+		}
+
+		p = node.fields
+	}
+}
+
+// collectUpdatePaths adds paths of all fields referenced by update operators to paths.
+func collectUpdatePaths(update *types.Document, paths rawPaths) {
+	for _, op := range update.Keys() {
+		params, ok := must.NotFail(update.Get(op)).(*types.Document)
+		if !ok {
+			continue
+		}
+
+		for _, key := range params.Keys() {
+			paths.add(key)
+
+			if op != "$rename" {
+				continue
+			}
+
+			if to, ok := must.NotFail(params.Get(key)).(string); ok {
+				paths.add(to)
+			}
+		}
+	}
+}
+
+// decodePartial shallowly decodes the raw document and returns it
+// together with the document containing only fields referenced by paths.
+//
+// Referenced subdocuments are decoded recursively, all other values are kept as is.
+func decodePartial(raw bson2.RawDocument, paths rawPaths) (*bson2.Document, *bson2.Document, error) {
+	orig, err := raw.Decode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := orig.FieldNames()
+	values := orig.Values()
+	seen := make(map[string]struct{}, len(names))
+	partial := bson2.MakeDocument(len(paths))
+
+	for i, name := range names {
+		if _, ok := seen[name]; ok {
+			return nil, nil, errors.Errorf("invalid key: %q (duplicate keys are not allowed)", name)
+		}
+		seen[name] = struct{}{}
+
+		node := paths[name]
+		if node == nil {
+			continue
+		}
+
+		v := values[i]
+		if sub, ok := v.(bson2.RawDocument); ok && !node.whole {
+			if _, v, err = decodePartial(sub, node.fields); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if err = partial.Add(name, v); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return orig, partial, nil
+}
+
+// mergePartial merges the updated partial document into the original shallowly decoded document.
+//
+// Fields not referenced by paths are copied from the original document,
+// fields removed by the update are dropped and new fields are appended to the end.
+// As for UpdateDocument, _id field is always the first one.
+func mergePartial(orig *bson2.Document, paths rawPaths, updated *bson2.Document) (*bson2.Document, error) {
+	res := bson2.MakeDocument(orig.Len() + updated.Len())
+
+	if id := updated.Get("_id"); id != nil {
+		if err := res.Add("_id", id); err != nil {
+			return nil, err
+		}
+	}
+
+	names := orig.FieldNames()
+	values := orig.Values()
+	seen := make(map[string]struct{}, len(names))
+
+	for i, name := range names {
+		seen[name] = struct{}{}
+
+		if name == "_id" && updated.Get("_id") != nil {
+			continue
+		}
+
+		node := paths[name]
+		if node == nil {
+			if err := res.Add(name, values[i]); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		v := updated.Get(name)
+		if v == nil {
+			// removed by the update
+			continue
+		}
+
+		if sub, ok := values[i].(bson2.RawDocument); ok && !node.whole {
+			if updatedSub, ok := v.(*bson2.Document); ok {
+				origSub, err := sub.Decode()
+				if err != nil {
+					return nil, err
+				}
+
+				if v, err = mergePartial(origSub, node.fields, updatedSub); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := res.Add(name, v); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedNames := updated.FieldNames()
+	updatedValues := updated.Values()
+
+	for i, name := range updatedNames {
+		if _, ok := seen[name]; ok || name == "_id" {
+			continue
+		}
+
+		if err := res.Add(name, updatedValues[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,160 @@
+package update
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+)
+
+func TestUpdateRaw(t *testing.T) {
+	oid := primitive.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}
+	document := bson.D{
+		{"name", "raw"},
+		{"_id", oid},
+		{"count", int32(1)},
+		{"stats", bson.D{
+			{"views", int64(10)},
+			{"daily", bson.D{{"mon", int32(1)}, {"tue", int32(2)}}},
+			{"tags", bson.A{"a", "b"}},
+		}},
+		{"items", bson.A{bson.D{{"qty", int32(1)}}, bson.D{{"qty", int32(2)}}}},
+		{"old", "value"},
+		{"untouched", bson.D{{"deep", bson.D{{"x", 1.5}}}}},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		update bson.D
+	}{
+		{"IncTopLevel", bson.D{{"$inc", bson.D{{"count", int32(1)}}}}},
+		{"IncNested", bson.D{{"$inc", bson.D{{"stats.views", int32(5)}, {"stats.daily.tue", int32(1)}}}}},
+		{"SetNewNested", bson.D{{"$set", bson.D{{"stats.daily.wed", int32(3)}, {"stats.new.field", "x"}}}}},
+		{"SetNewTopLevel", bson.D{{"$set", bson.D{{"z", "last"}, {"a", "first"}}}}},
+		{"SetWhole", bson.D{{"$set", bson.D{{"stats", bson.D{{"views", int32(0)}}}}}}},
+		{"Unset", bson.D{{"$unset", bson.D{{"old", ""}, {"stats.daily.mon", ""}}}}},
+		{"Rename", bson.D{{"$rename", bson.D{{"old", "renamed"}, {"stats.views", "stats.daily.views"}}}}},
+		{"PushNested", bson.D{{"$push", bson.D{{"stats.tags", "c"}}}}},
+		{"ArrayIndex", bson.D{{"$set", bson.D{{"items.1.qty", int32(20)}}}}},
+		{"Multiple", bson.D{
+			{"$inc", bson.D{{"count", int32(1)}, {"stats.views", int64(1)}}},
+			{"$set", bson.D{{"name", "updated"}}},
+			{"$setOnInsert", bson.D{{"created", true}}},
+		}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := UpdateDocument(document, tc.update)
+			test.That(t, err, test.ShouldBeNil)
+
+			raw, err := bson.Marshal(document)
+			test.That(t, err, test.ShouldBeNil)
+
+			res, err := UpdateRaw(raw, tc.update)
+			test.That(t, err, test.ShouldBeNil)
+
+			var actual bson.D
+			test.That(t, bson.Unmarshal(res, &actual), test.ShouldBeNil)
+			test.That(t, actual, test.ShouldResemble, expected)
+		})
+	}
+
+	t.Run("UntouchedBytes", func(t *testing.T) {
+		raw, err := bson.Marshal(document)
+		test.That(t, err, test.ShouldBeNil)
+
+		res, err := UpdateRaw(raw, bson.D{{"$inc", bson.D{{"count", int32(1)}}}})
+		test.That(t, err, test.ShouldBeNil)
+
+		// untouched subdocuments are copied as is, without normalization
+		test.That(t, bson.Raw(res).Lookup("untouched").Value, test.ShouldResemble, bson.Raw(raw).Lookup("untouched").Value)
+	})
+
+	t.Run("NoID", func(t *testing.T) {
+		update := bson.D{{"$set", bson.D{{"b", int32(2)}}}}
+
+		_, expected := UpdateDocument(bson.D{{"a", int32(1)}}, update)
+		test.That(t, expected, test.ShouldNotBeNil)
+
+		raw, err := bson.Marshal(bson.D{{"a", int32(1)}})
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = UpdateRaw(raw, update)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldEqual, expected.Error())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		raw, err := bson.Marshal(document)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = UpdateRaw(raw, bson.D{})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = UpdateRaw(raw, bson.D{{"$inc", bson.D{{"name", int32(1)}}}})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = UpdateRaw(bson.Raw{1, 2, 3}, bson.D{{"$inc", bson.D{{"count", int32(1)}}}})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		dup, err := bson.Marshal(bson.D{{"_id", int32(1)}, {"a", int32(1)}, {"a", int32(2)}})
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = UpdateRaw(dup, bson.D{{"$inc", bson.D{{"a", int32(1)}}}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "duplicate keys")
+	})
+}
+
+func BenchmarkUpdateRaw(b *testing.B) {
+	// a few megabytes of data that is not touched by the update
+	blobs := make(bson.D, 100)
+	for i := range blobs {
+		blobs[i] = bson.E{Key: "blob" + strings.Repeat("x", i), Value: bson.D{
+			{"data", strings.Repeat("y", 32*1024)},
+			{"values", bson.A{int32(1), int32(2), int32(3)}},
+		}}
+	}
+
+	d := bson.D{
+		{"_id", primitive.NewObjectID()},
+		{"counters", bson.D{{"views", int64(0)}, {"likes", int64(0)}}},
+		{"blobs", blobs},
+	}
+	update := bson.D{{"$inc", bson.D{{"counters.views", int32(1)}, {"counters.likes", int32(2)}}}}
+
+	raw, err := bson.Marshal(d)
+	test.That(b, err, test.ShouldBeNil)
+
+	b.Run("Raw", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := UpdateRaw(raw, update); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Document", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			var doc bson.D
+			if err := bson.Unmarshal(raw, &doc); err != nil {
+				b.Fatal(err)
+			}
+
+			res, err := UpdateDocument(doc, update)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if _, err = bson.Marshal(res); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package update
+
+import (
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common/aggregations/operators"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/textsearch"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TextIndex is a text index over in-memory documents
+// that lets filters use $text and {$meta: "textScore"}, like a MongoDB text index.
+//
+// It is safe for concurrent use.
+type TextIndex struct {
+	documents []bson.D
+	docs      []*types.Document
+	index     *textsearch.Index
+}
+
+// TextIndexOptions configures NewTextIndex.
+type TextIndexOptions struct {
+	// Weights are the relative weights of indexed fields, like the weights option of createIndexes.
+	// Fields not listed have weight 1; weighted fields are indexed even if they are not in the keys.
+	Weights bson.D
+
+	// DefaultLanguage is the language used to stem and drop stop words
+	// of documents and queries that do not specify one.
+	// If empty, "english" is used; "none" disables stemming.
+	DefaultLanguage string
+
+	// LanguageOverride is the document field that overrides the language of that document.
+	// If empty, "language" is used.
+	LanguageOverride string
+}
+
+// NewTextIndex builds a text index over the documents.
+//
+// The keys document is the index specification, like {title: "text", body: "text"},
+// or {"$**": "text"} to index all string fields.
+//
+// opts may be nil.
+func NewTextIndex(documents []bson.D, keys bson.D, opts *TextIndexOptions) (*TextIndex, error) {
+	if opts == nil {
+		opts = new(TextIndexOptions)
+	}
+
+	keysDoc, err := convertDToDocument(keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert keys")
+	}
+
+	var weights *types.Document
+
+	if opts.Weights != nil {
+		if weights, err = convertDToDocument(opts.Weights); err != nil {
+			return nil, errors.Wrap(err, "convert weights")
+		}
+	}
+
+	index, err := textsearch.NewIndex(keysDoc, weights, opts.DefaultLanguage, opts.LanguageOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := convertDsToDocuments(documents)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert documents")
+	}
+
+	for _, doc := range docs {
+		if err = index.Add(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return &TextIndex{
+		documents: documents,
+		docs:      docs,
+		index:     index,
+	}, nil
+}
+
+// Find is like Find for the indexed documents, but the filter may use $text,
+// and opts.Sort and opts.Projection may use {$meta: "textScore"}.
+//
+// opts may be nil.
+func (t *TextIndex) Find(filter bson.D, opts *FindOptions) ([]bson.D, error) {
+	return find(t.documents, t.docs, filter, opts, &operators.Metadata{TextIndex: t.index})
+}
+
+// Aggregate is like Aggregate for the indexed documents, but $match may use $text,
+// and $sort, $project and other expressions may use {$meta: "textScore"}.
+//
+// opts may be nil.
+func (t *TextIndex) Aggregate(pipeline []bson.D, opts *AggregateOptions) ([]bson.D, error) {
+	return aggregate(t.documents, pipeline, opts, &operators.Metadata{TextIndex: t.index})
+}
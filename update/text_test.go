@@ -0,0 +1,141 @@
+package update_test
+
+import (
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.viam.com/test"
+)
+
+func TestTextIndex(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"title", "Coffee shop"}, {"body", "Fresh coffee and cakes"}},
+		{{"_id", int32(2)}, {"title", "Tea house"}, {"body", "Green tea, black tea and coffee"}},
+		{{"_id", int32(3)}, {"title", "Bakery"}, {"body", "Running out of CAKES"}},
+		{{"_id", int32(4)}, {"title", "Café"}, {"body", "Espresso drinks"}},
+		{{"_id", int32(5)}, {"title", "Panadería"}, {"body", "pan y pasteles"}, {"language", "spanish"}},
+	}
+
+	idx, err := self.NewTextIndex(input, bson.D{{"title", "text"}, {"body", "text"}}, &self.TextIndexOptions{
+		Weights: bson.D{{"title", int32(10)}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	ids := func(docs []bson.D) []any {
+		res := make([]any, len(docs))
+		for i, d := range docs {
+			res[i] = d[0].Value
+		}
+		return res
+	}
+
+	tests := []struct {
+		name             string
+		text             bson.D
+		expected         []any
+		shouldContainErr string
+	}{
+		{name: "any word", text: bson.D{{"$search", "coffee espresso"}}, expected: []any{int32(1), int32(2), int32(4)}},
+		{name: "stemming", text: bson.D{{"$search", "cake run"}}, expected: []any{int32(1), int32(3)}},
+		{name: "stop words", text: bson.D{{"$search", "and of the"}}, expected: []any{}},
+		{name: "negation", text: bson.D{{"$search", "coffee -tea"}}, expected: []any{int32(1)}},
+		{name: "hyphenated word", text: bson.D{{"$search", "green-tea"}}, expected: []any{int32(2)}},
+		{name: "phrase", text: bson.D{{"$search", `"black tea" coffee`}}, expected: []any{int32(2)}},
+		{name: "negated phrase", text: bson.D{{"$search", `coffee -"coffee shop"`}}, expected: []any{int32(2)}},
+		{
+			name:     "case sensitive",
+			text:     bson.D{{"$search", "CAKES"}, {"$caseSensitive", true}},
+			expected: []any{int32(3)},
+		},
+		{name: "diacritic insensitive", text: bson.D{{"$search", "cafe"}}, expected: []any{int32(4)}},
+		{
+			name:     "diacritic sensitive",
+			text:     bson.D{{"$search", "cafe"}, {"$diacriticSensitive", true}},
+			expected: []any{},
+		},
+		{
+			name:     "language",
+			text:     bson.D{{"$search", "pastel"}, {"$language", "es"}},
+			expected: []any{int32(5)},
+		},
+		{
+			name:     "no stemming",
+			text:     bson.D{{"$search", "pasteles"}, {"$language", "none"}},
+			expected: []any{},
+		},
+		{name: "missing search", text: bson.D{{"$language", "en"}}, shouldContainErr: "$search required"},
+		{
+			name:             "unknown language",
+			text:             bson.D{{"$search", "x"}, {"$language", "klingon"}},
+			shouldContainErr: `unsupported language: "klingon"`,
+		},
+		{name: "extra field", text: bson.D{{"$search", "x"}, {"$foo", true}}, shouldContainErr: "extra fields in $text"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := idx.Find(bson.D{{"$text", tc.text}}, nil)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ids(res), test.ShouldResemble, tc.expected)
+		})
+	}
+
+	t.Run("sort and project score", func(t *testing.T) {
+		res, err := idx.Find(bson.D{{"$text", bson.D{{"$search", "coffee"}}}}, &self.FindOptions{
+			Sort:       bson.D{{"score", bson.D{{"$meta", "textScore"}}}},
+			Projection: bson.D{{"title", int32(1)}, {"score", bson.D{{"$meta", "textScore"}}}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{
+			{{"_id", int32(1)}, {"title", "Coffee shop"}, {"score", 8.166666666666666}},
+			{{"_id", int32(2)}, {"title", "Tea house"}, {"score", 0.6}},
+		})
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		res, err := idx.Aggregate([]bson.D{
+			{{"$match", bson.D{{"$text", bson.D{{"$search", "tea"}}}}}},
+			{{"$project", bson.D{{"score", bson.D{{"$meta", "textScore"}}}}}},
+			{{"$sort", bson.D{{"score", bson.D{{"$meta", "textScore"}}}}}},
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, []bson.D{{{"_id", int32(2)}, {"score", 8.55}}})
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		all, err := self.NewTextIndex(input, bson.D{{"$**", "text"}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		res, err := all.Find(bson.D{{"$text", bson.D{{"$search", "spanish bakery"}}}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ids(res), test.ShouldResemble, []any{int32(3)})
+	})
+
+	t.Run("without index", func(t *testing.T) {
+		_, err := self.Find(input, bson.D{{"$text", bson.D{{"$search", "coffee"}}}}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "text index required for $text query")
+	})
+
+	t.Run("score without text query", func(t *testing.T) {
+		_, err := idx.Find(bson.D{}, &self.FindOptions{Projection: bson.D{{"score", bson.D{{"$meta", "textScore"}}}}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "query requires text score metadata")
+	})
+
+	t.Run("invalid weight", func(t *testing.T) {
+		_, err := self.NewTextIndex(input, bson.D{{"title", "text"}}, &self.TextIndexOptions{
+			Weights: bson.D{{"title", int32(0)}},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "text index weight must be in the exclusive interval")
+	})
+}
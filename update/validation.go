@@ -0,0 +1,68 @@
+package update
+
+import (
+	"github.com/pkg/errors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/common"
+	"github.com/zaporter/go-update-mongo/internal/ferret/handler/handlererrors"
+	"github.com/zaporter/go-update-mongo/internal/ferret/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// documentValidationFailure is the code of errors returned for documents that fail validation.
+const documentValidationFailure = int(handlererrors.ErrDocumentValidationFailure)
+
+// newValidator validates the validator options and returns the validator,
+// or nil if no validator is set.
+func newValidator(opts *UpdateOptions) (*common.Validator, error) {
+	if opts.Validator == nil {
+		return nil, nil
+	}
+
+	filter, err := convertDToDocument(opts.Validator)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert validator")
+	}
+
+	return common.NewValidator(filter, opts.ValidationLevel, opts.ValidationAction)
+}
+
+// validate checks the updated document against the validator.
+// oldDoc is the document before the update; it is used only by the moderate validation level.
+func (c *CompiledUpdate) validate(oldDoc, newDoc *types.Document) error {
+	err := c.validator.ValidateUpdate(oldDoc, newDoc)
+	if err == nil {
+		return nil
+	}
+
+	var cmdErr *handlererrors.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code() != handlererrors.ErrDocumentValidationFailure {
+		return err
+	}
+
+	we := mongo.WriteError{
+		Code:    documentValidationFailure,
+		Message: cmdErr.Err().Error(),
+	}
+
+	if info := cmdErr.Info(); info != nil && info.Details != nil {
+		details, err := convertDocumentToD(info.Details)
+		if err != nil {
+			return err
+		}
+
+		if we.Details, err = bson.Marshal(details); err != nil {
+			return err
+		}
+	}
+
+	if c.validator.Action == common.ValidationActionWarn {
+		if c.onValidationWarning != nil {
+			c.onValidationWarning(we)
+		}
+
+		return nil
+	}
+
+	return we
+}
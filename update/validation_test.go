@@ -0,0 +1,244 @@
+package update_test
+
+import (
+	"errors"
+	"testing"
+
+	self "github.com/zaporter/go-update-mongo/update"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.viam.com/test"
+)
+
+func TestFindJSONSchema(t *testing.T) {
+	input := []bson.D{
+		{{"_id", int32(1)}, {"name", "Alice"}, {"age", int32(30)}, {"tags", bson.A{"a", "b"}}},
+		{{"_id", int32(2)}, {"name", "Bob"}, {"age", "unknown"}},
+		{{"_id", int32(3)}, {"name", "Carol"}, {"age", int32(-1)}, {"tags", bson.A{"a", "a"}}},
+		{{"_id", int32(4)}, {"age", int32(40)}},
+	}
+
+	tests := []struct {
+		name             string
+		schema           bson.D
+		expected         []bson.D
+		shouldContainErr string
+	}{
+		{
+			name: "required and bsonType",
+			schema: bson.D{
+				{"required", bson.A{"name"}},
+				{"properties", bson.D{{"age", bson.D{{"bsonType", "int"}}}}},
+			},
+			expected: []bson.D{input[0], input[2]},
+		},
+		{
+			name: "minimum",
+			schema: bson.D{{"properties", bson.D{
+				{"age", bson.D{{"minimum", int32(0)}}},
+			}}},
+			expected: []bson.D{input[0], input[1], input[3]},
+		},
+		{
+			name: "uniqueItems",
+			schema: bson.D{{"properties", bson.D{
+				{"tags", bson.D{{"type", "array"}, {"uniqueItems", true}}},
+			}}},
+			expected: []bson.D{input[0], input[1], input[3]},
+		},
+		{
+			name: "additionalProperties",
+			schema: bson.D{
+				{"properties", bson.D{{"_id", bson.D{}}, {"age", bson.D{}}}},
+				{"additionalProperties", false},
+			},
+			expected: []bson.D{input[3]},
+		},
+		{
+			name: "string keywords and anyOf",
+			schema: bson.D{{"properties", bson.D{
+				{"name", bson.D{{"anyOf", bson.A{
+					bson.D{{"pattern", "^A"}},
+					bson.D{{"maxLength", int32(3)}},
+				}}}},
+			}}},
+			expected: []bson.D{input[0], input[1], input[3]},
+		},
+		{
+			name:             "unknown keyword",
+			schema:           bson.D{{"foo", int32(1)}},
+			shouldContainErr: "Unknown $jsonSchema keyword: foo",
+		},
+		{
+			name:             "unsupported keyword",
+			schema:           bson.D{{"format", "email"}},
+			shouldContainErr: "$jsonSchema keyword 'format' is not currently supported",
+		},
+		{
+			name:             "integer type",
+			schema:           bson.D{{"type", "integer"}},
+			shouldContainErr: "$jsonSchema type 'integer' is not currently supported",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := self.Find(input, bson.D{{"$jsonSchema", tc.schema}}, nil)
+			if tc.shouldContainErr != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.shouldContainErr)
+				return
+			}
+
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, res, test.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestUpdateValidation(t *testing.T) {
+	validator := bson.D{
+		{"$jsonSchema", bson.D{
+			{"bsonType", "object"},
+			{"required", bson.A{"name"}},
+			{"properties", bson.D{
+				{"name", bson.D{{"bsonType", "string"}}},
+				{"age", bson.D{{"bsonType", "int"}, {"minimum", int32(0)}}},
+			}},
+		}},
+		{"status", bson.D{{"$in", bson.A{"active", "inactive"}}}},
+	}
+	doc := bson.D{{"_id", int32(1)}, {"name", "Alice"}, {"age", int32(30)}, {"status", "active"}}
+
+	t.Run("valid", func(t *testing.T) {
+		res, err := self.UpdateDocumentWithOptions(doc, bson.D{{"$inc", bson.D{{"age", int32(1)}}}},
+			&self.UpdateOptions{Validator: validator})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble,
+			bson.D{{"_id", int32(1)}, {"name", "Alice"}, {"age", int32(31)}, {"status", "active"}})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := self.UpdateDocumentWithOptions(doc, bson.D{{"$set", bson.D{{"age", int32(-1)}}}},
+			&self.UpdateOptions{Validator: validator})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		var we mongo.WriteError
+		test.That(t, errors.As(err, &we), test.ShouldBeTrue)
+		test.That(t, we.Code, test.ShouldEqual, 121)
+		test.That(t, we.Message, test.ShouldEqual, "Document failed validation")
+
+		var errInfo bson.D
+		test.That(t, bson.Unmarshal(we.Details, &errInfo), test.ShouldBeNil)
+		test.That(t, errInfo, test.ShouldResemble, bson.D{
+			{"failingDocumentId", int32(1)},
+			{"details", bson.D{
+				{"operatorName", "$and"},
+				{"clausesNotSatisfied", bson.A{bson.D{
+					{"index", int32(0)},
+					{"details", bson.D{
+						{"operatorName", "$jsonSchema"},
+						{"schemaRulesNotSatisfied", bson.A{bson.D{
+							{"operatorName", "properties"},
+							{"propertiesNotSatisfied", bson.A{bson.D{
+								{"propertyName", "age"},
+								{"details", bson.A{bson.D{
+									{"operatorName", "minimum"},
+									{"specifiedAs", bson.D{{"minimum", int32(0)}}},
+									{"reason", "comparison failed"},
+									{"consideredValue", int32(-1)},
+								}}},
+							}}},
+						}}},
+					}},
+				}}},
+			}},
+		})
+	})
+
+	t.Run("query operators", func(t *testing.T) {
+		_, err := self.UpdateDocumentWithOptions(doc, bson.D{{"$set", bson.D{{"status", "deleted"}}}},
+			&self.UpdateOptions{Validator: validator})
+
+		var we mongo.WriteError
+		test.That(t, errors.As(err, &we), test.ShouldBeTrue)
+		test.That(t, we.Code, test.ShouldEqual, 121)
+
+		var errInfo bson.D
+		test.That(t, bson.Unmarshal(we.Details, &errInfo), test.ShouldBeNil)
+		test.That(t, errInfo[1].Value, test.ShouldResemble, bson.D{
+			{"operatorName", "$and"},
+			{"clausesNotSatisfied", bson.A{bson.D{
+				{"index", int32(1)},
+				{"details", bson.D{
+					{"operatorName", "$in"},
+					{"specifiedAs", bson.D{{"status", bson.D{{"$in", bson.A{"active", "inactive"}}}}}},
+					{"reason", "comparison failed"},
+					{"consideredValue", "deleted"},
+				}},
+			}}},
+		})
+	})
+
+	t.Run("unset required", func(t *testing.T) {
+		c, err := self.CompileWithOptions(bson.D{{"$unset", bson.D{{"name", ""}}}}, &self.UpdateOptions{Validator: validator})
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = c.Apply(doc)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		raw, err := bson.Marshal(doc)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = c.ApplyRaw(raw)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("moderate", func(t *testing.T) {
+		invalid := bson.D{{"_id", int32(2)}, {"age", int32(5)}, {"status", "active"}}
+		opts := &self.UpdateOptions{Validator: validator, ValidationLevel: "moderate"}
+
+		res, err := self.UpdateDocumentWithOptions(invalid, bson.D{{"$set", bson.D{{"age", int32(-5)}}}}, opts)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldResemble, bson.D{{"_id", int32(2)}, {"age", int32(-5)}, {"status", "active"}})
+
+		_, err = self.UpdateDocumentWithOptions(doc, bson.D{{"$set", bson.D{{"age", int32(-5)}}}}, opts)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("off", func(t *testing.T) {
+		_, err := self.UpdateDocumentWithOptions(doc, bson.D{{"$set", bson.D{{"age", int32(-5)}}}},
+			&self.UpdateOptions{Validator: validator, ValidationLevel: "off"})
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		var warnings []error
+		res, err := self.UpdateDocumentWithOptions(doc, bson.D{{"$set", bson.D{{"age", int32(-5)}}}},
+			&self.UpdateOptions{
+				Validator:           validator,
+				ValidationAction:    "warn",
+				OnValidationWarning: func(err error) { warnings = append(warnings, err) },
+			})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res[2].Value, test.ShouldEqual, int32(-5))
+		test.That(t, warnings, test.ShouldHaveLength, 1)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		update := bson.D{{"$set", bson.D{{"a", int32(1)}}}}
+
+		for name, opts := range map[string]*self.UpdateOptions{
+			"level":  {Validator: validator, ValidationLevel: "sometimes"},
+			"action": {Validator: validator, ValidationAction: "ignore"},
+			"where":  {Validator: bson.D{{"$where", "true"}}},
+			"schema": {Validator: bson.D{{"$jsonSchema", bson.D{{"bsonType", "integer"}}}}},
+		} {
+			opts := opts
+			t.Run(name, func(t *testing.T) {
+				_, err := self.CompileWithOptions(update, opts)
+				test.That(t, err, test.ShouldNotBeNil)
+			})
+		}
+	})
+}